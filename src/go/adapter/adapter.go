@@ -1,5 +1,7 @@
 package adapter
 
+import "time"
+
 // VersionNumber - Build-time variable
 var VersionNumber string
 
@@ -10,6 +12,10 @@ var GRPCHost string = "localhost"
 var GRPCPort string = "8090"
 var GRPCServerAddress string = GRPCHost + ":" + GRPCPort
 
+// The MockLogic serves the SimControl gRPC service on this address
+var SimControlPort string = "8091"
+var SimControlServerAddress string = GRPCHost + ":" + SimControlPort
+
 // Connection types for communicating with clients
 const (
 	ConnectionTypeAll       string = "all" // Indicates a message that should be broadcast to all clients on all connections
@@ -18,15 +24,52 @@ const (
 
 // API message types
 const (
-	APIMessageTypeReserveTrip string = "reserveTrip"
-	APIMessageTypeAck         string = "ack"
-	APIMessageTypeAtDock      string = "atDock"
-	APIMessageTypeOnBoat      string = "onBoat"
-	APIMessageTypeOffBoat     string = "offBoat"
-	APIMessageTypeBoatStatus  string = "boatStatus"
-	APIMessageTypeArrived     string = "arrived"
+	APIMessageTypeReserveTrip   string = "reserveTrip"
+	APIMessageTypeAck           string = "ack"
+	APIMessageTypeAtDock        string = "atDock"
+	APIMessageTypeOnBoat        string = "onBoat"
+	APIMessageTypeOffBoat       string = "offBoat"
+	APIMessageTypeBoatStatus    string = "boatStatus"
+	APIMessageTypeArrived       string = "arrived"
+	APIMessageTypeTripProgress  string = "tripProgress"
+	APIMessageTypeCancelTrip    string = "cancelTrip"
+	APIMessageTypeCancelAck     string = "cancelAck"
+	APIMessageTypeGetTrip       string = "getTrip"
+	APIMessageTypeTripStatus    string = "tripStatus"
+	APIMessageTypeSubscribe     string = "subscribe"
+	APIMessageTypeUnsubscribe   string = "unsubscribe"
+	APIMessageTypeSubscriptions string = "subscriptions"
+	APIMessageTypeError         string = "error"
 )
 
+// ProtocolVersion is the version of the gRPC protocol between the MockLogic and
+// the Adapter. It is exchanged in the handshake, and is increased when a change
+// to the protocol means that older components cannot work with newer ones.
+const ProtocolVersion uint32 = 1
+
+// ToMockLogicMessageTypes are the API message types that the Adapter passes from
+// the API clients to the MockLogic
+var ToMockLogicMessageTypes = []string{
+	APIMessageTypeReserveTrip,
+	APIMessageTypeAtDock,
+	APIMessageTypeOnBoat,
+	APIMessageTypeOffBoat,
+	APIMessageTypeCancelTrip,
+	APIMessageTypeGetTrip,
+}
+
+// FromMockLogicMessageTypes are the API message types that the Adapter passes
+// from the MockLogic to the API clients
+var FromMockLogicMessageTypes = []string{
+	APIMessageTypeAck,
+	APIMessageTypeCancelAck,
+	APIMessageTypeTripStatus,
+	APIMessageTypeBoatStatus,
+	APIMessageTypeArrived,
+	APIMessageTypeTripProgress,
+	APIMessageTypeError,
+}
+
 // Service states
 const (
 	ServiceStateUnknown     int32 = 0
@@ -42,6 +85,33 @@ var ServiceStateConversion = map[int32]string{
 	ServiceStateUnavailable: "unavailable",
 }
 
+// Error reason codes
+const (
+	ErrorReasonUnknown            int32 = 0
+	ErrorReasonTripNotFound       int32 = 1
+	ErrorReasonTripMismatch       int32 = 2
+	ErrorReasonInvalidTripState   int32 = 3
+	ErrorReasonInvalidMessage     int32 = 4
+	ErrorReasonUnknownMessageType int32 = 5
+	ErrorReasonUnavailable        int32 = 6
+	ErrorReasonInvalidTrip        int32 = 7
+	ErrorReasonNoBoatInService    int32 = 8
+	ErrorReasonBoatsFull          int32 = 9
+)
+
+var ErrorReasonConversion = map[int32]string{
+	ErrorReasonUnknown:            "unknown",
+	ErrorReasonTripNotFound:       "tripNotFound",
+	ErrorReasonTripMismatch:       "tripMismatch",
+	ErrorReasonInvalidTripState:   "invalidTripState",
+	ErrorReasonInvalidMessage:     "invalidMessage",
+	ErrorReasonUnknownMessageType: "unknownMessageType",
+	ErrorReasonUnavailable:        "unavailable",
+	ErrorReasonInvalidTrip:        "invalidTrip",
+	ErrorReasonNoBoatInService:    "noBoatInService",
+	ErrorReasonBoatsFull:          "boatsFull",
+}
+
 const (
 	GangwayLocationFore string = "fore"
 	GangwayLocationAft  string = "aft"
@@ -63,8 +133,8 @@ func NewClientData(connName, connType string) ClientData {
 
 // Address
 type Address struct {
-	Number int32
-	Street string
+	Number int32  `json:"number"`
+	Street string `json:"street"`
 }
 
 func NewAddress(number int32, street string) Address {
@@ -76,8 +146,8 @@ func NewAddress(number int32, street string) Address {
 
 // Dock
 type Dock struct {
-	Address Address
-	Gangway string
+	Address Address `json:"address"`
+	Gangway string  `json:"gangway"`
 }
 
 func NewDock(address Address, gangway string) Dock {
@@ -89,8 +159,8 @@ func NewDock(address Address, gangway string) Dock {
 
 // Boat
 type Boat struct {
-	BoatID int32
-	Name   string
+	BoatID int32  `json:"boatID"`
+	Name   string `json:"name"`
 }
 
 func NewBoat(boatID int32, name string) Boat {
@@ -102,23 +172,37 @@ func NewBoat(boatID int32, name string) Boat {
 
 // ReserveTrip messages
 
-// ReserveTripAPIMessage contains the Reserve message received from the client
+// ReserveTripAPIMessage contains the Reserve message received from the client.
+// DepartureTime is the earliest time that the client wants to depart from the
+// SourceDock. When it is zero, the trip is reserved on the next boat.
+// PartySize is the number of passengers travelling together on the trip. When
+// it is zero, a seat is reserved for each of the PassengerNames, or for one
+// passenger if no names are given. PassengerNames optionally names the
+// passengers of the party so that each of them can be reported on or off the
+// boat.
 type ReserveTripAPIMessage struct {
-	MessageType     string // const "reserveTrip"
-	AuthToken       string
-	ClientID        string
-	SourceDock      Dock
-	DestinationDock Dock
+	MessageType     string    `json:"messageType"` // const "reserveTrip"
+	AuthToken       string    `json:"authToken"`
+	ClientID        string    `json:"clientID"`
+	SourceDock      Dock      `json:"sourceDock"`
+	DestinationDock Dock      `json:"destinationDock"`
+	DepartureTime   time.Time `json:"departureTime"`
+	PartySize       int32     `json:"partySize"`
+	PassengerNames  []string  `json:"passengerNames"`
 }
 
 func NewReserveTripAPIMessage(msgType, token, clientID string,
-	sourceDock, destDock Dock) ReserveTripAPIMessage {
+	sourceDock, destDock Dock, departureTime time.Time, partySize int32,
+	passengerNames []string) ReserveTripAPIMessage {
 	return ReserveTripAPIMessage{
 		MessageType:     msgType,
 		AuthToken:       token,
 		ClientID:        clientID,
 		SourceDock:      sourceDock,
 		DestinationDock: destDock,
+		DepartureTime:   departureTime,
+		PartySize:       partySize,
+		PassengerNames:  passengerNames,
 	}
 }
 
@@ -131,7 +215,7 @@ func (rm *ReserveTripAPIMessage) GetMessageType() string {
 // ReserveTripAPIMessage between the Adapter and the MockLogic
 type ReserveTripMockLogicMessage struct {
 	APIMessage ReserveTripAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewReserveTripMockLogicMessage(apiMsg ReserveTripAPIMessage,
@@ -145,23 +229,39 @@ func NewReserveTripMockLogicMessage(apiMsg ReserveTripAPIMessage,
 // Ack messages
 
 // AckAPIMessage contains the Ack message transmitted to the client
-// as a reply to the client Reserve message
+// as a reply to the client Reserve message. When IsReserved is false,
+// ReasonCode and Reason give the reason that the trip was not reserved.
+// SourceETA and DestinationETA are the estimated times that the boat
+// arrives at the docks of the trip, and are zero if they are not known.
+// DepartureTime is the confirmed departure from the SourceDock of a trip
+// reserved with a DepartureTime, and is zero for a trip on the next boat.
 type AckAPIMessage struct {
-	MessageType   string // const "ack"
-	ClientID      string
-	IsReserved    bool
-	Boat          Boat
-	TransactionID string
+	MessageType    string    `json:"messageType"` // const "ack"
+	ClientID       string    `json:"clientID"`
+	IsReserved     bool      `json:"isReserved"`
+	Boat           Boat      `json:"boat"`
+	TransactionID  string    `json:"transactionID"`
+	ReasonCode     int32     `json:"reasonCode"`
+	Reason         string    `json:"reason"`
+	SourceETA      time.Time `json:"sourceETA"`
+	DestinationETA time.Time `json:"destinationETA"`
+	DepartureTime  time.Time `json:"departureTime"`
 }
 
 func NewAckAPIMessage(msgType, clientID string, isReserved bool,
-	boat Boat, transactionID string) AckAPIMessage {
+	boat Boat, transactionID string, reasonCode int32, reason string,
+	sourceETA, destinationETA, departureTime time.Time) AckAPIMessage {
 	return AckAPIMessage{
-		MessageType:   msgType,
-		ClientID:      clientID,
-		IsReserved:    isReserved,
-		Boat:          boat,
-		TransactionID: transactionID,
+		MessageType:    msgType,
+		ClientID:       clientID,
+		IsReserved:     isReserved,
+		Boat:           boat,
+		TransactionID:  transactionID,
+		ReasonCode:     reasonCode,
+		Reason:         reason,
+		SourceETA:      sourceETA,
+		DestinationETA: destinationETA,
+		DepartureTime:  departureTime,
 	}
 }
 
@@ -174,7 +274,7 @@ func (a *AckAPIMessage) GetMessageType() string {
 // AckAPIMessage between the MockLogic and the Adapter
 type AckMockLogicMessage struct {
 	APIMessage AckAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewAckMockLogicMessage(apiMsg AckAPIMessage,
@@ -189,11 +289,11 @@ func NewAckMockLogicMessage(apiMsg AckAPIMessage,
 
 // AtDockAPIMessage contains the AtDock message received from the client
 type AtDockAPIMessage struct {
-	MessageType   string // const "atDock"
-	ClientID      string
-	Boat          Boat
-	Dock          Dock
-	TransactionID string
+	MessageType   string `json:"messageType"` // const "atDock"
+	ClientID      string `json:"clientID"`
+	Boat          Boat   `json:"boat"`
+	Dock          Dock   `json:"dock"`
+	TransactionID string `json:"transactionID"`
 }
 
 func NewAtDockAPIMessage(msgType, clientID string, boat Boat,
@@ -216,7 +316,7 @@ func (ac *AtDockAPIMessage) GetMessageType() string {
 // AtDockAPIMessage between the Adapter and the MockLogic
 type AtDockMockLogicMessage struct {
 	APIMessage AtDockAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewAtDockMockLogicMessage(apiMsg AtDockAPIMessage,
@@ -229,21 +329,25 @@ func NewAtDockMockLogicMessage(apiMsg AtDockAPIMessage,
 
 // OnBoat messages
 
-// OnBoatAPIMessage contains the OnBoat message received from the client
+// OnBoatAPIMessage contains the OnBoat message received from the client.
+// PassengerNames holds the passengers of the party that boarded the boat. When
+// it is empty, the message applies to every passenger of the party.
 type OnBoatAPIMessage struct {
-	MessageType   string // const "onBoat"
-	ClientID      string
-	Boat          Boat
-	TransactionID string
+	MessageType    string   `json:"messageType"` // const "onBoat"
+	ClientID       string   `json:"clientID"`
+	Boat           Boat     `json:"boat"`
+	TransactionID  string   `json:"transactionID"`
+	PassengerNames []string `json:"passengerNames"`
 }
 
 func NewOnBoatAPIMessage(msgType, clientID string,
-	boat Boat, transactionID string) OnBoatAPIMessage {
+	boat Boat, transactionID string, passengerNames []string) OnBoatAPIMessage {
 	return OnBoatAPIMessage{
-		MessageType:   msgType,
-		ClientID:      clientID,
-		Boat:          boat,
-		TransactionID: transactionID,
+		MessageType:    msgType,
+		ClientID:       clientID,
+		Boat:           boat,
+		TransactionID:  transactionID,
+		PassengerNames: passengerNames,
 	}
 }
 
@@ -256,7 +360,7 @@ func (ac *OnBoatAPIMessage) GetMessageType() string {
 // OnBoatAPIMessage between the Adapter and the MockLogic
 type OnBoatMockLogicMessage struct {
 	APIMessage OnBoatAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewOnBoatMockLogicMessage(apiMsg OnBoatAPIMessage,
@@ -269,21 +373,25 @@ func NewOnBoatMockLogicMessage(apiMsg OnBoatAPIMessage,
 
 // OffBoat messages
 
-// OffBoatAPIMessage contains the OffBoat message received from the client
+// OffBoatAPIMessage contains the OffBoat message received from the client.
+// PassengerNames holds the passengers of the party that got off the boat. When
+// it is empty, the message applies to every passenger of the party.
 type OffBoatAPIMessage struct {
-	MessageType   string // const "offBoat"
-	ClientID      string
-	Boat          Boat
-	TransactionID string
+	MessageType    string   `json:"messageType"` // const "offBoat"
+	ClientID       string   `json:"clientID"`
+	Boat           Boat     `json:"boat"`
+	TransactionID  string   `json:"transactionID"`
+	PassengerNames []string `json:"passengerNames"`
 }
 
 func NewOffBoatAPIMessage(msgType, clientID string,
-	boat Boat, transactionID string) OffBoatAPIMessage {
+	boat Boat, transactionID string, passengerNames []string) OffBoatAPIMessage {
 	return OffBoatAPIMessage{
-		MessageType:   msgType,
-		ClientID:      clientID,
-		Boat:          boat,
-		TransactionID: transactionID,
+		MessageType:    msgType,
+		ClientID:       clientID,
+		Boat:           boat,
+		TransactionID:  transactionID,
+		PassengerNames: passengerNames,
 	}
 }
 
@@ -296,7 +404,7 @@ func (ac *OffBoatAPIMessage) GetMessageType() string {
 // OffBoatAPIMessage between the Adapter and the MockLogic
 type OffBoatMockLogicMessage struct {
 	APIMessage OffBoatAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewOffBoatMockLogicMessage(apiMsg OffBoatAPIMessage,
@@ -311,23 +419,25 @@ func NewOffBoatMockLogicMessage(apiMsg OffBoatAPIMessage,
 
 // BoatStatusAPIMessage contains the BoatStatus message broadcast to all clients
 type BoatStatusAPIMessage struct {
-	MessageType  string // const "boatStatus"
-	Boat         Boat
-	ServiceState int32
-	PreviousDock Dock
-	CurrentDock  Dock
-	NextDock     Dock
+	MessageType    string `json:"messageType"` // const "boatStatus"
+	Boat           Boat   `json:"boat"`
+	ServiceState   int32  `json:"serviceState"`
+	PreviousDock   Dock   `json:"previousDock"`
+	CurrentDock    Dock   `json:"currentDock"`
+	NextDock       Dock   `json:"nextDock"`
+	SeatsAvailable int32  `json:"seatsAvailable"`
 }
 
 func NewBoatStatusAPIMessage(msgType string, boat Boat, serviceState int32,
-	previousDock, currentDock, nextDock Dock) BoatStatusAPIMessage {
+	previousDock, currentDock, nextDock Dock, seatsAvailable int32) BoatStatusAPIMessage {
 	return BoatStatusAPIMessage{
-		MessageType:  msgType,
-		Boat:         boat,
-		ServiceState: serviceState,
-		PreviousDock: previousDock,
-		CurrentDock:  currentDock,
-		NextDock:     nextDock,
+		MessageType:    msgType,
+		Boat:           boat,
+		ServiceState:   serviceState,
+		PreviousDock:   previousDock,
+		CurrentDock:    currentDock,
+		NextDock:       nextDock,
+		SeatsAvailable: seatsAvailable,
 	}
 }
 
@@ -341,7 +451,7 @@ func (ac *BoatStatusAPIMessage) GetMessageType() string {
 // BoatStatusAPIMessage between the MockLogic and the Adapter
 type BoatStatusMockLogicMessage struct {
 	APIMessage BoatStatusAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewBoatStatusMockLogicMessage(apiMsg BoatStatusAPIMessage,
@@ -356,11 +466,11 @@ func NewBoatStatusMockLogicMessage(apiMsg BoatStatusAPIMessage,
 
 // ArrivedAPIMessage contains the Arrived message transmitted to the client
 type ArrivedAPIMessage struct {
-	MessageType   string // const "Arrived"
-	ClientID      string
-	Boat          Boat
-	Dock          Dock
-	TransactionID string
+	MessageType   string `json:"messageType"` // const "arrived"
+	ClientID      string `json:"clientID"`
+	Boat          Boat   `json:"boat"`
+	Dock          Dock   `json:"dock"`
+	TransactionID string `json:"transactionID"`
 }
 
 func NewArrivedAPIMessage(msgType string, clientID string,
@@ -383,7 +493,7 @@ func (ac *ArrivedAPIMessage) GetMessageType() string {
 // ArrivedAPIMessage between the MockLogic and the Adapter
 type ArrivedMockLogicMessage struct {
 	APIMessage ArrivedAPIMessage
-	Client     ClientData
+	Client     ClientData `proto:"client_data"`
 }
 
 func NewArrivedMockLogicMessage(apiMsg ArrivedAPIMessage,
@@ -393,3 +503,359 @@ func NewArrivedMockLogicMessage(apiMsg ArrivedAPIMessage,
 		Client:     client,
 	}
 }
+
+// TripProgress messages
+
+// TripProgressAPIMessage contains the TripProgress message transmitted to the
+// client as the frames advance, with the updated estimated times that the boat
+// arrives at the docks of the trip. An ETA is zero if it is not known or the
+// boat has already arrived at the dock.
+type TripProgressAPIMessage struct {
+	MessageType    string    `json:"messageType"` // const "tripProgress"
+	ClientID       string    `json:"clientID"`
+	Boat           Boat      `json:"boat"`
+	TransactionID  string    `json:"transactionID"`
+	SourceETA      time.Time `json:"sourceETA"`
+	DestinationETA time.Time `json:"destinationETA"`
+}
+
+func NewTripProgressAPIMessage(msgType, clientID string, boat Boat, transactionID string,
+	sourceETA, destinationETA time.Time) TripProgressAPIMessage {
+	return TripProgressAPIMessage{
+		MessageType:    msgType,
+		ClientID:       clientID,
+		Boat:           boat,
+		TransactionID:  transactionID,
+		SourceETA:      sourceETA,
+		DestinationETA: destinationETA,
+	}
+}
+
+func (ac *TripProgressAPIMessage) GetMessageType() string {
+	return APIMessageTypeTripProgress
+}
+
+// TripProgressMockLogicMessage contains the TripProgressAPIMessage and the ClientData
+// for the client that will receive the message. This is used to transmit the
+// TripProgressAPIMessage between the MockLogic and the Adapter
+type TripProgressMockLogicMessage struct {
+	APIMessage TripProgressAPIMessage
+	Client     ClientData `proto:"client_data"`
+}
+
+func NewTripProgressMockLogicMessage(apiMsg TripProgressAPIMessage,
+	client ClientData) TripProgressMockLogicMessage {
+	return TripProgressMockLogicMessage{
+		APIMessage: apiMsg,
+		Client:     client,
+	}
+}
+
+// CancelTrip messages
+
+// CancelTripAPIMessage contains the CancelTrip message received from the client
+// to cancel a previously reserved trip
+type CancelTripAPIMessage struct {
+	MessageType   string `json:"messageType"` // const "cancelTrip"
+	ClientID      string `json:"clientID"`
+	TransactionID string `json:"transactionID"`
+}
+
+func NewCancelTripAPIMessage(msgType, clientID, transactionID string) CancelTripAPIMessage {
+	return CancelTripAPIMessage{
+		MessageType:   msgType,
+		ClientID:      clientID,
+		TransactionID: transactionID,
+	}
+}
+
+func (ac *CancelTripAPIMessage) GetMessageType() string {
+	return APIMessageTypeCancelTrip
+}
+
+// CancelTripMockLogicMessage contains the CancelTripAPIMessage and the ClientData for
+// the client that sent the message. This is used to transmit the
+// CancelTripAPIMessage between the Adapter and the MockLogic
+type CancelTripMockLogicMessage struct {
+	APIMessage CancelTripAPIMessage
+	Client     ClientData `proto:"client_data"`
+}
+
+func NewCancelTripMockLogicMessage(apiMsg CancelTripAPIMessage,
+	client ClientData) CancelTripMockLogicMessage {
+	return CancelTripMockLogicMessage{
+		APIMessage: apiMsg,
+		Client:     client,
+	}
+}
+
+// CancelAck messages
+
+// CancelAckAPIMessage contains the CancelAck message transmitted to the client
+// as a reply to the client CancelTrip message. When IsCancelled is false,
+// ReasonCode and Reason give the reason that the trip was not cancelled.
+type CancelAckAPIMessage struct {
+	MessageType   string `json:"messageType"` // const "cancelAck"
+	ClientID      string `json:"clientID"`
+	TransactionID string `json:"transactionID"`
+	IsCancelled   bool   `json:"isCancelled"`
+	ReasonCode    int32  `json:"reasonCode"`
+	Reason        string `json:"reason"`
+}
+
+func NewCancelAckAPIMessage(msgType, clientID, transactionID string, isCancelled bool,
+	reasonCode int32, reason string) CancelAckAPIMessage {
+	return CancelAckAPIMessage{
+		MessageType:   msgType,
+		ClientID:      clientID,
+		TransactionID: transactionID,
+		IsCancelled:   isCancelled,
+		ReasonCode:    reasonCode,
+		Reason:        reason,
+	}
+}
+
+func (ac *CancelAckAPIMessage) GetMessageType() string {
+	return APIMessageTypeCancelAck
+}
+
+// CancelAckMockLogicMessage contains the CancelAckAPIMessage and the ClientData for
+// the client that will receive the message. This is used to transmit the
+// CancelAckAPIMessage between the MockLogic and the Adapter
+type CancelAckMockLogicMessage struct {
+	APIMessage CancelAckAPIMessage
+	Client     ClientData `proto:"client_data"`
+}
+
+func NewCancelAckMockLogicMessage(apiMsg CancelAckAPIMessage,
+	client ClientData) CancelAckMockLogicMessage {
+	return CancelAckMockLogicMessage{
+		APIMessage: apiMsg,
+		Client:     client,
+	}
+}
+
+// GetTrip messages
+
+// GetTripAPIMessage contains the GetTrip message received from the client to
+// request the status of a previously reserved trip
+type GetTripAPIMessage struct {
+	MessageType   string `json:"messageType"` // const "getTrip"
+	ClientID      string `json:"clientID"`
+	TransactionID string `json:"transactionID"`
+}
+
+func NewGetTripAPIMessage(msgType, clientID, transactionID string) GetTripAPIMessage {
+	return GetTripAPIMessage{
+		MessageType:   msgType,
+		ClientID:      clientID,
+		TransactionID: transactionID,
+	}
+}
+
+func (ac *GetTripAPIMessage) GetMessageType() string {
+	return APIMessageTypeGetTrip
+}
+
+// GetTripMockLogicMessage contains the GetTripAPIMessage and the ClientData for
+// the client that sent the message. This is used to transmit the
+// GetTripAPIMessage between the Adapter and the MockLogic
+type GetTripMockLogicMessage struct {
+	APIMessage GetTripAPIMessage
+	Client     ClientData `proto:"client_data"`
+}
+
+func NewGetTripMockLogicMessage(apiMsg GetTripAPIMessage,
+	client ClientData) GetTripMockLogicMessage {
+	return GetTripMockLogicMessage{
+		APIMessage: apiMsg,
+		Client:     client,
+	}
+}
+
+// TripStatus messages
+
+// TripStatusAPIMessage contains the TripStatus message transmitted to the client
+// as a reply to the client GetTrip message. TripState is the name of the current
+// state of the trip and BoatStatus is the latest status of the boat assigned to
+// the trip. When IsFound is false, ReasonCode and Reason give the reason that the
+// trip could not be returned and the other trip fields are zero.
+type TripStatusAPIMessage struct {
+	MessageType     string               `json:"messageType"` // const "tripStatus"
+	ClientID        string               `json:"clientID"`
+	TransactionID   string               `json:"transactionID"`
+	IsFound         bool                 `json:"isFound"`
+	ReasonCode      int32                `json:"reasonCode"`
+	Reason          string               `json:"reason"`
+	TripState       string               `json:"tripState"`
+	SourceDock      Dock                 `json:"sourceDock"`
+	DestinationDock Dock                 `json:"destinationDock"`
+	Boat            Boat                 `json:"boat"`
+	BoatStatus      BoatStatusAPIMessage `json:"boatStatus"`
+	SourceETA       time.Time            `json:"sourceETA"`
+	DestinationETA  time.Time            `json:"destinationETA"`
+}
+
+func NewTripStatusAPIMessage(msgType, clientID, transactionID string, isFound bool,
+	reasonCode int32, reason, tripState string, sourceDock, destDock Dock, boat Boat,
+	boatStatus BoatStatusAPIMessage, sourceETA, destinationETA time.Time) TripStatusAPIMessage {
+	return TripStatusAPIMessage{
+		MessageType:     msgType,
+		ClientID:        clientID,
+		TransactionID:   transactionID,
+		IsFound:         isFound,
+		ReasonCode:      reasonCode,
+		Reason:          reason,
+		TripState:       tripState,
+		SourceDock:      sourceDock,
+		DestinationDock: destDock,
+		Boat:            boat,
+		BoatStatus:      boatStatus,
+		SourceETA:       sourceETA,
+		DestinationETA:  destinationETA,
+	}
+}
+
+func (ac *TripStatusAPIMessage) GetMessageType() string {
+	return APIMessageTypeTripStatus
+}
+
+// TripStatusMockLogicMessage contains the TripStatusAPIMessage and the ClientData for
+// the client that will receive the message. This is used to transmit the
+// TripStatusAPIMessage between the MockLogic and the Adapter
+type TripStatusMockLogicMessage struct {
+	APIMessage TripStatusAPIMessage
+	Client     ClientData `proto:"client_data"`
+}
+
+func NewTripStatusMockLogicMessage(apiMsg TripStatusAPIMessage,
+	client ClientData) TripStatusMockLogicMessage {
+	return TripStatusMockLogicMessage{
+		APIMessage: apiMsg,
+		Client:     client,
+	}
+}
+
+// Subscription messages
+
+// The subscription messages are handled by the WebSocketServer and are not
+// transmitted to the MockLogic, so there are no MockLogic messages for them.
+
+// SubscribeAPIMessage contains the Subscribe message received from the client to
+// receive the BoatStatus messages for the given boats, for the boats arriving at
+// or docked at the given docks, and, if MyTrips is true, for the boats of the trips
+// reserved on the client connection. A client that has never subscribed receives
+// the BoatStatus messages for every boat.
+type SubscribeAPIMessage struct {
+	MessageType string  `json:"messageType"` // const "subscribe"
+	BoatIDs     []int32 `json:"boatIDs"`
+	Docks       []Dock  `json:"docks"`
+	MyTrips     bool    `json:"myTrips"`
+}
+
+func NewSubscribeAPIMessage(msgType string, boatIDs []int32, docks []Dock,
+	myTrips bool) SubscribeAPIMessage {
+	return SubscribeAPIMessage{
+		MessageType: msgType,
+		BoatIDs:     boatIDs,
+		Docks:       docks,
+		MyTrips:     myTrips,
+	}
+}
+
+func (ac *SubscribeAPIMessage) GetMessageType() string {
+	return APIMessageTypeSubscribe
+}
+
+// UnsubscribeAPIMessage contains the Unsubscribe message received from the client
+// to stop receiving the BoatStatus messages for the given boats and docks, and, if
+// MyTrips is true, for the boats of the trips reserved on the client connection
+type UnsubscribeAPIMessage struct {
+	MessageType string  `json:"messageType"` // const "unsubscribe"
+	BoatIDs     []int32 `json:"boatIDs"`
+	Docks       []Dock  `json:"docks"`
+	MyTrips     bool    `json:"myTrips"`
+}
+
+func NewUnsubscribeAPIMessage(msgType string, boatIDs []int32, docks []Dock,
+	myTrips bool) UnsubscribeAPIMessage {
+	return UnsubscribeAPIMessage{
+		MessageType: msgType,
+		BoatIDs:     boatIDs,
+		Docks:       docks,
+		MyTrips:     myTrips,
+	}
+}
+
+func (ac *UnsubscribeAPIMessage) GetMessageType() string {
+	return APIMessageTypeUnsubscribe
+}
+
+// SubscriptionsAPIMessage contains the Subscriptions message transmitted to the
+// client as a reply to the client Subscribe and Unsubscribe messages, with the
+// subscriptions of the client connection after the message was applied
+type SubscriptionsAPIMessage struct {
+	MessageType string  `json:"messageType"` // const "subscriptions"
+	BoatIDs     []int32 `json:"boatIDs"`
+	Docks       []Dock  `json:"docks"`
+	MyTrips     bool    `json:"myTrips"`
+}
+
+func NewSubscriptionsAPIMessage(msgType string, boatIDs []int32, docks []Dock,
+	myTrips bool) SubscriptionsAPIMessage {
+	return SubscriptionsAPIMessage{
+		MessageType: msgType,
+		BoatIDs:     boatIDs,
+		Docks:       docks,
+		MyTrips:     myTrips,
+	}
+}
+
+func (ac *SubscriptionsAPIMessage) GetMessageType() string {
+	return APIMessageTypeSubscriptions
+}
+
+// Error messages
+
+// ErrorAPIMessage contains the Error message transmitted to the client when a
+// message sent by the client was invalid, unknown or rejected
+type ErrorAPIMessage struct {
+	MessageType         string `json:"messageType"` // const "error"
+	ClientID            string `json:"clientID"`
+	ReasonCode          int32  `json:"reasonCode"`
+	Reason              string `json:"reason"`
+	RejectedMessageType string `json:"rejectedMessageType"`
+	TransactionID       string `json:"transactionID"`
+}
+
+func NewErrorAPIMessage(msgType, clientID string, reasonCode int32, reason string,
+	rejectedMsgType, transactionID string) ErrorAPIMessage {
+	return ErrorAPIMessage{
+		MessageType:         msgType,
+		ClientID:            clientID,
+		ReasonCode:          reasonCode,
+		Reason:              reason,
+		RejectedMessageType: rejectedMsgType,
+		TransactionID:       transactionID,
+	}
+}
+
+func (ac *ErrorAPIMessage) GetMessageType() string {
+	return APIMessageTypeError
+}
+
+// ErrorMockLogicMessage contains the ErrorAPIMessage and the ClientData for
+// the client that will receive the message. This is used to transmit the
+// ErrorAPIMessage between the MockLogic and the Adapter
+type ErrorMockLogicMessage struct {
+	APIMessage ErrorAPIMessage
+	Client     ClientData `proto:"client_data"`
+}
+
+func NewErrorMockLogicMessage(apiMsg ErrorAPIMessage,
+	client ClientData) ErrorMockLogicMessage {
+	return ErrorMockLogicMessage{
+		APIMessage: apiMsg,
+		Client:     client,
+	}
+}
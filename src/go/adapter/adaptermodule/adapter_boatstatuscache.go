@@ -0,0 +1,91 @@
+package main
+
+import (
+	a "riden/adapter"
+	wss "riden/websocketserver"
+	"slices"
+	"sync"
+)
+
+// BoatStatusCache holds the latest BoatStatus message received from the MockLogic
+// for every boat, so that the current state of the boats can be sent to a client
+// as soon as it connects rather than at the next simulation frame. The gRPC
+// stream handler stores the statuses while the WebSocketServer read loop sends
+// them, so every method is safe for concurrent use. The lock is only held while
+// the statuses are read or stored, and never while a message waits for the
+// WebSocketServer write channel.
+type BoatStatusCache struct {
+	mux      sync.RWMutex
+	statuses map[int32][]byte
+}
+
+func NewBoatStatusCache() *BoatStatusCache {
+	return &BoatStatusCache{
+		statuses: make(map[int32][]byte),
+	}
+}
+
+// safeBoatStatusCache holds the latest BoatStatus message for every boat
+var safeBoatStatusCache = NewBoatStatusCache()
+
+// Store replaces the BoatStatus message for the given boat with the given
+// marshaled BoatStatusAPIMessage
+func (bsc *BoatStatusCache) Store(boatID int32, apiMsgBytes []byte) {
+	bsc.mux.Lock()
+	defer bsc.mux.Unlock()
+
+	bsc.statuses[boatID] = apiMsgBytes
+}
+
+// SendSnapshot sends the latest BoatStatus message for every boat, ordered by
+// BoatID, to the client with the given connection name. The statuses are read
+// when the write is made by the WebSocketServer writer rather than when it is
+// requested. Since a BoatStatus message is stored before it is broadcast, and the
+// next status is only stored once the broadcast was written, the snapshot holds
+// every status that was broadcast before it, so a client is never sent a cached
+// status after a newer one.
+func (bsc *BoatStatusCache) SendSnapshot(connName string) {
+	err := orderedWrite(func() error {
+		snapshot := bsc.snapshot()
+		Logger.Info().Msgf("Sending %d cached %s messages to ConnName: %s",
+			len(snapshot), a.APIMessageTypeBoatStatus, connName)
+
+		for _, apiMsgBytes := range snapshot {
+			err := writeToWebSocketServer(wss.NewAdapterMessage(connName, apiMsgBytes))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		Logger.Error().Msgf("Could not send the cached %s messages to ConnName: %s: %s",
+			a.APIMessageTypeBoatStatus, connName, err.Error())
+	}
+}
+
+// snapshot returns the marshaled BoatStatus messages for every boat, ordered
+// by BoatID
+func (bsc *BoatStatusCache) snapshot() [][]byte {
+	bsc.mux.RLock()
+	defer bsc.mux.RUnlock()
+
+	boatIDs := make([]int32, 0, len(bsc.statuses))
+	for boatID := range bsc.statuses {
+		boatIDs = append(boatIDs, boatID)
+	}
+	slices.Sort(boatIDs)
+
+	snapshot := make([][]byte, 0, len(boatIDs))
+	for _, boatID := range boatIDs {
+		snapshot = append(snapshot, bsc.statuses[boatID])
+	}
+
+	return snapshot
+}
+
+// SendSnapshotToClient sends the latest BoatStatus message for every boat to the
+// client with the given connection name
+func SendSnapshotToClient(connName string) {
+	safeBoatStatusCache.SendSnapshot(connName)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	a "riden/adapter"
+	pb "riden/proto"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// AdapterComponentName is the name of the Adapter in the handshake
+const AdapterComponentName string = "Adapter"
+
+// HandshakeState records the connection on which a compatible MockLogic has
+// completed the handshake, so that the streams are only served on that connection
+// and the connection to the WebSocketServer is only opened once the client traffic
+// can be handled. A connection is identified by the ID that it was given when it
+// was opened, so that a new connection from the same address does not inherit the
+// handshake of a closed one. Every method is safe for concurrent use.
+type HandshakeState struct {
+	mux sync.Mutex
+	// conn is the ID of the connection, or 0 if no connection has completed the
+	// handshake
+	conn uint64
+	once sync.Once
+	// ready is closed when the first handshake is completed
+	ready chan struct{}
+}
+
+func NewHandshakeState() *HandshakeState {
+	return &HandshakeState{
+		ready: make(chan struct{}),
+	}
+}
+
+// MockLogicHandshake records the handshake of the MockLogic
+var MockLogicHandshake = NewHandshakeState()
+
+// Complete records that a compatible MockLogic has completed the handshake on
+// the given connection, in place of any earlier connection
+func (hs *HandshakeState) Complete(conn uint64) {
+	hs.mux.Lock()
+	hs.conn = conn
+	hs.mux.Unlock()
+
+	hs.once.Do(func() {
+		close(hs.ready)
+	})
+}
+
+// Reset records that the given connection has closed, so that the MockLogic must
+// complete the handshake again before its streams are served
+func (hs *HandshakeState) Reset(conn uint64) {
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+
+	if hs.conn == conn {
+		hs.conn = 0
+	}
+}
+
+// IsComplete returns whether a compatible MockLogic has completed the handshake on
+// the given connection
+func (hs *HandshakeState) IsComplete(conn uint64) bool {
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+
+	return conn != 0 && hs.conn == conn
+}
+
+// Ready returns a channel that is closed once a compatible MockLogic has
+// completed the handshake
+func (hs *HandshakeState) Ready() <-chan struct{} {
+	return hs.ready
+}
+
+// CheckHello returns an error that gives the reason that the MockLogic that sent
+// the Hello is not compatible with the Adapter, or nil if it is compatible. The
+// MockLogic must speak the same protocol version and handle every message type
+// that the Adapter passes to it.
+func CheckHello(hello *pb.Hello) error {
+	if hello.GetProtocolVersion() != a.ProtocolVersion {
+		return fmt.Errorf("protocol version: %d is not supported, expected: %d",
+			hello.GetProtocolVersion(), a.ProtocolVersion)
+	}
+
+	var missing []string
+	for _, messageType := range a.ToMockLogicMessageTypes {
+		if !slices.Contains(hello.GetMessageTypes(), messageType) {
+			missing = append(missing, messageType)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("message types: %s, are not handled", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Handshake replies to the Hello of the MockLogic with a Welcome that accepts the
+// MockLogic if it is compatible with the Adapter
+func (s *adapterServer) Handshake(ctx context.Context, hello *pb.Hello) (*pb.Welcome, error) {
+	Logger.Info().Msgf("Received Hello from %s Version: %s Build Date: %s with protocol version: %d",
+		hello.GetComponent(), hello.GetVersionNumber(), hello.GetBuildDate(),
+		hello.GetProtocolVersion())
+
+	welcome := pb.Welcome{
+		Component:       AdapterComponentName,
+		VersionNumber:   a.VersionNumber,
+		BuildDate:       a.BuildDate,
+		ProtocolVersion: a.ProtocolVersion,
+		MessageTypes:    a.FromMockLogicMessageTypes,
+	}
+
+	err := CheckHello(hello)
+	if err != nil {
+		Logger.Warn().Msgf("Rejected the handshake of %s: %s", hello.GetComponent(), err.Error())
+		welcome.Reason = err.Error()
+		return &welcome, nil
+	}
+
+	conn := connectionID(ctx)
+	Logger.Info().Msgf("Accepted the handshake of %s on connection: %d from %s",
+		hello.GetComponent(), conn, connectionAddr(ctx))
+	welcome.IsAccepted = true
+	s.handshake.Complete(conn)
+
+	return &welcome, nil
+}
+
+// connectionID returns the ID of the connection that a request was received on,
+// or 0 if the connection was not given an ID
+func connectionID(ctx context.Context) uint64 {
+	conn, _ := ctx.Value(connIDKey{}).(uint64)
+
+	return conn
+}
+
+// connectionAddr returns the address of the MockLogic on the connection that a
+// request was received on, or an empty string if it is not known
+func connectionAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	return p.Addr.String()
+}
+
+// RequireHandshake is a grpc.StreamServerInterceptor that rejects the streams
+// opened on a connection on which a compatible MockLogic has not completed the
+// handshake
+func (hs *HandshakeState) RequireHandshake(srv any, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	conn := connectionID(ss.Context())
+	if !hs.IsComplete(conn) {
+		Logger.Warn().Msgf("Rejected %s stream on connection: %d from %s without a handshake",
+			info.FullMethod, conn, connectionAddr(ss.Context()))
+		return status.Error(codes.FailedPrecondition,
+			"the handshake must be completed on the connection before the streams are opened")
+	}
+
+	return handler(srv, ss)
+}
+
+// handshakeConnHandler is a stats.Handler that gives each connection an ID when
+// it is opened, and resets the handshake of the connection when it closes
+type handshakeConnHandler struct {
+	handshake *HandshakeState
+}
+
+// connIDKey is the context key of the ID of a connection
+type connIDKey struct{}
+
+// lastConnID is the ID that was given to the last connection that was opened
+var lastConnID atomic.Uint64
+
+func (h handshakeConnHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h handshakeConnHandler) HandleRPC(_ context.Context, _ stats.RPCStats) {}
+
+func (h handshakeConnHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connIDKey{}, lastConnID.Add(1))
+}
+
+func (h handshakeConnHandler) HandleConn(ctx context.Context, connStats stats.ConnStats) {
+	if _, ok := connStats.(*stats.ConnEnd); !ok {
+		return
+	}
+	conn := connectionID(ctx)
+	if conn == 0 {
+		return
+	}
+	Logger.Info().Msgf("Connection: %d of the MockLogic closed", conn)
+	h.handshake.Reset(conn)
+}
@@ -2,11 +2,24 @@ package main
 
 import (
 	"encoding/json"
+	"maps"
+	"math"
+	"math/rand"
 	"net/http"
+	"os"
+	"reflect"
+	a "riden/adapter"
 	wss "riden/websocketserver"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
 )
 
 type mockWebSocketReceiveHandler struct {
@@ -87,3 +100,297 @@ func (wsh mockWebSocketReserveSendHandler) ServeHTTP(w http.ResponseWriter, r *h
 	// Cleanup
 	c = nil
 }
+
+// nextOutboxMessage waits for a message of type T to be placed in the outbox and
+// returns the first message of that type that has not been acknowledged
+func nextOutboxMessage[T any](outbox *a.Outbox[any]) T {
+	for {
+		pushed := outbox.Notify()
+		for _, msg := range outbox.PendingAfter(0) {
+			if typedMsg, ok := msg.Message.(T); ok {
+				return typedMsg
+			}
+		}
+		<-pushed
+	}
+}
+
+// conversionRoundTrips is the number of random messages that each message type is
+// converted with in the round trip tests
+const conversionRoundTrips int = 200
+
+// randomAPIValue sets v to a random value. The strings, numbers and times are
+// never zero, and a slice is nil or holds up to three elements.
+func randomAPIValue(r *rand.Rand, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[time.Time]() {
+			v.Set(reflect.ValueOf(time.Unix(1+r.Int63n(4e9), r.Int63n(1e9)).UTC()))
+			return
+		}
+		for i := range v.NumField() {
+			randomAPIValue(r, v.Field(i))
+		}
+	case reflect.Slice:
+		n := r.Intn(4)
+		if n == 0 {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := range n {
+			randomAPIValue(r, v.Index(i))
+		}
+	case reflect.String:
+		v.SetString("s" + strconv.Itoa(r.Int()))
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int32, reflect.Int64:
+		v.SetInt(1 + r.Int63n(1000))
+	case reflect.Uint32, reflect.Uint64:
+		v.SetUint(1 + uint64(r.Int63n(1000)))
+	}
+}
+
+// randomGRPCMessage sets every field of m to a random value, apart from the epoch
+// and sequence, which are not held in the API structs. The strings, numbers and
+// times are never zero, and a repeated field holds one to three elements.
+func randomGRPCMessage(r *rand.Rand, m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.Name() == "epoch" || fd.Name() == "sequence" {
+			continue
+		}
+
+		if fd.IsList() {
+			list := m.Mutable(fd).List()
+			for range 1 + r.Intn(3) {
+				if fd.Message() != nil {
+					elem := list.NewElement()
+					randomGRPCMessage(r, elem.Message())
+					list.Append(elem)
+				} else {
+					list.Append(randomGRPCScalar(r, fd))
+				}
+			}
+			continue
+		}
+
+		switch {
+		case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Timestamp":
+			timestamp := m.Mutable(fd).Message()
+			timestamp.Set(fd.Message().Fields().ByName("seconds"),
+				protoreflect.ValueOfInt64(1+r.Int63n(4e9)))
+			timestamp.Set(fd.Message().Fields().ByName("nanos"),
+				protoreflect.ValueOfInt32(r.Int31n(1e9)))
+		case fd.Message() != nil:
+			randomGRPCMessage(r, m.Mutable(fd).Message())
+		default:
+			m.Set(fd, randomGRPCScalar(r, fd))
+		}
+	}
+}
+
+func randomGRPCScalar(r *rand.Rand, fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(1 + r.Int31n(9)))
+	case protoreflect.Int32Kind:
+		return protoreflect.ValueOfInt32(1 + r.Int31n(1000))
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	default:
+		return protoreflect.ValueOfString("s" + strconv.Itoa(r.Int()))
+	}
+}
+
+// checkConversionRoundTrip checks that random API structs of type T are the same
+// after they are converted to gRPC messages of type M and back, and that random
+// gRPC messages of type M are the same after they are converted to API structs of
+// type T and back. It also checks that the epoch and sequence are set on the
+// messages sent from an outbox, and that a missing message results in the zero
+// struct.
+func checkConversionRoundTrip[T any, M proto.Message](t *testing.T, r *rand.Rand, name string) {
+	for range conversionRoundTrips {
+		var apiMsg T
+		randomAPIValue(r, reflect.ValueOf(&apiMsg).Elem())
+		grpcMsg, err := a.ToGRPC[M](apiMsg)
+		if err != nil {
+			t.Fatalf("Expected no error converting %+v to gRPC but received %v in test case: %s",
+				apiMsg, err, name)
+		}
+		roundTrip, err := a.FromGRPC[T](grpcMsg)
+		if err != nil {
+			t.Fatalf("Expected no error converting %v from gRPC but received %v in test case: %s",
+				grpcMsg, err, name)
+		}
+		if !reflect.DeepEqual(roundTrip, apiMsg) {
+			t.Fatalf("Expected %+v after the round trip but received %+v in test case: %s",
+				apiMsg, roundTrip, name)
+		}
+
+		var emptyGRPCMsg M
+		grpcMsg = emptyGRPCMsg.ProtoReflect().Type().New().Interface().(M)
+		randomGRPCMessage(r, grpcMsg.ProtoReflect())
+		apiMsg, err = a.FromGRPC[T](grpcMsg)
+		if err != nil {
+			t.Fatalf("Expected no error converting %v from gRPC but received %v in test case: %s",
+				grpcMsg, err, name)
+		}
+		grpcRoundTrip, err := a.ToGRPC[M](apiMsg)
+		if err != nil {
+			t.Fatalf("Expected no error converting %+v to gRPC but received %v in test case: %s",
+				apiMsg, err, name)
+		}
+		if !proto.Equal(grpcRoundTrip, grpcMsg) {
+			t.Fatalf("Expected %v after the round trip but received %v in test case: %s",
+				grpcMsg, grpcRoundTrip, name)
+		}
+
+		sequencedMsg, err := a.SequencedToGRPC[M](a.SequencedMessage[any]{Epoch: 7, Sequence: 3, Message: apiMsg})
+		if err != nil {
+			t.Fatalf("Expected no error converting a SequencedMessage but received %v in test case: %s",
+				err, name)
+		}
+		sequenced := any(sequencedMsg).(sequencedGRPCMessage)
+		if sequenced.GetEpoch() != 7 || sequenced.GetSequence() != 3 {
+			t.Fatalf("Expected epoch 7 and sequence 3 but received %v in test case: %s",
+				sequencedMsg, name)
+		}
+	}
+
+	var zero T
+	var nilGRPCMsg M
+	emptyGRPCMsg := nilGRPCMsg.ProtoReflect().Type().New().Interface()
+	for _, grpcMsg := range []proto.Message{nilGRPCMsg, emptyGRPCMsg} {
+		apiMsg, err := a.FromGRPC[T](grpcMsg)
+		if err != nil {
+			t.Fatalf("Expected no error converting %v from gRPC but received %v in test case: %s",
+				grpcMsg, err, name)
+		}
+		if !reflect.DeepEqual(apiMsg, zero) {
+			t.Fatalf("Expected the zero %T but received %+v in test case: %s", zero, apiMsg, name)
+		}
+	}
+}
+
+// apiSpecFile is the AsyncAPI spec that the wire format of the API messages is
+// checked against
+const apiSpecFile string = "../../../../docs/api/asynapi.yaml"
+
+// loadAPISpec returns the AsyncAPI spec decoded into maps
+func loadAPISpec(t *testing.T) map[string]any {
+	specBytes, err := os.ReadFile(apiSpecFile)
+	if err != nil {
+		t.Fatalf("Error reading the AsyncAPI spec: %s", err.Error())
+	}
+	var spec map[string]any
+	err = yaml.Unmarshal(specBytes, &spec)
+	if err != nil {
+		t.Fatalf("Error unmarshaling the AsyncAPI spec: %s", err.Error())
+	}
+
+	return spec
+}
+
+// specObject returns the object at the given path of keys in the spec
+func specObject(t *testing.T, spec map[string]any, path ...string) map[string]any {
+	obj := spec
+	for _, key := range path {
+		next, ok := obj[key].(map[string]any)
+		if !ok {
+			t.Fatalf("The AsyncAPI spec has no object at %s", strings.Join(path, "/"))
+		}
+		obj = next
+	}
+
+	return obj
+}
+
+// checkSpecValue checks that the value decoded from the JSON of an API message
+// has the keys and the types of the given schema of the spec. The enums and
+// consts are not checked, since the values of the messages are random.
+func checkSpecValue(t *testing.T, spec, schema map[string]any, value any, path, name string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = specObject(t, spec, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...)
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			t.Fatalf("Expected an object at %s but received %v in test case: %s", path, value, name)
+		}
+		properties := specObject(t, schema, "properties")
+		keys := slices.Sorted(maps.Keys(obj))
+		specKeys := slices.Sorted(maps.Keys(properties))
+		if !slices.Equal(keys, specKeys) {
+			t.Fatalf("Expected the keys %v at %s but received %v in test case: %s",
+				specKeys, path, keys, name)
+		}
+		for key, property := range properties {
+			checkSpecValue(t, spec, property.(map[string]any), obj[key], path+"."+key, name)
+		}
+
+	case "array":
+		// An empty slice is sent as null
+		if value == nil {
+			return
+		}
+		elems, ok := value.([]any)
+		if !ok {
+			t.Fatalf("Expected an array at %s but received %v in test case: %s", path, value, name)
+		}
+		for i, elem := range elems {
+			checkSpecValue(t, spec, specObject(t, schema, "items"), elem,
+				path+"["+strconv.Itoa(i)+"]", name)
+		}
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			t.Fatalf("Expected a string at %s but received %v in test case: %s", path, value, name)
+		}
+		if schema["format"] == "date-time" {
+			_, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				t.Fatalf("Expected a date-time at %s but received %s in test case: %s", path, s, name)
+			}
+		}
+
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			t.Fatalf("Expected an integer at %s but received %v in test case: %s", path, value, name)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Fatalf("Expected a boolean at %s but received %v in test case: %s", path, value, name)
+		}
+
+	default:
+		t.Fatalf("The AsyncAPI spec has no type at %s in test case: %s", path, name)
+	}
+}
+
+// toLegacyKeys returns the value decoded from the JSON of an API message with
+// the keys of every object changed to the deprecated PascalCase keys
+func toLegacyKeys(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		legacy := make(map[string]any, len(value))
+		for key, elem := range value {
+			legacy[strings.ToUpper(key[:1])+key[1:]] = toLegacyKeys(elem)
+		}
+		return legacy
+	case []any:
+		legacy := make([]any, len(value))
+		for i, elem := range value {
+			legacy[i] = toLegacyKeys(elem)
+		}
+		return legacy
+	default:
+		return value
+	}
+}
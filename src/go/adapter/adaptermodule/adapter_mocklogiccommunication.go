@@ -2,410 +2,398 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	a "riden/adapter"
 	pb "riden/proto"
 	wss "riden/websocketserver"
-)
+	"sync"
 
-// GRPCChannels holds the channels that will be used to pass messages to the
-// MockLogic, using the gRPC bi-directional streams
-type GRPCChannels struct {
-	ReserveTripChannel chan a.ReserveTripMockLogicMessage
-	AtDockChannel      chan a.AtDockMockLogicMessage
-	OnBoatChannel      chan a.OnBoatMockLogicMessage
-	OffBoatChannel     chan a.OffBoatMockLogicMessage
-}
+	"google.golang.org/protobuf/proto"
+)
 
-func (grpcc *GRPCChannels) MakeReserveTrip() {
-	grpcc.ReserveTripChannel = make(chan a.ReserveTripMockLogicMessage, GRPCChannelBufferSize)
+// GRPCOutboxCapacity is the number of messages that are held for the MockLogic
+// before the clients are told that it is unavailable
+const GRPCOutboxCapacity int = 1024
+
+// GRPCOutbox holds the messages that are sent to the MockLogic, in the order that
+// the clients sent them, as ReserveTripMockLogicMessage, AtDockMockLogicMessage,
+// OnBoatMockLogicMessage, OffBoatMockLogicMessage, CancelTripMockLogicMessage and
+// GetTripMockLogicMessage values. A message stays in the outbox until the
+// MockLogic sends a Receipt for it, so the messages that were lost when a stream
+// dropped are sent again when the MockLogic reconnects.
+var GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
+
+// MockLogicInbox holds the sequences of the messages received from the MockLogic,
+// so that the messages that it sends again after reconnecting are only processed
+// once
+var MockLogicInbox = a.NewInbox()
+
+// adapterServer is used to implement adapter.AdapterServer. The handshake records
+// the connection on which the streams are served.
+type adapterServer struct {
+	pb.UnimplementedAdapterServer
+	handshake *HandshakeState
 }
 
-func (grpcc *GRPCChannels) CloseReserveTrip() {
-	close(grpcc.ReserveTripChannel)
+// toMockLogicDecoders decodes the messages that are sent to the MockLogic and
+// places them in the GRPCOutbox, keyed by message type
+var toMockLogicDecoders = map[string]func(*MockLogicMessage){
+	a.APIMessageTypeReserveTrip: func(mlMsg *MockLogicMessage) {
+		decodeAndPush(mlMsg, a.NewReserveTripMockLogicMessage)
+	},
+	a.APIMessageTypeAtDock: func(mlMsg *MockLogicMessage) {
+		decodeAndPush(mlMsg, a.NewAtDockMockLogicMessage)
+	},
+	a.APIMessageTypeOnBoat: func(mlMsg *MockLogicMessage) {
+		decodeAndPush(mlMsg, a.NewOnBoatMockLogicMessage)
+	},
+	a.APIMessageTypeOffBoat: func(mlMsg *MockLogicMessage) {
+		decodeAndPush(mlMsg, a.NewOffBoatMockLogicMessage)
+	},
+	a.APIMessageTypeCancelTrip: func(mlMsg *MockLogicMessage) {
+		decodeAndPush(mlMsg, a.NewCancelTripMockLogicMessage)
+	},
+	a.APIMessageTypeGetTrip: func(mlMsg *MockLogicMessage) {
+		decodeAndPush(mlMsg, a.NewGetTripMockLogicMessage)
+	},
 }
 
-func (grpcc *GRPCChannels) MakeAtDock() {
-	grpcc.AtDockChannel = make(chan a.AtDockMockLogicMessage, GRPCChannelBufferSize)
-}
+// ProcessMessageToMockLogic processes a message that is being sent to
+// the MockLogic
+func ProcessMessageToMockLogic(mlMsg *MockLogicMessage) {
+	decode, ok := toMockLogicDecoders[mlMsg.MessageType]
+	if !ok {
+		Logger.Warn().Msgf("Received unknown message type, %s, from ConnName: %s, ConnType: %s",
+			mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
+		SendErrorToClient(mlMsg, a.ErrorReasonUnknownMessageType,
+			fmt.Sprintf("message type: %q, is not supported", mlMsg.MessageType))
+		return
+	}
 
-func (grpcc *GRPCChannels) CloseAtDock() {
-	close(grpcc.AtDockChannel)
+	Logger.Info().Msgf("Processing %s message to MockLogic from ConnName: %s, ConnType: %s",
+		mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
+	decode(mlMsg)
 }
 
-func (grpcc *GRPCChannels) MakeOnBoat() {
-	grpcc.OnBoatChannel = make(chan a.OnBoatMockLogicMessage, GRPCChannelBufferSize)
-}
+// decodeAndPush decodes the API message of type T from a message that is being
+// sent to the MockLogic, wraps it with the client data using newMsg, and places
+// it in the GRPCOutbox. The client is sent an Error if the message cannot be
+// decoded.
+func decodeAndPush[T any, M any](mlMsg *MockLogicMessage, newMsg func(T, a.ClientData) M) {
+	var apiMsg T
+	err := json.Unmarshal(mlMsg.APIMessageBytes, &apiMsg)
+	if err != nil {
+		Logger.Warn().Msgf("Error unmarshaling %s message: %s",
+			mlMsg.MessageType, err.Error())
+		SendErrorToClient(mlMsg, a.ErrorReasonInvalidMessage,
+			"message could not be decoded: "+err.Error())
+		return
+	}
+	clientData := a.NewClientData(mlMsg.ConnName, mlMsg.ConnType)
 
-func (grpcc *GRPCChannels) CloseOnBoat() {
-	close(grpcc.OnBoatChannel)
+	pushToMockLogic(mlMsg, newMsg(apiMsg, clientData))
 }
 
-func (grpcc *GRPCChannels) MakeOffBoat() {
-	grpcc.OffBoatChannel = make(chan a.OffBoatMockLogicMessage, GRPCChannelBufferSize)
+// pushToMockLogic places a message for the MockLogic in the GRPCOutbox. The client
+// that sent the message is told that the MockLogic is unavailable if the outbox
+// is full.
+func pushToMockLogic(mlMsg *MockLogicMessage, msg any) {
+	_, err := GRPCOutbox.Push(msg)
+	if err != nil {
+		Logger.Error().Msgf("could not place %s message in GRPCOutbox: %+v: %s",
+			mlMsg.MessageType, msg, err.Error())
+		SendErrorToClient(mlMsg, a.ErrorReasonUnavailable,
+			"message could not be delivered to the trip service")
+	}
 }
 
-func (grpcc *GRPCChannels) CloseOffBoat() {
-	close(grpcc.OffBoatChannel)
+// rejectedMessageFields is used with json.Unmarshal() to decode the fields of
+// a rejected message that identify the client and the trip
+type rejectedMessageFields struct {
+	ClientID      string `json:"clientID"`
+	TransactionID string `json:"transactionID"`
 }
 
-const GRPCChannelBufferSize int = 32
-
-var GRPCChans GRPCChannels
+// SendErrorToClient sends an Error message from the Adapter to the client that
+// sent the given message, when the message could not be processed. The ClientID
+// and TransactionID are copied from the message if they can be decoded.
+func SendErrorToClient(mlMsg *MockLogicMessage, reasonCode int32, reason string) {
+	var fields rejectedMessageFields
+	// Ignore the error since the message may not be valid JSON
+	_ = json.Unmarshal(mlMsg.APIMessageBytes, &fields)
+
+	errorAPIMsg := a.NewErrorAPIMessage(a.APIMessageTypeError, fields.ClientID, reasonCode,
+		reason, mlMsg.MessageType, fields.TransactionID)
+	apiMsgBytes, err := json.Marshal(errorAPIMsg)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling %s message for ConnName: %s: %s",
+			a.APIMessageTypeError, mlMsg.ConnName, err.Error())
+		return
+	}
+	errorMsg := NewMockLogicMessage(mlMsg.ConnName, mlMsg.ConnType,
+		a.APIMessageTypeError, apiMsgBytes)
 
-// adapterServer is used to implement adapter.AdapterServer
-type adapterServer struct {
-	pb.UnimplementedAdapterServer
+	ProcessMessageFromMockLogic(&errorMsg)
 }
 
-// ProcessMessageToMockLogic processes a message that is being sent to
-// the MockLogic
-func ProcessMessageToMockLogic(mlMsg *MockLogicMessage) {
-	switch mlMsg.MessageType {
-	case a.APIMessageTypeReserveTrip:
-		Logger.Info().Msgf("Processing %s message to MockLogic from ConnName: %s, ConnType: %s",
-			mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
-		clientData := a.NewClientData(mlMsg.ConnName, mlMsg.ConnType)
-		var apiMsg a.ReserveTripAPIMessage
-		err := json.Unmarshal(mlMsg.APIMessageBytes, &apiMsg)
-		if err != nil {
-			Logger.Debug().Msgf("Error unmarshaling %s message: %s",
-				mlMsg.MessageType, err.Error())
+// receiveReceipts receives the stream of Receipt messages from the MockLogic and
+// removes the messages that they acknowledge from the outbox. The streamDone
+// channel is closed when the stream ends.
+func receiveReceipts[T any](recv func() (*pb.Receipt, error), outbox *a.Outbox[T],
+	streamDone chan struct{}) {
+	defer close(streamDone)
+	for {
+		receipt, err := recv()
+		if err == io.EOF {
+			// read done.
 			return
 		}
-		reserveTripMockLogicMsg := a.NewReserveTripMockLogicMessage(apiMsg, clientData)
-
-		select {
-		case GRPCChans.ReserveTripChannel <- reserveTripMockLogicMsg:
-		default:
-			Logger.Error().Msgf("could not place messaage on ReserveTripChannel: %+v", reserveTripMockLogicMsg)
-		}
-
-	case a.APIMessageTypeAtDock:
-		Logger.Info().Msgf("Processing %s message to MockLogic from ConnName: %s, ConnType: %s",
-			mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
-		clientData := a.NewClientData(mlMsg.ConnName, mlMsg.ConnType)
-		var apiMsg a.AtDockAPIMessage
-		err := json.Unmarshal(mlMsg.APIMessageBytes, &apiMsg)
 		if err != nil {
-			Logger.Debug().Msgf("Error unmarshaling %s message: %s",
-				mlMsg.MessageType, err.Error())
+			Logger.Debug().Msgf("Failed to receive a Receipt message: %s", err.Error())
 			return
 		}
+		outbox.Acknowledge(receipt.GetEpoch(), receipt.GetSequence())
+	}
+}
+
+// Session handles sending and receiving the bi-directional stream that carries
+// every message between the Adapter and the MockLogic
+func (s *adapterServer) Session(stream pb.Adapter_SessionServer) error {
+	// The messages from the outbox and the Receipts are sent from different
+	// goroutines, and a stream must not be sent on concurrently
+	var sendMux sync.Mutex
+	send := func(envelope *pb.AdapterEnvelope) error {
+		sendMux.Lock()
+		defer sendMux.Unlock()
+		return stream.Send(envelope)
+	}
 
-		atDockMockLogicMessage := a.NewAtDockMockLogicMessage(apiMsg, clientData)
+	streamDone := make(chan struct{})
+	// Launch a goroutine to receive the stream of MockLogicEnvelope messages
+	go receiveSession(stream.Recv, send, streamDone)
 
-		select {
-		case GRPCChans.AtDockChannel <- atDockMockLogicMessage:
-		default:
-			Logger.Error().Msgf("could not place messaage on AtDockChannel: %+v", atDockMockLogicMessage)
+	// Send the messages that have not been acknowledged, and then each message
+	// placed in the outbox, to the MockLogic
+	return a.SendPending(GRPCOutbox, streamDone, func(msg a.SequencedMessage[any]) error {
+		envelope, err := adapterEnvelopeToGRPC(msg)
+		if err != nil {
+			Logger.Error().Msgf("Failed to convert a message in the GRPCOutbox: %s", err.Error())
+			return nil
 		}
 
-	case a.APIMessageTypeOnBoat:
-		Logger.Info().Msgf("Processing %s message to MockLogic from ConnName: %s, ConnType: %s",
-			mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
-		clientData := a.NewClientData(mlMsg.ConnName, mlMsg.ConnType)
-		var apiMsg a.OnBoatAPIMessage
-		err := json.Unmarshal(mlMsg.APIMessageBytes, &apiMsg)
+		// Send message to MockLogic
+		err = send(envelope)
 		if err != nil {
-			Logger.Debug().Msgf("Error unmarshaling %s message: %s",
-				mlMsg.MessageType, err.Error())
-			return
+			Logger.Debug().Msgf("Failed to send an AdapterEnvelope: %s", err.Error())
 		}
+		return err
+	})
+}
 
-		onBoatMockLogicMessage := a.NewOnBoatMockLogicMessage(apiMsg, clientData)
+// receiveSession receives the stream of MockLogicEnvelope messages on the Session
+// stream. The Receipts remove the messages that they acknowledge from the
+// GRPCOutbox, and the other messages are delivered to the clients. The streamDone
+// channel is closed when the stream ends, which happens as well when a message
+// could not be delivered, so that the MockLogic sends it again when it reconnects.
+func receiveSession(recv func() (*pb.MockLogicEnvelope, error),
+	send func(*pb.AdapterEnvelope) error, streamDone chan struct{}) {
+	defer close(streamDone)
+	sendReceipt := func(receipt *pb.Receipt) error {
+		return send(&pb.AdapterEnvelope{
+			Message: &pb.AdapterEnvelope_Receipt{Receipt: receipt},
+		})
+	}
 
-		select {
-		case GRPCChans.OnBoatChannel <- onBoatMockLogicMessage:
-		default:
-			Logger.Error().Msgf("could not place messaage on OnBoatChannel: %+v", onBoatMockLogicMessage)
+	for {
+		in, err := recv()
+		if err == io.EOF {
+			// read done.
+			return
 		}
-
-	case a.APIMessageTypeOffBoat:
-		Logger.Info().Msgf("Processing %s message to MockLogic from ConnName: %s, ConnType: %s",
-			mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
-		clientData := a.NewClientData(mlMsg.ConnName, mlMsg.ConnType)
-		var apiMsg a.OffBoatAPIMessage
-		err := json.Unmarshal(mlMsg.APIMessageBytes, &apiMsg)
 		if err != nil {
-			Logger.Debug().Msgf("Error unmarshaling %s message: %s",
-				mlMsg.MessageType, err.Error())
+			Logger.Debug().Msgf("Failed to receive a MockLogicEnvelope message: %s", err.Error())
 			return
 		}
 
-		offBoatMockLogicMessage := a.NewOffBoatMockLogicMessage(apiMsg, clientData)
+		if receipt := in.GetReceipt(); receipt != nil {
+			GRPCOutbox.Acknowledge(receipt.GetEpoch(), receipt.GetSequence())
+			continue
+		}
 
-		select {
-		case GRPCChans.OffBoatChannel <- offBoatMockLogicMessage:
-		default:
-			Logger.Error().Msgf("could not place messaage on OffBoatChannel: %+v", offBoatMockLogicMessage)
+		err = acceptFromMockLogic(sendReceipt, a.SessionStreamName, in.GetEpoch(),
+			in.GetSequence(), func() error { return deliverEnvelope(in) })
+		if err != nil {
+			Logger.Debug().Msgf("Failed to deliver a MockLogicEnvelope message: %s", err.Error())
+			return
 		}
+	}
+}
 
+// deliverEnvelope delivers the message in a MockLogicEnvelope to the client
+func deliverEnvelope(in *pb.MockLogicEnvelope) error {
+	switch msg := in.GetMessage().(type) {
+	case *pb.MockLogicEnvelope_Ack:
+		return deliverAck(msg.Ack)
+	case *pb.MockLogicEnvelope_CancelAck:
+		return deliverCancelAck(msg.CancelAck)
+	case *pb.MockLogicEnvelope_TripStatus:
+		return deliverTripStatus(msg.TripStatus)
+	case *pb.MockLogicEnvelope_BoatStatus:
+		return deliverBoatStatus(msg.BoatStatus)
+	case *pb.MockLogicEnvelope_Arrived:
+		return deliverArrived(msg.Arrived)
+	case *pb.MockLogicEnvelope_TripProgress:
+		return deliverTripProgress(msg.TripProgress)
+	case *pb.MockLogicEnvelope_Error:
+		return deliverError(msg.Error)
 	default:
-		Logger.Warn().Msgf("Received unknown message type, %s, from ConnName: %s, ConnType: %s",
-			mlMsg.MessageType, mlMsg.ConnName, mlMsg.ConnType)
-
+		Logger.Warn().Msgf("Received a MockLogicEnvelope without a message with sequence: %d",
+			in.GetSequence())
+		return nil
 	}
 }
 
-// Reserve handles sending and receiving the bi-directional stream for ReserveMessage
-func (s *adapterServer) ReserveTrip(stream pb.Adapter_ReserveTripServer) error {
+// adapterEnvelopeToGRPC converts a message in the GRPCOutbox to a pb.AdapterEnvelope
+// with the epoch and sequence that it is sent with
+func adapterEnvelopeToGRPC(msg a.SequencedMessage[any]) (*pb.AdapterEnvelope, error) {
+	envelope := pb.AdapterEnvelope{
+		Epoch:    msg.Epoch,
+		Sequence: msg.Sequence,
+	}
 	var err error
+	switch m := msg.Message.(type) {
+	case a.ReserveTripMockLogicMessage:
+		reserveTrip := &pb.AdapterEnvelope_ReserveTrip{}
+		reserveTrip.ReserveTrip, err = a.ToGRPC[*pb.ReserveTripMessage](m)
+		envelope.Message = reserveTrip
+	case a.AtDockMockLogicMessage:
+		atDock := &pb.AdapterEnvelope_AtDock{}
+		atDock.AtDock, err = a.ToGRPC[*pb.AtDockMessage](m)
+		envelope.Message = atDock
+	case a.OnBoatMockLogicMessage:
+		onBoat := &pb.AdapterEnvelope_OnBoat{}
+		onBoat.OnBoat, err = a.ToGRPC[*pb.OnBoatMessage](m)
+		envelope.Message = onBoat
+	case a.OffBoatMockLogicMessage:
+		offBoat := &pb.AdapterEnvelope_OffBoat{}
+		offBoat.OffBoat, err = a.ToGRPC[*pb.OffBoatMessage](m)
+		envelope.Message = offBoat
+	case a.CancelTripMockLogicMessage:
+		cancelTrip := &pb.AdapterEnvelope_CancelTrip{}
+		cancelTrip.CancelTrip, err = a.ToGRPC[*pb.CancelTripMessage](m)
+		envelope.Message = cancelTrip
+	case a.GetTripMockLogicMessage:
+		getTrip := &pb.AdapterEnvelope_GetTrip{}
+		getTrip.GetTrip, err = a.ToGRPC[*pb.GetTripMessage](m)
+		envelope.Message = getTrip
+	default:
+		err = fmt.Errorf("unexpected message type: %T", msg.Message)
+	}
 
+	return &envelope, err
+}
+
+// sendToMockLogic sends the messages of type T in the GRPCOutbox to the MockLogic
+// as gRPC messages of type M with the given send function, on a stream that
+// carries only that type of message, and receives the Receipts for them with the
+// given recv function. The messages of the other types are sent on their own
+// streams.
+func sendToMockLogic[T any, M proto.Message](recv func() (*pb.Receipt, error),
+	send func(M) error) error {
 	streamDone := make(chan struct{})
-	// Launch a goroutine to receive the stream of Empty messages
-	// These are not expected to be received from the gRPC client and
-	// can be discarded.
-	go func() {
-		for {
-			_, err := stream.Recv()
-			if err == io.EOF {
-				// read done.
-				streamDone <- struct{}{}
-				return
-			}
-			if err != nil {
-				Logger.Debug().Msgf("Failed to receive an Empty message: %s", err.Error())
-				streamDone <- struct{}{}
-				return
-			}
+	// Launch a goroutine to receive the stream of Receipt messages
+	go receiveReceipts(recv, GRPCOutbox, streamDone)
+
+	// Send the messages that have not been acknowledged, and then each message
+	// placed in the outbox, to the MockLogic
+	return a.SendPending(GRPCOutbox, streamDone, func(msg a.SequencedMessage[any]) error {
+		if _, ok := msg.Message.(T); !ok {
+			return nil
+		}
+		grpcMsg, err := a.SequencedToGRPC[M](msg)
+		if err != nil {
+			Logger.Error().Msgf("Failed to convert a message in the GRPCOutbox: %s", err.Error())
+			return nil
 		}
-	}()
 
-	// Wait for messages to appear on the channel and send to the MockLogic
-	for {
-		select {
-		case reserveMockLogicMsg := <-GRPCChans.ReserveTripChannel:
-			// Create gRPC ReserveMessage
-			apiMessage := pb.ReserveTripAPIMessage{
-				MessageType: reserveMockLogicMsg.APIMessage.MessageType,
-				AuthToken:   reserveMockLogicMsg.APIMessage.AuthToken,
-				ClientId:    reserveMockLogicMsg.APIMessage.ClientID,
-				SourceDock: &pb.Dock{
-					Address: &pb.Address{
-						Number: reserveMockLogicMsg.APIMessage.SourceDock.Address.Number,
-						Street: reserveMockLogicMsg.APIMessage.SourceDock.Address.Street,
-					},
-					Gangway: reserveMockLogicMsg.APIMessage.SourceDock.Gangway,
-				},
-				DestinationDock: &pb.Dock{
-					Address: &pb.Address{
-						Number: reserveMockLogicMsg.APIMessage.DestinationDock.Address.Number,
-						Street: reserveMockLogicMsg.APIMessage.DestinationDock.Address.Street,
-					},
-					Gangway: reserveMockLogicMsg.APIMessage.DestinationDock.Gangway,
-				},
-			}
-			clientData := pb.ClientData{
-				ConnName: reserveMockLogicMsg.Client.ConnName,
-				ConnType: reserveMockLogicMsg.Client.ConnType,
-			}
-			reserveTripMsg := pb.ReserveTripMessage{
-				ApiMessage: &apiMessage,
-				ClientData: &clientData,
-			}
-
-			// Send message to MockLogic
-			if err = stream.Send(&reserveTripMsg); err != nil {
-				Logger.Debug().Msgf("Failed to send a ReserveMessage: %s", err.Error())
-				return err
-			}
-
-		case <-streamDone:
-			return err
+		// Send message to MockLogic
+		err = send(grpcMsg)
+		if err != nil {
+			Logger.Debug().Msgf("Failed to send a %s: %s",
+				grpcMsg.ProtoReflect().Descriptor().Name(), err.Error())
 		}
-	}
+		return err
+	})
+}
+
+// ReserveTrip handles sending and receiving the bi-directional stream for ReserveTripMessage
+func (s *adapterServer) ReserveTrip(stream pb.Adapter_ReserveTripServer) error {
+	return sendToMockLogic[a.ReserveTripMockLogicMessage](stream.Recv, stream.Send)
 }
 
 // AtDock handles sending and receiving the bi-directional stream for AtDockMessage
 func (s *adapterServer) AtDock(stream pb.Adapter_AtDockServer) error {
-	var err error
-
-	streamDone := make(chan struct{})
-	// Launch a goroutine to receive the stream of Empty messages
-	// These are not expected to be received from the gRPC client and
-	// can be discarded.
-	go func() {
-		for {
-			_, err := stream.Recv()
-			if err == io.EOF {
-				// read done.
-				streamDone <- struct{}{}
-				return
-			}
-			if err != nil {
-				Logger.Debug().Msgf("Failed to receive an Empty message : %v", err)
-				streamDone <- struct{}{}
-				return
-			}
-		}
-	}()
-
-	for {
-		select {
-		case atDockMockLogicMessage := <-GRPCChans.AtDockChannel:
-			// Create gRPC AtDockMessage
-			apiMessage := pb.AtDockAPIMessage{
-				MessageType: atDockMockLogicMessage.APIMessage.MessageType,
-				ClientId:    atDockMockLogicMessage.APIMessage.ClientID,
-				Boat: &pb.Boat{
-					BoatId: atDockMockLogicMessage.APIMessage.Boat.BoatID,
-					Name:   atDockMockLogicMessage.APIMessage.Boat.Name,
-				},
-				Dock: &pb.Dock{
-					Address: &pb.Address{
-						Number: atDockMockLogicMessage.APIMessage.Dock.Address.Number,
-						Street: atDockMockLogicMessage.APIMessage.Dock.Address.Street,
-					},
-					Gangway: atDockMockLogicMessage.APIMessage.Dock.Gangway,
-				},
-				TransactionId: atDockMockLogicMessage.APIMessage.TransactionID,
-			}
-			clientData := pb.ClientData{
-				ConnName: atDockMockLogicMessage.Client.ConnName,
-				ConnType: atDockMockLogicMessage.Client.ConnType,
-			}
-			atDockMessage := pb.AtDockMessage{
-				ApiMessage: &apiMessage,
-				ClientData: &clientData,
-			}
-
-			// Send message to MockLogic
-			if err = stream.Send(&atDockMessage); err != nil {
-				Logger.Debug().Msgf("Failed to send an AtDockMessage: %v", err)
-				return err
-			}
-
-		case <-streamDone:
-			return err
-		}
-	}
+	return sendToMockLogic[a.AtDockMockLogicMessage](stream.Recv, stream.Send)
 }
 
 // OnBoat handles sending and receiving the bi-directional stream for OnBoatMessage
 func (s *adapterServer) OnBoat(stream pb.Adapter_OnBoatServer) error {
-	var err error
-
-	streamDone := make(chan struct{})
-	// Launch a goroutine to receive the stream of Empty messages
-	// These are not expected to be received from the gRPC client and
-	// can be discarded
-	go func() {
-		for {
-			_, err := stream.Recv()
-			if err == io.EOF {
-				// read done.
-				streamDone <- struct{}{}
-				return
-			}
-			if err != nil {
-				Logger.Debug().Msgf("Failed to receive an Empty message : %v", err)
-				streamDone <- struct{}{}
-				return
-			}
-		}
-	}()
-
-	for {
-		select {
-		case onBoatMockLogicMessage := <-GRPCChans.OnBoatChannel:
-			// Create gRPC OnBoatMessage
-			apiMessage := pb.OnBoatAPIMessage{
-				MessageType: onBoatMockLogicMessage.APIMessage.MessageType,
-				ClientId:    onBoatMockLogicMessage.APIMessage.ClientID,
-				Boat: &pb.Boat{
-					BoatId: onBoatMockLogicMessage.APIMessage.Boat.BoatID,
-					Name:   onBoatMockLogicMessage.APIMessage.Boat.Name,
-				},
-				TransactionId: onBoatMockLogicMessage.APIMessage.TransactionID,
-			}
-			clientData := pb.ClientData{
-				ConnName: onBoatMockLogicMessage.Client.ConnName,
-				ConnType: onBoatMockLogicMessage.Client.ConnType,
-			}
-			onBoatMessage := pb.OnBoatMessage{
-				ApiMessage: &apiMessage,
-				ClientData: &clientData,
-			}
-
-			// Send message to MockLogic
-			if err = stream.Send(&onBoatMessage); err != nil {
-				Logger.Debug().Msgf("Failed to send an OnBoatMessage: %v", err)
-				return err
-			}
-
-		case <-streamDone:
-			return err
-		}
-	}
+	return sendToMockLogic[a.OnBoatMockLogicMessage](stream.Recv, stream.Send)
 }
 
 // OffBoat handles sending and receiving the bi-directional stream for OffBoatMessage
 func (s *adapterServer) OffBoat(stream pb.Adapter_OffBoatServer) error {
-	var err error
+	return sendToMockLogic[a.OffBoatMockLogicMessage](stream.Recv, stream.Send)
+}
 
-	streamDone := make(chan struct{})
-	// Launch a goroutine to receive the stream of Empty messages
-	// These are not expected to be received from the gRPC client and
-	// can be discarded
-	go func() {
-		for {
-			_, err := stream.Recv()
-			if err == io.EOF {
-				// read done.
-				streamDone <- struct{}{}
-				return
-			}
-			if err != nil {
-				Logger.Debug().Msgf("Failed to receive an Empty message : %v", err)
-				streamDone <- struct{}{}
-				return
-			}
-		}
-	}()
+// CancelTrip handles sending and receiving the bi-directional stream for CancelTripMessage
+func (s *adapterServer) CancelTrip(stream pb.Adapter_CancelTripServer) error {
+	return sendToMockLogic[a.CancelTripMockLogicMessage](stream.Recv, stream.Send)
+}
 
-	for {
-		select {
-		case offBoatMockLogicMessage := <-GRPCChans.OffBoatChannel:
-			// Create gRPC OffBoatMessage
-			apiMessage := pb.OffBoatAPIMessage{
-				MessageType: offBoatMockLogicMessage.APIMessage.MessageType,
-				ClientId:    offBoatMockLogicMessage.APIMessage.ClientID,
-				Boat: &pb.Boat{
-					BoatId: offBoatMockLogicMessage.APIMessage.Boat.BoatID,
-					Name:   offBoatMockLogicMessage.APIMessage.Boat.Name,
-				},
-				TransactionId: offBoatMockLogicMessage.APIMessage.TransactionID,
-			}
-			clientData := pb.ClientData{
-				ConnName: offBoatMockLogicMessage.Client.ConnName,
-				ConnType: offBoatMockLogicMessage.Client.ConnType,
-			}
-			offBoatMessage := pb.OffBoatMessage{
-				ApiMessage: &apiMessage,
-				ClientData: &clientData,
-			}
-
-			// Send message to MockLogic
-			if err = stream.Send(&offBoatMessage); err != nil {
-				Logger.Debug().Msgf("Failed to send an OffBoatMessage: %v", err)
-				return err
-			}
-
-		case <-streamDone:
+// GetTrip handles sending and receiving the bi-directional stream for GetTripMessage
+func (s *adapterServer) GetTrip(stream pb.Adapter_GetTripServer) error {
+	return sendToMockLogic[a.GetTripMockLogicMessage](stream.Recv, stream.Send)
+}
+
+// acceptFromMockLogic delivers a message received from the MockLogic on the named
+// stream with the given deliver function, and then sends a Receipt for it. The
+// Receipt is only sent once the message has been placed on the WebSocketServer
+// write channel, so that the MockLogic keeps the message and sends it again if
+// it could not be delivered. A duplicate of a message that was already delivered
+// is acknowledged again without being delivered, since the MockLogic sends it
+// again when the Receipt was lost.
+func acceptFromMockLogic(send func(*pb.Receipt) error, streamName string,
+	epoch, sequence uint64, deliver func() error) error {
+	if MockLogicInbox.Received(streamName, epoch, sequence) {
+		Logger.Debug().Msgf("Discarding duplicate %s message with sequence: %d", streamName, sequence)
+	} else {
+		err := deliver()
+		if err != nil {
 			return err
 		}
+		MockLogicInbox.Accept(streamName, epoch, sequence)
 	}
+
+	return send(&pb.Receipt{Epoch: epoch, Sequence: sequence})
 }
 
-// Ack handles sending and receiving the bi-directional stream for AckMessage
-func (s *adapterServer) Ack(stream pb.Adapter_AckServer) error {
-	// No goroutine is launched to write Empty messages since these are not expected
-	// by the MockLogic
+// sequencedGRPCMessage is a gRPC message that is sent with an epoch and sequence
+type sequencedGRPCMessage interface {
+	GetEpoch() uint64
+	GetSequence() uint64
+}
 
-	// Receive the stream of Ack messages
+// receiveFromMockLogic receives the messages of one type from the MockLogic with
+// the given recv function, on a stream that carries only that type of message,
+// and delivers each message that was not received before. The Receipt for each
+// message is sent with the given send function once it has been delivered.
+func receiveFromMockLogic[M sequencedGRPCMessage](recv func() (M, error),
+	send func(*pb.Receipt) error, messageType string, deliver func(M) error) error {
 	for {
-		in, err := stream.Recv()
+		in, err := recv()
 		if err == io.EOF {
 			return nil
 		}
@@ -413,116 +401,250 @@ func (s *adapterServer) Ack(stream pb.Adapter_AckServer) error {
 			return err
 		}
 
-		// Convert pb.AckMessage to MockLogicMessage message
-		boat := a.NewBoat(in.ApiMessage.Boat.BoatId, in.ApiMessage.Boat.Name)
-		ackAPIMsg := a.NewAckAPIMessage(a.APIMessageTypeAck, in.ApiMessage.ClientId,
-			in.ApiMessage.IsReserved, boat, in.ApiMessage.TransactionId)
-		apiMsgBytes, err := json.Marshal(ackAPIMsg)
+		err = acceptFromMockLogic(send, messageType, in.GetEpoch(), in.GetSequence(),
+			func() error { return deliver(in) })
 		if err != nil {
-			Logger.Debug().Msgf("Error marshaling %s message received from MockLogic: %s",
-				in.ApiMessage.MessageType, err.Error())
-			continue
+			return err
 		}
-		mlMsg := NewMockLogicMessage(in.ClientData.ConnName, in.ClientData.ConnType,
-			a.APIMessageTypeAck, apiMsgBytes)
-
-		go ProcessMessageFromMockLogic(&mlMsg)
 	}
 }
 
+// Ack handles sending and receiving the bi-directional stream for AckMessage
+func (s *adapterServer) Ack(stream pb.Adapter_AckServer) error {
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeAck, deliverAck)
+}
+
+// CancelAck handles sending and receiving the bi-directional stream for CancelAckMessage
+func (s *adapterServer) CancelAck(stream pb.Adapter_CancelAckServer) error {
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeCancelAck, deliverCancelAck)
+}
+
+// TripStatus handles sending and receiving the bi-directional stream for TripStatusMessage
+func (s *adapterServer) TripStatus(stream pb.Adapter_TripStatusServer) error {
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeTripStatus, deliverTripStatus)
+}
+
 // BoatStatus handles sending and receiving the bi-directional stream for BoatStatusMessage
 func (s *adapterServer) BoatStatus(stream pb.Adapter_BoatStatusServer) error {
-	// No goroutine is launched to write Empty messages since these are not expected
-	// by the MockLogic
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeBoatStatus, deliverBoatStatus)
+}
 
-	// Receive the stream of BoatStatus messages
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
-			return err
-		}
+// Arrived handles sending and receiving the bi-directional stream for ArrivedMessage
+func (s *adapterServer) Arrived(stream pb.Adapter_ArrivedServer) error {
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeArrived, deliverArrived)
+}
 
-		// Convert pb.BoatStatusMessage to MockLogicMessage message
-		boat := a.NewBoat(in.ApiMessage.Boat.BoatId, in.ApiMessage.Boat.Name)
-		previousDockAddress := a.NewAddress(in.ApiMessage.PreviousDock.Address.Number,
-			in.ApiMessage.PreviousDock.Address.Street)
-		previousDock := a.NewDock(previousDockAddress, in.ApiMessage.PreviousDock.Gangway)
-		currentDockAddress := a.NewAddress(in.ApiMessage.CurrentDock.Address.Number,
-			in.ApiMessage.CurrentDock.Address.Street)
-		currentDock := a.NewDock(currentDockAddress, in.ApiMessage.CurrentDock.Gangway)
-		nextDockAddress := a.NewAddress(in.ApiMessage.NextDock.Address.Number,
-			in.ApiMessage.NextDock.Address.Street)
-		nextDock := a.NewDock(nextDockAddress, in.ApiMessage.NextDock.Gangway)
-		boatStatusAPIMsg := a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus, boat,
-			int32(in.ApiMessage.ServiceState), previousDock, currentDock, nextDock)
-		apiMsgBytes, err := json.Marshal(boatStatusAPIMsg)
-		if err != nil {
-			Logger.Debug().Msgf("Error marshaling %s message received from MockLogic: %s",
-				in.ApiMessage.MessageType, err.Error())
-			continue
-		}
-		mlMsg := NewMockLogicMessage(in.ClientData.ConnName, a.ConnectionTypeAll,
-			a.APIMessageTypeBoatStatus, apiMsgBytes)
+// TripProgress handles sending and receiving the bi-directional stream for TripProgressMessage
+func (s *adapterServer) TripProgress(stream pb.Adapter_TripProgressServer) error {
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeTripProgress, deliverTripProgress)
+}
+
+// Error handles sending and receiving the bi-directional stream for ErrorMessage
+func (s *adapterServer) Error(stream pb.Adapter_ErrorServer) error {
+	return receiveFromMockLogic(stream.Recv, stream.Send, a.APIMessageTypeError, deliverError)
+}
 
-		go ProcessMessageFromMockLogic(&mlMsg)
+// deliverAck delivers an AckMessage from the MockLogic to the client
+func deliverAck(in *pb.AckMessage) error {
+	ack, err := a.FromGRPC[a.AckMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeAck, err.Error())
+		return nil
 	}
+	ack.APIMessage.MessageType = a.APIMessageTypeAck
+
+	return deliverToClient(ack.Client, a.APIMessageTypeAck, ack.APIMessage)
 }
 
-// Arrived handles sending and receiving the bi-directional stream for ArrivedMessage
-func (s *adapterServer) Arrived(stream pb.Adapter_ArrivedServer) error {
-	// No goroutine is launched to write Empty messages since these are not expected
-	// by the MockLogic
+// deliverCancelAck delivers a CancelAckMessage from the MockLogic to the client
+func deliverCancelAck(in *pb.CancelAckMessage) error {
+	cancelAck, err := a.FromGRPC[a.CancelAckMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeCancelAck, err.Error())
+		return nil
+	}
+	cancelAck.APIMessage.MessageType = a.APIMessageTypeCancelAck
 
-	// Receive the stream of Arrived messages
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
-			return err
-		}
+	return deliverToClient(cancelAck.Client, a.APIMessageTypeCancelAck, cancelAck.APIMessage)
+}
 
-		// Convert pb.ArrivedMessage to MockLogicMessage message
-		boat := a.NewBoat(in.ApiMessage.Boat.BoatId, in.ApiMessage.Boat.Name)
-		address := a.NewAddress(in.ApiMessage.Dock.Address.Number, in.ApiMessage.Dock.Address.Street)
-		dock := a.NewDock(address, in.ApiMessage.Dock.Gangway)
-		arrivedAPIMsg := a.NewArrivedAPIMessage(a.APIMessageTypeArrived, in.ApiMessage.ClientId,
-			boat, dock, in.ApiMessage.TransactionId)
-		apiMsgBytes, err := json.Marshal(arrivedAPIMsg)
-		if err != nil {
-			Logger.Debug().Msgf("Error marshaling %s message received from MockLogic: %s",
-				in.ApiMessage.MessageType, err.Error())
-			continue
-		}
-		mlMsg := NewMockLogicMessage(in.ClientData.ConnName, in.ClientData.ConnType,
-			a.APIMessageTypeArrived, apiMsgBytes)
+// deliverTripStatus delivers a TripStatusMessage from the MockLogic to the client
+func deliverTripStatus(in *pb.TripStatusMessage) error {
+	tripStatus, err := a.FromGRPC[a.TripStatusMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeTripStatus, err.Error())
+		return nil
+	}
+	tripStatus.APIMessage.MessageType = a.APIMessageTypeTripStatus
+
+	return deliverToClient(tripStatus.Client, a.APIMessageTypeTripStatus, tripStatus.APIMessage)
+}
+
+// deliverBoatStatus caches a BoatStatusMessage from the MockLogic and broadcasts
+// it to every client
+func deliverBoatStatus(in *pb.BoatStatusMessage) error {
+	status, err := a.FromGRPC[a.BoatStatusMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeBoatStatus, err.Error())
+		return nil
+	}
+	status.APIMessage.MessageType = a.APIMessageTypeBoatStatus
+
+	apiMsgBytes, err := json.Marshal(status.APIMessage)
+	if err != nil {
+		Logger.Debug().Msgf("Error marshaling %s message received from MockLogic: %s",
+			a.APIMessageTypeBoatStatus, err.Error())
+		return nil
+	}
+	mlMsg := NewMockLogicMessage(status.Client.ConnName, a.ConnectionTypeAll,
+		a.APIMessageTypeBoatStatus, apiMsgBytes)
+
+	// The status is cached before it is broadcast, so that a snapshot that is
+	// written after the broadcast holds this status or a later one
+	safeBoatStatusCache.Store(status.APIMessage.Boat.BoatID, apiMsgBytes)
+	return ProcessMessageFromMockLogic(&mlMsg)
+}
+
+// deliverArrived delivers an ArrivedMessage from the MockLogic to the client
+func deliverArrived(in *pb.ArrivedMessage) error {
+	arrived, err := a.FromGRPC[a.ArrivedMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeArrived, err.Error())
+		return nil
+	}
+	arrived.APIMessage.MessageType = a.APIMessageTypeArrived
+
+	return deliverToClient(arrived.Client, a.APIMessageTypeArrived, arrived.APIMessage)
+}
+
+// deliverTripProgress delivers a TripProgressMessage from the MockLogic to the client
+func deliverTripProgress(in *pb.TripProgressMessage) error {
+	progress, err := a.FromGRPC[a.TripProgressMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeTripProgress, err.Error())
+		return nil
+	}
+	progress.APIMessage.MessageType = a.APIMessageTypeTripProgress
 
-		go ProcessMessageFromMockLogic(&mlMsg)
+	return deliverToClient(progress.Client, a.APIMessageTypeTripProgress, progress.APIMessage)
+}
+
+// deliverError delivers an ErrorMessage from the MockLogic to the client
+func deliverError(in *pb.ErrorMessage) error {
+	errMsg, err := a.FromGRPC[a.ErrorMockLogicMessage](in)
+	if err != nil {
+		Logger.Error().Msgf("Error converting %s message received from MockLogic: %s",
+			a.APIMessageTypeError, err.Error())
+		return nil
+	}
+	errMsg.APIMessage.MessageType = a.APIMessageTypeError
+
+	return deliverToClient(errMsg.Client, a.APIMessageTypeError, errMsg.APIMessage)
+}
+
+// deliverToClient marshals an API message received from the MockLogic and
+// delivers it to the client. The message is delivered before the next message is
+// received, so that the messages reach the client in the order that the
+// MockLogic sent them. An error is returned if the message could not be placed
+// on the WebSocketServer write channel.
+func deliverToClient(client a.ClientData, messageType string, apiMsg any) error {
+	apiMsgBytes, err := json.Marshal(apiMsg)
+	if err != nil {
+		Logger.Debug().Msgf("Error marshaling %s message received from MockLogic: %s",
+			messageType, err.Error())
+		return nil
 	}
+	mlMsg := NewMockLogicMessage(client.ConnName, client.ConnType, messageType, apiMsgBytes)
+
+	return ProcessMessageFromMockLogic(&mlMsg)
 }
 
-// ProcessMessageToMockLogic processes a message that is being sent from
-// the MockLogic to the API clients
-func ProcessMessageFromMockLogic(mlMsg *MockLogicMessage) {
+// errWebSocketServerUnavailable is returned when a message from the MockLogic
+// could not be placed on the WebSocketServer write channel
+var errWebSocketServerUnavailable = errors.New("the WebSocketServer connection is unavailable")
+
+// ProcessMessageFromMockLogic processes a message that is being sent from
+// the MockLogic to the API clients. The message is placed on the WebSocketServer
+// write channel in order with the other writes to the WebSocketServer.
+// errWebSocketServerUnavailable is returned if the WebSocketServer is not
+// connected, or the connection closes before the message could be placed on its
+// write channel.
+func ProcessMessageFromMockLogic(mlMsg *MockLogicMessage) error {
 	switch mlMsg.ConnType {
 	case a.ConnectionTypeWebSocket:
 		// Convert message to wss.AdapterMessage
 		wssAdapterMsg := wss.NewAdapterMessage(mlMsg.ConnName, mlMsg.APIMessageBytes)
-		WebSocketServerConn.Write <- wssAdapterMsg
+		return orderedWrite(func() error { return writeToWebSocketServer(wssAdapterMsg) })
 
 	case a.ConnectionTypeAll:
 		// Convert message to wss.AdapterMessage
 		wssAdapterMsg := wss.NewAdapterMessage(wss.WSSServerAllClientsConnName,
 			mlMsg.APIMessageBytes)
-		WebSocketServerConn.Write <- wssAdapterMsg
+		return orderedWrite(func() error { return writeToWebSocketServer(wssAdapterMsg) })
 
 		// Convert message to other protocol types here once they are implemented
 
 	default:
 		Logger.Warn().Msgf("ProcessMessageFromMockLogic received a message with an unexpected ConnType: %s", mlMsg.ConnType)
+		return nil
+	}
+}
+
+// wsWriteRequest is a write to the WebSocketServer that is made by the
+// WebSocketServer writer, with the channel that the result of the write is sent on
+type wsWriteRequest struct {
+	write  func() error
+	result chan error
+}
+
+// wsWriteRequests holds the writes to the WebSocketServer in the order that they
+// were requested
+var wsWriteRequests = make(chan wsWriteRequest)
+
+// startWSWriter starts the WebSocketServer writer with the first write
+var startWSWriter sync.Once
+
+// orderedWrite makes the given write to the WebSocketServer once every write that
+// was requested before it has been made, and returns its result. The writes are
+// made one at a time by the WebSocketServer writer, so that the messages that are
+// sent from different goroutines are placed on the WebSocketServer write channel
+// in order without a lock being held while a write waits for the channel.
+func orderedWrite(write func() error) error {
+	startWSWriter.Do(func() { go wsWriter() })
+
+	request := wsWriteRequest{write: write, result: make(chan error, 1)}
+	wsWriteRequests <- request
+
+	return <-request.result
+}
+
+// wsWriter makes the writes to the WebSocketServer in the order that they were
+// requested
+func wsWriter() {
+	for request := range wsWriteRequests {
+		request.result <- request.write()
+	}
+}
+
+// writeToWebSocketServer places the message on the WebSocketServer write channel,
+// and returns errWebSocketServerUnavailable if the WebSocketServer is not
+// connected or the connection closes first
+func writeToWebSocketServer(adapterMsg wss.AdapterMessage) error {
+	write, closed := WebSocketServerConn.Write, WebSocketServerConn.Close
+	if write == nil {
+		return errWebSocketServerUnavailable
+	}
+
+	select {
+	case write <- adapterMsg:
+		return nil
+	case <-closed:
+		return errWebSocketServerUnavailable
 	}
 }
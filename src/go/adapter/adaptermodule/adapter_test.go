@@ -6,13 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
 	a "riden/adapter"
 	"riden/logger"
 	pb "riden/proto"
 	wss "riden/websocketserver"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,7 +24,10 @@ import (
 
 	"github.com/gorilla/websocket"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Common test parameters
@@ -38,6 +45,12 @@ var testServicState int32 = a.ServiceStateOnTime
 var testBoatID int32 = 911
 var testBoatName string = "testBoat"
 var testTransactionID string = "917K-956B"
+var testSeatsAvailable int32 = 12
+var testSourceETA time.Time = time.Date(2025, time.June, 1, 9, 30, 0, 0, time.UTC)
+var testDestETA time.Time = time.Date(2025, time.June, 1, 9, 45, 0, 0, time.UTC)
+var testDepartureTime time.Time = time.Date(2025, time.June, 1, 9, 30, 0, 0, time.UTC)
+var testPartySize int32 = 3
+var testPassengerNames []string = []string{"testPassenger1", "testPassenger2"}
 
 var testReserveTripAPIMessageBytes []byte
 var testReserveTripAPIMessage a.ReserveTripAPIMessage
@@ -60,12 +73,30 @@ var testOnBoatMockLogicMessage a.OnBoatMockLogicMessage
 var testOffBoatAPIMessage a.OffBoatAPIMessage
 var testOffBoatAPIMessageBytes []byte
 var testOffBoatMockLogicMessage a.OffBoatMockLogicMessage
+var testCancelTripAPIMessage a.CancelTripAPIMessage
+var testCancelTripAPIMessageBytes []byte
+var testCancelTripMockLogicMessage a.CancelTripMockLogicMessage
+var testCancelAckAPIMessage a.CancelAckAPIMessage
+var testCancelAckAPIMessageBytes []byte
+var testCancelAckAdapterMessage wss.AdapterMessage
+var testGetTripAPIMessage a.GetTripAPIMessage
+var testGetTripAPIMessageBytes []byte
+var testGetTripMockLogicMessage a.GetTripMockLogicMessage
+var testTripStatusAPIMessage a.TripStatusAPIMessage
+var testTripStatusAPIMessageBytes []byte
+var testTripStatusAdapterMessage wss.AdapterMessage
 var testBoatStatusAPIMessage a.BoatStatusAPIMessage
 var testBoatStatusAPIMessageBytes []byte
 var testBoatStatusAdapterMessage wss.AdapterMessage
 var testArrivedAPIMessage a.ArrivedAPIMessage
 var testArrivedAPIMessageBytes []byte
 var testArrivedAdapterMessage wss.AdapterMessage
+var testTripProgressAPIMessage a.TripProgressAPIMessage
+var testTripProgressAPIMessageBytes []byte
+var testTripProgressAdapterMessage wss.AdapterMessage
+var testErrorAPIMessage a.ErrorAPIMessage
+var testErrorAPIMessageBytes []byte
+var testErrorAdapterMessage wss.AdapterMessage
 
 func TestMain(m *testing.M) {
 	SetUp()
@@ -90,7 +121,7 @@ func SetUp() {
 	testDestAddress = a.NewAddress(testDestNumber, testDestStreet)
 	testDestDock = a.NewDock(testDestAddress, testDestGangway)
 	testReserveTripAPIMessage = a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, testToken, testClientID,
-		testSourceDock, testDestDock)
+		testSourceDock, testDestDock, testDepartureTime, testPartySize, testPassengerNames)
 	b, err := json.Marshal(testReserveTripAPIMessage)
 	if err != nil {
 		Logger.Error().Msgf("Error marshaling Reserve API msg in test set-up: %s", err.Error())
@@ -104,7 +135,8 @@ func SetUp() {
 	// Marshal an Ack API message for testing
 	testBoat = a.NewBoat(testBoatID, testBoatName)
 	testAckAPIMessage = a.NewAckAPIMessage(a.APIMessageTypeAck, testClientID, true,
-		testBoat, testTransactionID)
+		testBoat, testTransactionID, a.ErrorReasonUnknown, "", testSourceETA, testDestETA,
+		testDepartureTime)
 	b, err = json.Marshal(testAckAPIMessage)
 	if err != nil {
 		Logger.Error().Msgf("Error marshaling Reserve API msg in test set-up: %s", err.Error())
@@ -129,7 +161,7 @@ func SetUp() {
 
 	// Marshal an OnBoat API message for testing
 	testOnBoatAPIMessage = a.NewOnBoatAPIMessage(a.APIMessageTypeOnBoat,
-		testClientID, testBoat, testTransactionID)
+		testClientID, testBoat, testTransactionID, testPassengerNames[:1])
 	b, err = json.Marshal(testOnBoatAPIMessage)
 	if err != nil {
 		Logger.Error().Msgf("Error marshaling Reserve API msg in test set-up: %s", err.Error())
@@ -141,7 +173,7 @@ func SetUp() {
 
 	// Marshal an OffBoat API message for testing
 	testOffBoatAPIMessage = a.NewOffBoatAPIMessage(a.APIMessageTypeOffBoat,
-		testClientID, testBoat, testTransactionID)
+		testClientID, testBoat, testTransactionID, nil)
 	b, err = json.Marshal(testOffBoatAPIMessage)
 	if err != nil {
 		Logger.Error().Msgf("Error marshaling Reserve API msg in test set-up: %s", err.Error())
@@ -151,9 +183,35 @@ func SetUp() {
 	// Create an OffBoatMockLogicMessage for testing
 	testOffBoatMockLogicMessage = a.NewOffBoatMockLogicMessage(testOffBoatAPIMessage, testClientData)
 
+	// Marshal a CancelTrip API message for testing
+	testCancelTripAPIMessage = a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+		testClientID, testTransactionID)
+	b, err = json.Marshal(testCancelTripAPIMessage)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling CancelTrip API msg in test set-up: %s", err.Error())
+	}
+	testCancelTripAPIMessageBytes = b
+
+	// Create a CancelTripMockLogicMessage for testing
+	testCancelTripMockLogicMessage = a.NewCancelTripMockLogicMessage(testCancelTripAPIMessage,
+		testClientData)
+
+	// Marshal a CancelAck API message for testing
+	testCancelAckAPIMessage = a.NewCancelAckAPIMessage(a.APIMessageTypeCancelAck,
+		testClientID, testTransactionID, true, a.ErrorReasonUnknown, "")
+	b, err = json.Marshal(testCancelAckAPIMessage)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling CancelAck API msg in test set-up: %s", err.Error())
+	}
+	testCancelAckAPIMessageBytes = b
+
+	// Create a CancelAck AdapterMessage for testing
+	testCancelAckAdapterMessage = wss.NewAdapterMessage(testClientConnectionName,
+		testCancelAckAPIMessageBytes)
+
 	// Marshal an BoatStatus API message for testing
 	testBoatStatusAPIMessage = a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus,
-		testBoat, testServicState, testSourceDock, testDestDock, testDestDock)
+		testBoat, testServicState, testSourceDock, testDestDock, testDestDock, testSeatsAvailable)
 	b, err = json.Marshal(testBoatStatusAPIMessage)
 	if err != nil {
 		Logger.Error().Msgf("Error marshaling Reserve API msg in test set-up: %s", err.Error())
@@ -164,6 +222,33 @@ func SetUp() {
 	testBoatStatusAdapterMessage = wss.NewAdapterMessage(wss.WSSServerAllClientsConnName,
 		testBoatStatusAPIMessageBytes)
 
+	// Marshal a GetTrip API message for testing
+	testGetTripAPIMessage = a.NewGetTripAPIMessage(a.APIMessageTypeGetTrip,
+		testClientID, testTransactionID)
+	b, err = json.Marshal(testGetTripAPIMessage)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling GetTrip API msg in test set-up: %s", err.Error())
+	}
+	testGetTripAPIMessageBytes = b
+
+	// Create a GetTripMockLogicMessage for testing
+	testGetTripMockLogicMessage = a.NewGetTripMockLogicMessage(testGetTripAPIMessage,
+		testClientData)
+
+	// Marshal a TripStatus API message for testing
+	testTripStatusAPIMessage = a.NewTripStatusAPIMessage(a.APIMessageTypeTripStatus,
+		testClientID, testTransactionID, true, a.ErrorReasonUnknown, "", "reserved",
+		testSourceDock, testDestDock, testBoat, testBoatStatusAPIMessage, testSourceETA, testDestETA)
+	b, err = json.Marshal(testTripStatusAPIMessage)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling TripStatus API msg in test set-up: %s", err.Error())
+	}
+	testTripStatusAPIMessageBytes = b
+
+	// Create a TripStatus AdapterMessage for testing
+	testTripStatusAdapterMessage = wss.NewAdapterMessage(testClientConnectionName,
+		testTripStatusAPIMessageBytes)
+
 	// Marshal an Arrived API message for testing
 	testArrivedAPIMessage = a.NewArrivedAPIMessage(a.APIMessageTypeArrived, testClientID,
 		testBoat, testSourceDock, testTransactionID)
@@ -176,6 +261,33 @@ func SetUp() {
 	// Create an Arrived AdapterMessage for testing
 	testArrivedAdapterMessage = wss.NewAdapterMessage(testClientConnectionName,
 		testArrivedAPIMessageBytes)
+
+	// Marshal a TripProgress API message for testing
+	testTripProgressAPIMessage = a.NewTripProgressAPIMessage(a.APIMessageTypeTripProgress,
+		testClientID, testBoat, testTransactionID, testSourceETA, testDestETA)
+	b, err = json.Marshal(testTripProgressAPIMessage)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling TripProgress API msg in test set-up: %s", err.Error())
+	}
+	testTripProgressAPIMessageBytes = b
+
+	// Create a TripProgress AdapterMessage for testing
+	testTripProgressAdapterMessage = wss.NewAdapterMessage(testClientConnectionName,
+		testTripProgressAPIMessageBytes)
+
+	// Marshal an Error API message for testing
+	testErrorAPIMessage = a.NewErrorAPIMessage(a.APIMessageTypeError, testClientID,
+		a.ErrorReasonInvalidTripState, "trip is in state: 1, and cannot advance to state: 4",
+		a.APIMessageTypeOnBoat, testTransactionID)
+	b, err = json.Marshal(testErrorAPIMessage)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling Error API msg in test set-up: %s", err.Error())
+	}
+	testErrorAPIMessageBytes = b
+
+	// Create an Error AdapterMessage for testing
+	testErrorAdapterMessage = wss.NewAdapterMessage(testClientConnectionName,
+		testErrorAPIMessageBytes)
 }
 
 func TearDown() {
@@ -200,6 +312,34 @@ func TestGetMessageFromWebSocketMsg(t *testing.T) {
 			expectedError:       false,
 			expectedMessageType: "atDock",
 		},
+		{
+			name:                "GetMessageFromWebSocketMsg - Correct message with the keys of the spec",
+			connName:            "testConn",
+			messageBytes:        []byte(`{"messageType": "atDock","clientID": "string"}`),
+			expectedError:       false,
+			expectedMessageType: "atDock",
+		},
+		{
+			name:                "GetMessageFromWebSocketMsg - Message without a message type",
+			connName:            "testConn",
+			messageBytes:        []byte(`{"clientID": "string"}`),
+			expectedError:       false,
+			expectedMessageType: "",
+		},
+		{
+			name:                "GetMessageFromWebSocketMsg - Message type that is not a string",
+			connName:            "testConn",
+			messageBytes:        []byte(`{"messageType": 7}`),
+			expectedError:       true,
+			expectedMessageType: "",
+		},
+		{
+			name:                "GetMessageFromWebSocketMsg - Message that is not an object",
+			connName:            "testConn",
+			messageBytes:        []byte(`["atDock"]`),
+			expectedError:       true,
+			expectedMessageType: "",
+		},
 	}
 
 	for _, testCase := range cases {
@@ -394,8 +534,8 @@ func TestReceivingMessageFromWebSocketServer(t *testing.T) {
 		},
 	}
 
-	// Make a new Reserve message channel in the context of this test
-	GRPCChans.MakeReserveTrip()
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
 
 	for _, testCase := range cases {
 		time.Sleep(100 * time.Millisecond)
@@ -408,9 +548,9 @@ func TestReceivingMessageFromWebSocketServer(t *testing.T) {
 		}
 		Logger.Info().Msg("Wrote empty msg to mock WebSocketServer")
 
-		reserveMockLogicMsg := <-GRPCChans.ReserveTripChannel
+		reserveMockLogicMsg := nextOutboxMessage[a.ReserveTripMockLogicMessage](GRPCOutbox)
 
-		if reserveMockLogicMsg.APIMessage != testCase.expectedMessage {
+		if !reflect.DeepEqual(reserveMockLogicMsg.APIMessage, testCase.expectedMessage) {
 			t.Fatalf("Expected ReserveTripMockLogicMessage %+v but received %+v in test case: %s",
 				testCase.expectedMessage, reserveMockLogicMsg.APIMessage, testCase.name)
 		}
@@ -438,8 +578,8 @@ func TestProcessReserveMessageToMockLogic(t *testing.T) {
 		},
 	}
 
-	// Make a new channels in the context of this test
-	GRPCChans.MakeReserveTrip()
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
 
 	for _, testCase := range cases {
 		reserveTripToMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
@@ -447,9 +587,9 @@ func TestProcessReserveMessageToMockLogic(t *testing.T) {
 
 		ProcessMessageToMockLogic(&reserveTripToMockLogicMsg)
 
-		reserveTripMockLogicMsg := <-GRPCChans.ReserveTripChannel
+		reserveTripMockLogicMsg := nextOutboxMessage[a.ReserveTripMockLogicMessage](GRPCOutbox)
 
-		if reserveTripMockLogicMsg != testCase.expectedMessage {
+		if !reflect.DeepEqual(reserveTripMockLogicMsg, testCase.expectedMessage) {
 			t.Fatalf("Expected ReserveTripMockLogicMessage %+v but received %+v in test case: %s",
 				testCase.expectedMessage, reserveTripMockLogicMsg, testCase.name)
 		}
@@ -477,8 +617,8 @@ func TestProcessAtDockMessageToMockLogic(t *testing.T) {
 		},
 	}
 
-	// Make a new channels in the context of this test
-	GRPCChans.MakeAtDock()
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
 
 	for _, testCase := range cases {
 		atDockToMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
@@ -486,7 +626,7 @@ func TestProcessAtDockMessageToMockLogic(t *testing.T) {
 
 		ProcessMessageToMockLogic(&atDockToMockLogicMsg)
 
-		atDockMockLogicMsg := <-GRPCChans.AtDockChannel
+		atDockMockLogicMsg := nextOutboxMessage[a.AtDockMockLogicMessage](GRPCOutbox)
 
 		if atDockMockLogicMsg != testCase.expectedMessage {
 			t.Fatalf("Expected AtDockMockLogicMessage %+v but received %+v in test case: %s",
@@ -516,8 +656,8 @@ func TestProcessOnBoatMessageToMockLogic(t *testing.T) {
 		},
 	}
 
-	// Make a new channels in the context of this test
-	GRPCChans.MakeOnBoat()
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
 
 	for _, testCase := range cases {
 		onBoatToMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
@@ -525,9 +665,9 @@ func TestProcessOnBoatMessageToMockLogic(t *testing.T) {
 
 		ProcessMessageToMockLogic(&onBoatToMockLogicMsg)
 
-		onBoatMockLogicMsg := <-GRPCChans.OnBoatChannel
+		onBoatMockLogicMsg := nextOutboxMessage[a.OnBoatMockLogicMessage](GRPCOutbox)
 
-		if onBoatMockLogicMsg != testCase.expectedMessage {
+		if !reflect.DeepEqual(onBoatMockLogicMsg, testCase.expectedMessage) {
 			t.Fatalf("Expected OnBoatMockLogicMessage %+v but received %+v in test case: %s",
 				testCase.expectedMessage, onBoatMockLogicMsg, testCase.name)
 		}
@@ -555,8 +695,8 @@ func TestProcessOffBoatMessageToMockLogic(t *testing.T) {
 		},
 	}
 
-	// Make a new channels in the context of this test
-	GRPCChans.MakeOffBoat()
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
 
 	for _, testCase := range cases {
 		offBoatToMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
@@ -564,9 +704,9 @@ func TestProcessOffBoatMessageToMockLogic(t *testing.T) {
 
 		ProcessMessageToMockLogic(&offBoatToMockLogicMsg)
 
-		offBoatMockLogicMsg := <-GRPCChans.OffBoatChannel
+		offBoatMockLogicMsg := nextOutboxMessage[a.OffBoatMockLogicMessage](GRPCOutbox)
 
-		if offBoatMockLogicMsg != testCase.expectedMessage {
+		if !reflect.DeepEqual(offBoatMockLogicMsg, testCase.expectedMessage) {
 			t.Fatalf("Expected OffBoatMockLogicMessage %+v but received %+v in test case: %s",
 				testCase.expectedMessage, offBoatMockLogicMsg, testCase.name)
 		}
@@ -578,6 +718,84 @@ func TestProcessOffBoatMessageToMockLogic(t *testing.T) {
 	}
 }
 
+func TestProcessCancelTripMessageToMockLogic(t *testing.T) {
+	type testCase struct {
+		name                   string
+		expectedMessage        any
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "ProcessCancelTripMessageToMockLogic",
+			expectedMessage:        testCancelTripMockLogicMessage,
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
+
+	for _, testCase := range cases {
+		cancelTripToMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeCancelTrip, testCancelTripAPIMessageBytes)
+
+		ProcessMessageToMockLogic(&cancelTripToMockLogicMsg)
+
+		cancelTripMockLogicMsg := nextOutboxMessage[a.CancelTripMockLogicMessage](GRPCOutbox)
+
+		if cancelTripMockLogicMsg != testCase.expectedMessage {
+			t.Fatalf("Expected CancelTripMockLogicMessage %+v but received %+v in test case: %s",
+				testCase.expectedMessage, cancelTripMockLogicMsg, testCase.name)
+		}
+
+		if cancelTripMockLogicMsg.Client.ConnName != testCase.expectedClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedClientConnName, cancelTripMockLogicMsg.Client.ConnName, testCase.name)
+		}
+	}
+}
+
+func TestProcessGetTripMessageToMockLogic(t *testing.T) {
+	type testCase struct {
+		name                   string
+		expectedMessage        any
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "ProcessGetTripMessageToMockLogic",
+			expectedMessage:        testGetTripMockLogicMessage,
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make a new outbox in the context of this test
+	GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
+
+	for _, testCase := range cases {
+		getTripToMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeGetTrip, testGetTripAPIMessageBytes)
+
+		ProcessMessageToMockLogic(&getTripToMockLogicMsg)
+
+		getTripMockLogicMsg := nextOutboxMessage[a.GetTripMockLogicMessage](GRPCOutbox)
+
+		if getTripMockLogicMsg != testCase.expectedMessage {
+			t.Fatalf("Expected GetTripMockLogicMessage %+v but received %+v in test case: %s",
+				testCase.expectedMessage, getTripMockLogicMsg, testCase.name)
+		}
+
+		if getTripMockLogicMsg.Client.ConnName != testCase.expectedClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedClientConnName, getTripMockLogicMsg.Client.ConnName, testCase.name)
+		}
+	}
+}
+
 func TestProcessAckMessageFromMockLogic(t *testing.T) {
 	type testCase struct {
 		name                   string
@@ -621,6 +839,88 @@ func TestProcessAckMessageFromMockLogic(t *testing.T) {
 	}
 }
 
+func TestProcessCancelAckMessageFromMockLogic(t *testing.T) {
+	type testCase struct {
+		name                   string
+		expectedMessage        any
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "ProcessCancelAckMessageFromMockLogic",
+			expectedMessage:        testCancelAckAdapterMessage,
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make new channels in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+
+	for _, testCase := range cases {
+		cancelAckMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeCancelAck, testCancelAckAPIMessageBytes)
+
+		ProcessMessageFromMockLogic(&cancelAckMockLogicMsg)
+
+		cancelAckAdapterMsg := <-WebSocketServerConn.Write
+
+		if cancelAckAdapterMsg.ClientConnName != testCase.expectedMessage.(wss.AdapterMessage).ClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedMessage.(wss.AdapterMessage).ClientConnName,
+				cancelAckAdapterMsg.ClientConnName, testCase.name)
+		}
+
+		if !bytes.Equal(cancelAckAdapterMsg.MessageBytes, testCase.expectedMessage.(wss.AdapterMessage).MessageBytes) {
+			t.Fatalf("Expected message bytes %s but received %s in test case: %s",
+				string(testCase.expectedMessage.(wss.AdapterMessage).MessageBytes), string(cancelAckAdapterMsg.MessageBytes),
+				testCase.name)
+		}
+	}
+}
+
+func TestProcessTripStatusMessageFromMockLogic(t *testing.T) {
+	type testCase struct {
+		name                   string
+		expectedMessage        any
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "ProcessTripStatusMessageFromMockLogic",
+			expectedMessage:        testTripStatusAdapterMessage,
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make new channels in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+
+	for _, testCase := range cases {
+		tripStatusMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeTripStatus, testTripStatusAPIMessageBytes)
+
+		ProcessMessageFromMockLogic(&tripStatusMockLogicMsg)
+
+		tripStatusAdapterMsg := <-WebSocketServerConn.Write
+
+		if tripStatusAdapterMsg.ClientConnName != testCase.expectedMessage.(wss.AdapterMessage).ClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedMessage.(wss.AdapterMessage).ClientConnName,
+				tripStatusAdapterMsg.ClientConnName, testCase.name)
+		}
+
+		if !bytes.Equal(tripStatusAdapterMsg.MessageBytes, testCase.expectedMessage.(wss.AdapterMessage).MessageBytes) {
+			t.Fatalf("Expected message bytes %s but received %s in test case: %s",
+				string(testCase.expectedMessage.(wss.AdapterMessage).MessageBytes), string(tripStatusAdapterMsg.MessageBytes),
+				testCase.name)
+		}
+	}
+}
+
 func TestProcessBoatStatusMessageFromMockLogic(t *testing.T) {
 	type testCase struct {
 		name                   string
@@ -663,6 +963,138 @@ func TestProcessBoatStatusMessageFromMockLogic(t *testing.T) {
 	}
 }
 
+func TestSendSnapshotToClient(t *testing.T) {
+	type testCase struct {
+		name                   string
+		storedBoatIDs          []int32
+		storedMsgBytes         [][]byte
+		expectedMsgBytes       [][]byte
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "SendSnapshotToClient - Empty cache",
+			storedBoatIDs:          []int32{},
+			storedMsgBytes:         [][]byte{},
+			expectedMsgBytes:       [][]byte{},
+			expectedClientConnName: testClientConnectionName,
+		},
+		{
+			name:                   "SendSnapshotToClient - Latest status of each boat in BoatID order",
+			storedBoatIDs:          []int32{1, 2, 1},
+			storedMsgBytes:         [][]byte{[]byte("boat 1"), []byte("boat 2"), testBoatStatusAPIMessageBytes},
+			expectedMsgBytes:       [][]byte{testBoatStatusAPIMessageBytes, []byte("boat 2")},
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make new channels in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+	originalCache := safeBoatStatusCache
+	defer func() { safeBoatStatusCache = originalCache }()
+
+	for _, testCase := range cases {
+		safeBoatStatusCache = NewBoatStatusCache()
+		for i, boatID := range testCase.storedBoatIDs {
+			safeBoatStatusCache.Store(boatID, testCase.storedMsgBytes[i])
+		}
+
+		SendSnapshotToClient(testClientConnectionName)
+
+		for _, expectedMsgBytes := range testCase.expectedMsgBytes {
+			adapterMsg := <-WebSocketServerConn.Write
+
+			if adapterMsg.ClientConnName != testCase.expectedClientConnName {
+				t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+					testCase.expectedClientConnName, adapterMsg.ClientConnName, testCase.name)
+			}
+
+			if !bytes.Equal(adapterMsg.MessageBytes, expectedMsgBytes) {
+				t.Fatalf("Expected message bytes %s but received %s in test case: %s",
+					string(expectedMsgBytes), string(adapterMsg.MessageBytes), testCase.name)
+			}
+		}
+
+		if len(WebSocketServerConn.Write) != 0 {
+			t.Fatalf("Expected no more messages but %d remain in test case: %s",
+				len(WebSocketServerConn.Write), testCase.name)
+		}
+	}
+}
+
+func TestSnapshotOrderedWithBroadcast(t *testing.T) {
+	// The number of statuses that are broadcast for the boat. The seats available
+	// give the order of the statuses.
+	const broadcasts int32 = 10
+	newBoatStatus := func(seatsAvailable int32) *pb.BoatStatusMessage {
+		return &pb.BoatStatusMessage{
+			ApiMessage: &pb.BoatStatusAPIMessage{
+				Boat:           &pb.Boat{BoatId: testBoatID, Name: testBoatName},
+				SeatsAvailable: seatsAvailable,
+			},
+		}
+	}
+
+	// Make new channels and a new cache in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, 2*broadcasts)
+	originalCache := safeBoatStatusCache
+	defer func() { safeBoatStatusCache = originalCache }()
+	safeBoatStatusCache = NewBoatStatusCache()
+	deliverBoatStatus(newBoatStatus(0))
+	<-WebSocketServerConn.Write
+
+	// Broadcast newer statuses while snapshots are sent to connecting clients
+	var wg sync.WaitGroup
+	for range broadcasts {
+		wg.Go(func() { SendSnapshotToClient(testClientConnectionName) })
+	}
+	for seatsAvailable := range broadcasts {
+		deliverBoatStatus(newBoatStatus(seatsAvailable + 1))
+	}
+	wg.Wait()
+
+	// No snapshot may hold an older status than a status that was broadcast
+	// before it
+	var broadcastSeats int32
+	for len(WebSocketServerConn.Write) > 0 {
+		adapterMsg := <-WebSocketServerConn.Write
+		var status a.BoatStatusAPIMessage
+		err := json.Unmarshal(adapterMsg.MessageBytes, &status)
+		if err != nil {
+			t.Fatalf("Error unmarshaling the delivered message: %v", err)
+		}
+		if adapterMsg.ClientConnName == wss.WSSServerAllClientsConnName {
+			broadcastSeats = status.SeatsAvailable
+			continue
+		}
+		if status.SeatsAvailable < broadcastSeats {
+			t.Fatalf("Expected a snapshot with seats available of at least %d after the broadcast but received %d",
+				broadcastSeats, status.SeatsAvailable)
+		}
+	}
+	if broadcastSeats != broadcasts {
+		t.Fatalf("Expected the last status to be broadcast with seats available %d but received %d",
+			broadcasts, broadcastSeats)
+	}
+
+	// The cache is not locked while a snapshot waits for the write channel
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage)
+	go SendSnapshotToClient(testClientConnectionName)
+	stored := make(chan struct{})
+	go func() {
+		safeBoatStatusCache.Store(testBoatID, testBoatStatusAPIMessageBytes)
+		close(stored)
+	}()
+	select {
+	case <-stored:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the status to be stored while a snapshot waits for the write channel")
+	}
+	<-WebSocketServerConn.Write
+}
+
 func TestProcessArrivedMessageFromMockLogic(t *testing.T) {
 	type testCase struct {
 		name                   string
@@ -706,92 +1138,261 @@ func TestProcessArrivedMessageFromMockLogic(t *testing.T) {
 	}
 }
 
-func TestSendingMessageToMockLogicWithGRPC(t *testing.T) {
+func TestProcessTripProgressMessageFromMockLogic(t *testing.T) {
 	type testCase struct {
 		name                   string
-		expectedMessage        a.ReserveTripAPIMessage
+		expectedMessage        any
 		expectedClientConnName string
 	}
 
 	// Create test cases
 	cases := []testCase{
 		{
-			name:                   "Sending Message To MockLogic With GRPC - ReserveTripAPIMessage",
-			expectedMessage:        testReserveTripAPIMessage,
+			name:                   "ProcessTripProgressMessageFromMockLogic",
+			expectedMessage:        testTripProgressAdapterMessage,
 			expectedClientConnName: testClientConnectionName,
 		},
 	}
 
-	// Start gRPC server in the context of this test
-	go InitializeGRPCServer()
-
-	gRPCChannel := make(chan pb.ReserveTripMessage)
-
-	// Connect a mock gRPC client
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", 8090), opts...)
-	if err != nil {
-		Logger.Error().Msgf("Fail to dial gRPC: %v", err)
-	}
-	client := pb.NewAdapterClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	stream, err := client.ReserveTrip(ctx)
-	if err != nil {
-		Logger.Error().Msgf("client.Reserve failed to create stream: %v", err)
-	}
-
-	go func() {
-		for {
-			in, err := stream.Recv()
-			if err == io.EOF {
-				// read done.
-				return
-			}
-			if err != nil {
-				Logger.Error().Msgf("client.Reserve stream.Recv() returned an error: %v", err)
-				stream.CloseSend()
-				return
-			}
-			gRPCChannel <- *in
-		}
-	}()
+	// Make new channels in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
 
 	for _, testCase := range cases {
-		reserveMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
-			a.ConnectionTypeWebSocket, a.APIMessageTypeReserveTrip, testReserveTripAPIMessageBytes)
-
-		ProcessMessageToMockLogic(&reserveMockLogicMsg)
-
-		recdReserveGRPCMsg := <-gRPCChannel
+		tripProgressMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeTripProgress, testTripProgressAPIMessageBytes)
 
-		Logger.Info().Msgf("Message appeared on gRPCChannel: %+v", recdReserveGRPCMsg)
+		ProcessMessageFromMockLogic(&tripProgressMockLogicMsg)
 
-		recdSourceDockAddress := a.NewAddress(recdReserveGRPCMsg.ApiMessage.SourceDock.Address.Number,
-			recdReserveGRPCMsg.ApiMessage.SourceDock.Address.Street)
-		recdSourceDock := a.NewDock(recdSourceDockAddress,
-			recdReserveGRPCMsg.ApiMessage.SourceDock.Gangway)
-		recdDestDockAddress := a.NewAddress(recdReserveGRPCMsg.ApiMessage.DestinationDock.Address.Number,
-			recdReserveGRPCMsg.ApiMessage.DestinationDock.Address.Street)
-		recdDestDock := a.NewDock(recdDestDockAddress,
-			recdReserveGRPCMsg.ApiMessage.DestinationDock.Gangway)
+		Logger.Info().Msg("Called ProcessMessageFromMockLogic")
 
-		recdReserveTripAPIMessage := a.NewReserveTripAPIMessage(recdReserveGRPCMsg.ApiMessage.MessageType,
-			recdReserveGRPCMsg.ApiMessage.AuthToken, recdReserveGRPCMsg.ApiMessage.ClientId,
-			recdSourceDock, recdDestDock)
+		tripProgressAdapterMsg := <-WebSocketServerConn.Write
 
-		if recdReserveTripAPIMessage != testCase.expectedMessage {
-			t.Fatalf("Expected ReserveTripAPIMessage %+v but received %+v in test case: %s",
-				testCase.expectedMessage, recdReserveTripAPIMessage, testCase.name)
+		if tripProgressAdapterMsg.ClientConnName != testCase.expectedMessage.(wss.AdapterMessage).ClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedMessage.(wss.AdapterMessage).ClientConnName,
+				tripProgressAdapterMsg.ClientConnName, testCase.name)
 		}
 
-		if recdReserveGRPCMsg.ClientData.ConnName != testCase.expectedClientConnName {
-			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
-				testCase.expectedClientConnName, recdReserveGRPCMsg.ClientData.ConnName, testCase.name)
+		if !bytes.Equal(tripProgressAdapterMsg.MessageBytes, testCase.expectedMessage.(wss.AdapterMessage).MessageBytes) {
+			t.Fatalf("Expected message bytes %s but received %s in test case: %s",
+				string(testCase.expectedMessage.(wss.AdapterMessage).MessageBytes), string(tripProgressAdapterMsg.MessageBytes),
+				testCase.name)
 		}
 	}
+}
 
-	Logger.Info().Msg("TestSendingMessageToMockLogicWithGRPC cleaning up")
+func TestProcessErrorMessageFromMockLogic(t *testing.T) {
+	type testCase struct {
+		name                   string
+		expectedMessage        any
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "ProcessErrorMessageFromMockLogic",
+			expectedMessage:        testErrorAdapterMessage,
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make new channels in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+
+	for _, testCase := range cases {
+		errorMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeError, testErrorAPIMessageBytes)
+
+		ProcessMessageFromMockLogic(&errorMockLogicMsg)
+
+		Logger.Info().Msg("Called ProcessMessageFromMockLogic")
+
+		errorAdapterMsg := <-WebSocketServerConn.Write
+
+		if errorAdapterMsg.ClientConnName != testCase.expectedClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedClientConnName, errorAdapterMsg.ClientConnName, testCase.name)
+		}
+
+		if !bytes.Equal(errorAdapterMsg.MessageBytes, testCase.expectedMessage.(wss.AdapterMessage).MessageBytes) {
+			t.Fatalf("Expected message bytes %s but received %s in test case: %s",
+				string(testCase.expectedMessage.(wss.AdapterMessage).MessageBytes), string(errorAdapterMsg.MessageBytes),
+				testCase.name)
+		}
+	}
+}
+
+func TestSendErrorToClient(t *testing.T) {
+	type testCase struct {
+		name                        string
+		messageType                 string
+		apiMessageBytes             []byte
+		expectedReasonCode          int32
+		expectedRejectedMessageType string
+		expectedClientID            string
+		expectedTransactionID       string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                        "UnknownMessageType",
+			messageType:                 "cancelEverything",
+			apiMessageBytes:             []byte(`{"MessageType":"cancelEverything","ClientID":"Client1","TransactionID":"TX1"}`),
+			expectedReasonCode:          a.ErrorReasonUnknownMessageType,
+			expectedRejectedMessageType: "cancelEverything",
+			expectedClientID:            "Client1",
+			expectedTransactionID:       "TX1",
+		},
+		{
+			name:                        "InvalidAtDockMessage",
+			messageType:                 a.APIMessageTypeAtDock,
+			apiMessageBytes:             []byte(`{"MessageType":"atDock","ClientID":"Client1","Boat":"not a boat"}`),
+			expectedReasonCode:          a.ErrorReasonInvalidMessage,
+			expectedRejectedMessageType: a.APIMessageTypeAtDock,
+			expectedClientID:            "Client1",
+		},
+		{
+			name:                        "MalformedMessage",
+			messageType:                 a.APIMessageTypeOnBoat,
+			apiMessageBytes:             []byte(`{"MessageType":"onBoat",`),
+			expectedReasonCode:          a.ErrorReasonInvalidMessage,
+			expectedRejectedMessageType: a.APIMessageTypeOnBoat,
+		},
+	}
+
+	// Make new channels in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+
+	for _, testCase := range cases {
+		mockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, testCase.messageType, testCase.apiMessageBytes)
+
+		ProcessMessageToMockLogic(&mockLogicMsg)
+
+		errorAdapterMsg := <-WebSocketServerConn.Write
+
+		if errorAdapterMsg.ClientConnName != testClientConnectionName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testClientConnectionName, errorAdapterMsg.ClientConnName, testCase.name)
+		}
+
+		var errorAPIMsg a.ErrorAPIMessage
+		err := json.Unmarshal(errorAdapterMsg.MessageBytes, &errorAPIMsg)
+		if err != nil {
+			t.Fatalf("Error unmarshaling ErrorAPIMessage: %s in test case: %s",
+				err.Error(), testCase.name)
+		}
+
+		if errorAPIMsg.MessageType != a.APIMessageTypeError {
+			t.Fatalf("Expected MessageType %s but received %s in test case: %s",
+				a.APIMessageTypeError, errorAPIMsg.MessageType, testCase.name)
+		}
+
+		if errorAPIMsg.ReasonCode != testCase.expectedReasonCode {
+			t.Fatalf("Expected ReasonCode %d but received %d in test case: %s",
+				testCase.expectedReasonCode, errorAPIMsg.ReasonCode, testCase.name)
+		}
+
+		if errorAPIMsg.RejectedMessageType != testCase.expectedRejectedMessageType {
+			t.Fatalf("Expected RejectedMessageType %s but received %s in test case: %s",
+				testCase.expectedRejectedMessageType, errorAPIMsg.RejectedMessageType, testCase.name)
+		}
+
+		if errorAPIMsg.ClientID != testCase.expectedClientID {
+			t.Fatalf("Expected ClientID %s but received %s in test case: %s",
+				testCase.expectedClientID, errorAPIMsg.ClientID, testCase.name)
+		}
+
+		if errorAPIMsg.TransactionID != testCase.expectedTransactionID {
+			t.Fatalf("Expected TransactionID %s but received %s in test case: %s",
+				testCase.expectedTransactionID, errorAPIMsg.TransactionID, testCase.name)
+		}
+	}
+}
+
+func TestSendingMessageToMockLogicWithGRPC(t *testing.T) {
+	type testCase struct {
+		name                   string
+		expectedMessage        a.ReserveTripAPIMessage
+		expectedClientConnName string
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:                   "Sending Message To MockLogic With GRPC - ReserveTripAPIMessage",
+			expectedMessage:        testReserveTripAPIMessage,
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Start gRPC server in the context of this test
+	go InitializeGRPCServer()
+
+	gRPCChannel := make(chan *pb.ReserveTripMessage)
+
+	// Connect a mock gRPC client
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", 8090), opts...)
+	if err != nil {
+		Logger.Error().Msgf("Fail to dial gRPC: %v", err)
+	}
+	client := pb.NewAdapterClient(conn)
+	completeTestHandshake(t, client)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	stream, err := client.ReserveTrip(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.Reserve failed to create stream: %v", err)
+	}
+
+	go func() {
+		for {
+			in, err := stream.Recv()
+			if err == io.EOF {
+				// read done.
+				return
+			}
+			if err != nil {
+				Logger.Error().Msgf("client.Reserve stream.Recv() returned an error: %v", err)
+				stream.CloseSend()
+				return
+			}
+			gRPCChannel <- in
+		}
+	}()
+
+	for _, testCase := range cases {
+		reserveMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeReserveTrip, testReserveTripAPIMessageBytes)
+
+		ProcessMessageToMockLogic(&reserveMockLogicMsg)
+
+		recdReserveGRPCMsg := <-gRPCChannel
+
+		Logger.Info().Msgf("Message appeared on gRPCChannel: %+v", recdReserveGRPCMsg)
+
+		recdReserveTripMsg, err := a.FromGRPC[a.ReserveTripMockLogicMessage](recdReserveGRPCMsg)
+		if err != nil {
+			t.Fatalf("Expected no error converting ReserveTripMessage but received %v in test case: %s",
+				err, testCase.name)
+		}
+		recdReserveTripAPIMessage := recdReserveTripMsg.APIMessage
+
+		if !reflect.DeepEqual(recdReserveTripAPIMessage, testCase.expectedMessage) {
+			t.Fatalf("Expected ReserveTripAPIMessage %+v but received %+v in test case: %s",
+				testCase.expectedMessage, recdReserveTripAPIMessage, testCase.name)
+		}
+
+		if recdReserveGRPCMsg.ClientData.ConnName != testCase.expectedClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedClientConnName, recdReserveGRPCMsg.ClientData.ConnName, testCase.name)
+		}
+	}
+
+	Logger.Info().Msg("TestSendingMessageToMockLogicWithGRPC cleaning up")
 	stream.CloseSend()
 	conn.Close()
 	GRPCServer.Stop()
@@ -820,11 +1421,14 @@ func TestReceivingMessageFromMockLogicWithGRPC(t *testing.T) {
 		Name:   testBoatName,
 	}
 	ackAPIMessageGRPC := pb.AckAPIMessage{
-		MessageType:   a.APIMessageTypeAck,
-		ClientId:      testClientID,
-		IsReserved:    true,
-		Boat:          &ackBoatGRPC,
-		TransactionId: testTransactionID,
+		MessageType:    a.APIMessageTypeAck,
+		ClientId:       testClientID,
+		IsReserved:     true,
+		Boat:           &ackBoatGRPC,
+		TransactionId:  testTransactionID,
+		SourceEta:      timestamppb.New(testSourceETA),
+		DestinationEta: timestamppb.New(testDestETA),
+		DepartureTime:  timestamppb.New(testDepartureTime),
 	}
 	ackClientDataGRPC := pb.ClientData{
 		ConnName: testClientConnectionName,
@@ -847,6 +1451,7 @@ func TestReceivingMessageFromMockLogicWithGRPC(t *testing.T) {
 	}
 	defer conn.Close()
 	client := pb.NewAdapterClient(conn)
+	completeTestHandshake(t, client)
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	stream, err := client.Ack(ctx)
 	if err != nil {
@@ -857,7 +1462,7 @@ func TestReceivingMessageFromMockLogicWithGRPC(t *testing.T) {
 		// Wait 200 milliseconds and send the message
 		time.Sleep(200 * time.Millisecond)
 		if err := stream.Send(&ackMessageGRPC); err != nil {
-			Logger.Error().Msgf("client.Ack: stream.Send(%+v) failed: %v", ackMessageGRPC, err)
+			Logger.Error().Msgf("client.Ack: stream.Send(%+v) failed: %v", &ackMessageGRPC, err)
 		}
 	}()
 
@@ -888,3 +1493,1412 @@ func TestReceivingMessageFromMockLogicWithGRPC(t *testing.T) {
 	GRPCServer.Stop()
 	cancel()
 }
+
+func TestOutbox(t *testing.T) {
+	type testCase struct {
+		name              string
+		pushed            int
+		acknowledgeEpoch  bool
+		acknowledged      uint64
+		expectedSequences []uint64
+		expectedPushErr   error
+		pendingAfter      uint64
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:              "Outbox - Nothing acknowledged",
+			pushed:            3,
+			acknowledgeEpoch:  true,
+			acknowledged:      0,
+			expectedSequences: []uint64{1, 2, 3},
+		},
+		{
+			name:              "Outbox - Acknowledged a sequence",
+			pushed:            3,
+			acknowledgeEpoch:  true,
+			acknowledged:      2,
+			expectedSequences: []uint64{1, 3},
+		},
+		{
+			name:              "Outbox - Acknowledged from another epoch",
+			pushed:            3,
+			acknowledgeEpoch:  false,
+			acknowledged:      3,
+			expectedSequences: []uint64{1, 2, 3},
+		},
+		{
+			name:              "Outbox - Pending after a sequence",
+			pushed:            3,
+			acknowledgeEpoch:  true,
+			acknowledged:      1,
+			pendingAfter:      2,
+			expectedSequences: []uint64{3},
+		},
+		{
+			name:              "Outbox - Full",
+			pushed:            4,
+			acknowledgeEpoch:  true,
+			acknowledged:      0,
+			expectedSequences: []uint64{1, 2, 3},
+			expectedPushErr:   a.ErrOutboxFull,
+		},
+	}
+
+	for _, testCase := range cases {
+		outbox := a.NewOutbox[string](3)
+
+		var pushErr error
+		for i := range testCase.pushed {
+			_, pushErr = outbox.Push(fmt.Sprintf("message %d", i+1))
+		}
+		if pushErr != testCase.expectedPushErr {
+			t.Fatalf("Expected Push error %v but received %v in test case: %s",
+				testCase.expectedPushErr, pushErr, testCase.name)
+		}
+
+		epoch := outbox.Epoch()
+		if !testCase.acknowledgeEpoch {
+			epoch++
+		}
+		outbox.Acknowledge(epoch, testCase.acknowledged)
+
+		var sequences []uint64
+		for _, msg := range outbox.PendingAfter(testCase.pendingAfter) {
+			if msg.Epoch != outbox.Epoch() {
+				t.Fatalf("Expected epoch %d but received %d in test case: %s",
+					outbox.Epoch(), msg.Epoch, testCase.name)
+			}
+			if msg.Message != fmt.Sprintf("message %d", msg.Sequence) {
+				t.Fatalf("Expected message %d but received %q in test case: %s",
+					msg.Sequence, msg.Message, testCase.name)
+			}
+			sequences = append(sequences, msg.Sequence)
+		}
+
+		if !reflect.DeepEqual(sequences, testCase.expectedSequences) {
+			t.Fatalf("Expected pending sequences %v but received %v in test case: %s",
+				testCase.expectedSequences, sequences, testCase.name)
+		}
+	}
+}
+
+func TestOutboxPushLatest(t *testing.T) {
+	type testCase struct {
+		name             string
+		push             func(outbox *a.Outbox[string]) error
+		expectedPushErr  error
+		expectedMessages []string
+	}
+
+	// The test cases are run in order against the same outbox
+	cases := []testCase{
+		{
+			name: "Outbox push latest - Messages with different keys",
+			push: func(outbox *a.Outbox[string]) error {
+				_, err := outbox.PushLatest("boat 1", "boat 1 status 1")
+				if err != nil {
+					return err
+				}
+				_, err = outbox.PushLatest("boat 2", "boat 2 status 1")
+				return err
+			},
+			expectedMessages: []string{"boat 1 status 1", "boat 2 status 1"},
+		},
+		{
+			name: "Outbox push latest - Message replaces the message with the same key",
+			push: func(outbox *a.Outbox[string]) error {
+				_, err := outbox.PushLatest("boat 1", "boat 1 status 2")
+				return err
+			},
+			expectedMessages: []string{"boat 2 status 1", "boat 1 status 2"},
+		},
+		{
+			name: "Outbox push latest - Message without a key fills the outbox",
+			push: func(outbox *a.Outbox[string]) error {
+				_, err := outbox.Push("ack 1")
+				return err
+			},
+			expectedMessages: []string{"boat 2 status 1", "boat 1 status 2", "ack 1"},
+		},
+		{
+			name: "Outbox push latest - Message without a key removes the oldest message with a key",
+			push: func(outbox *a.Outbox[string]) error {
+				_, err := outbox.Push("ack 2")
+				if err != nil {
+					return err
+				}
+				_, err = outbox.Push("ack 3")
+				return err
+			},
+			expectedMessages: []string{"ack 1", "ack 2", "ack 3"},
+		},
+		{
+			name: "Outbox push latest - Full of messages without a key",
+			push: func(outbox *a.Outbox[string]) error {
+				_, err := outbox.PushLatest("boat 1", "boat 1 status 3")
+				return err
+			},
+			expectedPushErr:  a.ErrOutboxFull,
+			expectedMessages: []string{"ack 1", "ack 2", "ack 3"},
+		},
+		{
+			name: "Outbox push latest - Message with a key after an acknowledgement",
+			push: func(outbox *a.Outbox[string]) error {
+				outbox.Acknowledge(outbox.Epoch(), outbox.PendingAfter(0)[0].Sequence)
+				_, err := outbox.PushLatest("boat 1", "boat 1 status 3")
+				return err
+			},
+			expectedMessages: []string{"ack 2", "ack 3", "boat 1 status 3"},
+		},
+	}
+
+	outbox := a.NewOutbox[string](3)
+	for _, testCase := range cases {
+		err := testCase.push(outbox)
+		if err != testCase.expectedPushErr {
+			t.Fatalf("Expected push error %v but received %v in test case: %s",
+				testCase.expectedPushErr, err, testCase.name)
+		}
+
+		var messages []string
+		var sequence uint64
+		for _, msg := range outbox.PendingAfter(0) {
+			if msg.Sequence <= sequence {
+				t.Fatalf("Expected sequences in order but received %d after %d in test case: %s",
+					msg.Sequence, sequence, testCase.name)
+			}
+			sequence = msg.Sequence
+			messages = append(messages, msg.Message)
+		}
+		if !reflect.DeepEqual(messages, testCase.expectedMessages) {
+			t.Fatalf("Expected pending messages %v but received %v in test case: %s",
+				testCase.expectedMessages, messages, testCase.name)
+		}
+	}
+}
+
+func TestGRPCConversion(t *testing.T) {
+	type testCase struct {
+		name  string
+		check func(t *testing.T, r *rand.Rand, name string)
+	}
+
+	cases := []testCase{
+		{name: "Conversion - ReserveTrip", check: checkConversionRoundTrip[a.ReserveTripMockLogicMessage, *pb.ReserveTripMessage]},
+		{name: "Conversion - Ack", check: checkConversionRoundTrip[a.AckMockLogicMessage, *pb.AckMessage]},
+		{name: "Conversion - AtDock", check: checkConversionRoundTrip[a.AtDockMockLogicMessage, *pb.AtDockMessage]},
+		{name: "Conversion - OnBoat", check: checkConversionRoundTrip[a.OnBoatMockLogicMessage, *pb.OnBoatMessage]},
+		{name: "Conversion - OffBoat", check: checkConversionRoundTrip[a.OffBoatMockLogicMessage, *pb.OffBoatMessage]},
+		{name: "Conversion - CancelTrip", check: checkConversionRoundTrip[a.CancelTripMockLogicMessage, *pb.CancelTripMessage]},
+		{name: "Conversion - CancelAck", check: checkConversionRoundTrip[a.CancelAckMockLogicMessage, *pb.CancelAckMessage]},
+		{name: "Conversion - GetTrip", check: checkConversionRoundTrip[a.GetTripMockLogicMessage, *pb.GetTripMessage]},
+		{name: "Conversion - TripStatus", check: checkConversionRoundTrip[a.TripStatusMockLogicMessage, *pb.TripStatusMessage]},
+		{name: "Conversion - BoatStatus", check: checkConversionRoundTrip[a.BoatStatusMockLogicMessage, *pb.BoatStatusMessage]},
+		{name: "Conversion - Arrived", check: checkConversionRoundTrip[a.ArrivedMockLogicMessage, *pb.ArrivedMessage]},
+		{name: "Conversion - TripProgress", check: checkConversionRoundTrip[a.TripProgressMockLogicMessage, *pb.TripProgressMessage]},
+		{name: "Conversion - Error", check: checkConversionRoundTrip[a.ErrorMockLogicMessage, *pb.ErrorMessage]},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, testCase := range cases {
+		testCase.check(t, r, testCase.name)
+	}
+}
+
+func TestGRPCConversionMismatch(t *testing.T) {
+	type testCase struct {
+		name string
+		msg  any
+	}
+
+	cases := []testCase{
+		{
+			name: "Conversion mismatch - Field without a match",
+			msg: struct {
+				BoatID int32
+				Color  string
+			}{BoatID: 1, Color: "red"},
+		},
+		{
+			name: "Conversion mismatch - Field of another kind",
+			msg: struct {
+				BoatID string
+			}{BoatID: "1"},
+		},
+		{
+			name: "Conversion mismatch - Not a struct",
+			msg:  "boat",
+		},
+	}
+
+	for _, testCase := range cases {
+		_, err := a.ToGRPC[*pb.Boat](testCase.msg)
+		if err == nil {
+			t.Fatalf("Expected an error converting %+v to gRPC in test case: %s",
+				testCase.msg, testCase.name)
+		}
+	}
+
+	_, err := a.FromGRPC[struct{ Color string }](&pb.Boat{BoatId: 1})
+	if err == nil {
+		t.Fatalf("Expected an error converting a pb.Boat to a struct without a match")
+	}
+}
+
+func TestDeliveringMessagesWithMissingFieldsFromMockLogic(t *testing.T) {
+	type testCase struct {
+		name                   string
+		deliver                func()
+		expectedAPIMessage     any
+		expectedClientConnName string
+	}
+
+	cases := []testCase{
+		{
+			name: "Missing fields - Arrived without a boat or dock",
+			deliver: func() {
+				deliverArrived(&pb.ArrivedMessage{
+					ApiMessage: &pb.ArrivedAPIMessage{ClientId: testClientID, TransactionId: "trip"},
+					ClientData: &pb.ClientData{ConnName: testClientConnectionName,
+						ConnType: a.ConnectionTypeWebSocket},
+				})
+			},
+			expectedAPIMessage: a.NewArrivedAPIMessage(a.APIMessageTypeArrived, testClientID,
+				a.Boat{}, a.Dock{}, "trip"),
+			expectedClientConnName: testClientConnectionName,
+		},
+		{
+			name: "Missing fields - BoatStatus without docks or client data",
+			deliver: func() {
+				deliverBoatStatus(&pb.BoatStatusMessage{
+					ApiMessage: &pb.BoatStatusAPIMessage{Boat: &pb.Boat{BoatId: 4, Name: "Boat 4"}},
+				})
+			},
+			expectedAPIMessage: a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus,
+				a.NewBoat(4, "Boat 4"), a.ServiceStateUnknown, a.Dock{}, a.Dock{}, a.Dock{}, 0),
+			expectedClientConnName: wss.WSSServerAllClientsConnName,
+		},
+		{
+			name: "Missing fields - Ack without an API message",
+			deliver: func() {
+				deliverAck(&pb.AckMessage{
+					ClientData: &pb.ClientData{ConnName: testClientConnectionName,
+						ConnType: a.ConnectionTypeWebSocket},
+				})
+			},
+			expectedAPIMessage:     a.AckAPIMessage{MessageType: a.APIMessageTypeAck},
+			expectedClientConnName: testClientConnectionName,
+		},
+	}
+
+	// Make new channels and a new cache in the context of this test
+	WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+	originalCache := safeBoatStatusCache
+	defer func() { safeBoatStatusCache = originalCache }()
+	safeBoatStatusCache = NewBoatStatusCache()
+
+	for _, testCase := range cases {
+		testCase.deliver()
+
+		var adapterMsg wss.AdapterMessage
+		select {
+		case adapterMsg = <-WebSocketServerConn.Write:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected a message to be delivered in test case: %s", testCase.name)
+		}
+
+		if adapterMsg.ClientConnName != testCase.expectedClientConnName {
+			t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+				testCase.expectedClientConnName, adapterMsg.ClientConnName, testCase.name)
+		}
+		expectedBytes, err := json.Marshal(testCase.expectedAPIMessage)
+		if err != nil {
+			t.Fatalf("Error marshaling the expected message: %v in test case: %s", err, testCase.name)
+		}
+		if !bytes.Equal(adapterMsg.MessageBytes, expectedBytes) {
+			t.Fatalf("Expected message bytes %s but received %s in test case: %s",
+				string(expectedBytes), string(adapterMsg.MessageBytes), testCase.name)
+		}
+	}
+}
+
+func TestWireFormatConformsToSpec(t *testing.T) {
+	type testCase struct {
+		name        string
+		messageType string
+		msg         interface{ GetMessageType() string }
+	}
+
+	cases := []testCase{
+		{name: "Wire format - ReserveTrip", messageType: a.APIMessageTypeReserveTrip, msg: &a.ReserveTripAPIMessage{}},
+		{name: "Wire format - Ack", messageType: a.APIMessageTypeAck, msg: &a.AckAPIMessage{}},
+		{name: "Wire format - AtDock", messageType: a.APIMessageTypeAtDock, msg: &a.AtDockAPIMessage{}},
+		{name: "Wire format - OnBoat", messageType: a.APIMessageTypeOnBoat, msg: &a.OnBoatAPIMessage{}},
+		{name: "Wire format - OffBoat", messageType: a.APIMessageTypeOffBoat, msg: &a.OffBoatAPIMessage{}},
+		{name: "Wire format - BoatStatus", messageType: a.APIMessageTypeBoatStatus, msg: &a.BoatStatusAPIMessage{}},
+		{name: "Wire format - Arrived", messageType: a.APIMessageTypeArrived, msg: &a.ArrivedAPIMessage{}},
+		{name: "Wire format - TripProgress", messageType: a.APIMessageTypeTripProgress, msg: &a.TripProgressAPIMessage{}},
+		{name: "Wire format - CancelTrip", messageType: a.APIMessageTypeCancelTrip, msg: &a.CancelTripAPIMessage{}},
+		{name: "Wire format - CancelAck", messageType: a.APIMessageTypeCancelAck, msg: &a.CancelAckAPIMessage{}},
+		{name: "Wire format - GetTrip", messageType: a.APIMessageTypeGetTrip, msg: &a.GetTripAPIMessage{}},
+		{name: "Wire format - TripStatus", messageType: a.APIMessageTypeTripStatus, msg: &a.TripStatusAPIMessage{}},
+		{name: "Wire format - Subscribe", messageType: a.APIMessageTypeSubscribe, msg: &a.SubscribeAPIMessage{}},
+		{name: "Wire format - Unsubscribe", messageType: a.APIMessageTypeUnsubscribe, msg: &a.UnsubscribeAPIMessage{}},
+		{name: "Wire format - Subscriptions", messageType: a.APIMessageTypeSubscriptions, msg: &a.SubscriptionsAPIMessage{}},
+		{name: "Wire format - Error", messageType: a.APIMessageTypeError, msg: &a.ErrorAPIMessage{}},
+	}
+
+	spec := loadAPISpec(t)
+	specMessages := specObject(t, spec, "components", "messages")
+	if len(specMessages) != len(cases) {
+		t.Fatalf("Expected %d messages in the AsyncAPI spec but found %d",
+			len(cases), len(specMessages))
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, testCase := range cases {
+		payload := specObject(t, specMessages, testCase.messageType, "payload")
+		messageTypeConst := specObject(t, payload, "properties", "messageType")["const"]
+		if messageTypeConst != testCase.messageType || testCase.msg.GetMessageType() != testCase.messageType {
+			t.Fatalf("Expected the messageType %s but the spec has %v and the message has %s in test case: %s",
+				testCase.messageType, messageTypeConst, testCase.msg.GetMessageType(), testCase.name)
+		}
+
+		for range conversionRoundTrips {
+			randomAPIValue(r, reflect.ValueOf(testCase.msg).Elem())
+			msgBytes, err := json.Marshal(testCase.msg)
+			if err != nil {
+				t.Fatalf("Error marshaling JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+			var value any
+			err = json.Unmarshal(msgBytes, &value)
+			if err != nil {
+				t.Fatalf("Error unmarshaling JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+
+			checkSpecValue(t, spec, payload, value, testCase.messageType, testCase.name)
+		}
+	}
+}
+
+func TestDecodingLegacyWireFormat(t *testing.T) {
+	type testCase struct {
+		name string
+		msg  any
+	}
+
+	cases := []testCase{
+		{name: "Legacy wire format - ReserveTrip", msg: &a.ReserveTripAPIMessage{}},
+		{name: "Legacy wire format - AtDock", msg: &a.AtDockAPIMessage{}},
+		{name: "Legacy wire format - OnBoat", msg: &a.OnBoatAPIMessage{}},
+		{name: "Legacy wire format - OffBoat", msg: &a.OffBoatAPIMessage{}},
+		{name: "Legacy wire format - CancelTrip", msg: &a.CancelTripAPIMessage{}},
+		{name: "Legacy wire format - GetTrip", msg: &a.GetTripAPIMessage{}},
+		{name: "Legacy wire format - Subscribe", msg: &a.SubscribeAPIMessage{}},
+		{name: "Legacy wire format - Unsubscribe", msg: &a.UnsubscribeAPIMessage{}},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, testCase := range cases {
+		msgValue := reflect.ValueOf(testCase.msg).Elem()
+		for range conversionRoundTrips {
+			randomAPIValue(r, msgValue)
+			msgBytes, err := json.Marshal(testCase.msg)
+			if err != nil {
+				t.Fatalf("Error marshaling JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+			var value any
+			err = json.Unmarshal(msgBytes, &value)
+			if err != nil {
+				t.Fatalf("Error unmarshaling JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+			legacyBytes, err := json.Marshal(toLegacyKeys(value))
+			if err != nil {
+				t.Fatalf("Error marshaling legacy JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+
+			if a.HasLegacyKeys(msgBytes) {
+				t.Fatalf("Expected no legacy keys in %s in test case: %s", string(msgBytes), testCase.name)
+			}
+			if !a.HasLegacyKeys(legacyBytes) {
+				t.Fatalf("Expected legacy keys in %s in test case: %s", string(legacyBytes), testCase.name)
+			}
+
+			expectedType := msgValue.FieldByName("MessageType").String()
+			for _, data := range [][]byte{msgBytes, legacyBytes} {
+				messageType, err := a.DecodeMessageType(data)
+				if err != nil || messageType != expectedType {
+					t.Fatalf("Expected message type %s but received %s with error %v from %s in test case: %s",
+						expectedType, messageType, err, string(data), testCase.name)
+				}
+			}
+
+			decoded := reflect.New(msgValue.Type())
+			err = json.Unmarshal(legacyBytes, decoded.Interface())
+			if err != nil {
+				t.Fatalf("Error unmarshaling legacy JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+			expected := reflect.New(msgValue.Type())
+			err = json.Unmarshal(msgBytes, expected.Interface())
+			if err != nil {
+				t.Fatalf("Error unmarshaling JSON: %s in test case: %s", err.Error(), testCase.name)
+			}
+			if !reflect.DeepEqual(decoded.Interface(), expected.Interface()) {
+				t.Fatalf("Expected %+v but received %+v from %s in test case: %s",
+					expected.Elem(), decoded.Elem(), string(legacyBytes), testCase.name)
+			}
+		}
+	}
+}
+
+func TestInbox(t *testing.T) {
+	type received struct {
+		stream   string
+		epoch    uint64
+		sequence uint64
+	}
+	type testCase struct {
+		name             string
+		received         []received
+		expectedAccepted []bool
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name: "Inbox - Sequences in order",
+			received: []received{
+				{a.APIMessageTypeAck, 1, 1},
+				{a.APIMessageTypeAck, 1, 2},
+			},
+			expectedAccepted: []bool{true, true},
+		},
+		{
+			name: "Inbox - Duplicates",
+			received: []received{
+				{a.APIMessageTypeAck, 1, 1},
+				{a.APIMessageTypeAck, 1, 2},
+				{a.APIMessageTypeAck, 1, 1},
+				{a.APIMessageTypeAck, 1, 2},
+				{a.APIMessageTypeAck, 1, 3},
+			},
+			expectedAccepted: []bool{true, true, false, false, true},
+		},
+		{
+			name: "Inbox - New epoch",
+			received: []received{
+				{a.APIMessageTypeAck, 1, 1},
+				{a.APIMessageTypeAck, 1, 2},
+				{a.APIMessageTypeAck, 2, 1},
+			},
+			expectedAccepted: []bool{true, true, true},
+		},
+		{
+			name: "Inbox - Separate streams",
+			received: []received{
+				{a.APIMessageTypeAck, 1, 1},
+				{a.APIMessageTypeError, 1, 1},
+			},
+			expectedAccepted: []bool{true, true},
+		},
+		{
+			name: "Inbox - Without a sequence",
+			received: []received{
+				{a.APIMessageTypeAck, 0, 0},
+				{a.APIMessageTypeAck, 0, 0},
+			},
+			expectedAccepted: []bool{true, true},
+		},
+	}
+
+	for _, testCase := range cases {
+		inbox := a.NewInbox()
+
+		for i, msg := range testCase.received {
+			// A message that is accepted was not received before
+			received := inbox.Received(msg.stream, msg.epoch, msg.sequence)
+			if received == testCase.expectedAccepted[i] {
+				t.Fatalf("Expected received %t but received %t for message %d in test case: %s",
+					!testCase.expectedAccepted[i], received, i, testCase.name)
+			}
+			accepted := inbox.Accept(msg.stream, msg.epoch, msg.sequence)
+			if accepted != testCase.expectedAccepted[i] {
+				t.Fatalf("Expected accepted %t but received %t for message %d in test case: %s",
+					testCase.expectedAccepted[i], accepted, i, testCase.name)
+			}
+		}
+	}
+}
+
+// startTestGRPCServer starts an Adapter gRPC server on a free port and returns
+// a client connected to it
+func startTestGRPCServer(t *testing.T) pb.AdapterClient {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := NewGRPCServer()
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Fail to dial gRPC: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewAdapterClient(conn)
+}
+
+// completeTestHandshake completes the handshake of a compatible MockLogic with the
+// client, so that the streams of the client are served
+func completeTestHandshake(t *testing.T, client pb.AdapterClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	welcome, err := client.Handshake(ctx, &pb.Hello{
+		Component:       "MockLogic",
+		ProtocolVersion: a.ProtocolVersion,
+		MessageTypes:    a.ToMockLogicMessageTypes,
+	})
+	if err != nil || !welcome.GetIsAccepted() {
+		t.Fatalf("Expected the handshake to be accepted, got: %+v, %v", welcome, err)
+	}
+}
+
+func TestResendingMessagesToMockLogicAfterReconnect(t *testing.T) {
+	type testCase struct {
+		name              string
+		acknowledged      []uint64
+		expectedSequences []uint64
+	}
+
+	// Create test cases. Two messages are sent before the stream drops, and a
+	// third message is placed in the outbox after the MockLogic reconnects.
+	cases := []testCase{
+		{
+			name:              "Resending Messages To MockLogic After Reconnect - Nothing acknowledged",
+			acknowledged:      nil,
+			expectedSequences: []uint64{1, 2, 3},
+		},
+		{
+			name:              "Resending Messages To MockLogic After Reconnect - First acknowledged",
+			acknowledged:      []uint64{1},
+			expectedSequences: []uint64{2, 3},
+		},
+		{
+			name:              "Resending Messages To MockLogic After Reconnect - Second acknowledged",
+			acknowledged:      []uint64{2},
+			expectedSequences: []uint64{1, 3},
+		},
+		{
+			name:              "Resending Messages To MockLogic After Reconnect - All acknowledged",
+			acknowledged:      []uint64{1, 2},
+			expectedSequences: []uint64{3},
+		},
+	}
+
+	client := startTestGRPCServer(t)
+	completeTestHandshake(t, client)
+
+	for _, testCase := range cases {
+		// Make a new outbox in the context of this test
+		GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
+		outbox := GRPCOutbox
+
+		reserveMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+			a.ConnectionTypeWebSocket, a.APIMessageTypeReserveTrip, testReserveTripAPIMessageBytes)
+		ProcessMessageToMockLogic(&reserveMockLogicMsg)
+		ProcessMessageToMockLogic(&reserveMockLogicMsg)
+
+		// Receive both messages and drop the stream after acknowledging some
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		stream, err := client.ReserveTrip(ctx)
+		if err != nil {
+			t.Fatalf("client.ReserveTrip failed to create stream: %v in test case: %s", err, testCase.name)
+		}
+		for range 2 {
+			_, err = stream.Recv()
+			if err != nil {
+				t.Fatalf("client.ReserveTrip failed to receive: %v in test case: %s", err, testCase.name)
+			}
+		}
+		for _, sequence := range testCase.acknowledged {
+			err = stream.Send(&pb.Receipt{Epoch: outbox.Epoch(), Sequence: sequence})
+			if err != nil {
+				t.Fatalf("client.ReserveTrip failed to send Receipt: %v in test case: %s", err, testCase.name)
+			}
+		}
+		waitForOutboxLen(t, outbox, 2-len(testCase.acknowledged), testCase.name)
+		cancel()
+
+		// Reconnect and receive the messages that were not acknowledged
+		ctx, cancel = context.WithTimeout(context.Background(), 20*time.Second)
+		stream, err = client.ReserveTrip(ctx)
+		if err != nil {
+			t.Fatalf("client.ReserveTrip failed to create stream: %v in test case: %s", err, testCase.name)
+		}
+		ProcessMessageToMockLogic(&reserveMockLogicMsg)
+
+		for _, expectedSequence := range testCase.expectedSequences {
+			in, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("client.ReserveTrip failed to receive: %v in test case: %s", err, testCase.name)
+			}
+			if in.GetEpoch() != outbox.Epoch() || in.GetSequence() != expectedSequence {
+				t.Fatalf("Expected epoch %d and sequence %d but received %d and %d in test case: %s",
+					outbox.Epoch(), expectedSequence, in.GetEpoch(), in.GetSequence(), testCase.name)
+			}
+			if in.GetClientData().GetConnName() != testClientConnectionName {
+				t.Fatalf("Expected client conn name %s but received %s in test case: %s",
+					testClientConnectionName, in.GetClientData().GetConnName(), testCase.name)
+			}
+
+			err = stream.Send(&pb.Receipt{Epoch: in.GetEpoch(), Sequence: in.GetSequence()})
+			if err != nil {
+				t.Fatalf("client.ReserveTrip failed to send Receipt: %v in test case: %s", err, testCase.name)
+			}
+		}
+		waitForOutboxLen(t, outbox, 0, testCase.name)
+		cancel()
+	}
+}
+
+// waitForOutboxLen waits for the outbox to hold the given number of messages that
+// have not been acknowledged
+func waitForOutboxLen[T any](t *testing.T, outbox *a.Outbox[T], expectedLen int, name string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for outbox.Len() != expectedLen {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d messages in the outbox but there are %d in test case: %s",
+				expectedLen, outbox.Len(), name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDiscardingDuplicateMessagesFromMockLogic(t *testing.T) {
+	type sent struct {
+		epoch    uint64
+		sequence uint64
+	}
+	type testCase struct {
+		name              string
+		sent              []sent
+		expectedDelivered int
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:              "Discarding Duplicate Messages From MockLogic - No duplicates",
+			sent:              []sent{{1, 1}, {1, 2}},
+			expectedDelivered: 2,
+		},
+		{
+			name:              "Discarding Duplicate Messages From MockLogic - Resent after reconnect",
+			sent:              []sent{{1, 1}, {1, 2}, {1, 1}, {1, 2}, {1, 3}},
+			expectedDelivered: 3,
+		},
+		{
+			name:              "Discarding Duplicate Messages From MockLogic - MockLogic restarted",
+			sent:              []sent{{1, 1}, {1, 2}, {2, 1}},
+			expectedDelivered: 3,
+		},
+	}
+
+	client := startTestGRPCServer(t)
+	completeTestHandshake(t, client)
+
+	for _, testCase := range cases {
+		// Make new channels and a new inbox in the context of this test
+		WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+		MockLogicInbox = a.NewInbox()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		stream, err := client.Ack(ctx)
+		if err != nil {
+			t.Fatalf("client.Ack failed to create stream: %v in test case: %s", err, testCase.name)
+		}
+
+		for _, msg := range testCase.sent {
+			ackMessageGRPC := pb.AckMessage{
+				ApiMessage: &pb.AckAPIMessage{
+					MessageType:   a.APIMessageTypeAck,
+					ClientId:      testClientID,
+					IsReserved:    true,
+					Boat:          &pb.Boat{BoatId: testBoatID, Name: testBoatName},
+					TransactionId: testTransactionID,
+				},
+				ClientData: &pb.ClientData{
+					ConnName: testClientConnectionName,
+					ConnType: a.ConnectionTypeWebSocket,
+				},
+				Epoch:    msg.epoch,
+				Sequence: msg.sequence,
+			}
+			err = stream.Send(&ackMessageGRPC)
+			if err != nil {
+				t.Fatalf("client.Ack failed to send: %v in test case: %s", err, testCase.name)
+			}
+
+			// Every message is acknowledged, including the duplicates
+			receipt, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("client.Ack failed to receive Receipt: %v in test case: %s", err, testCase.name)
+			}
+			if receipt.GetEpoch() != msg.epoch || receipt.GetSequence() != msg.sequence {
+				t.Fatalf("Expected Receipt for epoch %d and sequence %d but received %d and %d in test case: %s",
+					msg.epoch, msg.sequence, receipt.GetEpoch(), receipt.GetSequence(), testCase.name)
+			}
+		}
+
+		for range testCase.expectedDelivered {
+			select {
+			case <-WebSocketServerConn.Write:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Expected %d messages to be delivered in test case: %s",
+					testCase.expectedDelivered, testCase.name)
+			}
+		}
+		select {
+		case <-WebSocketServerConn.Write:
+			t.Fatalf("Expected only %d messages to be delivered in test case: %s",
+				testCase.expectedDelivered, testCase.name)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		stream.CloseSend()
+		cancel()
+	}
+}
+
+// adapterEnvelopeMessageType returns the API message type of the message in an
+// AdapterEnvelope
+func adapterEnvelopeMessageType(envelope *pb.AdapterEnvelope) string {
+	switch msg := envelope.GetMessage().(type) {
+	case *pb.AdapterEnvelope_ReserveTrip:
+		return msg.ReserveTrip.GetApiMessage().GetMessageType()
+	case *pb.AdapterEnvelope_AtDock:
+		return msg.AtDock.GetApiMessage().GetMessageType()
+	case *pb.AdapterEnvelope_OnBoat:
+		return msg.OnBoat.GetApiMessage().GetMessageType()
+	case *pb.AdapterEnvelope_OffBoat:
+		return msg.OffBoat.GetApiMessage().GetMessageType()
+	case *pb.AdapterEnvelope_CancelTrip:
+		return msg.CancelTrip.GetApiMessage().GetMessageType()
+	case *pb.AdapterEnvelope_GetTrip:
+		return msg.GetTrip.GetApiMessage().GetMessageType()
+	}
+	return ""
+}
+
+func TestSendingMessagesToMockLogicOnSession(t *testing.T) {
+	type testCase struct {
+		name              string
+		acknowledged      []uint64
+		expectedSequences []uint64
+	}
+
+	// Create test cases. A message of each of three types is sent before the
+	// stream drops, and the messages that were not acknowledged are sent again
+	// after the MockLogic reconnects.
+	cases := []testCase{
+		{
+			name:              "Sending Messages To MockLogic On Session - Nothing acknowledged",
+			acknowledged:      nil,
+			expectedSequences: []uint64{1, 2, 3},
+		},
+		{
+			name:              "Sending Messages To MockLogic On Session - Second acknowledged",
+			acknowledged:      []uint64{2},
+			expectedSequences: []uint64{1, 3},
+		},
+		{
+			name:              "Sending Messages To MockLogic On Session - All acknowledged",
+			acknowledged:      []uint64{1, 2, 3},
+			expectedSequences: nil,
+		},
+	}
+
+	// The messages are sent in the order that the clients sent them, whatever
+	// their types
+	sentMessages := []MockLogicMessage{
+		NewMockLogicMessage(testClientConnectionName, a.ConnectionTypeWebSocket,
+			a.APIMessageTypeReserveTrip, testReserveTripAPIMessageBytes),
+		NewMockLogicMessage(testClientConnectionName, a.ConnectionTypeWebSocket,
+			a.APIMessageTypeAtDock, testAtDockAPIMessageBytes),
+		NewMockLogicMessage(testClientConnectionName, a.ConnectionTypeWebSocket,
+			a.APIMessageTypeGetTrip, testGetTripAPIMessageBytes),
+	}
+
+	client := startTestGRPCServer(t)
+	completeTestHandshake(t, client)
+
+	for _, testCase := range cases {
+		// Make a new outbox in the context of this test
+		GRPCOutbox = a.NewOutbox[any](GRPCOutboxCapacity)
+		outbox := GRPCOutbox
+
+		for i := range sentMessages {
+			ProcessMessageToMockLogic(&sentMessages[i])
+		}
+
+		// Receive every message and drop the stream after acknowledging some
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		stream, err := client.Session(ctx)
+		if err != nil {
+			t.Fatalf("client.Session failed to create stream: %v in test case: %s", err, testCase.name)
+		}
+		for i, sentMessage := range sentMessages {
+			envelope, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("client.Session failed to receive: %v in test case: %s", err, testCase.name)
+			}
+			if envelope.GetEpoch() != outbox.Epoch() || envelope.GetSequence() != uint64(i+1) ||
+				adapterEnvelopeMessageType(envelope) != sentMessage.MessageType {
+				t.Fatalf("Expected %s message with sequence %d but received %s message with sequence %d in test case: %s",
+					sentMessage.MessageType, i+1, adapterEnvelopeMessageType(envelope),
+					envelope.GetSequence(), testCase.name)
+			}
+		}
+		for _, sequence := range testCase.acknowledged {
+			err = stream.Send(&pb.MockLogicEnvelope{
+				Message: &pb.MockLogicEnvelope_Receipt{
+					Receipt: &pb.Receipt{Epoch: outbox.Epoch(), Sequence: sequence},
+				},
+			})
+			if err != nil {
+				t.Fatalf("client.Session failed to send Receipt: %v in test case: %s", err, testCase.name)
+			}
+		}
+		waitForOutboxLen(t, outbox, len(sentMessages)-len(testCase.acknowledged), testCase.name)
+		cancel()
+
+		// Reconnect and receive the messages that were not acknowledged
+		ctx, cancel = context.WithTimeout(context.Background(), 20*time.Second)
+		stream, err = client.Session(ctx)
+		if err != nil {
+			t.Fatalf("client.Session failed to create stream: %v in test case: %s", err, testCase.name)
+		}
+		for _, expectedSequence := range testCase.expectedSequences {
+			envelope, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("client.Session failed to receive: %v in test case: %s", err, testCase.name)
+			}
+			if envelope.GetSequence() != expectedSequence {
+				t.Fatalf("Expected sequence %d but received %d in test case: %s",
+					expectedSequence, envelope.GetSequence(), testCase.name)
+			}
+		}
+		cancel()
+	}
+}
+
+func TestReceivingMessagesFromMockLogicOnSession(t *testing.T) {
+	type sent struct {
+		epoch       uint64
+		sequence    uint64
+		messageType string
+	}
+	type testCase struct {
+		name              string
+		sent              []sent
+		expectedDelivered int
+	}
+
+	// The envelopes that are sent for each message type
+	envelopes := map[string]*pb.MockLogicEnvelope{
+		a.APIMessageTypeAck: {Message: &pb.MockLogicEnvelope_Ack{Ack: &pb.AckMessage{
+			ApiMessage: &pb.AckAPIMessage{
+				MessageType:   a.APIMessageTypeAck,
+				ClientId:      testClientID,
+				IsReserved:    true,
+				Boat:          &pb.Boat{BoatId: testBoatID, Name: testBoatName},
+				TransactionId: testTransactionID,
+			},
+			ClientData: &pb.ClientData{
+				ConnName: testClientConnectionName,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}}},
+		a.APIMessageTypeError: {Message: &pb.MockLogicEnvelope_Error{Error: &pb.ErrorMessage{
+			ApiMessage: &pb.ErrorAPIMessage{
+				MessageType:         a.APIMessageTypeError,
+				ClientId:            testClientID,
+				ReasonCode:          pb.ErrorReason_ERROR_REASON_TRIP_NOT_FOUND,
+				Reason:              "trip not found",
+				RejectedMessageType: a.APIMessageTypeAtDock,
+				TransactionId:       testTransactionID,
+			},
+			ClientData: &pb.ClientData{
+				ConnName: testClientConnectionName,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}}},
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name:              "Receiving Messages From MockLogic On Session - Types in one sequence",
+			sent:              []sent{{1, 1, a.APIMessageTypeAck}, {1, 2, a.APIMessageTypeError}},
+			expectedDelivered: 2,
+		},
+		{
+			name: "Receiving Messages From MockLogic On Session - Resent after reconnect",
+			sent: []sent{{1, 1, a.APIMessageTypeAck}, {1, 2, a.APIMessageTypeError},
+				{1, 1, a.APIMessageTypeAck}, {1, 2, a.APIMessageTypeError}, {1, 3, a.APIMessageTypeAck}},
+			expectedDelivered: 3,
+		},
+		{
+			name: "Receiving Messages From MockLogic On Session - MockLogic restarted",
+			sent: []sent{{1, 1, a.APIMessageTypeAck}, {1, 2, a.APIMessageTypeError},
+				{2, 1, a.APIMessageTypeAck}},
+			expectedDelivered: 3,
+		},
+	}
+
+	client := startTestGRPCServer(t)
+	completeTestHandshake(t, client)
+
+	for _, testCase := range cases {
+		// Make new channels and a new inbox in the context of this test
+		WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+		MockLogicInbox = a.NewInbox()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		stream, err := client.Session(ctx)
+		if err != nil {
+			t.Fatalf("client.Session failed to create stream: %v in test case: %s", err, testCase.name)
+		}
+
+		for _, msg := range testCase.sent {
+			err = stream.Send(&pb.MockLogicEnvelope{
+				Epoch:    msg.epoch,
+				Sequence: msg.sequence,
+				Message:  envelopes[msg.messageType].GetMessage(),
+			})
+			if err != nil {
+				t.Fatalf("client.Session failed to send: %v in test case: %s", err, testCase.name)
+			}
+
+			// Every message is acknowledged, including the duplicates
+			in, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("client.Session failed to receive Receipt: %v in test case: %s", err, testCase.name)
+			}
+			receipt := in.GetReceipt()
+			if receipt.GetEpoch() != msg.epoch || receipt.GetSequence() != msg.sequence {
+				t.Fatalf("Expected Receipt for epoch %d and sequence %d but received %+v in test case: %s",
+					msg.epoch, msg.sequence, in, testCase.name)
+			}
+		}
+
+		for range testCase.expectedDelivered {
+			select {
+			case <-WebSocketServerConn.Write:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Expected %d messages to be delivered in test case: %s",
+					testCase.expectedDelivered, testCase.name)
+			}
+		}
+		select {
+		case <-WebSocketServerConn.Write:
+			t.Fatalf("Expected only %d messages to be delivered in test case: %s",
+				testCase.expectedDelivered, testCase.name)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		stream.CloseSend()
+		cancel()
+	}
+}
+
+func TestDeliveringMessagesInOrderFromMockLogic(t *testing.T) {
+	// The messages that the MockLogic sends to one client, in order
+	sentMessages := []*pb.MockLogicEnvelope{
+		{Message: &pb.MockLogicEnvelope_Ack{Ack: &pb.AckMessage{
+			ApiMessage: &pb.AckAPIMessage{
+				MessageType:   a.APIMessageTypeAck,
+				ClientId:      testClientID,
+				IsReserved:    true,
+				Boat:          &pb.Boat{BoatId: testBoatID, Name: testBoatName},
+				TransactionId: testTransactionID,
+			},
+			ClientData: &pb.ClientData{
+				ConnName: testClientConnectionName,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}}},
+		{Message: &pb.MockLogicEnvelope_Arrived{Arrived: &pb.ArrivedMessage{
+			ApiMessage: &pb.ArrivedAPIMessage{
+				MessageType:   a.APIMessageTypeArrived,
+				ClientId:      testClientID,
+				Boat:          &pb.Boat{BoatId: testBoatID, Name: testBoatName},
+				TransactionId: testTransactionID,
+			},
+			ClientData: &pb.ClientData{
+				ConnName: testClientConnectionName,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}}},
+		{Message: &pb.MockLogicEnvelope_Error{Error: &pb.ErrorMessage{
+			ApiMessage: &pb.ErrorAPIMessage{
+				MessageType:         a.APIMessageTypeError,
+				ClientId:            testClientID,
+				ReasonCode:          pb.ErrorReason_ERROR_REASON_INVALID_TRIP_STATE,
+				Reason:              "the boat left the source dock before the party boarded",
+				RejectedMessageType: a.APIMessageTypeReserveTrip,
+				TransactionId:       testTransactionID,
+			},
+			ClientData: &pb.ClientData{
+				ConnName: testClientConnectionName,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}}},
+	}
+	expectedMessageTypes := []string{a.APIMessageTypeAck, a.APIMessageTypeArrived,
+		a.APIMessageTypeError}
+
+	// The messages are received several times, since messages that are delivered
+	// out of order may still arrive in order by chance
+	for round := range 20 {
+		// Make new channels and a new inbox in the context of this test
+		WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+		MockLogicInbox = a.NewInbox()
+
+		received := 0
+		recv := func() (*pb.MockLogicEnvelope, error) {
+			if received == len(sentMessages) {
+				return nil, io.EOF
+			}
+			envelope := sentMessages[received]
+			received++
+			return &pb.MockLogicEnvelope{
+				Epoch:    1,
+				Sequence: uint64(received),
+				Message:  envelope.GetMessage(),
+			}, nil
+		}
+		send := func(*pb.AdapterEnvelope) error { return nil }
+		receiveSession(recv, send, make(chan struct{}))
+
+		for _, expectedMessageType := range expectedMessageTypes {
+			var adapterMsg wss.AdapterMessage
+			select {
+			case adapterMsg = <-WebSocketServerConn.Write:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Expected %s message to be delivered in round %d", expectedMessageType, round)
+			}
+			messageType, err := a.DecodeMessageType(adapterMsg.MessageBytes)
+			if err != nil {
+				t.Fatalf("Error decoding the delivered message: %v in round %d", err, round)
+			}
+			if adapterMsg.ClientConnName != testClientConnectionName || messageType != expectedMessageType {
+				t.Fatalf("Expected %s message for %s but received %s message for %s in round %d",
+					expectedMessageType, testClientConnectionName, messageType,
+					adapterMsg.ClientConnName, round)
+			}
+		}
+	}
+}
+
+func TestReceiptAfterDeliveryFromMockLogic(t *testing.T) {
+	type testCase struct {
+		name              string
+		isConnected       bool
+		expectedReceipt   bool
+		expectedDelivered bool
+	}
+
+	// Create test cases. The same message is sent in each test case, as the
+	// MockLogic sends it again when it did not receive a Receipt.
+	cases := []testCase{
+		{
+			name:              "Receipt After Delivery From MockLogic - WebSocketServer not connected",
+			isConnected:       false,
+			expectedReceipt:   false,
+			expectedDelivered: false,
+		},
+		{
+			name:              "Receipt After Delivery From MockLogic - Sent again once connected",
+			isConnected:       true,
+			expectedReceipt:   true,
+			expectedDelivered: true,
+		},
+		{
+			name:              "Receipt After Delivery From MockLogic - Duplicate",
+			isConnected:       true,
+			expectedReceipt:   true,
+			expectedDelivered: false,
+		},
+	}
+
+	envelope := &pb.MockLogicEnvelope{
+		Epoch:    1,
+		Sequence: 1,
+		Message: &pb.MockLogicEnvelope_Ack{Ack: &pb.AckMessage{
+			ApiMessage: &pb.AckAPIMessage{
+				MessageType:   a.APIMessageTypeAck,
+				ClientId:      testClientID,
+				IsReserved:    true,
+				Boat:          &pb.Boat{BoatId: testBoatID, Name: testBoatName},
+				TransactionId: testTransactionID,
+			},
+			ClientData: &pb.ClientData{
+				ConnName: testClientConnectionName,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}},
+	}
+
+	// Make a new inbox in the context of this test
+	MockLogicInbox = a.NewInbox()
+	defer func() { WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize) }()
+
+	for _, testCase := range cases {
+		WebSocketServerConn.Write = nil
+		if testCase.isConnected {
+			WebSocketServerConn.Write = make(chan wss.AdapterMessage, WSChannelBufferSize)
+		}
+
+		isSent := false
+		recv := func() (*pb.MockLogicEnvelope, error) {
+			if isSent {
+				return nil, io.EOF
+			}
+			isSent = true
+			return envelope, nil
+		}
+		var receipts []*pb.Receipt
+		send := func(out *pb.AdapterEnvelope) error {
+			receipts = append(receipts, out.GetReceipt())
+			return nil
+		}
+		receiveSession(recv, send, make(chan struct{}))
+
+		if (len(receipts) == 1) != testCase.expectedReceipt {
+			t.Fatalf("Expected Receipt to be sent %t but %d were sent in test case: %s",
+				testCase.expectedReceipt, len(receipts), testCase.name)
+		}
+		if (len(WebSocketServerConn.Write) == 1) != testCase.expectedDelivered {
+			t.Fatalf("Expected message to be delivered %t but %d were delivered in test case: %s",
+				testCase.expectedDelivered, len(WebSocketServerConn.Write), testCase.name)
+		}
+	}
+}
+
+func TestCheckHello(t *testing.T) {
+	type testCase struct {
+		name          string
+		hello         *pb.Hello
+		expectedError bool
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name: "CheckHello - Compatible",
+			hello: &pb.Hello{
+				Component:       "MockLogic",
+				ProtocolVersion: a.ProtocolVersion,
+				MessageTypes:    a.ToMockLogicMessageTypes,
+			},
+			expectedError: false,
+		},
+		{
+			name: "CheckHello - Other protocol version",
+			hello: &pb.Hello{
+				Component:       "MockLogic",
+				ProtocolVersion: a.ProtocolVersion + 1,
+				MessageTypes:    a.ToMockLogicMessageTypes,
+			},
+			expectedError: true,
+		},
+		{
+			name: "CheckHello - Message type not handled",
+			hello: &pb.Hello{
+				Component:       "MockLogic",
+				ProtocolVersion: a.ProtocolVersion,
+				MessageTypes:    []string{a.APIMessageTypeReserveTrip, a.APIMessageTypeCancelTrip},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "CheckHello - Missing Hello",
+			hello:         nil,
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		err := CheckHello(testCase.hello)
+		if (err != nil) != testCase.expectedError {
+			t.Fatalf("Expected error: %t but received %v in test case: %s",
+				testCase.expectedError, err, testCase.name)
+		}
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	type testCase struct {
+		name             string
+		hello            *pb.Hello
+		expectedAccepted bool
+	}
+
+	// Create test cases. The MockLogic that is rejected is tried first, so that
+	// the Adapter must still be waiting for the handshake afterwards.
+	cases := []testCase{
+		{
+			name: "Handshake - Rejected",
+			hello: &pb.Hello{
+				Component:       "MockLogic",
+				ProtocolVersion: a.ProtocolVersion + 1,
+				MessageTypes:    a.ToMockLogicMessageTypes,
+			},
+			expectedAccepted: false,
+		},
+		{
+			name: "Handshake - Accepted",
+			hello: &pb.Hello{
+				Component:       "MockLogic",
+				ProtocolVersion: a.ProtocolVersion,
+				MessageTypes:    a.ToMockLogicMessageTypes,
+			},
+			expectedAccepted: true,
+		},
+	}
+
+	// Make a new handshake state and server in the context of this test
+	handshake := NewHandshakeState()
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := newGRPCServer(handshake)
+	go s.Serve(listener)
+	defer s.Stop()
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Fail to dial gRPC: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewAdapterClient(conn)
+
+	for _, testCase := range cases {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		welcome, err := client.Handshake(ctx, testCase.hello)
+		cancel()
+		if err != nil {
+			t.Fatalf("client.Handshake failed: %v in test case: %s", err, testCase.name)
+		}
+
+		if welcome.GetIsAccepted() != testCase.expectedAccepted {
+			t.Fatalf("Expected accepted %t but received %t with reason %q in test case: %s",
+				testCase.expectedAccepted, welcome.GetIsAccepted(), welcome.GetReason(), testCase.name)
+		}
+		if welcome.GetIsAccepted() == (welcome.GetReason() != "") {
+			t.Fatalf("Expected a reason only when rejected but received %q in test case: %s",
+				welcome.GetReason(), testCase.name)
+		}
+		if welcome.GetProtocolVersion() != a.ProtocolVersion ||
+			!reflect.DeepEqual(welcome.GetMessageTypes(), a.FromMockLogicMessageTypes) {
+			t.Fatalf("Expected protocol version %d and message types %v but received %+v in test case: %s",
+				a.ProtocolVersion, a.FromMockLogicMessageTypes, welcome, testCase.name)
+		}
+
+		select {
+		case <-handshake.Ready():
+			if !testCase.expectedAccepted {
+				t.Fatalf("Expected the Adapter to wait for the handshake in test case: %s", testCase.name)
+			}
+		default:
+			if testCase.expectedAccepted {
+				t.Fatalf("Expected the handshake to be complete in test case: %s", testCase.name)
+			}
+		}
+	}
+}
+
+func TestHandshakeRequiredForStreams(t *testing.T) {
+	// Make a new handshake state and server in the context of this test. The
+	// outbox is shared with the servers of the other tests, so it is not replaced.
+	handshake := NewHandshakeState()
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := newGRPCServer(handshake)
+	go s.Serve(listener)
+	defer s.Stop()
+	dial := func() (*grpc.ClientConn, pb.AdapterClient) {
+		conn, err := grpc.NewClient(listener.Addr().String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Fail to dial gRPC: %v", err)
+		}
+		return conn, pb.NewAdapterClient(conn)
+	}
+	// recvCode opens a Session stream and returns the code of the first receive
+	recvCode := func(client pb.AdapterClient) codes.Code {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		stream, err := client.Session(ctx)
+		if err != nil {
+			t.Fatalf("client.Session failed to create stream: %v", err)
+		}
+		_, err = stream.Recv()
+		return status.Code(err)
+	}
+
+	// The stream is rejected before the handshake
+	conn1, client1 := dial()
+	defer conn1.Close()
+	if code := recvCode(client1); code != codes.FailedPrecondition {
+		t.Fatalf("Expected stream to be rejected with %s before the handshake, got: %s",
+			codes.FailedPrecondition, code)
+	}
+
+	// The stream is served after the handshake
+	completeTestHandshake(t, client1)
+	reserveMockLogicMsg := NewMockLogicMessage(testClientConnectionName,
+		a.ConnectionTypeWebSocket, a.APIMessageTypeReserveTrip, testReserveTripAPIMessageBytes)
+	ProcessMessageToMockLogic(&reserveMockLogicMsg)
+	if code := recvCode(client1); code != codes.OK {
+		t.Fatalf("Expected stream to be served after the handshake, got: %s", code)
+	}
+
+	// The stream is rejected on another connection
+	conn2, client2 := dial()
+	defer conn2.Close()
+	if code := recvCode(client2); code != codes.FailedPrecondition {
+		t.Fatalf("Expected stream to be rejected with %s on another connection, got: %s",
+			codes.FailedPrecondition, code)
+	}
+
+	// The handshake is reset when the connection closes
+	handshake.mux.Lock()
+	handshakeConn := handshake.conn
+	handshake.mux.Unlock()
+	conn1.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for handshake.IsComplete(handshakeConn) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the handshake of connection: %d to be reset", handshakeConn)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A new connection does not inherit the handshake of the closed connection
+	conn3, client3 := dial()
+	defer conn3.Close()
+	if code := recvCode(client3); code != codes.FailedPrecondition {
+		t.Fatalf("Expected stream to be rejected with %s on a new connection, got: %s",
+			codes.FailedPrecondition, code)
+	}
+}
@@ -42,6 +42,22 @@ func WSServerReadLoop(wsServer *WebSocketServerConnnection) error {
 
 		messageType, adapterMessage, err := GetMessageFromWebSocketMsg(message)
 		if err != nil {
+			// The client can only be informed if the client connection name
+			// was decoded
+			if adapterMessage.ClientConnName != "" {
+				mlMsg := NewMockLogicMessage(adapterMessage.ClientConnName,
+					a.ConnectionTypeWebSocket, string(messageType), adapterMessage.MessageBytes)
+				SendErrorToClient(&mlMsg, a.ErrorReasonInvalidMessage,
+					"message could not be decoded: "+err.Error())
+			}
+			continue
+		}
+
+		// The WebSocketServer notifies the adapter when a client connects, so the
+		// client is sent the cached state rather than the message being sent to
+		// the MockLogic
+		if string(messageType) == wss.WSSClientConnectedMessageType {
+			go SendSnapshotToClient(adapterMessage.ClientConnName)
 			continue
 		}
 
@@ -54,10 +70,10 @@ func WSServerReadLoop(wsServer *WebSocketServerConnnection) error {
 }
 
 // GetMessageFromWebSocketMsg retrieves the message type string from a riden
-// API message received through a WebSocket connection.
+// API message received through a WebSocket connection. The deprecated
+// MessageType key is accepted as well as the messageType key of the spec.
 func GetMessageFromWebSocketMsg(wssMsg []byte) (MessageType, wss.AdapterMessage, error) {
 	var err error
-	var messageType MessageType
 
 	// Unmarshal message to get message type
 	var adapterMsg wss.AdapterMessage
@@ -67,20 +83,11 @@ func GetMessageFromWebSocketMsg(wssMsg []byte) (MessageType, wss.AdapterMessage,
 		return "", adapterMsg, err
 	}
 
-	var raw map[string]json.RawMessage
-	err = json.Unmarshal(adapterMsg.MessageBytes, &raw)
+	msgType, err := a.DecodeMessageType(adapterMsg.MessageBytes)
 	if err != nil {
-		Logger.Error().Msgf("Error unmarshalling JSON into json.RawMessage: %s", err.Error())
+		Logger.Error().Msgf("Error unmarshalling JSON into messageType: %s", err.Error())
 		return "", adapterMsg, err
 	}
 
-	if _, ok := raw["MessageType"]; ok {
-		err = json.Unmarshal(raw["MessageType"], &messageType)
-		if err != nil {
-			Logger.Error().Msgf("Error unmarshalling JSON into raw[\"MessageType\"]: %s", err.Error())
-			return "", adapterMsg, err
-		}
-	}
-
-	return messageType, adapterMsg, err
+	return MessageType(msgType), adapterMsg, err
 }
@@ -62,8 +62,9 @@ func (ws *WebSocketServerConnnection) Initialize() {
 
 func (ws *WebSocketServerConnnection) Reset() {
 	Logger.Info().Msg("Resetting WebSocketServer connection")
+	// The Write channel is not closed, since the messages from the MockLogic may
+	// still be placed on it. They are abandoned once the Close channel is closed.
 	close(ws.Close)
-	close(ws.Write)
 
 	if ws.Conn != nil {
 		// Send a close message with 1001 status code
@@ -108,14 +109,15 @@ func NewMockLogicMessage(connName, connType string,
 // MessageType from a riden API defined message
 type MessageType string
 
+// InitializeConnections starts the gRPC server for the MockLogic, and opens the
+// WebSocketServer connection once a compatible MockLogic has completed the
+// handshake, so that no client traffic is accepted before it can be handled
 func InitializeConnections() {
-	// TODO: Define handshake messages between the MockLogic and the Adapter
-	// and only proceed to initiating the WebSocketServer connection if the
-	// MockLogic has completed the handshake
-	// Connect to MockLogic through gRPC first, then create WebSocketServer
-	// connection
 	go InitializeGRPCServer()
 
+	Logger.Info().Msg("Waiting for the MockLogic to complete the handshake")
+	<-MockLogicHandshake.Ready()
+
 	// Initialize WebSocketServer connection
 	InitializeWebSocketServerConn()
 }
@@ -130,6 +132,23 @@ func InitializeWebSocketServerConn() {
 	DialWebsocketServer()
 }
 
+// NewGRPCServer returns the gRPC server of the Adapter, which only serves the
+// streams of the MockLogic on the connection that completed the handshake
+// recorded in MockLogicHandshake
+func NewGRPCServer() *grpc.Server {
+	return newGRPCServer(MockLogicHandshake)
+}
+
+// newGRPCServer returns a gRPC server of the Adapter that records the handshake of
+// the MockLogic in the given HandshakeState
+func newGRPCServer(hs *HandshakeState) *grpc.Server {
+	s := grpc.NewServer(grpc.StreamInterceptor(hs.RequireHandshake),
+		grpc.StatsHandler(handshakeConnHandler{handshake: hs}))
+	pb.RegisterAdapterServer(s, &adapterServer{handshake: hs})
+
+	return s
+}
+
 func InitializeGRPCServer() {
 	// Start server
 	listener, err := net.Listen("tcp", a.GRPCServerAddress)
@@ -137,9 +156,8 @@ func InitializeGRPCServer() {
 		Logger.Error().Msgf("Error: failed to listen: %s", err.Error())
 		return
 	}
-	s := grpc.NewServer()
+	s := NewGRPCServer()
 	GRPCServer = s
-	pb.RegisterAdapterServer(s, &adapterServer{})
 	Logger.Info().Msgf("gRPC server listening at %v", listener.Addr())
 
 	// Serve blocks until the process is killed or the server is stopped.
@@ -244,12 +262,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Make channels to pass messages to the gRPC bi-directional streams
-	GRPCChans.MakeReserveTrip()
-	GRPCChans.MakeAtDock()
-	GRPCChans.MakeOnBoat()
-	GRPCChans.MakeOffBoat()
-
 	// Initialize connections to the necessary servers
 	InitializeConnections()
 
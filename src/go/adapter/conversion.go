@@ -0,0 +1,315 @@
+package adapter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The API structs are converted to and from the gRPC messages that are generated
+// from adapter.proto by matching their fields by name, so that the conversion
+// follows both type sets rather than being written out for each message. The
+// names are compared without case or underscores, so that ClientID matches
+// client_id, and a field with a proto struct tag is matched to the proto field
+// named in the tag. A field that has no match in the other type set is an error,
+// so that the two type sets cannot drift apart.
+//
+// The nested structs are converted to messages that are always set, and a
+// missing message is converted to the zero struct. The zero time.Time is
+// converted to a Timestamp that is not set, and a Timestamp that is not set is
+// converted to the zero time. An int32 is converted to an enum by its number.
+
+const timestampName protoreflect.FullName = "google.protobuf.Timestamp"
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// ToGRPC returns the gRPC message of type M with the fields of the given API
+// struct
+func ToGRPC[M proto.Message](msg any) (M, error) {
+	var grpcMsg M
+	out := grpcMsg.ProtoReflect().Type().New()
+
+	v := reflect.Indirect(reflect.ValueOf(msg))
+	if v.Kind() != reflect.Struct {
+		return grpcMsg, fmt.Errorf("cannot convert %T to %s", msg, out.Descriptor().FullName())
+	}
+	err := structToGRPC(v, out)
+	if err != nil {
+		return grpcMsg, err
+	}
+
+	return out.Interface().(M), nil
+}
+
+// FromGRPC returns the API struct of type T with the fields of the given gRPC
+// message. A nil message, or a nil message in one of its fields, results in
+// zero values.
+func FromGRPC[T any](msg proto.Message) (T, error) {
+	var apiMsg T
+	v := reflect.ValueOf(&apiMsg).Elem()
+	if v.Kind() != reflect.Struct {
+		return apiMsg, fmt.Errorf("cannot convert a gRPC message to %T", apiMsg)
+	}
+	if msg == nil {
+		return apiMsg, nil
+	}
+	err := structFromGRPC(msg.ProtoReflect(), v)
+
+	return apiMsg, err
+}
+
+// SequencedToGRPC returns the gRPC message of type M with the fields of the API
+// struct in the given SequencedMessage, along with the epoch and sequence that it
+// is sent with
+func SequencedToGRPC[M proto.Message, T any](msg SequencedMessage[T]) (M, error) {
+	grpcMsg, err := ToGRPC[M](msg.Message)
+	if err != nil {
+		return grpcMsg, err
+	}
+
+	m := grpcMsg.ProtoReflect()
+	epoch := m.Descriptor().Fields().ByName("epoch")
+	sequence := m.Descriptor().Fields().ByName("sequence")
+	if epoch == nil || sequence == nil {
+		return grpcMsg, fmt.Errorf("%s has no epoch and sequence", m.Descriptor().FullName())
+	}
+	m.Set(epoch, protoreflect.ValueOfUint64(msg.Epoch))
+	m.Set(sequence, protoreflect.ValueOfUint64(msg.Sequence))
+
+	return grpcMsg, nil
+}
+
+// grpcField returns the field of the message that matches the given struct field
+func grpcField(desc protoreflect.MessageDescriptor, field reflect.StructField) (protoreflect.FieldDescriptor, error) {
+	name := field.Tag.Get("proto")
+	if name == "" {
+		name = field.Name
+	}
+
+	fields := desc.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if normalizeFieldName(string(fd.Name())) == normalizeFieldName(name) {
+			return fd, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s has no field for %s", desc.FullName(), field.Name)
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// structToGRPC sets the fields of the message to the fields of the struct
+func structToGRPC(v reflect.Value, m protoreflect.Message) error {
+	for i := range v.NumField() {
+		fd, err := grpcField(m.Descriptor(), v.Type().Field(i))
+		if err != nil {
+			return err
+		}
+		fv := v.Field(i)
+
+		switch {
+		case fd.IsList():
+			if fv.Kind() != reflect.Slice {
+				return fieldMismatch(fd, fv)
+			}
+			if fv.Len() == 0 {
+				continue
+			}
+			list := m.Mutable(fd).List()
+			for j := range fv.Len() {
+				elem, err := valueToGRPC(fd, fv.Index(j), list.NewElement)
+				if err != nil {
+					return err
+				}
+				list.Append(elem)
+			}
+
+		default:
+			if fd.Message() != nil && fd.Message().FullName() == timestampName &&
+				fv.Type() == timeType && fv.IsZero() {
+				continue
+			}
+			elem, err := valueToGRPC(fd, fv, func() protoreflect.Value {
+				return m.NewField(fd)
+			})
+			if err != nil {
+				return err
+			}
+			m.Set(fd, elem)
+		}
+	}
+
+	return nil
+}
+
+// valueToGRPC converts a struct field, or an element of a slice, to the value of
+// the given field. newMessage returns an empty message for the field when it
+// holds messages.
+func valueToGRPC(fd protoreflect.FieldDescriptor, fv reflect.Value,
+	newMessage func() protoreflect.Value) (protoreflect.Value, error) {
+	if fd.Message() != nil {
+		if fd.Message().FullName() == timestampName {
+			if fv.Type() != timeType {
+				return protoreflect.Value{}, fieldMismatch(fd, fv)
+			}
+			t := fv.Interface().(time.Time)
+			elem := newMessage()
+			fields := fd.Message().Fields()
+			elem.Message().Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+			elem.Message().Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+			return elem, nil
+		}
+		if fv.Kind() != reflect.Struct {
+			return protoreflect.Value{}, fieldMismatch(fd, fv)
+		}
+		elem := newMessage()
+		return elem, structToGRPC(fv, elem.Message())
+	}
+
+	if fv.Kind() != scalarKind(fd) {
+		return protoreflect.Value{}, fieldMismatch(fd, fv)
+	}
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(fv.Int())), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(fv.Int())), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(fv.Int()), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(fv.Uint())), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(fv.Uint()), nil
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(fv.Float())), nil
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(fv.Float()), nil
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(fv.Bool()), nil
+	default:
+		return protoreflect.ValueOfString(fv.String()), nil
+	}
+}
+
+// structFromGRPC sets the fields of the struct to the fields of the message
+func structFromGRPC(m protoreflect.Message, v reflect.Value) error {
+	for i := range v.NumField() {
+		fd, err := grpcField(m.Descriptor(), v.Type().Field(i))
+		if err != nil {
+			return err
+		}
+		fv := v.Field(i)
+
+		if fd.IsList() {
+			if fv.Kind() != reflect.Slice {
+				return fieldMismatch(fd, fv)
+			}
+			list := m.Get(fd).List()
+			if list.Len() == 0 {
+				continue
+			}
+			elems := reflect.MakeSlice(fv.Type(), list.Len(), list.Len())
+			for j := range list.Len() {
+				err := valueFromGRPC(fd, list.Get(j), elems.Index(j))
+				if err != nil {
+					return err
+				}
+			}
+			fv.Set(elems)
+			continue
+		}
+
+		if fd.Message() != nil && fd.Message().FullName() == timestampName && !m.Has(fd) {
+			if fv.Type() != timeType {
+				return fieldMismatch(fd, fv)
+			}
+			continue
+		}
+		err = valueFromGRPC(fd, m.Get(fd), fv)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// valueFromGRPC sets a struct field, or an element of a slice, to the value of
+// the given field
+func valueFromGRPC(fd protoreflect.FieldDescriptor, value protoreflect.Value, fv reflect.Value) error {
+	if fd.Message() != nil {
+		if fd.Message().FullName() == timestampName {
+			if fv.Type() != timeType {
+				return fieldMismatch(fd, fv)
+			}
+			fields := fd.Message().Fields()
+			seconds := value.Message().Get(fields.ByName("seconds")).Int()
+			nanos := value.Message().Get(fields.ByName("nanos")).Int()
+			fv.Set(reflect.ValueOf(time.Unix(seconds, nanos).UTC()))
+			return nil
+		}
+		if fv.Kind() != reflect.Struct {
+			return fieldMismatch(fd, fv)
+		}
+		return structFromGRPC(value.Message(), fv)
+	}
+
+	if fv.Kind() != scalarKind(fd) {
+		return fieldMismatch(fd, fv)
+	}
+	switch fv.Kind() {
+	case reflect.Int32, reflect.Int64:
+		if fd.Kind() == protoreflect.EnumKind {
+			fv.SetInt(int64(value.Enum()))
+		} else {
+			fv.SetInt(value.Int())
+		}
+	case reflect.Uint32, reflect.Uint64:
+		fv.SetUint(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		fv.SetFloat(value.Float())
+	case reflect.Bool:
+		fv.SetBool(value.Bool())
+	default:
+		fv.SetString(value.String())
+	}
+
+	return nil
+}
+
+// scalarKind returns the kind of the struct field that holds the value of the
+// given field, which does not hold messages
+func scalarKind(fd protoreflect.FieldDescriptor) reflect.Kind {
+	switch fd.Kind() {
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind,
+		protoreflect.Sfixed32Kind:
+		return reflect.Int32
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return reflect.Int64
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return reflect.Uint32
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return reflect.Uint64
+	case protoreflect.FloatKind:
+		return reflect.Float32
+	case protoreflect.DoubleKind:
+		return reflect.Float64
+	case protoreflect.BoolKind:
+		return reflect.Bool
+	case protoreflect.StringKind:
+		return reflect.String
+	default:
+		return reflect.Invalid
+	}
+}
+
+func fieldMismatch(fd protoreflect.FieldDescriptor, fv reflect.Value) error {
+	return fmt.Errorf("cannot convert %s between %s and %s", fd.FullName(), fd.Kind(), fv.Type())
+}
@@ -0,0 +1,279 @@
+package adapter
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrOutboxFull is returned by Outbox.Push when the outbox already holds its
+// capacity of messages that have not been acknowledged
+var ErrOutboxFull = errors.New("outbox is full")
+
+// SequencedMessage holds a message with the epoch and sequence that it is sent
+// with on a gRPC stream
+type SequencedMessage[T any] struct {
+	Epoch    uint64
+	Sequence uint64
+	Message  T
+}
+
+// Outbox holds the messages that are sent on a gRPC stream until the receiver
+// acknowledges them, so that the messages that were lost when a stream dropped
+// are sent again on the next stream. The messages are numbered in order from 1
+// within the epoch of the outbox, which is set when the outbox is created so that
+// a receiver can tell a restarted sender apart from a retransmission. Each message
+// is acknowledged on its own, since the messages of an outbox may be spread over
+// several streams. The messages that are replaced by later messages, such as the
+// statuses of a boat, are pushed with PushLatest so that they cannot take the
+// place of the other messages. Every method is safe for concurrent use.
+type Outbox[T any] struct {
+	mux      sync.Mutex
+	epoch    uint64
+	capacity int
+	sequence uint64
+	pending  []SequencedMessage[T]
+	// latest holds the sequence of the message that is pending for each key that
+	// messages are pushed with by PushLatest
+	latest map[string]uint64
+	// pushed is closed and replaced when a message is pushed, so that every
+	// sender waiting on the outbox is woken
+	pushed chan struct{}
+}
+
+// NewOutbox returns an empty Outbox that holds up to the given number of
+// messages that have not been acknowledged
+func NewOutbox[T any](capacity int) *Outbox[T] {
+	return &Outbox[T]{
+		epoch:    uint64(time.Now().UnixNano()),
+		capacity: capacity,
+		latest:   make(map[string]uint64),
+		pushed:   make(chan struct{}),
+	}
+}
+
+// Epoch returns the epoch of the outbox
+func (o *Outbox[T]) Epoch() uint64 {
+	return o.epoch
+}
+
+// Push adds the message to the outbox with the next sequence and returns the
+// sequence. When the outbox is full, the oldest message that was pushed with
+// PushLatest is removed to make room, since a later message replaces it.
+// ErrOutboxFull is returned and the message is not added if the outbox is full
+// of other messages.
+func (o *Outbox[T]) Push(msg T) (uint64, error) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	if len(o.pending) >= o.capacity {
+		i := slices.IndexFunc(o.pending, o.isLatest)
+		if i < 0 {
+			return 0, ErrOutboxFull
+		}
+		o.remove(o.pending[i].Sequence)
+	}
+
+	return o.push(msg), nil
+}
+
+// PushLatest adds the message to the outbox with the next sequence in place of
+// the message with the same key that has not been acknowledged, and returns the
+// sequence. The outbox holds only the latest of the messages with a key, so that
+// a receiver that reconnects is sent the current state rather than every change
+// that it missed. ErrOutboxFull is returned and the message is not added if the
+// outbox is full.
+func (o *Outbox[T]) PushLatest(key string, msg T) (uint64, error) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	if sequence, ok := o.latest[key]; ok {
+		o.remove(sequence)
+	}
+	if len(o.pending) >= o.capacity {
+		return 0, ErrOutboxFull
+	}
+	sequence := o.push(msg)
+	o.latest[key] = sequence
+
+	return sequence, nil
+}
+
+// push adds the message to the outbox with the next sequence and wakes the
+// senders. The mutex must be held.
+func (o *Outbox[T]) push(msg T) uint64 {
+	o.sequence++
+	o.pending = append(o.pending, SequencedMessage[T]{
+		Epoch:    o.epoch,
+		Sequence: o.sequence,
+		Message:  msg,
+	})
+
+	close(o.pushed)
+	o.pushed = make(chan struct{})
+
+	return o.sequence
+}
+
+// remove removes the message with the given sequence from the outbox, along with
+// its key. The mutex must be held.
+func (o *Outbox[T]) remove(sequence uint64) {
+	o.pending = slices.DeleteFunc(o.pending, func(msg SequencedMessage[T]) bool {
+		return msg.Sequence == sequence
+	})
+	for key, latest := range o.latest {
+		if latest == sequence {
+			delete(o.latest, key)
+			return
+		}
+	}
+}
+
+// isLatest returns whether the message was pushed with PushLatest. The mutex must
+// be held.
+func (o *Outbox[T]) isLatest(msg SequencedMessage[T]) bool {
+	for _, sequence := range o.latest {
+		if sequence == msg.Sequence {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Notify returns a channel that is closed when the next message is pushed
+func (o *Outbox[T]) Notify() <-chan struct{} {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	return o.pushed
+}
+
+// PendingAfter returns the messages that have not been acknowledged and have a
+// sequence after the given sequence, in order
+func (o *Outbox[T]) PendingAfter(sequence uint64) []SequencedMessage[T] {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	var msgs []SequencedMessage[T]
+	for _, msg := range o.pending {
+		if msg.Sequence > sequence {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs
+}
+
+// Acknowledge removes the message with the given sequence from the outbox. A
+// Receipt from another epoch is ignored.
+func (o *Outbox[T]) Acknowledge(epoch, sequence uint64) {
+	if epoch != o.epoch {
+		return
+	}
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	o.remove(sequence)
+}
+
+// Len returns the number of messages that have not been acknowledged
+func (o *Outbox[T]) Len() int {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	return len(o.pending)
+}
+
+// SendPending sends the messages in the outbox that have not been acknowledged
+// with the given send function, followed by each message that is pushed, until
+// the done channel is closed or a send fails. Every message is sent again on a
+// new stream since the messages sent on a dropped stream may not have arrived.
+func SendPending[T any](outbox *Outbox[T], done <-chan struct{},
+	send func(SequencedMessage[T]) error) error {
+	var sent uint64
+	for {
+		// The channel is taken before the pending messages so that a message
+		// pushed in between is not missed
+		pushed := outbox.Notify()
+		for _, msg := range outbox.PendingAfter(sent) {
+			err := send(msg)
+			if err != nil {
+				return err
+			}
+			sent = msg.Sequence
+		}
+
+		select {
+		case <-pushed:
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// SessionStreamName is the name that the messages received on the Session stream
+// are recorded under in an Inbox
+const SessionStreamName string = "Session"
+
+// inboxStream holds the epoch and the last sequence received on a stream
+type inboxStream struct {
+	epoch    uint64
+	sequence uint64
+}
+
+// Inbox holds the epoch and the last sequence of the messages received on each
+// gRPC stream in one direction, so that the messages that are sent again after a
+// stream reconnects are only delivered once. Every method is safe for concurrent
+// use.
+type Inbox struct {
+	mux     sync.Mutex
+	streams map[string]inboxStream
+}
+
+func NewInbox() *Inbox {
+	return &Inbox{
+		streams: make(map[string]inboxStream),
+	}
+}
+
+// Received returns whether the message with the given epoch and sequence was
+// already received on the stream with the given name, without recording it
+func (in *Inbox) Received(streamName string, epoch, sequence uint64) bool {
+	if sequence == 0 {
+		return false
+	}
+
+	in.mux.Lock()
+	defer in.mux.Unlock()
+
+	stream := in.streams[streamName]
+	return stream.epoch == epoch && sequence <= stream.sequence
+}
+
+// Accept records the message with the given epoch and sequence received on the
+// stream with the given name, and returns whether it should be delivered. A
+// message with a sequence that was already received in the same epoch is a
+// duplicate. A new epoch means that the sender restarted, so its sequences begin
+// again. A message without a sequence is always delivered.
+func (in *Inbox) Accept(streamName string, epoch, sequence uint64) bool {
+	if sequence == 0 {
+		return true
+	}
+
+	in.mux.Lock()
+	defer in.mux.Unlock()
+
+	stream := in.streams[streamName]
+	if stream.epoch == epoch && sequence <= stream.sequence {
+		return false
+	}
+	in.streams[streamName] = inboxStream{
+		epoch:    epoch,
+		sequence: sequence,
+	}
+
+	return true
+}
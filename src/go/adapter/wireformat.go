@@ -0,0 +1,65 @@
+package adapter
+
+import (
+	"encoding/json"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The API messages are sent to and from the clients as JSON objects with the
+// lowerCamelCase keys of the AsyncAPI spec in docs/api/asynapi.yaml, which are
+// given by the json struct tags of the API structs. The messages are decoded with
+// encoding/json, which matches the keys to the struct tags without case, so that
+// a message with the PascalCase keys that were sent before the keys followed the
+// spec is still decoded. The PascalCase keys are deprecated, and are accepted
+// only until the clients have moved to the keys of the spec.
+
+// messageHeader is used with json.Unmarshal() to decode the type of an API
+// message without decoding the rest of the message
+type messageHeader struct {
+	MessageType string `json:"messageType"`
+}
+
+// DecodeMessageType returns the messageType of the given API message, or an
+// empty string if the message has none. The deprecated MessageType key is also
+// accepted. An error is returned if the message is not a JSON object or the
+// messageType is not a string.
+func DecodeMessageType(data []byte) (string, error) {
+	var header messageHeader
+	err := json.Unmarshal(data, &header)
+
+	return header.MessageType, err
+}
+
+// HasLegacyKeys reports whether the given API message, or an object nested in
+// it, has a key that begins with an upper case letter, as the deprecated
+// PascalCase keys do. A message that is not valid JSON has no legacy keys.
+func HasLegacyKeys(data []byte) bool {
+	var msg any
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		return false
+	}
+
+	return hasLegacyKeys(msg)
+}
+
+func hasLegacyKeys(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			first, _ := utf8.DecodeRuneInString(key)
+			if unicode.IsUpper(first) || hasLegacyKeys(elem) {
+				return true
+			}
+		}
+	case []any:
+		for _, elem := range v {
+			if hasLegacyKeys(elem) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
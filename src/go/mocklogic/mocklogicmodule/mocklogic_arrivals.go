@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	a "riden/adapter"
+)
+
+// errNoArrival is returned from the trip update in CheckArrivals when the
+// boat status does not represent an arrival for the trip
+var errNoArrival = errors.New("boat status is not an arrival for the trip")
+
+// CheckArrivals compares the given boat status with the trips assigned to the
+// boat and returns the Arrived messages that should be sent to the clients. A
+// trip is moved to TripStateBoatArrivedAtSource when the boat is at the SourceDock
+// of a trip that has not boarded, and to TripStateBoatArrivedAtDest when the boat
+// is at the DestinationDock of a trip that is on board. Since the state of the
+// trip has already advanced when the boat remains at the dock for the next frame,
+// only one Arrived message is returned for each leg of the trip. The seats of the
+// passengers of the party that did not board are given back when the boat arrives
+// at the DestinationDock.
+func CheckArrivals(boatStatus a.BoatStatusAPIMessage) []a.ArrivedMockLogicMessage {
+	var arrivals []a.ArrivedMockLogicMessage
+
+	// The boat is travelling between docks
+	if boatStatus.CurrentDock == (a.Dock{}) {
+		return arrivals
+	}
+
+	for _, trip := range safeTrips.LoadByBoatID(boatStatus.Boat.BoatID) {
+		updatedTrip, err := safeTrips.Update(trip.TransactionID, func(t *Trip) error {
+			state, ok := arrivalTripState(t, boatStatus.CurrentDock)
+			if !ok {
+				return errNoArrival
+			}
+			return t.AdvanceToTripState(state)
+		})
+		if errors.Is(err, errNoArrival) {
+			continue
+		}
+		if err != nil {
+			Logger.Error().Msgf("Could not update trip with TransactionID: %s for arrival of BoatID: %d: %s",
+				trip.TransactionID, boatStatus.Boat.BoatID, err.Error())
+			continue
+		}
+
+		Logger.Info().Msgf("BoatID: %d arrived at %d %s for trip with TransactionID: %s, TripState: %d",
+			boatStatus.Boat.BoatID, boatStatus.CurrentDock.Address.Number,
+			boatStatus.CurrentDock.Address.Street, updatedTrip.TransactionID, updatedTrip.TripState)
+		if updatedTrip.TripState == TripStateBoatArrivedAtDest {
+			missed := updatedTrip.CountPassengers(PassengerStateReserved)
+			if missed > 0 {
+				Logger.Info().Msgf("%d passengers of trip with TransactionID: %s did not board",
+					missed, updatedTrip.TransactionID)
+				releaseSeats(boatStatus.Boat.BoatID, missed, false)
+			}
+		}
+		arrivedAPIMsg := a.NewArrivedAPIMessage(a.APIMessageTypeArrived,
+			updatedTrip.Reservation.ClientID, updatedTrip.Boat, boatStatus.CurrentDock,
+			updatedTrip.TransactionID)
+		arrivals = append(arrivals, a.NewArrivedMockLogicMessage(arrivedAPIMsg, updatedTrip.Client))
+	}
+
+	return arrivals
+}
+
+// arrivalTripState returns the state that the given trip should advance to
+// when its boat is at the given dock, and false if the boat being at the dock
+// is not an arrival for the trip
+func arrivalTripState(trip *Trip, dock a.Dock) (int32, bool) {
+	switch {
+	case dock == trip.Reservation.SourceDock &&
+		(trip.TripState == TripStateReserved || trip.TripState == TripStateClientAtDock):
+		return TripStateBoatArrivedAtSource, true
+
+	case dock == trip.Reservation.DestinationDock && trip.TripState == TripStateClientOnBoat:
+		return TripStateBoatArrivedAtDest, true
+
+	default:
+		return TripStateUnknown, false
+	}
+}
+
+// errNoDeparture is returned from the trip update in CheckDepartures when the
+// boat status does not represent a departure that finishes the trip
+var errNoDeparture = errors.New("boat status is not a departure for the trip")
+
+// CheckDepartures compares the given boat status with the trips assigned to the
+// boat and finishes the trips that the boat has left behind, returning the Error
+// messages that should be sent to the clients. A trip that no passenger boarded
+// is cancelled once the boat has left the SourceDock, and the seats of the party
+// are given back. The passengers that are still on board once the boat has left
+// the DestinationDock are taken off the boat, and the trip advances to
+// TripStateClientOffBoat.
+func CheckDepartures(boatStatus a.BoatStatusAPIMessage) []a.ErrorMockLogicMessage {
+	var errMsgs []a.ErrorMockLogicMessage
+
+	for _, trip := range safeTrips.LoadByBoatID(boatStatus.Boat.BoatID) {
+		var released int32
+		var onBoard bool
+		updatedTrip, err := safeTrips.Update(trip.TransactionID, func(t *Trip) error {
+			switch {
+			case t.TripState == TripStateBoatArrivedAtSource &&
+				boatStatus.CurrentDock != t.Reservation.SourceDock:
+				released = t.CountPassengers(PassengerStateReserved)
+				return t.AdvanceToTripState(TripStateCancelled)
+
+			case (t.TripState == TripStateBoatArrivedAtDest || t.TripState == TripStateClientOffBoat) &&
+				boatStatus.CurrentDock != t.Reservation.DestinationDock &&
+				t.CountPassengers(PassengerStateOnBoard) > 0:
+				if t.TripState != TripStateClientOffBoat {
+					err := t.AdvanceToTripState(TripStateClientOffBoat)
+					if err != nil {
+						return err
+					}
+				}
+				var err error
+				onBoard = true
+				released, err = t.AdvancePassengers(nil, PassengerStateOnBoard, PassengerStateOffBoat)
+				return err
+
+			default:
+				return errNoDeparture
+			}
+		})
+		if errors.Is(err, errNoDeparture) {
+			continue
+		}
+		if err != nil {
+			Logger.Error().Msgf("Could not update trip with TransactionID: %s for departure of BoatID: %d: %s",
+				trip.TransactionID, boatStatus.Boat.BoatID, err.Error())
+			continue
+		}
+
+		releaseSeats(boatStatus.Boat.BoatID, released, onBoard)
+		if onBoard {
+			Logger.Info().Msgf("%d passengers of trip with TransactionID: %s did not get off before BoatID: %d left the destination dock",
+				released, updatedTrip.TransactionID, boatStatus.Boat.BoatID)
+			continue
+		}
+
+		Logger.Warn().Msgf("Cancelled trip with TransactionID: %s for ClientID: %s since no passenger boarded before BoatID: %d left the source dock",
+			updatedTrip.TransactionID, updatedTrip.Reservation.ClientID, boatStatus.Boat.BoatID)
+		missedErr := NewTripMessageError(a.ErrorReasonInvalidTripState,
+			"the boat left the source dock before the party boarded")
+		errMsgs = append(errMsgs, NewTripMessageErrorMessage(missedErr,
+			a.APIMessageTypeReserveTrip, updatedTrip.Reservation.ClientID,
+			updatedTrip.TransactionID, updatedTrip.Client))
+	}
+
+	return errMsgs
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock gives the time of the simulation and the ticks that advance it, so that
+// runs can be sped up and tests can step the simulation without waiting
+type Clock interface {
+	// Now returns the current time of the simulation
+	Now() time.Time
+	// Tick returns a channel that receives a tick each time the given duration
+	// of simulation time elapses
+	Tick(d time.Duration) <-chan time.Time
+}
+
+// SimClock is the clock of the simulation. It runs at the speed given by the
+// -speed flag.
+var SimClock Clock = NewScaledClock(1)
+
+// SpeedClock is a Clock whose speed can be changed while it runs
+type SpeedClock interface {
+	Clock
+	// Speed returns the multiple of real time that the clock runs at
+	Speed() float64
+	// SetSpeed changes the multiple of real time that the clock runs at. The
+	// speed must be positive.
+	SetSpeed(speed float64)
+}
+
+// ScaledClock is a Clock that runs at a multiple of real time. The time of the
+// clock is kept when the speed is changed.
+type ScaledClock struct {
+	mux sync.RWMutex
+	// The time of the clock and the real time when the speed was last changed
+	base     time.Time
+	baseReal time.Time
+	speed    float64
+	// changed is closed and replaced when the speed is changed, so that the
+	// tickers wait for the next tick at the new speed
+	changed chan struct{}
+}
+
+// NewScaledClock returns a ScaledClock that starts at the current time and runs
+// at the given multiple of real time. The speed must be positive.
+func NewScaledClock(speed float64) *ScaledClock {
+	now := time.Now()
+	return &ScaledClock{
+		base:     now,
+		baseReal: now,
+		speed:    speed,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Now returns the time of the clock when the speed was last changed plus the
+// scaled real time that has elapsed since
+func (sc *ScaledClock) Now() time.Time {
+	sc.mux.RLock()
+	defer sc.mux.RUnlock()
+
+	return sc.now()
+}
+
+// now returns the time of the clock. The caller must hold the lock.
+func (sc *ScaledClock) now() time.Time {
+	elapsed := time.Since(sc.baseReal)
+	return sc.base.Add(time.Duration(float64(elapsed) * sc.speed))
+}
+
+// Speed returns the multiple of real time that the clock runs at
+func (sc *ScaledClock) Speed() float64 {
+	sc.mux.RLock()
+	defer sc.mux.RUnlock()
+
+	return sc.speed
+}
+
+// SetSpeed changes the multiple of real time that the clock runs at from the
+// current time of the clock. The speed must be positive.
+func (sc *ScaledClock) SetSpeed(speed float64) {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+
+	sc.base = sc.now()
+	sc.baseReal = time.Now()
+	sc.speed = speed
+	close(sc.changed)
+	sc.changed = make(chan struct{})
+}
+
+// untilTime returns the real time until the clock reaches the given time, and
+// the channel that is closed when the speed is changed
+func (sc *ScaledClock) untilTime(t time.Time) (time.Duration, <-chan struct{}) {
+	sc.mux.RLock()
+	defer sc.mux.RUnlock()
+
+	wait := time.Duration(float64(t.Sub(sc.now())) / sc.speed)
+	return max(wait, 0), sc.changed
+}
+
+// Tick returns a channel that receives the time of the clock each time the given
+// duration of scaled time elapses, following changes of speed. Like time.Tick,
+// ticks are dropped for a slow receiver.
+func (sc *ScaledClock) Tick(d time.Duration) <-chan time.Time {
+	ticks := make(chan time.Time, 1)
+	go func() {
+		next := sc.Now().Add(d)
+		for {
+			wait, changed := sc.untilTime(next)
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-changed:
+				timer.Stop()
+				continue
+			}
+
+			now := sc.Now()
+			if now.Before(next) {
+				continue
+			}
+			select {
+			case ticks <- now:
+			default:
+			}
+			for !next.After(now) {
+				next = next.Add(d)
+			}
+		}
+	}()
+
+	return ticks
+}
+
+// manualTicker holds a channel returned by ManualClock.Tick and the time of its
+// next tick
+type manualTicker struct {
+	ticks  chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+// ManualClock is a Clock that only moves when it is advanced, so that tests can
+// step the simulation one frame at a time
+type ManualClock struct {
+	mux     sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+// NewManualClock returns a ManualClock that is stopped at the given time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time that the clock has been advanced to
+func (mc *ManualClock) Now() time.Time {
+	mc.mux.Lock()
+	defer mc.mux.Unlock()
+
+	return mc.now
+}
+
+// Tick returns a channel that receives a tick each time the clock is advanced
+// past a multiple of the given duration from the current time
+func (mc *ManualClock) Tick(d time.Duration) <-chan time.Time {
+	mc.mux.Lock()
+	defer mc.mux.Unlock()
+
+	ticker := &manualTicker{
+		ticks:  make(chan time.Time),
+		period: d,
+		next:   mc.now.Add(d),
+	}
+	mc.tickers = append(mc.tickers, ticker)
+
+	return ticker.ticks
+}
+
+// Advance moves the clock forward by the given duration and sends the ticks that
+// fall due, in order for each channel. Advance blocks until each tick has been
+// received, so every channel returned by Tick must be read while the clock is
+// advanced.
+func (mc *ManualClock) Advance(d time.Duration) {
+	mc.mux.Lock()
+	mc.now = mc.now.Add(d)
+	type dueTick struct {
+		ticks chan time.Time
+		at    time.Time
+	}
+	var due []dueTick
+	for _, ticker := range mc.tickers {
+		for !ticker.next.After(mc.now) {
+			due = append(due, dueTick{ticks: ticker.ticks, at: ticker.next})
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+	mc.mux.Unlock()
+
+	// The ticks are sent without the lock so that the receivers can call Now
+	for _, tick := range due {
+		tick.ticks <- tick.at
+	}
+}
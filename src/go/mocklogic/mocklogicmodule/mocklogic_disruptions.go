@@ -0,0 +1,95 @@
+package main
+
+import (
+	"cmp"
+	a "riden/adapter"
+	"slices"
+)
+
+// groupDisruptions returns the given disruptions keyed by BoatID, with the
+// disruptions of each boat ordered by StartFrame
+func groupDisruptions(disruptions []ScenarioDisruption) map[int32][]ScenarioDisruption {
+	grouped := make(map[int32][]ScenarioDisruption)
+	for _, disruption := range disruptions {
+		grouped[disruption.BoatID] = append(grouped[disruption.BoatID], disruption)
+	}
+	for _, boatDisruptions := range grouped {
+		slices.SortFunc(boatDisruptions, func(x, y ScenarioDisruption) int {
+			return cmp.Compare(x.StartFrame, y.StartFrame)
+		})
+	}
+
+	return grouped
+}
+
+// boatFrameIndex returns the index of the frame that gives the location of the
+// boat in the given step of the simulation, and the ServiceState of the
+// disruption of the boat in that step. The ServiceState is ServiceStateUnknown
+// when the boat is not disrupted. Every disruption that has ended moves the boat
+// back by its DurationFrames, and a disrupted boat remains in the frame that it
+// was in when the disruption started. The caller must hold the read lock.
+func (fs *FrameSchedule) boatFrameIndex(boatID int32, step int) (int, int32) {
+	n := len(fs.frames)
+	delay := 0
+	for _, disruption := range fs.disruptions[boatID] {
+		if step < disruption.StartFrame {
+			break
+		}
+		if disruption.DurationFrames == 0 || step < disruption.StartFrame+disruption.DurationFrames {
+			return ((disruption.StartFrame-delay)%n + n) % n, disruption.ServiceState
+		}
+		delay += disruption.DurationFrames
+	}
+
+	return ((step-delay)%n + n) % n, a.ServiceStateUnknown
+}
+
+// boatStatus returns the status of the boat in the given step of the simulation,
+// and false if the boat is not in the frames. The caller must hold the read lock.
+func (fs *FrameSchedule) boatStatus(boatID int32, step int) (a.BoatStatusAPIMessage, bool) {
+	index, serviceState := fs.boatFrameIndex(boatID, step)
+	for _, status := range fs.frames[index] {
+		if status.Boat.BoatID != boatID {
+			continue
+		}
+		if serviceState != a.ServiceStateUnknown {
+			status.ServiceState = serviceState
+		}
+		return status, true
+	}
+
+	return a.BoatStatusAPIMessage{}, false
+}
+
+// disruptionFrames returns the number of frames that the boat is held back by
+// the disruptions that have a DurationFrames. The caller must hold the read lock.
+func (fs *FrameSchedule) disruptionFrames(boatID int32) int {
+	frames := 0
+	for _, disruption := range fs.disruptions[boatID] {
+		frames += disruption.DurationFrames
+	}
+
+	return frames
+}
+
+// BoatStatuses returns the status of every boat in the given step of the
+// simulation, with the disruptions applied, in the order of the frames
+func (fs *FrameSchedule) BoatStatuses(step int) []a.BoatStatusAPIMessage {
+	fs.mux.RLock()
+	defer fs.mux.RUnlock()
+
+	if len(fs.frames) == 0 {
+		return nil
+	}
+
+	baseStatuses := fs.frames[step%len(fs.frames)]
+	statuses := make([]a.BoatStatusAPIMessage, 0, len(baseStatuses))
+	for _, baseStatus := range baseStatuses {
+		status, ok := fs.boatStatus(baseStatus.Boat.BoatID, step)
+		if ok {
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	a "riden/adapter"
+	"slices"
+	"time"
+)
+
+// ErrUnknownDock is returned by DockGraph queries for a dock that is not in
+// the graph
+var ErrUnknownDock = errors.New("dock is not in the dock graph")
+
+// ErrUnreachableDock is returned by DockGraph queries when there is no route
+// from the start dock to the goal dock
+var ErrUnreachableDock = errors.New("dock cannot be reached")
+
+// DockRoute is a directed connection from one dock to the next dock that a
+// boat travels to. TravelFrames is the number of frames from the boat arriving
+// at the From dock until it arrives at the To dock.
+type DockRoute struct {
+	From         a.Dock
+	To           a.Dock
+	TravelFrames int
+}
+
+// DockGraph is a directed, weighted graph of the docks that the boats travel
+// between. Docks are identified by their full a.Dock value, so docks on the same
+// street are distinct. The graph is built once from the scenario and is only read
+// afterwards, so queries are safe for concurrent use.
+type DockGraph struct {
+	routes map[a.Dock][]DockRoute
+}
+
+func NewDockGraph() *DockGraph {
+	return &DockGraph{
+		routes: make(map[a.Dock][]DockRoute),
+	}
+}
+
+// AddDock adds a dock to the graph without any routes
+func (g *DockGraph) AddDock(dock a.Dock) {
+	if _, ok := g.routes[dock]; !ok {
+		g.routes[dock] = nil
+	}
+}
+
+// AddRoute adds a route between two docks, adding the docks to the graph if
+// needed. An error is returned if the route is from a dock to itself, the
+// TravelFrames is not positive, or the route already exists.
+func (g *DockGraph) AddRoute(from, to a.Dock, travelFrames int) error {
+	var err error
+	if from == to {
+		err = fmt.Errorf("route from dock: %d %s, to itself", from.Address.Number, from.Address.Street)
+		return err
+	}
+	if travelFrames <= 0 {
+		err = fmt.Errorf("route from dock: %d %s, has invalid TravelFrames: %d",
+			from.Address.Number, from.Address.Street, travelFrames)
+		return err
+	}
+	for _, route := range g.routes[from] {
+		if route.To == to {
+			err = fmt.Errorf("route from dock: %d %s, to dock: %d %s, already exists",
+				from.Address.Number, from.Address.Street, to.Address.Number, to.Address.Street)
+			return err
+		}
+	}
+
+	g.AddDock(to)
+	g.routes[from] = append(g.routes[from], DockRoute{
+		From:         from,
+		To:           to,
+		TravelFrames: travelFrames,
+	})
+
+	return err
+}
+
+// HasDock returns whether the dock is in the graph
+func (g *DockGraph) HasDock(dock a.Dock) bool {
+	_, ok := g.routes[dock]
+	return ok
+}
+
+// Routes returns the routes that leave the given dock
+func (g *DockGraph) Routes(from a.Dock) []DockRoute {
+	return slices.Clone(g.routes[from])
+}
+
+// ShortestPath returns the docks on the quickest path from start to goal,
+// including both, and the number of frames the path takes. An error wrapping
+// ErrUnknownDock or ErrUnreachableDock is returned if there is no path.
+func (g *DockGraph) ShortestPath(start, goal a.Dock) ([]a.Dock, int, error) {
+	if !g.HasDock(start) {
+		return nil, 0, fmt.Errorf("start dock: %d %s: %w",
+			start.Address.Number, start.Address.Street, ErrUnknownDock)
+	}
+	if !g.HasDock(goal) {
+		return nil, 0, fmt.Errorf("goal dock: %d %s: %w",
+			goal.Address.Number, goal.Address.Street, ErrUnknownDock)
+	}
+
+	// Dijkstra's algorithm
+	frames := map[a.Dock]int{start: 0}
+	previous := make(map[a.Dock]a.Dock)
+	visited := make(map[a.Dock]bool)
+	queue := &dockQueue{{dock: start, frames: 0}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(dockQueueItem)
+		if visited[item.dock] {
+			continue
+		}
+		visited[item.dock] = true
+		if item.dock == goal {
+			break
+		}
+
+		for _, route := range g.routes[item.dock] {
+			routeFrames := item.frames + route.TravelFrames
+			if known, ok := frames[route.To]; ok && known <= routeFrames {
+				continue
+			}
+			frames[route.To] = routeFrames
+			previous[route.To] = item.dock
+			heap.Push(queue, dockQueueItem{dock: route.To, frames: routeFrames})
+		}
+	}
+
+	if !visited[goal] {
+		return nil, 0, fmt.Errorf("goal dock: %d %s, from start dock: %d %s: %w",
+			goal.Address.Number, goal.Address.Street, start.Address.Number,
+			start.Address.Street, ErrUnreachableDock)
+	}
+
+	path := []a.Dock{goal}
+	for dock := goal; dock != start; {
+		dock = previous[dock]
+		path = append(path, dock)
+	}
+	slices.Reverse(path)
+
+	return path, frames[goal], nil
+}
+
+// TravelFrames returns the number of frames on the quickest path from start to goal
+func (g *DockGraph) TravelFrames(start, goal a.Dock) (int, error) {
+	_, frames, err := g.ShortestPath(start, goal)
+	return frames, err
+}
+
+// TravelTime returns the time that the quickest path from start to goal takes
+// with the given frame duration
+func (g *DockGraph) TravelTime(start, goal a.Dock, frameDuration time.Duration) (time.Duration, error) {
+	frames, err := g.TravelFrames(start, goal)
+	return time.Duration(frames) * frameDuration, err
+}
+
+// dockQueueItem is a dock and the number of frames it takes to reach it
+type dockQueueItem struct {
+	dock   a.Dock
+	frames int
+}
+
+// dockQueue is a min-heap of dockQueueItem ordered by frames, used by
+// ShortestPath. It implements heap.Interface.
+type dockQueue []dockQueueItem
+
+func (q dockQueue) Len() int           { return len(q) }
+func (q dockQueue) Less(i, j int) bool { return q[i].frames < q[j].frames }
+func (q dockQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *dockQueue) Push(x any) {
+	*q = append(*q, x.(dockQueueItem))
+}
+
+func (q *dockQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
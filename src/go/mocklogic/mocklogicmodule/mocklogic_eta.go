@@ -0,0 +1,226 @@
+package main
+
+import (
+	a "riden/adapter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FrameSchedule holds the frames and the disruptions of the simulation and the
+// step of the simulation that was sent last, so that the time that a boat arrives
+// at a dock can be estimated from the steps that follow it. AdvanceSimFrames
+// records each step as it is sent while the stream handlers estimate arrivals, so
+// every method is safe for concurrent use.
+type FrameSchedule struct {
+	mux           sync.RWMutex
+	frames        [][]a.BoatStatusAPIMessage
+	disruptions   map[int32][]ScenarioDisruption
+	frameDuration time.Duration
+	lastStep      int
+	lastSentAt    time.Time
+}
+
+func NewFrameSchedule(simFrames SimulationFrames, frameDuration time.Duration) *FrameSchedule {
+	return &FrameSchedule{
+		frames:        simFrames.BoatLocations,
+		disruptions:   groupDisruptions(simFrames.Disruptions),
+		frameDuration: frameDuration,
+	}
+}
+
+// SimSchedule holds the schedule of the simulation frames
+var SimSchedule *FrameSchedule
+
+// RecordFrame records that the given step of the simulation was sent at the
+// given time
+func (fs *FrameSchedule) RecordFrame(step int, sentAt time.Time) {
+	fs.mux.Lock()
+	defer fs.mux.Unlock()
+
+	fs.lastStep = step
+	fs.lastSentAt = sentAt
+}
+
+// FrameCount returns the number of frames in a loop of the simulation
+func (fs *FrameSchedule) FrameCount() int {
+	fs.mux.RLock()
+	defer fs.mux.RUnlock()
+
+	return len(fs.frames)
+}
+
+// TripETAs returns the estimated times that the boat of the trip arrives at the
+// SourceDock and at the DestinationDock of the trip. The ETAs include the delays
+// of the disruptions to the boat. An ETA is zero if the boat has already arrived
+// at the dock, it does not reach the dock in a full loop of the frames, or no
+// frame has been sent yet. The SourceETA of a scheduled trip is
+// its confirmed departure.
+func (fs *FrameSchedule) TripETAs(trip Trip) (sourceETA, destinationETA time.Time) {
+	fs.mux.RLock()
+	defer fs.mux.RUnlock()
+
+	if fs.lastSentAt.IsZero() || len(fs.frames) == 0 {
+		return sourceETA, destinationETA
+	}
+
+	// The destination can only be reached after the source
+	destinationAfter := 0
+	switch trip.TripState {
+	case TripStateScheduled:
+		frames, ok := fs.framesUntil(trip.DepartureTime)
+		if !ok {
+			return sourceETA, destinationETA
+		}
+		sourceETA = trip.DepartureTime
+		destinationAfter = frames
+
+	case TripStateReserved, TripStateClientAtDock:
+		frames, ok := fs.framesUntilArrival(trip.Boat.BoatID, trip.Reservation.SourceDock, 0)
+		if !ok {
+			return sourceETA, destinationETA
+		}
+		sourceETA = fs.lastSentAt.Add(time.Duration(frames) * fs.frameDuration)
+		destinationAfter = frames
+
+	case TripStateBoatArrivedAtSource, TripStateClientOnBoat:
+
+	default:
+		return sourceETA, destinationETA
+	}
+
+	frames, ok := fs.framesUntilArrival(trip.Boat.BoatID, trip.Reservation.DestinationDock,
+		destinationAfter)
+	if ok {
+		destinationETA = fs.lastSentAt.Add(time.Duration(frames) * fs.frameDuration)
+	}
+
+	return sourceETA, destinationETA
+}
+
+// framesUntilArrival returns the number of frames after the last sent frame until
+// the first frame after the given number of frames in which the boat is at the
+// dock, and false if the boat is not at the dock in a full loop of the frames and
+// the delays of its disruptions. The caller must hold the read lock.
+func (fs *FrameSchedule) framesUntilArrival(boatID int32, dock a.Dock, after int) (int, bool) {
+	loop := len(fs.frames) + fs.disruptionFrames(boatID)
+	for frames := after + 1; frames <= after+loop; frames++ {
+		status, ok := fs.boatStatus(boatID, fs.lastStep+frames)
+		if ok && status.CurrentDock == dock {
+			return frames, true
+		}
+	}
+
+	return 0, false
+}
+
+// framesUntil returns the number of frames after the last sent frame until the
+// frame that is sent closest to the given time, and false if the time is before
+// the last sent frame. The caller must hold the read lock.
+func (fs *FrameSchedule) framesUntil(t time.Time) (int, bool) {
+	if t.Before(fs.lastSentAt) {
+		return 0, false
+	}
+
+	return int((t.Sub(fs.lastSentAt) + fs.frameDuration/2) / fs.frameDuration), true
+}
+
+// isArrivalFrame returns whether the boat is at the dock in the given step after
+// being away from the dock in the step before it. The caller must hold the read
+// lock.
+func (fs *FrameSchedule) isArrivalFrame(boatID int32, dock a.Dock, step int) bool {
+	atDock := func(step int) bool {
+		status, ok := fs.boatStatus(boatID, step)
+		return ok && status.CurrentDock == dock
+	}
+
+	return atDock(step) && !atDock(step-1)
+}
+
+// ScheduledDeparture returns the first of the given boats that arrives at the dock
+// in service at or after the given time, and the time that it arrives. It returns
+// false if none of the boats arrive at the dock in a full loop of the frames and
+// the delays of the disruptions, or no frame has been sent yet. Boats that arrive
+// in the same frame are chosen in the order of the frame.
+func (fs *FrameSchedule) ScheduledDeparture(boats []a.Boat, dock a.Dock,
+	after time.Time) (a.Boat, time.Time, bool) {
+	fs.mux.RLock()
+	defer fs.mux.RUnlock()
+
+	if fs.lastSentAt.IsZero() || len(fs.frames) == 0 {
+		return a.Boat{}, time.Time{}, false
+	}
+
+	// Round up to the first frame that is sent at or after the given time
+	first := 1
+	if after.After(fs.lastSentAt) {
+		first = max(first, int((after.Sub(fs.lastSentAt)+fs.frameDuration-1)/fs.frameDuration))
+	}
+
+	loop := len(fs.frames)
+	for _, boat := range boats {
+		loop = max(loop, len(fs.frames)+fs.disruptionFrames(boat.BoatID))
+	}
+	for frames := first; frames < first+loop; frames++ {
+		step := fs.lastStep + frames
+		for _, baseStatus := range fs.frames[step%len(fs.frames)] {
+			boatID := baseStatus.Boat.BoatID
+			isCandidate := slices.ContainsFunc(boats, func(boat a.Boat) bool {
+				return boat.BoatID == boatID
+			})
+			if !isCandidate {
+				continue
+			}
+			status, ok := fs.boatStatus(boatID, step)
+			if !ok || status.CurrentDock != dock || status.ServiceState == a.ServiceStateUnavailable {
+				continue
+			}
+			if fs.isArrivalFrame(boatID, dock, step) {
+				return status.Boat, fs.lastSentAt.Add(time.Duration(frames) * fs.frameDuration), true
+			}
+		}
+	}
+
+	return a.Boat{}, time.Time{}, false
+}
+
+// IsNextDeparture returns whether the confirmed departure of the scheduled trip
+// is the next arrival of its boat at the SourceDock, so that the boat does not
+// call at the dock again before the trip departs. It returns false if no frame
+// has been sent yet.
+func (fs *FrameSchedule) IsNextDeparture(trip Trip) bool {
+	fs.mux.RLock()
+	defer fs.mux.RUnlock()
+
+	if fs.lastSentAt.IsZero() || len(fs.frames) == 0 {
+		return false
+	}
+
+	frames, ok := fs.framesUntilArrival(trip.Boat.BoatID, trip.Reservation.SourceDock, 0)
+	if !ok {
+		return false
+	}
+	nextArrival := fs.lastSentAt.Add(time.Duration(frames) * fs.frameDuration)
+
+	// Allow for the drift of the frame ticker since the departure was confirmed
+	return !nextArrival.Before(trip.DepartureTime.Add(-fs.frameDuration / 2))
+}
+
+// TripProgressMessages returns the TripProgress messages with the updated ETAs
+// for the trips on the given boat that have not reached the destination dock
+func TripProgressMessages(boatID int32) []a.TripProgressMockLogicMessage {
+	var progress []a.TripProgressMockLogicMessage
+
+	for _, trip := range safeTrips.LoadByBoatID(boatID) {
+		if trip.TripState < TripStateReserved || trip.TripState > TripStateClientOnBoat {
+			continue
+		}
+
+		sourceETA, destinationETA := SimSchedule.TripETAs(trip)
+		progressAPIMsg := a.NewTripProgressAPIMessage(a.APIMessageTypeTripProgress,
+			trip.Reservation.ClientID, trip.Boat, trip.TransactionID, sourceETA, destinationETA)
+		progress = append(progress, a.NewTripProgressMockLogicMessage(progressAPIMsg, trip.Client))
+	}
+
+	return progress
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// JournalCompactionEntries is the number of entries that are appended to the
+// journal before it is compacted again while the MockLogic runs
+const JournalCompactionEntries = 1000
+
+// Journal entry types
+const (
+	JournalEntryTypeUnknown int32 = 0
+	JournalEntryTypeTrip    int32 = 1
+	JournalEntryTypeRemoval int32 = 2
+	JournalEntryTypeFrame   int32 = 3
+)
+
+var JournalEntryTypeConversion = map[int32]string{
+	JournalEntryTypeUnknown: "unknown",
+	JournalEntryTypeTrip:    "trip",
+	JournalEntryTypeRemoval: "removal",
+	JournalEntryTypeFrame:   "frame",
+}
+
+// JournalEntry is a line of the journal. A trip entry holds the whole trip after
+// it was created or changed, a removal entry holds the TransactionID of a trip
+// that was removed from the registry, and a frame entry holds the step of the
+// simulation that was sent.
+type JournalEntry struct {
+	EntryType     int32
+	Trip          *Trip
+	TransactionID string
+	Step          int
+}
+
+// JournalState holds the state that was replayed from the journal. Trips are in
+// the order that they were created, and Step is the last step of the simulation
+// that was sent, or -1 if no frame was recorded.
+type JournalState struct {
+	Trips []Trip
+	Step  int
+}
+
+// Journal appends the trips and the frames of the simulation to a file, one JSON
+// entry per line, so that the MockLogic can restore them when it restarts. The
+// entries are written to the file without buffering, so they survive the process
+// exiting. Every method is safe for concurrent use, and the methods of a nil
+// Journal do nothing.
+type Journal struct {
+	mux  sync.Mutex
+	path string
+	file *os.File
+	// appended is the number of entries appended since the journal was compacted
+	appended int
+}
+
+// SimJournal holds the journal of the simulation. It is nil when the journal is
+// not in use.
+var SimJournal *Journal
+
+// OpenJournal replays the journal at the given path and returns it opened for
+// appending, along with the replayed state. A journal that does not exist is
+// created. Replay stops at the first line that cannot be decoded, since an
+// entry may have been cut short when the process exited, and the rest of the
+// file is discarded. The journal is then compacted to a single entry for each
+// trip and the last frame. The trips that have finished are left out of the
+// compacted journal and of the replayed state.
+func OpenJournal(path string) (*Journal, JournalState, error) {
+	state := JournalState{Step: -1}
+
+	file, err := os.Open(path)
+	switch {
+	case err == nil:
+		state, err = replayJournal(file)
+		file.Close()
+		if err != nil {
+			return nil, state, fmt.Errorf("could not replay journal: %s: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, state, fmt.Errorf("could not open journal: %s: %w", path, err)
+	}
+
+	state.Trips = slices.DeleteFunc(state.Trips, func(trip Trip) bool {
+		return trip.IsFinished()
+	})
+	err = compactJournal(path, state)
+	if err != nil {
+		return nil, state, fmt.Errorf("could not compact journal: %s: %w", path, err)
+	}
+
+	file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, state, fmt.Errorf("could not open journal: %s: %w", path, err)
+	}
+
+	return &Journal{path: path, file: file}, state, nil
+}
+
+// replayJournal reads the entries from the journal and returns the state that
+// they record. An error is only returned if the journal could not be read.
+func replayJournal(r io.Reader) (JournalState, error) {
+	state := JournalState{Step: -1}
+	trips := make(map[string]Trip)
+	var order []string
+
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				Logger.Warn().Msgf("Discarding incomplete journal entry on line %d", lineNum)
+			}
+			break
+		}
+		if err != nil {
+			return state, err
+		}
+
+		var entry JournalEntry
+		err = json.Unmarshal(line, &entry)
+		if err != nil {
+			Logger.Warn().Msgf("Discarding the journal from line %d, which could not be decoded: %s",
+				lineNum, err.Error())
+			break
+		}
+
+		switch entry.EntryType {
+		case JournalEntryTypeTrip:
+			if entry.Trip == nil || entry.Trip.TransactionID == "" {
+				Logger.Warn().Msgf("Skipping trip entry without a trip on line %d of the journal", lineNum)
+				continue
+			}
+			if _, ok := trips[entry.Trip.TransactionID]; !ok {
+				order = append(order, entry.Trip.TransactionID)
+			}
+			trips[entry.Trip.TransactionID] = *entry.Trip
+
+		case JournalEntryTypeRemoval:
+			delete(trips, entry.TransactionID)
+
+		case JournalEntryTypeFrame:
+			state.Step = entry.Step
+
+		default:
+			Logger.Warn().Msgf("Skipping journal entry with unknown type: %d on line %d",
+				entry.EntryType, lineNum)
+		}
+	}
+
+	for _, transactionID := range order {
+		trip, ok := trips[transactionID]
+		if ok {
+			state.Trips = append(state.Trips, trip)
+			// A trip that was removed and created again is only added once
+			delete(trips, transactionID)
+		}
+	}
+
+	return state, nil
+}
+
+// compactJournal replaces the journal at the given path with the entries for the
+// given state, leaving out the trips that have finished. The entries are written to
+// a temporary file that is renamed over the journal, so the journal is not lost if
+// the process exits while compacting.
+func compactJournal(path string, state JournalState) error {
+	tempPath := path + ".tmp"
+	file, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for i := range state.Trips {
+		if state.Trips[i].IsFinished() {
+			continue
+		}
+		err = writeJournalEntry(writer, JournalEntry{
+			EntryType: JournalEntryTypeTrip,
+			Trip:      &state.Trips[i],
+		})
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if state.Step >= 0 {
+		err = writeJournalEntry(writer, JournalEntry{
+			EntryType: JournalEntryTypeFrame,
+			Step:      state.Step,
+		})
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	err = writer.Flush()
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+// writeJournalEntry writes the entry to w as a line of JSON
+func writeJournalEntry(w io.Writer, entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+
+	return err
+}
+
+// append writes the entry to the journal. An error is logged rather than
+// returned, since the trips and the simulation carry on without the journal.
+func (j *Journal) append(entry JournalEntry) {
+	if j == nil {
+		return
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	err := writeJournalEntry(j.file, entry)
+	if err != nil {
+		Logger.Error().Msgf("Could not write %s entry to the journal: %s",
+			JournalEntryTypeConversion[entry.EntryType], err.Error())
+		return
+	}
+	j.appended++
+}
+
+// NeedsCompaction returns whether JournalCompactionEntries entries have been
+// appended to the journal since it was compacted
+func (j *Journal) NeedsCompaction() bool {
+	if j == nil {
+		return false
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	return j.appended >= JournalCompactionEntries
+}
+
+// Compact replaces the journal with the entries for the given state and carries
+// on appending to the compacted journal. An error is returned if the journal could
+// not be compacted or opened again.
+func (j *Journal) Compact(state JournalState) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	err := compactJournal(j.path, state)
+	if err != nil {
+		return fmt.Errorf("could not compact journal: %s: %w", j.path, err)
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open journal: %s: %w", j.path, err)
+	}
+	j.file.Close()
+	j.file = file
+	j.appended = 0
+
+	return nil
+}
+
+// RecordTrip writes the trip to the journal after it was created or changed
+func (j *Journal) RecordTrip(trip Trip) {
+	j.append(JournalEntry{
+		EntryType: JournalEntryTypeTrip,
+		Trip:      &trip,
+	})
+}
+
+// RecordRemoval writes the removal of the trip with the given TransactionID to
+// the journal
+func (j *Journal) RecordRemoval(transactionID string) {
+	j.append(JournalEntry{
+		EntryType:     JournalEntryTypeRemoval,
+		TransactionID: transactionID,
+	})
+}
+
+// RecordFrame writes the step of the simulation that was sent to the journal
+func (j *Journal) RecordFrame(step int) {
+	j.append(JournalEntry{
+		EntryType: JournalEntryTypeFrame,
+		Step:      step,
+	})
+}
+
+// Close closes the file of the journal
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	return j.file.Close()
+}
+
+// RestoreTrips inserts the trips replayed from the journal into safeTrips and
+// takes the seats that the passengers of the trips hold in safeSeats
+func RestoreTrips(trips []Trip) {
+	for _, trip := range trips {
+		err := safeTrips.Insert(trip)
+		if err != nil {
+			Logger.Error().Msgf("Could not restore trip with TransactionID: %s: %s",
+				trip.TransactionID, err.Error())
+			continue
+		}
+
+		err = restoreTripSeats(trip)
+		if err != nil {
+			Logger.Error().Msgf("Could not restore the seats of trip with TransactionID: %s on BoatID: %d: %s",
+				trip.TransactionID, trip.Boat.BoatID, err.Error())
+		}
+	}
+	Logger.Info().Msgf("Restored %d trips from the journal", len(trips))
+}
+
+// restoreTripSeats takes the seats that the passengers of the trip hold. A
+// scheduled trip holds no seats, and the seats of the passengers that did not
+// board were given back when the boat arrived at the DestinationDock.
+func restoreTripSeats(trip Trip) error {
+	var reserved int32
+	switch trip.TripState {
+	case TripStateReserved, TripStateClientAtDock, TripStateBoatArrivedAtSource,
+		TripStateClientOnBoat:
+		reserved = trip.CountPassengers(PassengerStateReserved)
+
+	case TripStateBoatArrivedAtDest, TripStateClientOffBoat:
+
+	default:
+		return nil
+	}
+
+	onBoard := trip.CountPassengers(PassengerStateOnBoard)
+	if reserved+onBoard == 0 {
+		return nil
+	}
+	err := safeSeats.Reserve(trip.Boat.BoatID, reserved+onBoard)
+	if err != nil {
+		return err
+	}
+	if onBoard == 0 {
+		return nil
+	}
+
+	return safeSeats.Board(trip.Boat.BoatID, onBoard)
+}
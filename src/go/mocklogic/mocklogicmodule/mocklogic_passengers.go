@@ -0,0 +1,132 @@
+package main
+
+import (
+	a "riden/adapter"
+	"slices"
+)
+
+const (
+	PassengerStateReserved int32 = 0
+	PassengerStateOnBoard  int32 = 1
+	PassengerStateOffBoat  int32 = 2
+)
+
+var PassengerStateConversion = map[int32]string{
+	PassengerStateReserved: "reserved",
+	PassengerStateOnBoard:  "onBoard",
+	PassengerStateOffBoat:  "offBoat",
+}
+
+// Passenger holds a passenger of the party that travels on a trip. Name is empty
+// for a passenger that was not named in the reservation.
+type Passenger struct {
+	Name  string
+	State int32
+}
+
+// PartySize returns the number of seats that the reservation takes. A PartySize
+// of zero takes a seat for each of the PassengerNames, or one seat if no names
+// are given.
+func PartySize(reservation a.ReserveTripAPIMessage) int32 {
+	if reservation.PartySize > 0 {
+		return reservation.PartySize
+	}
+
+	return max(int32(len(reservation.PassengerNames)), 1)
+}
+
+// ValidateReservationParty checks that the PartySize of the reservation is not
+// negative and that the PassengerNames are not empty, are unique and do not
+// outnumber the party
+func ValidateReservationParty(reservation a.ReserveTripAPIMessage) error {
+	if reservation.PartySize < 0 {
+		return NewTripMessageError(a.ErrorReasonInvalidTrip,
+			"party size: %d, is negative", reservation.PartySize)
+	}
+	partySize := PartySize(reservation)
+	if int32(len(reservation.PassengerNames)) > partySize {
+		return NewTripMessageError(a.ErrorReasonInvalidTrip,
+			"%d passenger names were given for a party of %d",
+			len(reservation.PassengerNames), partySize)
+	}
+	for i, name := range reservation.PassengerNames {
+		if name == "" {
+			return NewTripMessageError(a.ErrorReasonInvalidTrip, "passenger name is empty")
+		}
+		if slices.Contains(reservation.PassengerNames[:i], name) {
+			return NewTripMessageError(a.ErrorReasonInvalidTrip,
+				"passenger name: %q, is given more than once", name)
+		}
+	}
+
+	return nil
+}
+
+// NewPassengers returns the passengers of the party in the reservation, in the
+// order of the PassengerNames, followed by the passengers that were not named
+func NewPassengers(reservation a.ReserveTripAPIMessage) []Passenger {
+	passengers := make([]Passenger, PartySize(reservation))
+	for i, name := range reservation.PassengerNames {
+		passengers[i].Name = name
+	}
+
+	return passengers
+}
+
+// CountPassengers returns the number of passengers of the trip in the given state
+func (t *Trip) CountPassengers(state int32) int32 {
+	var count int32
+	for _, passenger := range t.Passengers {
+		if passenger.State == state {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AdvancePassengers moves the passengers of the trip with the given names from
+// state from to state to, and returns the number of passengers that were moved.
+// When no names are given, every passenger in state from is moved. A
+// TripMessageError is returned and no passenger is moved if a name is not in the
+// party, a named passenger is not in state from, or there is no passenger to move.
+func (t *Trip) AdvancePassengers(names []string, from, to int32) (int32, error) {
+	// The passengers are copied so that the trips loaded from safeTrips before
+	// the update are not changed
+	passengers := slices.Clone(t.Passengers)
+	var moved int32
+
+	if len(names) == 0 {
+		for i := range passengers {
+			if passengers[i].State == from {
+				passengers[i].State = to
+				moved++
+			}
+		}
+		if moved == 0 {
+			return 0, NewTripMessageError(a.ErrorReasonInvalidTripState,
+				"no passenger of the party is in state: %s", PassengerStateConversion[from])
+		}
+		t.Passengers = passengers
+		return moved, nil
+	}
+
+	for _, name := range names {
+		i := slices.IndexFunc(passengers, func(passenger Passenger) bool {
+			return name != "" && passenger.Name == name
+		})
+		if i < 0 {
+			return 0, NewTripMessageError(a.ErrorReasonTripMismatch,
+				"passenger: %q, is not in the party of the trip", name)
+		}
+		if passengers[i].State != from {
+			return 0, NewTripMessageError(a.ErrorReasonInvalidTripState,
+				"passenger: %q, is in state: %s", name, PassengerStateConversion[passengers[i].State])
+		}
+		passengers[i].State = to
+		moved++
+	}
+	t.Passengers = passengers
+
+	return moved, nil
+}
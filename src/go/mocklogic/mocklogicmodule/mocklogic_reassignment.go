@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	a "riden/adapter"
+)
+
+// errNotStranded is returned from the trip update in ReassignTrips when the trip
+// is not affected by its boat going out of service
+var errNotStranded = errors.New("trip is not stranded by the boat going out of service")
+
+// ReassignTrips moves the trips on the given boat, which has gone out of service,
+// to another boat in service, and returns the Ack messages that inform the clients
+// of the new boat. A trip that could not be reassigned is cancelled, and the Error
+// messages that inform the clients are returned. Trips with passengers on board the
+// boat cannot be moved, so they are flagged for operator follow-up.
+func ReassignTrips(boatID int32) ([]a.AckMockLogicMessage, []a.ErrorMockLogicMessage) {
+	var acks []a.AckMockLogicMessage
+	var errMsgs []a.ErrorMockLogicMessage
+
+	for _, trip := range safeTrips.LoadByBoatID(boatID) {
+		var reassignErr error
+		// A scheduled trip does not hold a seat until it is activated
+		holdsSeats := false
+		updatedTrip, err := safeTrips.Update(trip.TransactionID, func(t *Trip) error {
+			switch t.TripState {
+			case TripStateReserved, TripStateClientAtDock, TripStateBoatArrivedAtSource,
+				TripStateScheduled:
+				holdsSeats = t.TripState != TripStateScheduled
+				reassignErr = t.reassignBoat()
+				if reassignErr != nil {
+					return t.AdvanceToTripState(TripStateCancelled)
+				}
+				return nil
+
+			case TripStateClientOnBoat, TripStateBoatArrivedAtDest:
+				t.NeedsFollowUp = true
+				return nil
+
+			default:
+				return errNotStranded
+			}
+		})
+		if errors.Is(err, errNotStranded) {
+			continue
+		}
+		if err != nil {
+			Logger.Error().Msgf("Could not update trip with TransactionID: %s on out of service BoatID: %d: %s",
+				trip.TransactionID, boatID, err.Error())
+			continue
+		}
+
+		if updatedTrip.NeedsFollowUp {
+			Logger.Warn().Msgf("Trip with TransactionID: %s for ClientID: %s is on board out of service BoatID: %d and needs operator follow-up",
+				updatedTrip.TransactionID, updatedTrip.Reservation.ClientID, boatID)
+			continue
+		}
+
+		if holdsSeats {
+			releaseSeats(boatID, PartySize(updatedTrip.Reservation), false)
+		}
+
+		if reassignErr != nil {
+			Logger.Warn().Msgf("Cancelled trip with TransactionID: %s for ClientID: %s on out of service BoatID: %d: %s",
+				updatedTrip.TransactionID, updatedTrip.Reservation.ClientID, boatID, reassignErr.Error())
+			errMsgs = append(errMsgs, NewTripMessageErrorMessage(reassignErr,
+				a.APIMessageTypeReserveTrip, updatedTrip.Reservation.ClientID,
+				updatedTrip.TransactionID, updatedTrip.Client))
+			continue
+		}
+
+		Logger.Info().Msgf("Reassigned trip with TransactionID: %s for ClientID: %s from out of service BoatID: %d to BoatID: %d",
+			updatedTrip.TransactionID, updatedTrip.Reservation.ClientID, boatID, updatedTrip.Boat.BoatID)
+		sourceETA, destinationETA := SimSchedule.TripETAs(updatedTrip)
+		ackAPIMsg := a.NewAckAPIMessage(a.APIMessageTypeAck, updatedTrip.Reservation.ClientID,
+			true, updatedTrip.Boat, updatedTrip.TransactionID, a.ErrorReasonUnknown, "",
+			sourceETA, destinationETA, updatedTrip.DepartureTime)
+		acks = append(acks, a.NewAckMockLogicMessage(ackAPIMsg, updatedTrip.Client))
+	}
+
+	return acks, errMsgs
+}
+
+// reassignBoat assigns another boat in service to a trip that has not boarded, with
+// the same selection as a new reservation. The seats of the party are reserved on
+// the new boat, and a scheduled trip is scheduled again for the DepartureTime of the
+// reservation. A TripMessageError is returned and the trip is not changed if no boat
+// could be assigned.
+func (t *Trip) reassignBoat() error {
+	reassigned := *t
+
+	if t.TripState == TripStateScheduled {
+		// The trip is scheduled again from the start
+		reassigned.TripState = TripStateUnknown
+		err := reassigned.ScheduleDeparture()
+		if err != nil {
+			return err
+		}
+		*t = reassigned
+		return nil
+	}
+
+	err := reassigned.GetBoatAndServiceStateForTrip()
+	if err != nil {
+		return err
+	}
+	if reassigned.TripState == TripStateBoatArrivedAtSource {
+		// The new boat has not arrived at the source dock
+		reassigned.TripState = TripStateReserved
+	}
+	*t = reassigned
+
+	return nil
+}
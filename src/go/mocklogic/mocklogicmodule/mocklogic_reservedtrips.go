@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	a "riden/adapter"
+	"slices"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,40 +20,399 @@ const (
 	TripStateClientOnBoat        int32 = 4
 	TripStateBoatArrivedAtDest   int32 = 5
 	TripStateClientOffBoat       int32 = 6
+	TripStateCancelled           int32 = 7
+	TripStateScheduled           int32 = 8
 )
 
+var TripStateConversion = map[int32]string{
+	TripStateUnknown:             "unknown",
+	TripStateReserved:            "reserved",
+	TripStateClientAtDock:        "clientAtDock",
+	TripStateBoatArrivedAtSource: "boatArrivedAtSource",
+	TripStateClientOnBoat:        "clientOnBoat",
+	TripStateBoatArrivedAtDest:   "boatArrivedAtDest",
+	TripStateClientOffBoat:       "clientOffBoat",
+	TripStateCancelled:           "cancelled",
+	TripStateScheduled:           "scheduled",
+}
+
+// Trip holds a reserved trip. DepartureTime is the confirmed departure from the
+// SourceDock of a trip reserved with a DepartureTime, and is zero for a trip on
+// the next boat. Passengers holds the state of each passenger of the party.
+// NeedsFollowUp is set when the boat went out of service with passengers of the
+// trip on board, so that an operator can follow up with the client. FinishedAt is
+// the time that the trip finished, and is zero until then.
 type Trip struct {
 	Reservation   a.ReserveTripAPIMessage
+	Client        a.ClientData
 	TransactionID string
 	Boat          a.Boat
 	ServiceState  int32
 	TripState     int32
+	DepartureTime time.Time
+	Passengers    []Passenger
+	NeedsFollowUp bool
+	FinishedAt    time.Time
 }
 
 // NewTrip returns a new Trip with the fields populated and returns
-// an error if the reservation could not be made
+// an error if the reservation could not be made. A reservation with a
+// DepartureTime in the future is scheduled on the boat that departs at or
+// after that time. Every passenger of the party is seated on the same boat.
 func NewTrip(reservation a.ReserveTripAPIMessage) (*Trip, error) {
 	var trip = Trip{
 		Reservation: reservation,
 	}
 	trip.GenerateTransactionID()
-	err := trip.GetBoatAndServiceStateForTrip()
+	err := ValidateReservationDocks(reservation)
+	if err != nil {
+		return &trip, err
+	}
+	err = ValidateReservationParty(reservation)
+	if err != nil {
+		return &trip, err
+	}
+	trip.Passengers = NewPassengers(reservation)
+	if reservation.DepartureTime.After(SimClock.Now()) {
+		err = trip.ScheduleDeparture()
+		return &trip, err
+	}
+	err = trip.GetBoatAndServiceStateForTrip()
 	if err != nil {
 		return &trip, err
 	}
 	err = trip.AdvanceToTripState(TripStateReserved)
 	if err != nil {
+		releaseSeats(trip.Boat.BoatID, PartySize(reservation), false)
 		return &trip, err
 	}
 
 	return &trip, err
 }
 
+// ProcessReserveTrip creates a Trip for the reservation in the given message and
+// returns the Ack that should be sent to the client that made the reservation.
+// If a boat could not be assigned to the trip, the Ack has IsReserved set to false
+// and gives the reason.
+func ProcessReserveTrip(reserveMsg a.ReserveTripMockLogicMessage) a.AckMockLogicMessage {
+	trip, err := NewTrip(reserveMsg.APIMessage)
+	if err != nil {
+		Logger.Warn().Msgf("Could not reserve trip for ClientID: %s: %s",
+			reserveMsg.APIMessage.ClientID, err.Error())
+		return newRejectedAck(reserveMsg, err)
+	}
+	trip.Client = reserveMsg.Client
+	err = safeTrips.Insert(*trip)
+	if err != nil {
+		Logger.Error().Msgf("Could not store trip for ClientID: %s: %s",
+			reserveMsg.APIMessage.ClientID, err.Error())
+		if trip.TripState != TripStateScheduled {
+			releaseSeats(trip.Boat.BoatID, PartySize(trip.Reservation), false)
+		}
+		return newRejectedAck(reserveMsg, err)
+	}
+
+	Logger.Info().Msgf("Reserved trip with TransactionID: %s on BoatID: %d for ClientID: %s, party of %d",
+		trip.TransactionID, trip.Boat.BoatID, reserveMsg.APIMessage.ClientID,
+		PartySize(trip.Reservation))
+	sourceETA, destinationETA := SimSchedule.TripETAs(*trip)
+	ackAPIMsg := a.NewAckAPIMessage(a.APIMessageTypeAck, reserveMsg.APIMessage.ClientID,
+		true, trip.Boat, trip.TransactionID, a.ErrorReasonUnknown, "", sourceETA, destinationETA,
+		trip.DepartureTime)
+
+	return a.NewAckMockLogicMessage(ackAPIMsg, reserveMsg.Client)
+}
+
+// newRejectedAck returns the Ack for a reservation that could not be made. The
+// ReasonCode is taken from the error if it is a TripMessageError.
+func newRejectedAck(reserveMsg a.ReserveTripMockLogicMessage, err error) a.AckMockLogicMessage {
+	reasonCode := a.ErrorReasonUnknown
+	var tripErr *TripMessageError
+	if errors.As(err, &tripErr) {
+		reasonCode = tripErr.ReasonCode
+	}
+	ackAPIMsg := a.NewAckAPIMessage(a.APIMessageTypeAck, reserveMsg.APIMessage.ClientID,
+		false, a.Boat{}, "", reasonCode, err.Error(), time.Time{}, time.Time{}, time.Time{})
+
+	return a.NewAckMockLogicMessage(ackAPIMsg, reserveMsg.Client)
+}
+
+// TripMessageError describes why a message from a client could not be applied
+// to a trip. The ReasonCode is one of the ErrorReason codes that are sent to the
+// client in the Error message.
+type TripMessageError struct {
+	ReasonCode int32
+	Reason     string
+}
+
+func NewTripMessageError(reasonCode int32, format string, args ...any) *TripMessageError {
+	return &TripMessageError{
+		ReasonCode: reasonCode,
+		Reason:     fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *TripMessageError) Error() string {
+	return e.Reason
+}
+
+// NewTripMessageErrorMessage returns the Error message that informs the client that
+// the message of the given type was rejected with the given error
+func NewTripMessageErrorMessage(err error, rejectedMsgType, clientID, transactionID string,
+	client a.ClientData) a.ErrorMockLogicMessage {
+	reasonCode := a.ErrorReasonUnknown
+	var tripErr *TripMessageError
+	if errors.As(err, &tripErr) {
+		reasonCode = tripErr.ReasonCode
+	}
+	errAPIMsg := a.NewErrorAPIMessage(a.APIMessageTypeError, clientID, reasonCode,
+		err.Error(), rejectedMsgType, transactionID)
+
+	return a.NewErrorMockLogicMessage(errAPIMsg, client)
+}
+
+// ProcessAtDock checks that the AtDock message matches the reservation of the trip
+// and advances the trip to TripStateClientAtDock. If the boat has already arrived at
+// the source dock, the message is accepted without changing the trip state. A
+// TripMessageError is returned if the message was rejected.
+func ProcessAtDock(atDockMsg a.AtDockAPIMessage) error {
+	_, err := safeTrips.Update(atDockMsg.TransactionID, func(trip *Trip) error {
+		err := validateTripMessage(trip, atDockMsg.ClientID, atDockMsg.Boat)
+		if err != nil {
+			return err
+		}
+		if atDockMsg.Dock != trip.Reservation.SourceDock {
+			return NewTripMessageError(a.ErrorReasonTripMismatch,
+				"dock: %d %s, is not the source dock of the trip",
+				atDockMsg.Dock.Address.Number, atDockMsg.Dock.Address.Street)
+		}
+		if trip.TripState == TripStateBoatArrivedAtSource {
+			return nil
+		}
+
+		return advanceForTripMessage(trip, TripStateClientAtDock)
+	})
+
+	return tripMessageUpdateError(atDockMsg.TransactionID, err)
+}
+
+// ProcessOnBoat checks that the OnBoat message matches the reservation of the trip
+// and boards the passengers in the message, or every passenger of the party that
+// has not boarded if the message does not name any. The trip advances to
+// TripStateClientOnBoat when the first passengers board, and the rest of the
+// party may board until the boat arrives at the destination dock. A
+// TripMessageError is returned if the message was rejected.
+func ProcessOnBoat(onBoatMsg a.OnBoatAPIMessage) error {
+	var boarded int32
+	trip, err := safeTrips.Update(onBoatMsg.TransactionID, func(trip *Trip) error {
+		err := validateTripMessage(trip, onBoatMsg.ClientID, onBoatMsg.Boat)
+		if err != nil {
+			return err
+		}
+		if trip.TripState != TripStateClientOnBoat {
+			err = advanceForTripMessage(trip, TripStateClientOnBoat)
+			if err != nil {
+				return err
+			}
+		}
+
+		boarded, err = trip.AdvancePassengers(onBoatMsg.PassengerNames,
+			PassengerStateReserved, PassengerStateOnBoard)
+		return err
+	})
+	if err != nil {
+		return tripMessageUpdateError(onBoatMsg.TransactionID, err)
+	}
+
+	err = safeSeats.Board(trip.Boat.BoatID, boarded)
+	if err != nil {
+		Logger.Error().Msgf("Could not board seat for TransactionID: %s: %s",
+			trip.TransactionID, err.Error())
+	}
+
+	return nil
+}
+
+// ProcessOffBoat checks that the OffBoat message matches the reservation of the trip
+// and gives back the seats of the passengers in the message, or of every passenger
+// of the party on board if the message does not name any. The trip advances to
+// TripStateClientOffBoat when the first passengers get off. A TripMessageError is
+// returned if the message was rejected.
+func ProcessOffBoat(offBoatMsg a.OffBoatAPIMessage) error {
+	var leaving int32
+	trip, err := safeTrips.Update(offBoatMsg.TransactionID, func(trip *Trip) error {
+		err := validateTripMessage(trip, offBoatMsg.ClientID, offBoatMsg.Boat)
+		if err != nil {
+			return err
+		}
+		if trip.TripState != TripStateClientOffBoat {
+			err = advanceForTripMessage(trip, TripStateClientOffBoat)
+			if err != nil {
+				return err
+			}
+		}
+
+		leaving, err = trip.AdvancePassengers(offBoatMsg.PassengerNames,
+			PassengerStateOnBoard, PassengerStateOffBoat)
+		return err
+	})
+	if err != nil {
+		return tripMessageUpdateError(offBoatMsg.TransactionID, err)
+	}
+
+	releaseSeats(trip.Boat.BoatID, leaving, true)
+
+	return nil
+}
+
+// ProcessCancelTrip cancels the trip in the CancelTrip message and returns the
+// CancelAck that should be sent to the client. The seats reserved for the party
+// are given back to the boat. If the trip could not be cancelled, such as when the
+// client is already on board, the CancelAck has IsCancelled set to false and gives
+// the reason.
+func ProcessCancelTrip(cancelMsg a.CancelTripMockLogicMessage) a.CancelAckMockLogicMessage {
+	apiMsg := cancelMsg.APIMessage
+	// A scheduled trip does not hold a seat until it is activated
+	holdsSeat := false
+	trip, err := safeTrips.Update(apiMsg.TransactionID, func(trip *Trip) error {
+		if apiMsg.ClientID != trip.Reservation.ClientID {
+			return NewTripMessageError(a.ErrorReasonTripMismatch,
+				"ClientID: %s, did not reserve the trip", apiMsg.ClientID)
+		}
+		if trip.TripState == TripStateClientOnBoat || trip.TripState == TripStateBoatArrivedAtDest {
+			return NewTripMessageError(a.ErrorReasonInvalidTripState,
+				"client is already on board the boat")
+		}
+
+		holdsSeat = trip.TripState != TripStateScheduled
+		return advanceForTripMessage(trip, TripStateCancelled)
+	})
+	err = tripMessageUpdateError(apiMsg.TransactionID, err)
+	if err != nil {
+		Logger.Warn().Msgf("Could not cancel trip with TransactionID: %s for ClientID: %s: %s",
+			apiMsg.TransactionID, apiMsg.ClientID, err.Error())
+		reasonCode := a.ErrorReasonUnknown
+		var tripErr *TripMessageError
+		if errors.As(err, &tripErr) {
+			reasonCode = tripErr.ReasonCode
+		}
+		cancelAckAPIMsg := a.NewCancelAckAPIMessage(a.APIMessageTypeCancelAck, apiMsg.ClientID,
+			apiMsg.TransactionID, false, reasonCode, err.Error())
+
+		return a.NewCancelAckMockLogicMessage(cancelAckAPIMsg, cancelMsg.Client)
+	}
+
+	if holdsSeat {
+		releaseSeats(trip.Boat.BoatID, PartySize(trip.Reservation), false)
+	}
+
+	Logger.Info().Msgf("Cancelled trip with TransactionID: %s on BoatID: %d for ClientID: %s",
+		trip.TransactionID, trip.Boat.BoatID, apiMsg.ClientID)
+	cancelAckAPIMsg := a.NewCancelAckAPIMessage(a.APIMessageTypeCancelAck, apiMsg.ClientID,
+		apiMsg.TransactionID, true, a.ErrorReasonUnknown, "")
+
+	return a.NewCancelAckMockLogicMessage(cancelAckAPIMsg, cancelMsg.Client)
+}
+
+// ProcessGetTrip looks up the trip in the GetTrip message and returns the TripStatus
+// that should be sent to the client, with the current state of the trip, the latest
+// status of the assigned boat and the ETAs. If the trip was not found or was reserved
+// by another client, the TripStatus has IsFound set to false and gives the reason.
+func ProcessGetTrip(getTripMsg a.GetTripMockLogicMessage) a.TripStatusMockLogicMessage {
+	apiMsg := getTripMsg.APIMessage
+	trip, ok := safeTrips.Load(apiMsg.TransactionID)
+	if !ok || trip.Reservation.ClientID != apiMsg.ClientID {
+		// A trip reserved by another client is reported as not found so that
+		// clients cannot discover the trips of other clients
+		Logger.Warn().Msgf("Could not find trip with TransactionID: %s for ClientID: %s",
+			apiMsg.TransactionID, apiMsg.ClientID)
+		tripStatusAPIMsg := a.NewTripStatusAPIMessage(a.APIMessageTypeTripStatus,
+			apiMsg.ClientID, apiMsg.TransactionID, false, a.ErrorReasonTripNotFound,
+			fmt.Sprintf("trip with TransactionID: %s was not found", apiMsg.TransactionID),
+			"", a.Dock{}, a.Dock{}, a.Boat{}, a.BoatStatusAPIMessage{}, time.Time{}, time.Time{})
+
+		return a.NewTripStatusMockLogicMessage(tripStatusAPIMsg, getTripMsg.Client)
+	}
+
+	var boatStatus a.BoatStatusAPIMessage
+	boatStatusVal, ok := safeBoatStatuses.Load(trip.Boat.BoatID)
+	if ok {
+		boatStatus = boatStatusVal.(a.BoatStatusAPIMessage)
+	}
+	sourceETA, destinationETA := SimSchedule.TripETAs(trip)
+	tripStatusAPIMsg := a.NewTripStatusAPIMessage(a.APIMessageTypeTripStatus, apiMsg.ClientID,
+		apiMsg.TransactionID, true, a.ErrorReasonUnknown, "", TripStateConversion[trip.TripState],
+		trip.Reservation.SourceDock, trip.Reservation.DestinationDock, trip.Boat, boatStatus,
+		sourceETA, destinationETA)
+
+	return a.NewTripStatusMockLogicMessage(tripStatusAPIMsg, getTripMsg.Client)
+}
+
+// releaseSeats gives back the given number of seats on the boat and logs the
+// error if the seat ledger does not hold the seats
+func releaseSeats(boatID, count int32, onBoard bool) {
+	err := safeSeats.Release(boatID, count, onBoard)
+	if err != nil {
+		Logger.Error().Msgf("Could not release seats: %s", err.Error())
+	}
+}
+
+// validateTripMessage checks that the ClientID and the boat in a message from a
+// client match the reservation of the trip
+func validateTripMessage(trip *Trip, clientID string, boat a.Boat) error {
+	if clientID != trip.Reservation.ClientID {
+		return NewTripMessageError(a.ErrorReasonTripMismatch,
+			"ClientID: %s, did not reserve the trip", clientID)
+	}
+	if boat.BoatID != trip.Boat.BoatID {
+		return NewTripMessageError(a.ErrorReasonTripMismatch,
+			"BoatID: %d, is not the boat reserved for the trip", boat.BoatID)
+	}
+
+	return nil
+}
+
+// advanceForTripMessage advances the trip to the given state and returns a
+// TripMessageError if the message arrived out of order
+func advanceForTripMessage(trip *Trip, state int32) error {
+	err := trip.AdvanceToTripState(state)
+	if err != nil {
+		return NewTripMessageError(a.ErrorReasonInvalidTripState,
+			"trip is in state: %d, and cannot advance to state: %d", trip.TripState, state)
+	}
+
+	return nil
+}
+
+// tripMessageUpdateError converts an error returned from updating the trip in
+// safeTrips to a TripMessageError
+func tripMessageUpdateError(transactionID string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var tripErr *TripMessageError
+	if errors.As(err, &tripErr) {
+		return tripErr
+	}
+
+	// The only other error from Update is a missing trip
+	return NewTripMessageError(a.ErrorReasonTripNotFound,
+		"trip with TransactionID: %s was not found", transactionID)
+}
+
+// transactionSequence is appended to the TransactionID so that trips reserved
+// within the same millisecond do not share a TransactionID
+var transactionSequence atomic.Uint64
+
 func (t *Trip) GenerateTransactionID() {
-	transactionNum := time.Now().UnixMilli()
-	t.TransactionID = strconv.Itoa(int(transactionNum))
+	transactionNum := SimClock.Now().UnixMilli()
+	t.TransactionID = strconv.Itoa(int(transactionNum)) + "-" +
+		strconv.FormatUint(transactionSequence.Add(1), 10)
 }
 
+// GetBoatAndServiceStateForTrip assigns the closest boat in service that has a
+// seat available for every passenger of the party to the trip and reserves the
+// seats on it. A TripMessageError is returned if no boat could be assigned.
 func (t *Trip) GetBoatAndServiceStateForTrip() error {
 	var err error
 	var potentialBoats []a.Boat
@@ -58,38 +420,72 @@ func (t *Trip) GetBoatAndServiceStateForTrip() error {
 	// if they are on-time or delayed
 	AddBoatsInService(&potentialBoats)
 	if len(potentialBoats) == 0 {
-		err = fmt.Errorf("no boats were available for service")
+		err = NewTripMessageError(a.ErrorReasonNoBoatInService, "no boats were available for service")
 		return err
 	}
 	// Check which boat is closest to the SourceDock and set as the boat for
-	// the trip and set the service state of the boat.
-	closestBoat, err := ReturnClosestBoat(t.Reservation.SourceDock, &potentialBoats)
-	if err != nil {
-		return err
-	}
-	t.Boat = closestBoat
-	boatStatusVal, ok := safeBoatStatuses.Load(t.Boat.BoatID)
-	if ok {
-		boatStatus := boatStatusVal.(a.BoatStatusAPIMessage)
-		t.ServiceState = boatStatus.ServiceState
-	} else {
-		err = fmt.Errorf("could not retrieve boat status for ID: %d", t.Boat.BoatID)
+	// the trip and set the service state of the boat. Boats without room for
+	// the party are removed from the potential boats and the next closest boat
+	// is checked.
+	for len(potentialBoats) > 0 {
+		var closestBoat a.Boat
+		closestBoat, err = ReturnClosestBoat(t.Reservation.SourceDock, &potentialBoats)
+		if err != nil {
+			return NewTripMessageError(a.ErrorReasonNoBoatInService, "%s", err.Error())
+		}
+		boatStatusVal, ok := safeBoatStatuses.Load(closestBoat.BoatID)
+		if !ok {
+			err = fmt.Errorf("could not retrieve boat status for ID: %d", closestBoat.BoatID)
+			return err
+		}
+
+		err = safeSeats.Reserve(closestBoat.BoatID, PartySize(t.Reservation))
+		if errors.Is(err, ErrBoatFull) {
+			potentialBoats = slices.DeleteFunc(potentialBoats, func(boat a.Boat) bool {
+				return boat.BoatID == closestBoat.BoatID
+			})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		t.Boat = closestBoat
+		t.ServiceState = boatStatusVal.(a.BoatStatusAPIMessage).ServiceState
+		return nil
 	}
 
+	err = NewTripMessageError(a.ErrorReasonBoatsFull,
+		"no boat in service has %d seats available", PartySize(t.Reservation))
 	return err
 }
 
+// tripStateTransitions holds the states that a trip may advance to from each
+// state. Trips normally advance one state at a time, but the boat may arrive at
+// the source dock before the client has reported that they are at the dock. A
+// trip may be cancelled until the client is on board, and TripStateCancelled is
+// a terminal state. A scheduled trip is reserved when its departure is close.
+var tripStateTransitions = map[int32][]int32{
+	TripStateUnknown:             {TripStateReserved, TripStateScheduled},
+	TripStateReserved:            {TripStateClientAtDock, TripStateBoatArrivedAtSource, TripStateCancelled},
+	TripStateClientAtDock:        {TripStateBoatArrivedAtSource, TripStateCancelled},
+	TripStateBoatArrivedAtSource: {TripStateClientOnBoat, TripStateCancelled},
+	TripStateClientOnBoat:        {TripStateBoatArrivedAtDest},
+	TripStateBoatArrivedAtDest:   {TripStateClientOffBoat},
+	TripStateScheduled:           {TripStateReserved, TripStateCancelled},
+}
+
 // AdvanceToTripState checks the current trip state and advances it to the given
 // state if that is permitted
 func (t *Trip) AdvanceToTripState(state int32) error {
 	var err error
-	if state-1 != t.TripState {
-		err = fmt.Errorf("current state: %d, cannot advance to state: %d", t.TripState, state)
+	if state > TripStateScheduled || state < TripStateReserved {
+		err = fmt.Errorf("state: %d, is out of range", state)
 		return err
 	}
 
-	if state > TripStateClientOffBoat || state < TripStateReserved {
-		err = fmt.Errorf("state: %d, is out of range", state)
+	if !slices.Contains(tripStateTransitions[t.TripState], state) {
+		err = fmt.Errorf("current state: %d, cannot advance to state: %d", t.TripState, state)
 		return err
 	}
 
@@ -98,6 +494,19 @@ func (t *Trip) AdvanceToTripState(state int32) error {
 	return err
 }
 
+// IsFinished returns whether the trip has finished, which is when it was cancelled
+// or when the party got off the boat and no passenger is left on board
+func (t *Trip) IsFinished() bool {
+	switch t.TripState {
+	case TripStateCancelled:
+		return true
+	case TripStateClientOffBoat:
+		return t.CountPassengers(PassengerStateOnBoard) == 0
+	}
+
+	return false
+}
+
 // safeBoatStatuses holds the statuses of the boats in the system in a
 // [int32]BoatStatusAPIMessage map
 var safeBoatStatuses sync.Map
@@ -115,43 +524,59 @@ func AddBoatsInService(boats *[]a.Boat) {
 }
 
 // ReturnClosestBoat checks which boat is closest to the given dock and
-// returns it. The check should use the NextDock field in the
-// BoatStatusAPIMessage. If the CurrentDock of the boat is the same as
-// the SourceDock, boarding has begun and it is too late to reserve
-// this boat.
+// returns it. The check uses the NextDock field in the BoatStatusAPIMessage
+// and the travel time through SimDockGraph. If the CurrentDock of the boat
+// is the same as the SourceDock, boarding has begun and it is too late to
+// reserve this boat, so it is skipped. Boats that cannot reach the dock are
+// also skipped, and an error is returned if no boat can reach it.
 func ReturnClosestBoat(dock a.Dock, boats *[]a.Boat) (a.Boat, error) {
 	var err error
-	var shortestDistance int8 = math.MaxInt8
+	shortestFrames := math.MaxInt
 	var closestBoat a.Boat
-	var boatStatus a.BoatStatusAPIMessage
 	for _, boat := range *boats {
 		boatStatusVal, ok := safeBoatStatuses.Load(boat.BoatID)
-		if ok {
-			boatStatus = boatStatusVal.(a.BoatStatusAPIMessage)
-		} else {
-			err = fmt.Errorf("could not retrieve boat status for ID: %d", boat.BoatID)
+		if !ok {
+			Logger.Warn().Msgf("Could not retrieve boat status for ID: %d", boat.BoatID)
+			continue
 		}
-		distance := ReturnDistance(boatStatus.NextDock, dock)
-		if distance < shortestDistance {
+		boatStatus := boatStatusVal.(a.BoatStatusAPIMessage)
+		if boatStatus.CurrentDock == dock {
+			Logger.Debug().Msgf("BoatID: %d is boarding at the dock", boat.BoatID)
+			continue
+		}
+
+		frames, routeErr := SimDockGraph.TravelFrames(boatStatus.NextDock, dock)
+		if routeErr != nil {
+			Logger.Debug().Msgf("BoatID: %d cannot reach dock: %s", boat.BoatID, routeErr.Error())
+			continue
+		}
+		if frames < shortestFrames {
+			shortestFrames = frames
 			closestBoat = boat
 		}
 	}
 
-	return closestBoat, err
+	if shortestFrames == math.MaxInt {
+		err = fmt.Errorf("no boat in service can reach dock: %d %s",
+			dock.Address.Number, dock.Address.Street)
+	}
 
+	return closestBoat, err
 }
 
-// ReturnDistance returns an integer representing the distance betweeen the start
-// and goal docks
-func ReturnDistance(start, goal a.Dock) int8 {
-	if start == goal {
-		return 0
+// ValidateReservationDocks checks that the SourceDock and the DestinationDock of
+// the reservation are in SimDockGraph and that the DestinationDock can be reached
+// from the SourceDock
+func ValidateReservationDocks(reservation a.ReserveTripAPIMessage) error {
+	if reservation.SourceDock == reservation.DestinationDock {
+		return NewTripMessageError(a.ErrorReasonInvalidTrip,
+			"source dock and destination dock are the same")
 	}
 
-	// Get the next dock after start and recursively call
-	// ReturnDistance()
-	var nextDock a.Dock
-	nextDock = SimDockAdjacencyList[start.Address.Street].Front().Value.(a.Dock)
+	_, err := SimDockGraph.TravelFrames(reservation.SourceDock, reservation.DestinationDock)
+	if err != nil {
+		return NewTripMessageError(a.ErrorReasonInvalidTrip, "invalid trip: %s", err.Error())
+	}
 
-	return 1 + ReturnDistance(nextDock, goal)
+	return nil
 }
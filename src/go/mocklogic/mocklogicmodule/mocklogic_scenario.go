@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	a "riden/adapter"
+	"time"
+)
+
+// defaultScenarioBytes simulates two boats moving between 4 docks. The total
+// number of frames is 16, with the boats remaining stationary at the docks
+// for two frames each and spending two frames traveling between each dock.
+//
+//go:embed scenarios/default.json
+var defaultScenarioBytes []byte
+
+// Scenario describes a simulation run: the boats, the docks, the routes the
+// boats travel between the docks, the frames that give the status of each
+// boat over time, and the disruptions to the service of the boats. Docks are
+// referred to by Name within the scenario.
+type Scenario struct {
+	Name string
+	// FrameDuration is parsed with time.ParseDuration, e.g. "15s". When it
+	// is empty, DefaultSimFrameDuration is used.
+	FrameDuration string
+	Boats         []ScenarioBoat
+	Docks         []ScenarioDock
+	Routes        []ScenarioRoute
+	Frames        [][]ScenarioBoatLocation
+	Disruptions   []ScenarioDisruption
+}
+
+// ScenarioBoat is a boat and the number of passengers that it can carry
+type ScenarioBoat struct {
+	BoatID   int32
+	Name     string
+	Capacity int32
+}
+
+type ScenarioDock struct {
+	Name    string
+	Address a.Address
+	Gangway string
+}
+
+// ScenarioRoute is a directed connection from one dock to the next dock that
+// a boat travels to. TravelFrames is the number of frames from the boat arriving
+// at the From dock until it arrives at the To dock. A dock may have routes to
+// more than one dock.
+type ScenarioRoute struct {
+	From         string
+	To           string
+	TravelFrames int
+}
+
+// ScenarioBoatLocation is the status of one boat in one frame. CurrentDock is
+// empty while the boat is travelling between PreviousDock and NextDock.
+type ScenarioBoatLocation struct {
+	BoatID       int32
+	ServiceState int32
+	PreviousDock string
+	CurrentDock  string
+	NextDock     string
+}
+
+// ScenarioDisruption is a timed change to the service of one boat. From
+// StartFrame, the boat remains where it is for DurationFrames frames with the
+// given ServiceState, and then continues its route that many frames later than
+// the frames of the scenario. StartFrame counts the frames sent since the start
+// of the simulation, so it may fall in a later loop of the frames. A disruption
+// with ServiceStateUnavailable and no DurationFrames takes the boat out of
+// service for the rest of the simulation.
+type ScenarioDisruption struct {
+	BoatID         int32
+	ServiceState   int32
+	StartFrame     int
+	DurationFrames int
+}
+
+// LoadScenario reads and validates the scenario in the given file. The
+// default scenario is returned when filePath is empty.
+func LoadScenario(filePath string) (Scenario, error) {
+	if filePath == "" {
+		return ParseScenario(defaultScenarioBytes)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("could not read scenario file: %s: %w", filePath, err)
+	}
+
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("invalid scenario file: %s: %w", filePath, err)
+	}
+
+	return scenario, nil
+}
+
+// ParseScenario decodes a JSON scenario and validates it. Unknown fields are
+// rejected so that misspelled keys are not silently ignored.
+func ParseScenario(data []byte) (Scenario, error) {
+	var scenario Scenario
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&scenario)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("could not decode scenario: %w", err)
+	}
+
+	err = scenario.Validate()
+	if err != nil {
+		return Scenario{}, err
+	}
+
+	return scenario, nil
+}
+
+// Validate checks that the scenario can be simulated. Every boat must have a
+// status in every frame, every dock that is referred to must be declared, every
+// dock must have a route to another dock, and the disruptions of a boat must not
+// overlap.
+func (s Scenario) Validate() error {
+	_, err := s.SimFrameDuration()
+	if err != nil {
+		return err
+	}
+
+	if len(s.Boats) == 0 {
+		return fmt.Errorf("scenario has no boats")
+	}
+	boatIDs := make(map[int32]struct{})
+	for _, boat := range s.Boats {
+		if boat.BoatID <= 0 {
+			return fmt.Errorf("boat: %q has an invalid BoatID: %d", boat.Name, boat.BoatID)
+		}
+		if _, ok := boatIDs[boat.BoatID]; ok {
+			return fmt.Errorf("BoatID: %d is declared more than once", boat.BoatID)
+		}
+		if boat.Capacity <= 0 {
+			return fmt.Errorf("BoatID: %d has an invalid Capacity: %d", boat.BoatID, boat.Capacity)
+		}
+		boatIDs[boat.BoatID] = struct{}{}
+	}
+
+	if len(s.Docks) == 0 {
+		return fmt.Errorf("scenario has no docks")
+	}
+	dockNames := make(map[string]struct{})
+	dockIdentities := make(map[a.Dock]string)
+	for _, dock := range s.Docks {
+		if dock.Name == "" {
+			return fmt.Errorf("dock at %d %s has no Name", dock.Address.Number, dock.Address.Street)
+		}
+		if _, ok := dockNames[dock.Name]; ok {
+			return fmt.Errorf("dock: %q is declared more than once", dock.Name)
+		}
+		dockNames[dock.Name] = struct{}{}
+		// Docks are identified by their address and gangway in the dock graph
+		identity := a.NewDock(dock.Address, dock.Gangway)
+		if name, ok := dockIdentities[identity]; ok {
+			return fmt.Errorf("dock: %q has the same Address and Gangway as dock: %q",
+				dock.Name, name)
+		}
+		dockIdentities[identity] = dock.Name
+		if dock.Gangway != a.GangwayLocationFore && dock.Gangway != a.GangwayLocationAft {
+			return fmt.Errorf("dock: %q has an invalid Gangway: %q", dock.Name, dock.Gangway)
+		}
+	}
+
+	docksWithRoutes := make(map[string]struct{})
+	routes := make(map[[2]string]struct{})
+	for _, route := range s.Routes {
+		if _, ok := dockNames[route.From]; !ok {
+			return fmt.Errorf("route from unknown dock: %q", route.From)
+		}
+		if _, ok := dockNames[route.To]; !ok {
+			return fmt.Errorf("route to unknown dock: %q", route.To)
+		}
+		if route.From == route.To {
+			return fmt.Errorf("route from dock: %q to itself", route.From)
+		}
+		if route.TravelFrames <= 0 {
+			return fmt.Errorf("route from dock: %q to dock: %q has invalid TravelFrames: %d",
+				route.From, route.To, route.TravelFrames)
+		}
+		if _, ok := routes[[2]string{route.From, route.To}]; ok {
+			return fmt.Errorf("route from dock: %q to dock: %q is declared more than once",
+				route.From, route.To)
+		}
+		routes[[2]string{route.From, route.To}] = struct{}{}
+		docksWithRoutes[route.From] = struct{}{}
+	}
+	for _, dock := range s.Docks {
+		if _, ok := docksWithRoutes[dock.Name]; !ok {
+			return fmt.Errorf("dock: %q has no route to another dock", dock.Name)
+		}
+	}
+
+	if len(s.Frames) == 0 {
+		return fmt.Errorf("scenario has no frames")
+	}
+	for i, frame := range s.Frames {
+		if len(frame) != len(s.Boats) {
+			return fmt.Errorf("frame %d has %d boat locations but the scenario has %d boats",
+				i, len(frame), len(s.Boats))
+		}
+		framedBoatIDs := make(map[int32]struct{})
+		for _, location := range frame {
+			if _, ok := boatIDs[location.BoatID]; !ok {
+				return fmt.Errorf("frame %d has unknown BoatID: %d", i, location.BoatID)
+			}
+			if _, ok := framedBoatIDs[location.BoatID]; ok {
+				return fmt.Errorf("frame %d has more than one location for BoatID: %d",
+					i, location.BoatID)
+			}
+			framedBoatIDs[location.BoatID] = struct{}{}
+
+			if _, ok := a.ServiceStateConversion[location.ServiceState]; !ok ||
+				location.ServiceState == a.ServiceStateUnknown {
+				return fmt.Errorf("frame %d has an invalid ServiceState: %d for BoatID: %d",
+					i, location.ServiceState, location.BoatID)
+			}
+
+			for _, dockName := range []string{location.PreviousDock, location.NextDock} {
+				if _, ok := dockNames[dockName]; !ok {
+					return fmt.Errorf("frame %d has unknown dock: %q for BoatID: %d",
+						i, dockName, location.BoatID)
+				}
+			}
+			if _, ok := dockNames[location.CurrentDock]; !ok && location.CurrentDock != "" {
+				return fmt.Errorf("frame %d has unknown dock: %q for BoatID: %d",
+					i, location.CurrentDock, location.BoatID)
+			}
+		}
+	}
+
+	return s.validateDisruptions(boatIDs)
+}
+
+// validateDisruptions checks that each disruption is for a boat in the scenario,
+// delays the boat or takes it out of service, and does not overlap another
+// disruption of the same boat
+func (s Scenario) validateDisruptions(boatIDs map[int32]struct{}) error {
+	for i, disruption := range s.Disruptions {
+		if _, ok := boatIDs[disruption.BoatID]; !ok {
+			return fmt.Errorf("disruption %d has unknown BoatID: %d", i, disruption.BoatID)
+		}
+		if disruption.ServiceState != a.ServiceStateDelayed &&
+			disruption.ServiceState != a.ServiceStateUnavailable {
+			return fmt.Errorf("disruption %d has an invalid ServiceState: %d", i, disruption.ServiceState)
+		}
+		if disruption.StartFrame < 0 {
+			return fmt.Errorf("disruption %d has an invalid StartFrame: %d", i, disruption.StartFrame)
+		}
+		if disruption.DurationFrames < 0 ||
+			(disruption.DurationFrames == 0 && disruption.ServiceState != a.ServiceStateUnavailable) {
+			return fmt.Errorf("disruption %d has an invalid DurationFrames: %d",
+				i, disruption.DurationFrames)
+		}
+	}
+
+	for boatID, disruptions := range groupDisruptions(s.Disruptions) {
+		for i := 1; i < len(disruptions); i++ {
+			previous := disruptions[i-1]
+			if previous.DurationFrames == 0 ||
+				disruptions[i].StartFrame < previous.StartFrame+previous.DurationFrames {
+				return fmt.Errorf("disruptions of BoatID: %d overlap at frame %d",
+					boatID, disruptions[i].StartFrame)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SimFrameDuration returns the parsed FrameDuration of the scenario
+func (s Scenario) SimFrameDuration() (time.Duration, error) {
+	if s.FrameDuration == "" {
+		return DefaultSimFrameDuration, nil
+	}
+
+	duration, err := time.ParseDuration(s.FrameDuration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid FrameDuration: %q: %w", s.FrameDuration, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("FrameDuration: %q must be positive", s.FrameDuration)
+	}
+
+	return duration, nil
+}
+
+// BuildSimulationFrames converts the frames of a validated scenario to the
+// boat statuses that are sent to the clients, along with the disruptions that
+// change them. SeatsAvailable is set from the seat ledger when each frame is
+// sent.
+func (s Scenario) BuildSimulationFrames() SimulationFrames {
+	docks := s.docksByName()
+	boats := make(map[int32]a.Boat)
+	for _, boat := range s.Boats {
+		boats[boat.BoatID] = a.NewBoat(boat.BoatID, boat.Name)
+	}
+
+	simFrames := SimulationFrames{
+		BoatLocations: make([][]a.BoatStatusAPIMessage, len(s.Frames)),
+		Disruptions:   s.Disruptions,
+	}
+	for i, frame := range s.Frames {
+		statuses := make([]a.BoatStatusAPIMessage, 0, len(frame))
+		for _, location := range frame {
+			// An empty CurrentDock is not in docks, so it results in a.Dock{}
+			statuses = append(statuses, a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus,
+				boats[location.BoatID], location.ServiceState, docks[location.PreviousDock],
+				docks[location.CurrentDock], docks[location.NextDock], 0))
+		}
+		simFrames.BoatLocations[i] = statuses
+	}
+
+	return simFrames
+}
+
+// docksByName returns the docks of the scenario keyed by Name
+func (s Scenario) docksByName() map[string]a.Dock {
+	docks := make(map[string]a.Dock)
+	for _, dock := range s.Docks {
+		docks[dock.Name] = a.NewDock(dock.Address, dock.Gangway)
+	}
+
+	return docks
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	a "riden/adapter"
+	"time"
+)
+
+// MaxDepartureLead is how far ahead of the current time a trip may be scheduled
+const MaxDepartureLead time.Duration = 24 * time.Hour
+
+// ScheduleDeparture assigns the boat in service that arrives at the SourceDock at
+// or after the DepartureTime of the reservation to the trip, and advances the trip
+// to TripStateScheduled with the confirmed departure. The trip does not hold a seat
+// until it is activated, so it is activated immediately if the boat does not call
+// at the SourceDock before the departure. A TripMessageError is returned if the
+// trip could not be scheduled.
+func (t *Trip) ScheduleDeparture() error {
+	if t.Reservation.DepartureTime.After(SimClock.Now().Add(MaxDepartureLead)) {
+		return NewTripMessageError(a.ErrorReasonInvalidTrip,
+			"departure time is more than %s ahead", MaxDepartureLead)
+	}
+
+	var potentialBoats []a.Boat
+	AddBoatsInService(&potentialBoats)
+	if len(potentialBoats) == 0 {
+		return NewTripMessageError(a.ErrorReasonNoBoatInService, "no boats were available for service")
+	}
+
+	boat, departure, ok := SimSchedule.ScheduledDeparture(potentialBoats,
+		t.Reservation.SourceDock, t.Reservation.DepartureTime)
+	if !ok {
+		return NewTripMessageError(a.ErrorReasonNoBoatInService,
+			"no boat in service departs from dock: %d %s", t.Reservation.SourceDock.Address.Number,
+			t.Reservation.SourceDock.Address.Street)
+	}
+	boatStatusVal, ok := safeBoatStatuses.Load(boat.BoatID)
+	if ok {
+		t.ServiceState = boatStatusVal.(a.BoatStatusAPIMessage).ServiceState
+	}
+	t.Boat = boat
+	t.DepartureTime = departure
+
+	err := t.AdvanceToTripState(TripStateScheduled)
+	if err != nil {
+		return err
+	}
+
+	if SimSchedule.IsNextDeparture(*t) {
+		return t.ActivateScheduledTrip()
+	}
+
+	return nil
+}
+
+// ActivateScheduledTrip reserves the seats of the party on the boat of the
+// scheduled trip and advances the trip to TripStateReserved. A TripMessageError
+// is returned if the boat does not have room for the party.
+func (t *Trip) ActivateScheduledTrip() error {
+	partySize := PartySize(t.Reservation)
+	err := safeSeats.Reserve(t.Boat.BoatID, partySize)
+	if err != nil {
+		return NewTripMessageError(a.ErrorReasonBoatsFull,
+			"BoatID: %d, does not have %d seats for the scheduled departure", t.Boat.BoatID,
+			partySize)
+	}
+
+	err = t.AdvanceToTripState(TripStateReserved)
+	if err != nil {
+		releaseSeats(t.Boat.BoatID, partySize, false)
+		return err
+	}
+
+	return nil
+}
+
+// ActivateScheduledTrips activates the scheduled trips on the given boat whose
+// departure is the next arrival of the boat at the SourceDock, so that the trips
+// are reserved before the boat calls at the dock. A trip that could not be
+// activated is cancelled, and the Error messages that inform the clients are
+// returned.
+func ActivateScheduledTrips(boatID int32) []a.ErrorMockLogicMessage {
+	var errMsgs []a.ErrorMockLogicMessage
+
+	for _, trip := range safeTrips.LoadByBoatID(boatID) {
+		if trip.TripState != TripStateScheduled || !SimSchedule.IsNextDeparture(trip) {
+			continue
+		}
+
+		var activateErr error
+		_, err := safeTrips.Update(trip.TransactionID, func(t *Trip) error {
+			// The trip may have been cancelled since it was loaded
+			if t.TripState != TripStateScheduled {
+				return nil
+			}
+			activateErr = t.ActivateScheduledTrip()
+			if activateErr != nil {
+				return t.AdvanceToTripState(TripStateCancelled)
+			}
+			return nil
+		})
+		if err != nil {
+			Logger.Error().Msgf("Could not update scheduled trip with TransactionID: %s: %s",
+				trip.TransactionID, err.Error())
+			continue
+		}
+		if activateErr != nil {
+			Logger.Warn().Msgf("Cancelled scheduled trip with TransactionID: %s for ClientID: %s: %s",
+				trip.TransactionID, trip.Reservation.ClientID, activateErr.Error())
+			errMsgs = append(errMsgs, NewTripMessageErrorMessage(activateErr,
+				a.APIMessageTypeReserveTrip, trip.Reservation.ClientID, trip.TransactionID,
+				trip.Client))
+			continue
+		}
+
+		Logger.Info().Msgf("Activated scheduled trip with TransactionID: %s on BoatID: %d for ClientID: %s",
+			trip.TransactionID, boatID, trip.Reservation.ClientID)
+	}
+
+	return errMsgs
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBoatFull is returned by SeatLedger.Reserve when the boat has no seats
+// available
+var ErrBoatFull = errors.New("boat has no seats available")
+
+// BoatSeats holds the capacity of a boat and the number of passengers that
+// have reserved a seat but not boarded, and that are on board
+type BoatSeats struct {
+	Capacity int32
+	Reserved int32
+	OnBoard  int32
+}
+
+// Available returns the number of seats that have not been reserved
+func (bs BoatSeats) Available() int32 {
+	return bs.Capacity - bs.Reserved - bs.OnBoard
+}
+
+// SeatLedger holds the seat counts of each boat. A seat is taken for each
+// passenger of the party when a trip is reserved and is given back when the
+// passenger gets off the boat or the trip is cancelled. Every method is safe for
+// concurrent use.
+type SeatLedger struct {
+	mux   sync.Mutex
+	boats map[int32]*BoatSeats
+}
+
+func NewSeatLedger() *SeatLedger {
+	return &SeatLedger{
+		boats: make(map[int32]*BoatSeats),
+	}
+}
+
+// safeSeats holds the seat counts of the boats in the system
+var safeSeats = NewSeatLedger()
+
+// SetCapacity sets the capacity of the boat. The passenger counts of a boat that
+// is already in the ledger are kept.
+func (sl *SeatLedger) SetCapacity(boatID, capacity int32) {
+	sl.mux.Lock()
+	defer sl.mux.Unlock()
+
+	seats, ok := sl.boats[boatID]
+	if !ok {
+		seats = &BoatSeats{}
+		sl.boats[boatID] = seats
+	}
+	seats.Capacity = capacity
+}
+
+// Reserve takes the given number of seats on the boat for passengers that have
+// not boarded. Either every seat is taken or none is. An error wrapping
+// ErrBoatFull is returned if not enough seats are available.
+func (sl *SeatLedger) Reserve(boatID, count int32) error {
+	sl.mux.Lock()
+	defer sl.mux.Unlock()
+
+	seats, err := sl.loadForUpdate(boatID, count)
+	if err != nil {
+		return err
+	}
+	if seats.Available() < count {
+		return fmt.Errorf("BoatID: %d, seats requested: %d, seats available: %d: %w",
+			boatID, count, seats.Available(), ErrBoatFull)
+	}
+	seats.Reserved += count
+
+	return nil
+}
+
+// Board moves the given number of reserved seats on the boat to on board
+func (sl *SeatLedger) Board(boatID, count int32) error {
+	sl.mux.Lock()
+	defer sl.mux.Unlock()
+
+	seats, err := sl.loadForUpdate(boatID, count)
+	if err != nil {
+		return err
+	}
+	if seats.Reserved < count {
+		return fmt.Errorf("BoatID: %d has %d reserved seats, cannot board %d",
+			boatID, seats.Reserved, count)
+	}
+	seats.Reserved -= count
+	seats.OnBoard += count
+
+	return nil
+}
+
+// Release gives back the given number of seats on the boat. onBoard selects
+// whether the seats were taken by passengers on board or by passengers that had
+// not boarded.
+func (sl *SeatLedger) Release(boatID, count int32, onBoard bool) error {
+	sl.mux.Lock()
+	defer sl.mux.Unlock()
+
+	seats, err := sl.loadForUpdate(boatID, count)
+	if err != nil {
+		return err
+	}
+	held := &seats.Reserved
+	if onBoard {
+		held = &seats.OnBoard
+	}
+	if *held < count {
+		return fmt.Errorf("BoatID: %d has %d seats, cannot release %d, onBoard: %t",
+			boatID, *held, count, onBoard)
+	}
+	*held -= count
+
+	return nil
+}
+
+// loadForUpdate returns the seat counts of the boat after checking that the
+// number of seats to change is positive. The caller must hold the lock.
+func (sl *SeatLedger) loadForUpdate(boatID, count int32) (*BoatSeats, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("seat count: %d, must be positive", count)
+	}
+	seats, ok := sl.boats[boatID]
+	if !ok {
+		return nil, fmt.Errorf("BoatID: %d is not in the seat ledger", boatID)
+	}
+
+	return seats, nil
+}
+
+// Load returns the seat counts of the boat and whether it was found
+func (sl *SeatLedger) Load(boatID int32) (BoatSeats, bool) {
+	sl.mux.Lock()
+	defer sl.mux.Unlock()
+
+	seats, ok := sl.boats[boatID]
+	if !ok {
+		return BoatSeats{}, false
+	}
+
+	return *seats, true
+}
+
+// SeatsAvailable returns the number of seats that can be reserved on the boat.
+// Zero is returned for a boat that is not in the ledger.
+func (sl *SeatLedger) SeatsAvailable(boatID int32) int32 {
+	seats, _ := sl.Load(boatID)
+	return seats.Available()
+}
+
+// InitializeSeats sets the capacity of each boat in the scenario in safeSeats
+func InitializeSeats(scenario Scenario) {
+	for _, boat := range scenario.Boats {
+		safeSeats.SetCapacity(boat.BoatID, boat.Capacity)
+	}
+}
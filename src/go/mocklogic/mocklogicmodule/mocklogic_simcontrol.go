@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	a "riden/adapter"
+	pb "riden/proto"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Simulation control commands
+const (
+	SimControlCommandUnknown     int32 = 0
+	SimControlCommandPause       int32 = 1
+	SimControlCommandResume      int32 = 2
+	SimControlCommandStep        int32 = 3
+	SimControlCommandSetSpeed    int32 = 4
+	SimControlCommandJumpToFrame int32 = 5
+	SimControlCommandGetStatus   int32 = 6
+)
+
+var SimControlCommandConversion = map[int32]string{
+	SimControlCommandUnknown:     "unknown",
+	SimControlCommandPause:       "pause",
+	SimControlCommandResume:      "resume",
+	SimControlCommandStep:        "step",
+	SimControlCommandSetSpeed:    "setSpeed",
+	SimControlCommandJumpToFrame: "jumpToFrame",
+	SimControlCommandGetStatus:   "getStatus",
+}
+
+// SimControlTimeout is how long a control request waits for the simulation to
+// apply it. The simulation does not apply requests while it is blocked sending a
+// frame.
+const SimControlTimeout time.Duration = 5 * time.Second
+
+// SimControlRequest is a command for the goroutine that advances the simulation.
+// FrameIndex is given for SimControlCommandJumpToFrame and Speed for
+// SimControlCommandSetSpeed. The result is sent on Reply.
+type SimControlRequest struct {
+	Command    int32
+	FrameIndex int
+	Speed      float64
+	Reply      chan SimControlReply
+}
+
+// SimControlReply holds the status of the simulation after a SimControlRequest
+// was applied, and the error if the request was rejected
+type SimControlReply struct {
+	Status SimStatus
+	Err    error
+}
+
+// SimStatus holds the state of the simulation. Step and FrameIndex are those of
+// the frame that was sent last, and are -1 before the first frame is sent. Speed
+// is zero when SimClock cannot change speed.
+type SimStatus struct {
+	Step       int
+	FrameIndex int
+	FrameCount int
+	Paused     bool
+	Speed      float64
+}
+
+// SimControlError is returned when a SimControlRequest is rejected
+type SimControlError struct {
+	Code    codes.Code
+	Message string
+}
+
+func NewSimControlError(code codes.Code, format string, args ...any) *SimControlError {
+	return &SimControlError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *SimControlError) Error() string {
+	return e.Message
+}
+
+// SimControlChannel carries the control requests to AdvanceSimFrames
+var SimControlChannel chan SimControlRequest
+
+// simRun holds the progress of the simulation in AdvanceSimFrames
+type simRun struct {
+	// The next step of the simulation to send
+	step   int
+	paused bool
+}
+
+// sendNextFrame sends the next step of the simulation and moves on to the step
+// after it
+func (r *simRun) sendNextFrame() {
+	sendSimFrame(r.step, true)
+	r.step++
+}
+
+// skipNextFrame updates the trips for the next step of the simulation without
+// sending its boat statuses to the Adapter, and moves on to the step after it
+func (r *simRun) skipNextFrame() {
+	sendSimFrame(r.step, false)
+	r.step++
+}
+
+// status returns the status of the simulation
+func (r *simRun) status() SimStatus {
+	frameCount := SimSchedule.FrameCount()
+	simStatus := SimStatus{
+		Step:       r.step - 1,
+		FrameIndex: -1,
+		FrameCount: frameCount,
+		Paused:     r.paused,
+	}
+	if r.step > 0 && frameCount > 0 {
+		simStatus.FrameIndex = (r.step - 1) % frameCount
+	}
+	if clock, ok := SimClock.(SpeedClock); ok {
+		simStatus.Speed = clock.Speed()
+	}
+
+	return simStatus
+}
+
+// apply carries out the control request and returns the status of the
+// simulation afterwards. A SimControlError is returned and the simulation is
+// not changed if the request was rejected.
+func (r *simRun) apply(request SimControlRequest) (SimStatus, error) {
+	Logger.Info().Msgf("Applying simulation control command: %s",
+		SimControlCommandConversion[request.Command])
+
+	switch request.Command {
+	case SimControlCommandPause:
+		r.paused = true
+
+	case SimControlCommandResume:
+		r.paused = false
+
+	case SimControlCommandStep:
+		if !r.paused {
+			return r.status(), NewSimControlError(codes.FailedPrecondition,
+				"the simulation must be paused to step")
+		}
+		r.sendNextFrame()
+
+	case SimControlCommandSetSpeed:
+		clock, ok := SimClock.(SpeedClock)
+		if !ok {
+			return r.status(), NewSimControlError(codes.FailedPrecondition,
+				"the simulation clock cannot change speed")
+		}
+		if request.Speed <= 0 {
+			return r.status(), NewSimControlError(codes.InvalidArgument,
+				"speed: %g, is not positive", request.Speed)
+		}
+		clock.SetSpeed(request.Speed)
+
+	case SimControlCommandJumpToFrame:
+		frameCount := SimSchedule.FrameCount()
+		if request.FrameIndex < 0 || request.FrameIndex >= frameCount {
+			return r.status(), NewSimControlError(codes.InvalidArgument,
+				"frame index: %d, is not in the %d frames", request.FrameIndex, frameCount)
+		}
+		// The steps only move forward, since the disruptions and the ETAs
+		// follow the steps of the simulation. The skipped steps are played so
+		// that the arrivals, departures and activations of the trips in them
+		// are not missed.
+		skipped := ((request.FrameIndex-r.step)%frameCount + frameCount) % frameCount
+		for range skipped {
+			r.skipNextFrame()
+		}
+		r.sendNextFrame()
+
+	case SimControlCommandGetStatus:
+
+	default:
+		return r.status(), NewSimControlError(codes.InvalidArgument,
+			"simulation control command: %d, is unknown", request.Command)
+	}
+
+	return r.status(), nil
+}
+
+// SendSimControl sends the control request to the simulation and waits for the
+// reply. A SimControlError with codes.DeadlineExceeded is returned if the
+// simulation did not apply the request within the SimControlTimeout.
+func SendSimControl(request SimControlRequest) (SimStatus, error) {
+	request.Reply = make(chan SimControlReply, 1)
+	timeout := time.NewTimer(SimControlTimeout)
+	defer timeout.Stop()
+
+	select {
+	case SimControlChannel <- request:
+	case <-timeout.C:
+		return SimStatus{}, NewSimControlError(codes.DeadlineExceeded,
+			"the simulation did not accept the %s command",
+			SimControlCommandConversion[request.Command])
+	}
+
+	select {
+	case reply := <-request.Reply:
+		return reply.Status, reply.Err
+	case <-timeout.C:
+		return SimStatus{}, NewSimControlError(codes.DeadlineExceeded,
+			"the simulation did not apply the %s command",
+			SimControlCommandConversion[request.Command])
+	}
+}
+
+// simControlServer implements the SimControl gRPC service
+type simControlServer struct {
+	pb.UnimplementedSimControlServer
+}
+
+func (s *simControlServer) Pause(_ context.Context, _ *pb.PauseRequest) (*pb.SimStatus, error) {
+	return simControlRPC(SimControlRequest{Command: SimControlCommandPause})
+}
+
+func (s *simControlServer) Resume(_ context.Context, _ *pb.ResumeRequest) (*pb.SimStatus, error) {
+	return simControlRPC(SimControlRequest{Command: SimControlCommandResume})
+}
+
+func (s *simControlServer) Step(_ context.Context, _ *pb.StepRequest) (*pb.SimStatus, error) {
+	return simControlRPC(SimControlRequest{Command: SimControlCommandStep})
+}
+
+func (s *simControlServer) SetSpeed(_ context.Context, in *pb.SetSpeedRequest) (*pb.SimStatus, error) {
+	return simControlRPC(SimControlRequest{
+		Command: SimControlCommandSetSpeed,
+		Speed:   in.GetSpeed(),
+	})
+}
+
+func (s *simControlServer) JumpToFrame(_ context.Context, in *pb.JumpToFrameRequest) (*pb.SimStatus, error) {
+	return simControlRPC(SimControlRequest{
+		Command:    SimControlCommandJumpToFrame,
+		FrameIndex: int(in.GetFrameIndex()),
+	})
+}
+
+func (s *simControlServer) GetStatus(_ context.Context, _ *pb.GetStatusRequest) (*pb.SimStatus, error) {
+	return simControlRPC(SimControlRequest{Command: SimControlCommandGetStatus})
+}
+
+// simControlRPC applies the request of a SimControl RPC and converts the reply to
+// the gRPC response. A rejected request is returned as a gRPC status error.
+func simControlRPC(request SimControlRequest) (*pb.SimStatus, error) {
+	simStatus, err := SendSimControl(request)
+	if err != nil {
+		Logger.Warn().Msgf("Simulation control command: %s was rejected: %s",
+			SimControlCommandConversion[request.Command], err.Error())
+		code := codes.Internal
+		if controlErr, ok := err.(*SimControlError); ok {
+			code = controlErr.Code
+		}
+		return nil, status.Error(code, err.Error())
+	}
+
+	return &pb.SimStatus{
+		Step:       int64(simStatus.Step),
+		FrameIndex: int32(simStatus.FrameIndex),
+		FrameCount: int32(simStatus.FrameCount),
+		Paused:     simStatus.Paused,
+		Speed:      simStatus.Speed,
+	}, nil
+}
+
+// InitializeSimControlServer serves the SimControl gRPC service on the
+// SimControlServerAddress
+func InitializeSimControlServer() {
+	listener, err := net.Listen("tcp", a.SimControlServerAddress)
+	if err != nil {
+		Logger.Error().Msgf("Error: failed to listen: %s", err.Error())
+		return
+	}
+	s := grpc.NewServer()
+	pb.RegisterSimControlServer(s, &simControlServer{})
+	Logger.Info().Msgf("SimControl gRPC server listening at %v", listener.Addr())
+
+	// Serve blocks until the process is killed or the server is stopped.
+	if err := s.Serve(listener); err != nil {
+		Logger.Error().Msgf("Error: Failed to serve: %s", err.Error())
+	}
+}
@@ -1,416 +1,173 @@
 package main
 
 import (
-	"container/list"
-	"container/ring"
 	a "riden/adapter"
 	wss "riden/websocketserver"
+	"strconv"
 	"time"
 )
 
-var SimBoat1 = a.Boat{
-	BoatID: 1,
-	Name:   "Argo",
-}
-
-var SimBoat2 = a.Boat{
-	BoatID: 2,
-	Name:   "Riverview",
-}
-
-var SimDock1 = a.Dock{
-	Address: a.Address{
-		Number: 901,
-		Street: "Carson St",
-	},
-	Gangway: a.GangwayLocationFore,
-}
+// DefaultSimFrameDuration is the time that each frame is displayed when the
+// scenario does not give a FrameDuration
+const DefaultSimFrameDuration time.Duration = 15 * time.Second
 
-var SimDock2 = a.Dock{
-	Address: a.Address{
-		Number: 996,
-		Street: "Ohio Rver Blvd",
-	},
-	Gangway: a.GangwayLocationFore,
-}
-
-var SimDock3 = a.Dock{
-	Address: a.Address{
-		Number: 993,
-		Street: "16th St",
-	},
-	Gangway: a.GangwayLocationFore,
-}
+// SimFrameDuration is the time that each frame is displayed. It is set from
+// the scenario when the simulation is initialized.
+var SimFrameDuration time.Duration = DefaultSimFrameDuration
 
-var SimDock4 = a.Dock{
-	Address: a.Address{
-		Number: 964,
-		Street: "Stanwix St",
-	},
-	Gangway: a.GangwayLocationFore,
+// SimulationFrames holds the boat statuses for each frame of the simulation.
+// BoatLocations[i] holds the status of every boat in frame i. The frames loop
+// for the length of the simulation, and Disruptions hold back the boats and
+// change their ServiceState in the frames that are sent.
+type SimulationFrames struct {
+	BoatLocations [][]a.BoatStatusAPIMessage
+	Disruptions   []ScenarioDisruption
 }
 
-const SimFrameTotal int = 16
-
-const SimBoatTotal int = 2
+// AdvanceSimFrames sends the boat statuses of the next step of the simulation
+// from SimSchedule on the SimBoatStatus channel and to the Adapter each time a
+// tick is received on the given channel, which is usually SimClock ticking every
+// frame duration. The simulation starts from the given step, so that a restarted
+// MockLogic carries on from the frame that it sent last. The requests on the
+// SimControlChannel are applied between the steps, and the ticks are ignored
+// while the simulation is paused.
+func AdvanceSimFrames(advance <-chan time.Time, firstStep int) {
+	Logger.Info().Msg("EnteredAdvanceSimFrames()")
+	run := simRun{step: firstStep}
+	for {
+		select {
+		case <-advance:
+			if run.paused {
+				continue
+			}
+			run.sendNextFrame()
 
-const SimFrameDuration time.Duration = 15 * time.Second
+		case request := <-SimControlChannel:
+			status, err := run.apply(request)
+			request.Reply <- SimControlReply{Status: status, Err: err}
 
-type SimulationFrames struct {
-	BoatLocations [SimFrameTotal][SimBoatTotal]a.BoatStatusAPIMessage
+		case <-StopSimFrames:
+			Logger.Info().Msg("AdvanceSimFrames has received a stop signal")
+			// handle close
+			return
+		}
+	}
 }
 
-// SimFrames simulates two boats moving between 4 docks. The total
-// number of frames is 16, with the boats remaining stationary at the
-// docks for two frames each and spending two frames traveling
-// between each dock.
-var SimFrames = SimulationFrames{
-	BoatLocations: [SimFrameTotal][SimBoatTotal]a.BoatStatusAPIMessage{
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				CurrentDock:  SimDock3,
-				NextDock:     SimDock4,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				CurrentDock:  SimDock1,
-				NextDock:     SimDock2,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				CurrentDock:  SimDock3,
-				NextDock:     SimDock4,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				CurrentDock:  SimDock1,
-				NextDock:     SimDock2,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				NextDock:     SimDock4,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				NextDock:     SimDock2,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				NextDock:     SimDock4,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				NextDock:     SimDock2,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				CurrentDock:  SimDock4,
-				NextDock:     SimDock1,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				CurrentDock:  SimDock2,
-				NextDock:     SimDock3,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				CurrentDock:  SimDock4,
-				NextDock:     SimDock1,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				CurrentDock:  SimDock2,
-				NextDock:     SimDock3,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				NextDock:     SimDock1,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				NextDock:     SimDock3,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				NextDock:     SimDock1,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				NextDock:     SimDock3,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				CurrentDock:  SimDock1,
-				NextDock:     SimDock2,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				CurrentDock:  SimDock3,
-				NextDock:     SimDock4,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				CurrentDock:  SimDock1,
-				NextDock:     SimDock2,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				CurrentDock:  SimDock3,
-				NextDock:     SimDock4,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				NextDock:     SimDock2,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				NextDock:     SimDock4,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				NextDock:     SimDock2,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				NextDock:     SimDock4,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				CurrentDock:  SimDock2,
-				NextDock:     SimDock3,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				CurrentDock:  SimDock4,
-				NextDock:     SimDock1,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock1,
-				CurrentDock:  SimDock2,
-				NextDock:     SimDock3,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock3,
-				CurrentDock:  SimDock4,
-				NextDock:     SimDock1,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				NextDock:     SimDock3,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				NextDock:     SimDock1,
-			},
-		},
-		{
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat1,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock2,
-				NextDock:     SimDock3,
-			},
-			{
-				MessageType:  a.APIMessageTypeBoatStatus,
-				Boat:         SimBoat2,
-				ServiceState: a.ServiceStateOnTime,
-				PreviousDock: SimDock4,
-				NextDock:     SimDock1,
+// sendSimFrame sends the boat statuses of the given step of the simulation on the
+// SimBoatStatus channel, and to the Adapter if broadcast is set. The trips that
+// finished more than FinishedTripRetention ago are removed first, and the journal
+// is compacted once enough entries have been appended to it.
+func sendSimFrame(step int, broadcast bool) {
+	Logger.Info().Msgf("Pushing sim frame for step %d to channel and advancing", step)
+	boatStatuses := SimSchedule.BoatStatuses(step)
+	// Record the frame before the statuses are sent so that the ETAs
+	// computed for the statuses are relative to this frame
+	SimSchedule.RecordFrame(step, SimClock.Now())
+	SimJournal.RecordFrame(step)
+	for _, trip := range safeTrips.RemoveFinished(SimClock.Now().Add(-FinishedTripRetention)) {
+		Logger.Info().Msgf("Removed finished trip with TransactionID: %s", trip.TransactionID)
+	}
+	if SimJournal.NeedsCompaction() {
+		err := safeTrips.CompactJournal(step)
+		if err != nil {
+			Logger.Error().Msgf("Could not compact the journal: %s", err.Error())
+		}
+	}
+	for _, boatStatusAPI := range boatStatuses {
+		boatStatusAPI.SeatsAvailable = safeSeats.SeatsAvailable(boatStatusAPI.Boat.BoatID)
+		// Store the boat status
+		SimFrameBoatStatusChannel <- boatStatusAPI
+		if !broadcast {
+			continue
+		}
+		// Send the boat status to the Adapter
+		boatStatus := a.BoatStatusMockLogicMessage{
+			APIMessage: boatStatusAPI,
+			Client: a.ClientData{
+				ConnName: wss.WSSServerAllClientsConnName,
+				ConnType: a.ConnectionTypeAll,
 			},
-		},
-	},
-}
-
-// BuildSimFrameRing places the SimulationFrames in a Ring container.
-// This circular container allows the frames to be advanced through
-// all the frames and wrap around to the beginning by repeatedly
-// calling Next()
-func BuildSimFrameRing(simFrames SimulationFrames) *ring.Ring {
-	frameRing := ring.New(len(simFrames.BoatLocations))
-
-	// Get the length of the ring
-	n := frameRing.Len()
-
-	// Initialize the ring with SimulationFrames.BoatLocations
-	for i := range n {
-		frameRing.Value = simFrames.BoatLocations[i]
-		frameRing = frameRing.Next()
+		}
+		pushLatestToAdapter(strconv.Itoa(int(boatStatusAPI.Boat.BoatID)), boatStatus,
+			a.APIMessageTypeBoatStatus)
 	}
-
-	return frameRing
 }
 
-// AdvanceSimFrames advances the sim frames and places the current frame
-// on the SimBoatStatus channel
-func AdvanceSimFrames() {
-	Logger.Info().Msg("EnteredAdvanceSimFrames()")
-	advance := time.Tick(SimFrameDuration)
+// UpdateBoatStatuses waits for boat statuses to appear on the
+// SimFrameBoatStatusChannel and stores them in safeBoatStatuses, so that
+// reservations are made against the current location of each boat. When a boat
+// goes out of service, its trips are reassigned to other boats and the Ack and
+// Error messages are sent to the Adapter. Each status is then checked for the
+// trips that the boat has left behind at their docks, which are finished, and for
+// arrivals of the boat at the docks of the reserved trips, and the Error and
+// Arrived messages are sent to the Adapter. The scheduled trips on
+// the boat that depart at the next call of the boat are then activated, followed
+// by the TripProgress messages for the trips on the boat.
+func UpdateBoatStatuses() {
+	Logger.Info().Msg("Entered UpdateBoatStatuses()")
 	for {
 		select {
-		case <-advance:
-			Logger.Info().Msg("Pushing sim frame to channel and advancing")
-			statuses := SimFrameRing.Value.([]a.BoatStatusAPIMessage)
-			for _, boatStatusAPI := range statuses {
-				// Store the boat status
-				SimFrameBoatStatusChannel <- boatStatusAPI
-				// Send the boat status to the Adapter
-				boatStatus := a.BoatStatusMockLogicMessage{
-					APIMessage: boatStatusAPI,
-					Client: a.ClientData{
-						ConnName: wss.WSSServerAllClientsConnName,
-						ConnType: a.ConnectionTypeAll,
-					},
+		case boatStatus := <-SimFrameBoatStatusChannel:
+			previousVal, ok := safeBoatStatuses.Load(boatStatus.Boat.BoatID)
+			wentOutOfService := boatStatus.ServiceState == a.ServiceStateUnavailable &&
+				(!ok || previousVal.(a.BoatStatusAPIMessage).ServiceState != a.ServiceStateUnavailable)
+			// The status is stored first so that trips are not reassigned to
+			// the boat that went out of service
+			safeBoatStatuses.Store(boatStatus.Boat.BoatID, boatStatus)
+
+			if wentOutOfService {
+				Logger.Warn().Msgf("BoatID: %d went out of service", boatStatus.Boat.BoatID)
+				acks, errMsgs := ReassignTrips(boatStatus.Boat.BoatID)
+				for _, ack := range acks {
+					pushToAdapter(ack, a.APIMessageTypeAck)
+				}
+				for _, errMsg := range errMsgs {
+					pushToAdapter(errMsg, a.APIMessageTypeError)
 				}
-				AdapterBoatStatusChannel <- boatStatus
 			}
-			SimFrameRing = SimFrameRing.Next()
+
+			for _, errMsg := range CheckDepartures(boatStatus) {
+				pushToAdapter(errMsg, a.APIMessageTypeError)
+			}
+
+			for _, arrived := range CheckArrivals(boatStatus) {
+				pushToAdapter(arrived, a.APIMessageTypeArrived)
+			}
+
+			for _, errMsg := range ActivateScheduledTrips(boatStatus.Boat.BoatID) {
+				pushToAdapter(errMsg, a.APIMessageTypeError)
+			}
+
+			for _, progress := range TripProgressMessages(boatStatus.Boat.BoatID) {
+				pushLatestToAdapter(progress.APIMessage.TransactionID, progress,
+					a.APIMessageTypeTripProgress)
+			}
 
 		case <-StopSimFrames:
-			Logger.Info().Msg("AdvanceSimFrames has received a stop signal")
-			// handle close
+			Logger.Info().Msg("UpdateBoatStatuses has received a stop signal")
 			return
 		}
 	}
 }
 
-// BuildDockAdjacencyList places the SimDocks in a map[string]*list.List
-// container. This adjacency list stores the order of the docks that the boats
-// travel
-func BuildDockAdjacencyList() map[string]*list.List {
-	docks := []a.Dock{SimDock1, SimDock2, SimDock3, SimDock4}
-	var adjacencyList = make(map[string]*list.List)
+// BuildDockGraph places the docks and routes of the scenario in a DockGraph.
+// The scenario must have been validated.
+func BuildDockGraph(scenario Scenario) *DockGraph {
+	docks := scenario.docksByName()
+	graph := NewDockGraph()
 
-	for i, dock := range docks {
-		list := list.New()
-		if i < len(docks)-1 {
-			_ = list.PushBack(docks[i+1])
-		} else {
-			// This is the last dock in docks, so push the first
-			// dock onto the list
-			_ = list.PushBack(docks[0])
+	for _, dock := range scenario.Docks {
+		graph.AddDock(docks[dock.Name])
+	}
+	for _, route := range scenario.Routes {
+		err := graph.AddRoute(docks[route.From], docks[route.To], route.TravelFrames)
+		if err != nil {
+			// Validation of the scenario prevents this
+			Logger.Error().Msgf("Could not add route from dock: %q to dock: %q: %s",
+				route.From, route.To, err.Error())
 		}
-		adjacencyList[dock.Address.Street] = list
 	}
 
-	return adjacencyList
+	return graph
 }
@@ -1,13 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	a "riden/adapter"
 	"riden/logger"
+	pb "riden/proto"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 // Common test parameters
@@ -79,7 +92,14 @@ func SetUp() {
 		Gangway: a.GangwayLocationFore,
 	}
 
-	SimDockAdjacencyList = BuildDockAdjacencyList()
+	scenario, err := LoadScenario("")
+	if err != nil {
+		fmt.Println("Error loading default scenario:", err)
+		os.Exit(1)
+	}
+	SimDockGraph = BuildDockGraph(scenario)
+	InitializeSeats(scenario)
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), DefaultSimFrameDuration)
 }
 
 func TearDown() {
@@ -87,47 +107,490 @@ func TearDown() {
 
 }
 
-func TestReturnDistance(t *testing.T) {
+func TestLoadScenario(t *testing.T) {
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+
+	simFrames := scenario.BuildSimulationFrames()
+	if len(simFrames.BoatLocations) != 16 {
+		t.Fatalf("Expected 16 frames but received %d", len(simFrames.BoatLocations))
+	}
+
+	expectedStatus := a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus, simBoat1,
+		a.ServiceStateOnTime, simDock2, simDock3, simDock4, 0)
+	if simFrames.BoatLocations[0][0] != expectedStatus {
+		t.Fatalf("Expected boat status %+v but received %+v",
+			expectedStatus, simFrames.BoatLocations[0][0])
+	}
+
+	// The boat is travelling between docks in frame 2
+	expectedStatus = a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus, simBoat2,
+		a.ServiceStateOnTime, simDock1, a.Dock{}, simDock2, 0)
+	if simFrames.BoatLocations[2][1] != expectedStatus {
+		t.Fatalf("Expected boat status %+v but received %+v",
+			expectedStatus, simFrames.BoatLocations[2][1])
+	}
+
+	scenario, err = LoadScenario("./scenarios/disruptions.json")
+	if err != nil {
+		t.Fatalf("Error loading disruptions scenario: %s", err.Error())
+	}
+	if len(scenario.Disruptions) != 2 {
+		t.Fatalf("Expected 2 disruptions but received %d", len(scenario.Disruptions))
+	}
+
+	_, err = LoadScenario("./scenarios/does_not_exist.json")
+	if err == nil {
+		t.Fatalf("Expected an error loading a missing scenario file")
+	}
+}
+
+func TestParseScenario(t *testing.T) {
 	type testCase struct {
-		name             string
-		startDock        a.Dock
-		goalDock         a.Dock
-		expectedDistance int8
+		name           string
+		scenario       string
+		expectedError  bool
+		expectedFrames int
+		expectedDur    time.Duration
+	}
+
+	const docks = `"Docks": [
+		{"Name": "a", "Address": {"Number": 1, "Street": "A St"}, "Gangway": "fore"},
+		{"Name": "b", "Address": {"Number": 2, "Street": "B St"}, "Gangway": "aft"}]`
+	const routes = `"Routes": [{"From": "a", "To": "b", "TravelFrames": 2}, {"From": "b", "To": "a", "TravelFrames": 2}]`
+	const boats = `"Boats": [{"BoatID": 1, "Name": "One", "Capacity": 10}]`
+
+	cases := []testCase{
+		{
+			name: "ParseScenario - Valid scenario",
+			scenario: `{"FrameDuration": "10ms", ` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [
+				[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "b", "CurrentDock": "a", "NextDock": "b"}],
+				[{"BoatID": 1, "ServiceState": 2, "PreviousDock": "a", "NextDock": "b"}],
+				[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "CurrentDock": "b", "NextDock": "a"}]]}`,
+			expectedError:  false,
+			expectedFrames: 3,
+			expectedDur:    10 * time.Millisecond,
+		},
+		{
+			name: "ParseScenario - Default FrameDuration",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError:  false,
+			expectedFrames: 1,
+			expectedDur:    DefaultSimFrameDuration,
+		},
+		{
+			name:          "ParseScenario - Malformed JSON",
+			scenario:      `{"Boats": [`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Unknown field",
+			scenario: `{"Boat": [], ` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Invalid FrameDuration",
+			scenario: `{"FrameDuration": "soon", ` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - No frames",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": []}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Boat without Capacity",
+			scenario: `{"Boats": [{"BoatID": 1, "Name": "One"}], ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Duplicate BoatID",
+			scenario: `{"Boats": [{"BoatID": 1, "Name": "One", "Capacity": 10}, {"BoatID": 1, "Name": "Two", "Capacity": 10}], ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Invalid Gangway",
+			scenario: `{` + boats + `, "Docks": [
+				{"Name": "a", "Address": {"Number": 1, "Street": "A St"}, "Gangway": "port"},
+				{"Name": "b", "Address": {"Number": 2, "Street": "B St"}, "Gangway": "aft"}], ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Dock without a route",
+			scenario: `{` + boats + `, ` + docks + `, "Routes": [{"From": "a", "To": "b", "TravelFrames": 2}],
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Route to unknown dock",
+			scenario: `{` + boats + `, ` + docks + `, "Routes": [{"From": "a", "To": "b", "TravelFrames": 2}, {"From": "b", "To": "c", "TravelFrames": 2}],
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Route without TravelFrames",
+			scenario: `{` + boats + `, ` + docks + `, "Routes": [{"From": "a", "To": "b", "TravelFrames": 2}, {"From": "b", "To": "a"}],
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Duplicate route",
+			scenario: `{` + boats + `, ` + docks + `, "Routes": [{"From": "a", "To": "b", "TravelFrames": 2},
+				{"From": "b", "To": "a", "TravelFrames": 2}, {"From": "a", "To": "b", "TravelFrames": 3}],
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Duplicate dock identity",
+			scenario: `{` + boats + `, "Docks": [
+				{"Name": "a", "Address": {"Number": 1, "Street": "A St"}, "Gangway": "fore"},
+				{"Name": "b", "Address": {"Number": 1, "Street": "A St"}, "Gangway": "fore"}], ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Boat missing from frame",
+			scenario: `{"Boats": [{"BoatID": 1, "Name": "One", "Capacity": 10}, {"BoatID": 2, "Name": "Two", "Capacity": 10}], ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Unknown BoatID in frame",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 3, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Unknown dock in frame",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "CurrentDock": "c", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Invalid ServiceState",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 0, "PreviousDock": "a", "NextDock": "b"}]]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Disruptions",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]],
+				"Disruptions": [{"BoatID": 1, "ServiceState": 2, "StartFrame": 2, "DurationFrames": 3},
+				{"BoatID": 1, "ServiceState": 3, "StartFrame": 5}]}`,
+			expectedError:  false,
+			expectedFrames: 1,
+			expectedDur:    DefaultSimFrameDuration,
+		},
+		{
+			name: "ParseScenario - Disruption of unknown boat",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]],
+				"Disruptions": [{"BoatID": 2, "ServiceState": 2, "StartFrame": 2, "DurationFrames": 3}]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Disruption that keeps the boat on time",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]],
+				"Disruptions": [{"BoatID": 1, "ServiceState": 1, "StartFrame": 2, "DurationFrames": 3}]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Delay without DurationFrames",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]],
+				"Disruptions": [{"BoatID": 1, "ServiceState": 2, "StartFrame": 2}]}`,
+			expectedError: true,
+		},
+		{
+			name: "ParseScenario - Overlapping disruptions",
+			scenario: `{` + boats + `, ` + docks + `, ` + routes + `,
+				"Frames": [[{"BoatID": 1, "ServiceState": 1, "PreviousDock": "a", "NextDock": "b"}]],
+				"Disruptions": [{"BoatID": 1, "ServiceState": 3, "StartFrame": 4, "DurationFrames": 2},
+				{"BoatID": 1, "ServiceState": 2, "StartFrame": 2, "DurationFrames": 3}]}`,
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		scenario, err := ParseScenario([]byte(testCase.scenario))
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expected error: %t but received err: %v in test case: %s",
+				testCase.expectedError, err, testCase.name)
+		}
+		if err != nil {
+			continue
+		}
+
+		simFrames := scenario.BuildSimulationFrames()
+		if len(simFrames.BoatLocations) != testCase.expectedFrames {
+			t.Fatalf("Expected %d frames but received %d in test case: %s",
+				testCase.expectedFrames, len(simFrames.BoatLocations), testCase.name)
+		}
+
+		duration, _ := scenario.SimFrameDuration()
+		if duration != testCase.expectedDur {
+			t.Fatalf("Expected FrameDuration %s but received %s in test case: %s",
+				testCase.expectedDur, duration, testCase.name)
+		}
+	}
+}
+
+func TestDockGraphShortestPath(t *testing.T) {
+	type testCase struct {
+		name           string
+		graph          *DockGraph
+		startDock      a.Dock
+		goalDock       a.Dock
+		expectedPath   []a.Dock
+		expectedFrames int
+		expectedErr    error
+	}
+
+	// The default scenario is a loop of four docks with four frames between docks
+	defaultGraph := SimDockGraph
+
+	// Branching graph: dock 1 -> dock 2 -> dock 4 is longer in hops than
+	// dock 1 -> dock 3 -> dock 4, but quicker. Dock 4 cannot reach dock 5.
+	simDock5 := a.NewDock(a.NewAddress(964, "Stanwix St"), a.GangwayLocationAft)
+	branchingGraph := NewDockGraph()
+	for _, route := range []DockRoute{
+		{From: simDock1, To: simDock2, TravelFrames: 1},
+		{From: simDock2, To: simDock4, TravelFrames: 1},
+		{From: simDock1, To: simDock3, TravelFrames: 5},
+		{From: simDock3, To: simDock4, TravelFrames: 1},
+		{From: simDock4, To: simDock1, TravelFrames: 2},
+		{From: simDock5, To: simDock1, TravelFrames: 2},
+	} {
+		err := branchingGraph.AddRoute(route.From, route.To, route.TravelFrames)
+		if err != nil {
+			t.Fatalf("Error adding route: %s", err.Error())
+		}
+	}
+
+	cases := []testCase{
+		{
+			name:           "ShortestPath - Same dock for start and goal",
+			graph:          defaultGraph,
+			startDock:      simDock1,
+			goalDock:       simDock1,
+			expectedPath:   []a.Dock{simDock1},
+			expectedFrames: 0,
+		},
+		{
+			name:           "ShortestPath - Dock 1 to Dock 4",
+			graph:          defaultGraph,
+			startDock:      simDock1,
+			goalDock:       simDock4,
+			expectedPath:   []a.Dock{simDock1, simDock2, simDock3, simDock4},
+			expectedFrames: 12,
+		},
+		{
+			name:           "ShortestPath - Dock 2 to Dock 3",
+			graph:          defaultGraph,
+			startDock:      simDock2,
+			goalDock:       simDock3,
+			expectedPath:   []a.Dock{simDock2, simDock3},
+			expectedFrames: 4,
+		},
+		{
+			name:           "ShortestPath - Dock 2 to Dock 1 - wraparound",
+			graph:          defaultGraph,
+			startDock:      simDock2,
+			goalDock:       simDock1,
+			expectedPath:   []a.Dock{simDock2, simDock3, simDock4, simDock1},
+			expectedFrames: 12,
+		},
+		{
+			name:           "ShortestPath - Branching route takes the quicker branch",
+			graph:          branchingGraph,
+			startDock:      simDock1,
+			goalDock:       simDock4,
+			expectedPath:   []a.Dock{simDock1, simDock2, simDock4},
+			expectedFrames: 2,
+		},
+		{
+			name:           "ShortestPath - Same street, different gangway",
+			graph:          branchingGraph,
+			startDock:      simDock5,
+			goalDock:       simDock3,
+			expectedPath:   []a.Dock{simDock5, simDock1, simDock3},
+			expectedFrames: 7,
+		},
+		{
+			name:        "ShortestPath - Unreachable dock",
+			graph:       branchingGraph,
+			startDock:   simDock4,
+			goalDock:    simDock5,
+			expectedErr: ErrUnreachableDock,
+		},
+		{
+			name:        "ShortestPath - Unknown dock",
+			graph:       defaultGraph,
+			startDock:   simDock1,
+			goalDock:    simDock5,
+			expectedErr: ErrUnknownDock,
+		},
+	}
+
+	for _, testCase := range cases {
+		path, frames, err := testCase.graph.ShortestPath(testCase.startDock, testCase.goalDock)
+
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Fatalf("Expected error %v but received %v in test case: %s",
+				testCase.expectedErr, err, testCase.name)
+		}
+
+		if !slices.Equal(path, testCase.expectedPath) {
+			t.Fatalf("Expected path %+v but received %+v in test case: %s",
+				testCase.expectedPath, path, testCase.name)
+		}
+
+		if frames != testCase.expectedFrames {
+			t.Fatalf("Expected frames %d but received %d in test case: %s",
+				testCase.expectedFrames, frames, testCase.name)
+		}
+	}
+}
+
+func TestDockGraphAddRoute(t *testing.T) {
+	type testCase struct {
+		name          string
+		from          a.Dock
+		to            a.Dock
+		travelFrames  int
+		expectedError bool
+	}
+
+	graph := NewDockGraph()
+	err := graph.AddRoute(simDock1, simDock2, 1)
+	if err != nil {
+		t.Fatalf("Error adding route: %s", err.Error())
+	}
+
+	cases := []testCase{
+		{
+			name:          "AddRoute - New route",
+			from:          simDock2,
+			to:            simDock1,
+			travelFrames:  3,
+			expectedError: false,
+		},
+		{
+			name:          "AddRoute - Duplicate route",
+			from:          simDock1,
+			to:            simDock2,
+			travelFrames:  2,
+			expectedError: true,
+		},
+		{
+			name:          "AddRoute - Route to itself",
+			from:          simDock3,
+			to:            simDock3,
+			travelFrames:  1,
+			expectedError: true,
+		},
+		{
+			name:          "AddRoute - Invalid TravelFrames",
+			from:          simDock3,
+			to:            simDock4,
+			travelFrames:  0,
+			expectedError: true,
+		},
 	}
 
+	for _, testCase := range cases {
+		err := graph.AddRoute(testCase.from, testCase.to, testCase.travelFrames)
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expected error: %t but received err: %v in test case: %s",
+				testCase.expectedError, err, testCase.name)
+		}
+	}
+
+	if graph.HasDock(simDock3) || graph.HasDock(simDock4) {
+		t.Fatalf("Expected rejected routes to not add docks to the graph")
+	}
+}
+
+func TestReturnClosestBoat(t *testing.T) {
+	type testCase struct {
+		name          string
+		dock          a.Dock
+		boats         []a.Boat
+		expectedBoat  a.Boat
+		expectedError bool
+	}
+
+	// Boat 1 is heading to dock 4 and boat 2 is heading to dock 2
+	safeBoatStatuses.Store(simBoat1.BoatID, a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus,
+		simBoat1, a.ServiceStateOnTime, simDock3, a.Dock{}, simDock4, 0))
+	safeBoatStatuses.Store(simBoat2.BoatID, a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus,
+		simBoat2, a.ServiceStateOnTime, simDock1, a.Dock{}, simDock2, 0))
+	defer safeBoatStatuses.Delete(simBoat1.BoatID)
+	defer safeBoatStatuses.Delete(simBoat2.BoatID)
+
+	// The boarding boat is at dock 3 and is heading to dock 4
+	boardingBoat := a.NewBoat(4, "Boarding")
+	safeBoatStatuses.Store(boardingBoat.BoatID, a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus,
+		boardingBoat, a.ServiceStateOnTime, a.Dock{}, simDock3, simDock4, 0))
+	defer safeBoatStatuses.Delete(boardingBoat.BoatID)
+
+	unknownDock := a.NewDock(a.NewAddress(1, "Nowhere St"), a.GangwayLocationFore)
+
 	cases := []testCase{
 		{
-			name:             "ReturnDistance - Same dock for start and goal",
-			startDock:        simDock1,
-			goalDock:         simDock1,
-			expectedDistance: 0,
+			name:         "ReturnClosestBoat - Boat heading to the dock",
+			dock:         simDock4,
+			boats:        []a.Boat{simBoat1, simBoat2},
+			expectedBoat: simBoat1,
 		},
 		{
-			name:             "ReturnDistance - Dock 1 to Dock 4",
-			startDock:        simDock1,
-			goalDock:         simDock4,
-			expectedDistance: 3,
+			name:         "ReturnClosestBoat - Boat one dock away",
+			dock:         simDock3,
+			boats:        []a.Boat{simBoat1, simBoat2},
+			expectedBoat: simBoat2,
 		},
 		{
-			name:             "ReturnDistance - Dock 2 to Dock 3",
-			startDock:        simDock2,
-			goalDock:         simDock3,
-			expectedDistance: 1,
+			name:         "ReturnClosestBoat - Boat without status is skipped",
+			dock:         simDock3,
+			boats:        []a.Boat{simBoat1, a.NewBoat(3, "Unknown")},
+			expectedBoat: simBoat1,
 		},
 		{
-			name:             "ReturnDistance - Dock 2 to Dock 1 - wraparound",
-			startDock:        simDock2,
-			goalDock:         simDock1,
-			expectedDistance: 3,
+			name:          "ReturnClosestBoat - Boat boarding at the dock is skipped",
+			dock:          simDock3,
+			boats:         []a.Boat{boardingBoat},
+			expectedError: true,
+		},
+		{
+			name:          "ReturnClosestBoat - Dock not in graph",
+			dock:          unknownDock,
+			boats:         []a.Boat{simBoat1, simBoat2},
+			expectedError: true,
 		},
 	}
 
 	for _, testCase := range cases {
-		recdDistance := ReturnDistance(testCase.startDock, testCase.goalDock)
+		boat, err := ReturnClosestBoat(testCase.dock, &testCase.boats)
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expected error: %t but received err: %v in test case: %s",
+				testCase.expectedError, err, testCase.name)
+		}
 
-		if recdDistance != testCase.expectedDistance {
-			t.Fatalf("Expected distance %d but received %d in test case: %s",
-				testCase.expectedDistance, recdDistance, testCase.name)
+		if err == nil && boat != testCase.expectedBoat {
+			t.Fatalf("Expected boat %+v but received %+v in test case: %s",
+				testCase.expectedBoat, boat, testCase.name)
 		}
 	}
 }
@@ -251,6 +714,78 @@ func TestAdvanceToTripState(t *testing.T) {
 			state:         TripStateClientAtDock,
 			expectedError: true,
 		},
+		{
+			name: "AdvanceToTripState - Boat arrives before client is at dock",
+			trip: Trip{
+				TripState: TripStateReserved,
+			},
+			state:         TripStateBoatArrivedAtSource,
+			expectedError: false,
+		},
+		{
+			name: "AdvanceToTripState - Client on boat before boat arrives",
+			trip: Trip{
+				TripState: TripStateClientAtDock,
+			},
+			state:         TripStateClientOnBoat,
+			expectedError: true,
+		},
+		{
+			name: "AdvanceToTripState - Cancel reserved trip",
+			trip: Trip{
+				TripState: TripStateReserved,
+			},
+			state:         TripStateCancelled,
+			expectedError: false,
+		},
+		{
+			name: "AdvanceToTripState - Cancel after boat arrived at source dock",
+			trip: Trip{
+				TripState: TripStateBoatArrivedAtSource,
+			},
+			state:         TripStateCancelled,
+			expectedError: false,
+		},
+		{
+			name: "AdvanceToTripState - Cancel with client on boat",
+			trip: Trip{
+				TripState: TripStateClientOnBoat,
+			},
+			state:         TripStateCancelled,
+			expectedError: true,
+		},
+		{
+			name: "AdvanceToTripState - Cancelled trip is terminal",
+			trip: Trip{
+				TripState: TripStateCancelled,
+			},
+			state:         TripStateClientAtDock,
+			expectedError: true,
+		},
+		{
+			name: "AdvanceToTripState - Schedule new trip",
+			trip: Trip{
+				TripState: TripStateUnknown,
+			},
+			state:         TripStateScheduled,
+			expectedError: false,
+		},
+		{
+			name: "AdvanceToTripState - Activate scheduled trip",
+			trip: Trip{
+				TripState: TripStateScheduled,
+			},
+			state:         TripStateReserved,
+			expectedError: false,
+		},
+		{
+			name: "AdvanceToTripState - Boat arrives for scheduled trip",
+			trip: Trip{
+				TripState: TripStateScheduled,
+			},
+			state:         TripStateBoatArrivedAtSource,
+			expectedError: true,
+		},
 		{
 			name: "AdvanceToTripState - State below range",
 			trip: Trip{
@@ -278,3 +813,3877 @@ func TestAdvanceToTripState(t *testing.T) {
 		}
 	}
 }
+
+func TestSeatLedger(t *testing.T) {
+	type testCase struct {
+		name              string
+		operation         func(sl *SeatLedger) error
+		expectedError     bool
+		expectedAvailable int32
+	}
+
+	const boatID int32 = 7
+	ledger := NewSeatLedger()
+	ledger.SetCapacity(boatID, 2)
+
+	cases := []testCase{
+		{
+			name:              "SeatLedger - Reserve first seat",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID, 1) },
+			expectedError:     false,
+			expectedAvailable: 1,
+		},
+		{
+			name:              "SeatLedger - Reserve last seat",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID, 1) },
+			expectedError:     false,
+			expectedAvailable: 0,
+		},
+		{
+			name:              "SeatLedger - Reserve on full boat",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID, 1) },
+			expectedError:     true,
+			expectedAvailable: 0,
+		},
+		{
+			name:              "SeatLedger - Board does not free a seat",
+			operation:         func(sl *SeatLedger) error { return sl.Board(boatID, 1) },
+			expectedError:     false,
+			expectedAvailable: 0,
+		},
+		{
+			name:              "SeatLedger - Release on board seat",
+			operation:         func(sl *SeatLedger) error { return sl.Release(boatID, 1, true) },
+			expectedError:     false,
+			expectedAvailable: 1,
+		},
+		{
+			name:              "SeatLedger - Release on board seat with nobody on board",
+			operation:         func(sl *SeatLedger) error { return sl.Release(boatID, 1, true) },
+			expectedError:     true,
+			expectedAvailable: 1,
+		},
+		{
+			name:              "SeatLedger - Release reserved seat",
+			operation:         func(sl *SeatLedger) error { return sl.Release(boatID, 1, false) },
+			expectedError:     false,
+			expectedAvailable: 2,
+		},
+		{
+			name:              "SeatLedger - Board without reserved seat",
+			operation:         func(sl *SeatLedger) error { return sl.Board(boatID, 1) },
+			expectedError:     true,
+			expectedAvailable: 2,
+		},
+		{
+			name:              "SeatLedger - Reserve party larger than the seats available",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID, 3) },
+			expectedError:     true,
+			expectedAvailable: 2,
+		},
+		{
+			name:              "SeatLedger - Reserve no seats",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID, 0) },
+			expectedError:     true,
+			expectedAvailable: 2,
+		},
+		{
+			name:              "SeatLedger - Reserve party",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID, 2) },
+			expectedError:     false,
+			expectedAvailable: 0,
+		},
+		{
+			name:              "SeatLedger - Board more passengers than the party",
+			operation:         func(sl *SeatLedger) error { return sl.Board(boatID, 3) },
+			expectedError:     true,
+			expectedAvailable: 0,
+		},
+		{
+			name:              "SeatLedger - Board part of party",
+			operation:         func(sl *SeatLedger) error { return sl.Board(boatID, 1) },
+			expectedError:     false,
+			expectedAvailable: 0,
+		},
+		{
+			name:              "SeatLedger - Release party",
+			operation:         func(sl *SeatLedger) error { return sl.Release(boatID, 1, false) },
+			expectedError:     false,
+			expectedAvailable: 1,
+		},
+		{
+			name:              "SeatLedger - Release more passengers than on board",
+			operation:         func(sl *SeatLedger) error { return sl.Release(boatID, 2, true) },
+			expectedError:     true,
+			expectedAvailable: 1,
+		},
+		{
+			name:              "SeatLedger - Release rest of party",
+			operation:         func(sl *SeatLedger) error { return sl.Release(boatID, 1, true) },
+			expectedError:     false,
+			expectedAvailable: 2,
+		},
+		{
+			name:              "SeatLedger - Reserve on unknown boat",
+			operation:         func(sl *SeatLedger) error { return sl.Reserve(boatID+1, 1) },
+			expectedError:     true,
+			expectedAvailable: 2,
+		},
+	}
+
+	for _, testCase := range cases {
+		err := testCase.operation(ledger)
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expected error: %t but received err: %v in test case: %s",
+				testCase.expectedError, err, testCase.name)
+		}
+
+		available := ledger.SeatsAvailable(boatID)
+		if available != testCase.expectedAvailable {
+			t.Fatalf("Expected %d seats available but received %d in test case: %s",
+				testCase.expectedAvailable, available, testCase.name)
+		}
+	}
+
+	// Concurrent reservations never overbook the boat
+	ledger.SetCapacity(boatID, 10)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = ledger.Reserve(boatID, 1)
+		}()
+	}
+	wg.Wait()
+
+	seats, _ := ledger.Load(boatID)
+	if seats.Reserved != 10 || ledger.SeatsAvailable(boatID) != 0 {
+		t.Fatalf("Expected 10 reserved seats and none available but received %+v", seats)
+	}
+}
+
+func TestProcessReserveTrip(t *testing.T) {
+	type testCase struct {
+		name               string
+		boatStatus1        a.BoatStatusAPIMessage
+		boatStatus2        a.BoatStatusAPIMessage
+		capacity1          int32
+		capacity2          int32
+		reserveMsg         a.ReserveTripMockLogicMessage
+		expectedIsReserved bool
+		expectedBoat       a.Boat
+		expectedReasonCode int32
+	}
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+
+	cases := []testCase{
+		{
+			name: "ProcessReserveTrip - Closest boat is reserved",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 24,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 0, nil), clientData),
+			expectedIsReserved: true,
+			expectedBoat:       simBoat2,
+			expectedReasonCode: a.ErrorReasonUnknown,
+		},
+		{
+			name: "ProcessReserveTrip - No boats in service",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateUnavailable,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateUnavailable,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 24,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 0, nil), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonNoBoatInService,
+		},
+		{
+			name: "ProcessReserveTrip - Destination dock is not in the dock graph",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 24,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken", "testClient",
+					simDock2, a.NewDock(a.NewAddress(1, "Nowhere St"), a.GangwayLocationFore), time.Time{}, 0, nil), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonInvalidTrip,
+		},
+		{
+			name: "ProcessReserveTrip - Closest boat is full",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 0,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 0, nil), clientData),
+			expectedIsReserved: true,
+			expectedBoat:       simBoat1,
+			expectedReasonCode: a.ErrorReasonUnknown,
+		},
+		{
+			name: "ProcessReserveTrip - Every boat is full",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 0,
+			capacity2: 0,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 0, nil), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonBoatsFull,
+		},
+		{
+			name: "ProcessReserveTrip - Party is reserved on the closest boat with room for every passenger",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 2,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 3, nil), clientData),
+			expectedIsReserved: true,
+			expectedBoat:       simBoat1,
+			expectedReasonCode: a.ErrorReasonUnknown,
+		},
+		{
+			name: "ProcessReserveTrip - Party size is taken from the passenger names",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 2,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 0, []string{"Ana", "Ben", "Cy"}), clientData),
+			expectedIsReserved: true,
+			expectedBoat:       simBoat1,
+			expectedReasonCode: a.ErrorReasonUnknown,
+		},
+		{
+			name: "ProcessReserveTrip - Party does not fit on any boat",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 2,
+			capacity2: 2,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 3, nil), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonBoatsFull,
+		},
+		{
+			name: "ProcessReserveTrip - Negative party size",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 24,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, -1, nil), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonInvalidTrip,
+		},
+		{
+			name: "ProcessReserveTrip - More passenger names than the party size",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 24,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 1, []string{"Ana", "Ben"}), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonInvalidTrip,
+		},
+		{
+			name: "ProcessReserveTrip - Passenger name given twice",
+			boatStatus1: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock3,
+				NextDock:     simDock4,
+			},
+			boatStatus2: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				ServiceState: a.ServiceStateOnTime,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			capacity1: 40,
+			capacity2: 24,
+			reserveMsg: a.NewReserveTripMockLogicMessage(
+				a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+					"testClient", simDock2, simDock4, time.Time{}, 2, []string{"Ana", "Ana"}), clientData),
+			expectedIsReserved: false,
+			expectedBoat:       a.Boat{},
+			expectedReasonCode: a.ErrorReasonInvalidTrip,
+		},
+	}
+
+	// Restore the capacities of the default scenario after the test
+	defer safeSeats.SetCapacity(simBoat1.BoatID, 40)
+	defer safeSeats.SetCapacity(simBoat2.BoatID, 24)
+
+	for _, testCase := range cases {
+		safeSeats.SetCapacity(simBoat1.BoatID, testCase.capacity1)
+		safeSeats.SetCapacity(simBoat2.BoatID, testCase.capacity2)
+		safeBoatStatuses.Store(testCase.boatStatus1.Boat.BoatID,
+			testCase.boatStatus1)
+		safeBoatStatuses.Store(testCase.boatStatus2.Boat.BoatID,
+			testCase.boatStatus2)
+
+		partySize := PartySize(testCase.reserveMsg.APIMessage)
+		seatsBefore, _ := safeSeats.Load(testCase.expectedBoat.BoatID)
+		ack := ProcessReserveTrip(testCase.reserveMsg)
+		safeTrips.Remove(ack.APIMessage.TransactionID)
+		seatsAfter, _ := safeSeats.Load(testCase.expectedBoat.BoatID)
+		if ack.APIMessage.IsReserved {
+			safeSeats.Release(ack.APIMessage.Boat.BoatID, partySize, false)
+		}
+
+		if ack.APIMessage.IsReserved && seatsAfter.Reserved != seatsBefore.Reserved+partySize {
+			t.Fatalf("Expected %d reserved seats but received %d in test case: %s",
+				seatsBefore.Reserved+partySize, seatsAfter.Reserved, testCase.name)
+		}
+
+		if ack.APIMessage.ReasonCode != testCase.expectedReasonCode {
+			t.Fatalf("Expected ReasonCode %d but received %d in test case: %s",
+				testCase.expectedReasonCode, ack.APIMessage.ReasonCode, testCase.name)
+		}
+
+		if testCase.expectedIsReserved == (ack.APIMessage.Reason != "") {
+			t.Fatalf("Expected a Reason only for a rejected trip but received %q in test case: %s",
+				ack.APIMessage.Reason, testCase.name)
+		}
+
+		if ack.APIMessage.IsReserved != testCase.expectedIsReserved {
+			t.Fatalf("Expected IsReserved %v but received %v in test case: %s",
+				testCase.expectedIsReserved, ack.APIMessage.IsReserved, testCase.name)
+		}
+
+		if ack.APIMessage.Boat != testCase.expectedBoat {
+			t.Fatalf("Expected boat %+v but received %+v in test case: %s",
+				testCase.expectedBoat, ack.APIMessage.Boat, testCase.name)
+		}
+
+		if testCase.expectedIsReserved == (ack.APIMessage.TransactionID == "") {
+			t.Fatalf("Expected a TransactionID only for a reserved trip but received %q in test case: %s",
+				ack.APIMessage.TransactionID, testCase.name)
+		}
+
+		if ack.APIMessage.MessageType != a.APIMessageTypeAck {
+			t.Fatalf("Expected message type %s but received %s in test case: %s",
+				a.APIMessageTypeAck, ack.APIMessage.MessageType, testCase.name)
+		}
+
+		if ack.Client != testCase.reserveMsg.Client {
+			t.Fatalf("Expected client data %+v but received %+v in test case: %s",
+				testCase.reserveMsg.Client, ack.Client, testCase.name)
+		}
+	}
+}
+
+func TestProcessReserveTripScheduled(t *testing.T) {
+	type testCase struct {
+		name               string
+		capacity1          int32
+		departure          time.Duration
+		expectedIsReserved bool
+		expectedReasonCode int32
+		expectedBoat       a.Boat
+		expectedDeparture  time.Duration
+		expectedTripState  int32
+		expectedReserved   int32
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	sentAt := time.Now()
+
+	defaultSchedule := SimSchedule
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), frameDuration)
+	SimSchedule.RecordFrame(1, sentAt)
+	defer func() { SimSchedule = defaultSchedule }()
+
+	// Use a separate seat ledger so the seats of the scenario are not changed
+	scenarioSeats := safeSeats
+	safeSeats = NewSeatLedger()
+	defer func() { safeSeats = scenarioSeats }()
+
+	for _, boat := range []a.Boat{simBoat1, simBoat2} {
+		safeBoatStatuses.Store(boat.BoatID, a.BoatStatusAPIMessage{
+			Boat:         boat,
+			ServiceState: a.ServiceStateOnTime,
+		})
+	}
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+
+	// In frame 1, boat 1 arrives next at dock 1 in frame 8, and then again in
+	// frame 24
+	cases := []testCase{
+		{
+			name:               "ProcessReserveTrip Scheduled - Departure at the next call is reserved",
+			capacity1:          40,
+			departure:          frameDuration,
+			expectedIsReserved: true,
+			expectedReasonCode: a.ErrorReasonUnknown,
+			expectedBoat:       simBoat1,
+			expectedDeparture:  7 * frameDuration,
+			expectedTripState:  TripStateReserved,
+			expectedReserved:   1,
+		},
+		{
+			name:               "ProcessReserveTrip Scheduled - Later departure is scheduled",
+			capacity1:          40,
+			departure:          16 * frameDuration,
+			expectedIsReserved: true,
+			expectedReasonCode: a.ErrorReasonUnknown,
+			expectedBoat:       simBoat1,
+			expectedDeparture:  23 * frameDuration,
+			expectedTripState:  TripStateScheduled,
+			expectedReserved:   0,
+		},
+		{
+			name:               "ProcessReserveTrip Scheduled - Boat of the next call is full",
+			capacity1:          0,
+			departure:          frameDuration,
+			expectedIsReserved: false,
+			expectedReasonCode: a.ErrorReasonBoatsFull,
+			expectedBoat:       a.Boat{},
+			expectedReserved:   0,
+		},
+		{
+			name:               "ProcessReserveTrip Scheduled - Departure is too far ahead",
+			capacity1:          40,
+			departure:          MaxDepartureLead + time.Hour,
+			expectedIsReserved: false,
+			expectedReasonCode: a.ErrorReasonInvalidTrip,
+			expectedBoat:       a.Boat{},
+			expectedReserved:   0,
+		},
+	}
+
+	for _, testCase := range cases {
+		safeSeats.SetCapacity(simBoat1.BoatID, testCase.capacity1)
+		reserveMsg := a.NewReserveTripMockLogicMessage(
+			a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken", "testClient",
+				simDock1, simDock2, sentAt.Add(testCase.departure), 0, nil), clientData)
+
+		ack := ProcessReserveTrip(reserveMsg)
+		trip, _ := safeTrips.Remove(ack.APIMessage.TransactionID)
+		seats, _ := safeSeats.Load(simBoat1.BoatID)
+		if trip.TripState == TripStateReserved {
+			safeSeats.Release(simBoat1.BoatID, 1, false)
+		}
+
+		if ack.APIMessage.IsReserved != testCase.expectedIsReserved {
+			t.Fatalf("Expected IsReserved %v but received %v with reason: %s in test case: %s",
+				testCase.expectedIsReserved, ack.APIMessage.IsReserved, ack.APIMessage.Reason,
+				testCase.name)
+		}
+
+		if ack.APIMessage.ReasonCode != testCase.expectedReasonCode {
+			t.Fatalf("Expected ReasonCode %d but received %d in test case: %s",
+				testCase.expectedReasonCode, ack.APIMessage.ReasonCode, testCase.name)
+		}
+
+		if ack.APIMessage.Boat != testCase.expectedBoat {
+			t.Fatalf("Expected boat %+v but received %+v in test case: %s",
+				testCase.expectedBoat, ack.APIMessage.Boat, testCase.name)
+		}
+
+		if seats.Reserved != testCase.expectedReserved {
+			t.Fatalf("Expected %d reserved seats but received %d in test case: %s",
+				testCase.expectedReserved, seats.Reserved, testCase.name)
+		}
+
+		if !testCase.expectedIsReserved {
+			if !ack.APIMessage.DepartureTime.IsZero() {
+				t.Fatalf("Expected no DepartureTime but received %s in test case: %s",
+					ack.APIMessage.DepartureTime, testCase.name)
+			}
+			continue
+		}
+
+		expectedDeparture := sentAt.Add(testCase.expectedDeparture)
+		if !ack.APIMessage.DepartureTime.Equal(expectedDeparture) {
+			t.Fatalf("Expected DepartureTime %s but received %s in test case: %s",
+				expectedDeparture, ack.APIMessage.DepartureTime, testCase.name)
+		}
+
+		if !ack.APIMessage.SourceETA.Equal(expectedDeparture) {
+			t.Fatalf("Expected SourceETA %s but received %s in test case: %s",
+				expectedDeparture, ack.APIMessage.SourceETA, testCase.name)
+		}
+
+		if trip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, trip.TripState, testCase.name)
+		}
+	}
+}
+
+func TestTripRegistry(t *testing.T) {
+	type testCase struct {
+		name                  string
+		operation             func(tr *TripRegistry) error
+		expectedError         bool
+		expectedLen           int
+		expectedClient1Trips  int
+		expectedBoat1Trips    int
+		expectedBoat2Trips    int
+		expectedTrip1State    int32
+		expectedTrip1InRecord bool
+	}
+
+	trip1 := Trip{
+		Reservation:   a.ReserveTripAPIMessage{ClientID: "testClient1"},
+		TransactionID: "1000-1",
+		Boat:          simBoat1,
+		TripState:     TripStateReserved,
+	}
+	trip2 := Trip{
+		Reservation:   a.ReserveTripAPIMessage{ClientID: "testClient1"},
+		TransactionID: "1000-2",
+		Boat:          simBoat2,
+		TripState:     TripStateReserved,
+	}
+	trip3 := Trip{
+		Reservation:   a.ReserveTripAPIMessage{ClientID: "testClient2"},
+		TransactionID: "1000-3",
+		Boat:          simBoat1,
+		TripState:     TripStateReserved,
+	}
+
+	// The test cases are run in order against the same registry
+	registry := NewTripRegistry()
+
+	cases := []testCase{
+		{
+			name: "TripRegistry - Insert three trips",
+			operation: func(tr *TripRegistry) error {
+				for _, trip := range []Trip{trip1, trip2, trip3} {
+					if err := tr.Insert(trip); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expectedError:         false,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    2,
+			expectedBoat2Trips:    1,
+			expectedTrip1State:    TripStateReserved,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Insert duplicate TransactionID",
+			operation: func(tr *TripRegistry) error {
+				return tr.Insert(trip1)
+			},
+			expectedError:         true,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    2,
+			expectedBoat2Trips:    1,
+			expectedTrip1State:    TripStateReserved,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Insert trip without TransactionID",
+			operation: func(tr *TripRegistry) error {
+				return tr.Insert(Trip{})
+			},
+			expectedError:         true,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    2,
+			expectedBoat2Trips:    1,
+			expectedTrip1State:    TripStateReserved,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Update trip state",
+			operation: func(tr *TripRegistry) error {
+				_, err := tr.UpdateTripState(trip1.TransactionID, TripStateClientAtDock)
+				return err
+			},
+			expectedError:         false,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    2,
+			expectedBoat2Trips:    1,
+			expectedTrip1State:    TripStateClientAtDock,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Update trip to invalid state",
+			operation: func(tr *TripRegistry) error {
+				_, err := tr.UpdateTripState(trip1.TransactionID, TripStateClientOffBoat)
+				return err
+			},
+			expectedError:         true,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    2,
+			expectedBoat2Trips:    1,
+			expectedTrip1State:    TripStateClientAtDock,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Update state of unknown trip",
+			operation: func(tr *TripRegistry) error {
+				_, err := tr.UpdateTripState("unknown", TripStateClientAtDock)
+				return err
+			},
+			expectedError:         true,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    2,
+			expectedBoat2Trips:    1,
+			expectedTrip1State:    TripStateClientAtDock,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Update boat re-indexes trip",
+			operation: func(tr *TripRegistry) error {
+				_, err := tr.Update(trip1.TransactionID, func(trip *Trip) error {
+					trip.Boat = simBoat2
+					return nil
+				})
+				return err
+			},
+			expectedError:         false,
+			expectedLen:           3,
+			expectedClient1Trips:  2,
+			expectedBoat1Trips:    1,
+			expectedBoat2Trips:    2,
+			expectedTrip1State:    TripStateClientAtDock,
+			expectedTrip1InRecord: true,
+		},
+		{
+			name: "TripRegistry - Remove trip",
+			operation: func(tr *TripRegistry) error {
+				if _, ok := tr.Remove(trip1.TransactionID); !ok {
+					return fmt.Errorf("trip was not removed")
+				}
+				return nil
+			},
+			expectedError:         false,
+			expectedLen:           2,
+			expectedClient1Trips:  1,
+			expectedBoat1Trips:    1,
+			expectedBoat2Trips:    1,
+			expectedTrip1InRecord: false,
+		},
+		{
+			name: "TripRegistry - Remove unknown trip",
+			operation: func(tr *TripRegistry) error {
+				if _, ok := tr.Remove(trip1.TransactionID); !ok {
+					return fmt.Errorf("trip was not found")
+				}
+				return nil
+			},
+			expectedError:         true,
+			expectedLen:           2,
+			expectedClient1Trips:  1,
+			expectedBoat1Trips:    1,
+			expectedBoat2Trips:    1,
+			expectedTrip1InRecord: false,
+		},
+	}
+
+	for _, testCase := range cases {
+		err := testCase.operation(registry)
+
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expectation of error was %v for test %s but received error was: %v",
+				testCase.expectedError, testCase.name, err)
+		}
+
+		if registry.Len() != testCase.expectedLen {
+			t.Fatalf("Expected registry length %d but received %d in test case: %s",
+				testCase.expectedLen, registry.Len(), testCase.name)
+		}
+
+		client1Trips := registry.LoadByClientID("testClient1")
+		if len(client1Trips) != testCase.expectedClient1Trips {
+			t.Fatalf("Expected %d trips for testClient1 but received %d in test case: %s",
+				testCase.expectedClient1Trips, len(client1Trips), testCase.name)
+		}
+
+		boat1Trips := registry.LoadByBoatID(simBoat1.BoatID)
+		if len(boat1Trips) != testCase.expectedBoat1Trips {
+			t.Fatalf("Expected %d trips for boat 1 but received %d in test case: %s",
+				testCase.expectedBoat1Trips, len(boat1Trips), testCase.name)
+		}
+
+		boat2Trips := registry.LoadByBoatID(simBoat2.BoatID)
+		if len(boat2Trips) != testCase.expectedBoat2Trips {
+			t.Fatalf("Expected %d trips for boat 2 but received %d in test case: %s",
+				testCase.expectedBoat2Trips, len(boat2Trips), testCase.name)
+		}
+
+		trip, ok := registry.Load(trip1.TransactionID)
+		if ok != testCase.expectedTrip1InRecord {
+			t.Fatalf("Expected trip 1 found to be %v but received %v in test case: %s",
+				testCase.expectedTrip1InRecord, ok, testCase.name)
+		}
+
+		if ok && trip.TripState != testCase.expectedTrip1State {
+			t.Fatalf("Expected trip 1 state %d but received %d in test case: %s",
+				testCase.expectedTrip1State, trip.TripState, testCase.name)
+		}
+	}
+}
+
+// TestTripRegistryConcurrentAccess exercises the registry from many goroutines
+// at once and is intended to be run with the race detector
+func TestTripRegistryConcurrentAccess(t *testing.T) {
+	const workers int = 16
+	const tripsPerWorker int = 50
+
+	registry := NewTripRegistry()
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clientID := "testClient" + strconv.Itoa(w)
+			for i := range tripsPerWorker {
+				transactionID := clientID + "-" + strconv.Itoa(i)
+				trip := Trip{
+					Reservation:   a.ReserveTripAPIMessage{ClientID: clientID},
+					TransactionID: transactionID,
+					Boat:          a.Boat{BoatID: int32(i % 2)},
+					TripState:     TripStateReserved,
+				}
+				if err := registry.Insert(trip); err != nil {
+					t.Errorf("Insert returned an error: %s", err.Error())
+					return
+				}
+				if _, err := registry.UpdateTripState(transactionID, TripStateClientAtDock); err != nil {
+					t.Errorf("UpdateTripState returned an error: %s", err.Error())
+					return
+				}
+				_ = registry.LoadByBoatID(int32(i % 2))
+				_ = registry.LoadByClientID(clientID)
+				if i%2 == 0 {
+					registry.Remove(transactionID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	expectedLen := workers * tripsPerWorker / 2
+	if registry.Len() != expectedLen {
+		t.Fatalf("Expected registry length %d but received %d", expectedLen, registry.Len())
+	}
+
+	for _, trip := range registry.LoadByBoatID(1) {
+		if trip.TripState != TripStateClientAtDock {
+			t.Fatalf("Expected trip state %d but received %d for TransactionID: %s",
+				TripStateClientAtDock, trip.TripState, trip.TransactionID)
+		}
+	}
+}
+
+func TestOpenJournal(t *testing.T) {
+	type testCase struct {
+		name                   string
+		entries                []JournalEntry
+		tail                   string
+		expectedTransactionIDs []string
+		expectedTripStates     []int32
+		expectedStep           int
+	}
+
+	newJournalTrip := func(transactionID string, tripState int32) *Trip {
+		reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testJournalClient", simDock1, simDock2, time.Time{}, 2, []string{"Ada"})
+		return &Trip{
+			Reservation:   reservation,
+			TransactionID: transactionID,
+			Boat:          simBoat1,
+			TripState:     tripState,
+			Passengers:    NewPassengers(reservation),
+		}
+	}
+	withPassengerStates := func(trip *Trip, states ...int32) *Trip {
+		for i, state := range states {
+			trip.Passengers[i].State = state
+		}
+		return trip
+	}
+
+	cases := []testCase{
+		{
+			name:         "OpenJournal - Missing journal is created empty",
+			expectedStep: -1,
+		},
+		{
+			name: "OpenJournal - Latest entry of each trip is kept in order of creation",
+			entries: []JournalEntry{
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-2", TripStateReserved)},
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-1", TripStateReserved)},
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-2", TripStateClientAtDock)},
+			},
+			expectedTransactionIDs: []string{"trip-2", "trip-1"},
+			expectedTripStates:     []int32{TripStateClientAtDock, TripStateReserved},
+			expectedStep:           -1,
+		},
+		{
+			name: "OpenJournal - Removed trip is dropped",
+			entries: []JournalEntry{
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-1", TripStateReserved)},
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-2", TripStateReserved)},
+				{EntryType: JournalEntryTypeRemoval, TransactionID: "trip-1"},
+			},
+			expectedTransactionIDs: []string{"trip-2"},
+			expectedTripStates:     []int32{TripStateReserved},
+			expectedStep:           -1,
+		},
+		{
+			name: "OpenJournal - Finished trips are dropped",
+			entries: []JournalEntry{
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-1", TripStateReserved)},
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-2", TripStateCancelled)},
+				{EntryType: JournalEntryTypeTrip, Trip: withPassengerStates(newJournalTrip("trip-3", TripStateClientOffBoat),
+					PassengerStateOffBoat, PassengerStateOffBoat)},
+				{EntryType: JournalEntryTypeTrip, Trip: withPassengerStates(newJournalTrip("trip-4", TripStateClientOffBoat),
+					PassengerStateOffBoat, PassengerStateOnBoard)},
+				{EntryType: JournalEntryTypeFrame, Step: 3},
+			},
+			expectedTransactionIDs: []string{"trip-1", "trip-4"},
+			expectedTripStates:     []int32{TripStateReserved, TripStateClientOffBoat},
+			expectedStep:           3,
+		},
+		{
+			name: "OpenJournal - Last frame is kept",
+			entries: []JournalEntry{
+				{EntryType: JournalEntryTypeFrame, Step: 4},
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-1", TripStateScheduled)},
+				{EntryType: JournalEntryTypeFrame, Step: 5},
+			},
+			expectedTransactionIDs: []string{"trip-1"},
+			expectedTripStates:     []int32{TripStateScheduled},
+			expectedStep:           5,
+		},
+		{
+			name: "OpenJournal - Incomplete entry at the end is discarded",
+			entries: []JournalEntry{
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-1", TripStateReserved)},
+				{EntryType: JournalEntryTypeFrame, Step: 2},
+			},
+			tail:                   `{"EntryType":1,"Trip":{"Reservation":{"Messa`,
+			expectedTransactionIDs: []string{"trip-1"},
+			expectedTripStates:     []int32{TripStateReserved},
+			expectedStep:           2,
+		},
+		{
+			name: "OpenJournal - Journal is discarded from a corrupt entry",
+			entries: []JournalEntry{
+				{EntryType: JournalEntryTypeTrip, Trip: newJournalTrip("trip-1", TripStateReserved)},
+			},
+			tail: "\x00\x00\x00\x00\n" +
+				`{"EntryType":3,"Step":7}` + "\n",
+			expectedTransactionIDs: []string{"trip-1"},
+			expectedTripStates:     []int32{TripStateReserved},
+			expectedStep:           -1,
+		},
+	}
+
+	for _, tc := range cases {
+		journalPath := filepath.Join(t.TempDir(), "journal.jsonl")
+		if tc.entries != nil || tc.tail != "" {
+			var contents bytes.Buffer
+			for _, entry := range tc.entries {
+				err := writeJournalEntry(&contents, entry)
+				if err != nil {
+					t.Fatalf("Error writing journal entry: %s in test case: %s", err.Error(), tc.name)
+				}
+			}
+			contents.WriteString(tc.tail)
+			err := os.WriteFile(journalPath, contents.Bytes(), 0644)
+			if err != nil {
+				t.Fatalf("Error writing journal: %s in test case: %s", err.Error(), tc.name)
+			}
+		}
+
+		// The journal is opened a second time to check that the compacted
+		// journal holds the same state
+		for _, pass := range []string{"replayed", "compacted"} {
+			journal, state, err := OpenJournal(journalPath)
+			if err != nil {
+				t.Fatalf("Expected %s journal to open, got: %s in test case: %s", pass, err.Error(), tc.name)
+			}
+			journal.Close()
+
+			var transactionIDs []string
+			var tripStates []int32
+			for _, trip := range state.Trips {
+				transactionIDs = append(transactionIDs, trip.TransactionID)
+				tripStates = append(tripStates, trip.TripState)
+			}
+			if !slices.Equal(transactionIDs, tc.expectedTransactionIDs) ||
+				!slices.Equal(tripStates, tc.expectedTripStates) {
+				t.Fatalf("Expected %s trips: %v in states: %v, got: %v in states: %v in test case: %s",
+					pass, tc.expectedTransactionIDs, tc.expectedTripStates, transactionIDs, tripStates, tc.name)
+			}
+			if state.Step != tc.expectedStep {
+				t.Fatalf("Expected %s Step: %d, got: %d in test case: %s", pass, tc.expectedStep, state.Step, tc.name)
+			}
+			for _, trip := range state.Trips {
+				if !slices.Equal(trip.Reservation.PassengerNames, []string{"Ada"}) || len(trip.Passengers) != 2 {
+					t.Fatalf("Expected %s trip with the party of the reservation, got: %+v in test case: %s",
+						pass, trip, tc.name)
+				}
+			}
+		}
+	}
+}
+
+func TestJournalRestoreTrips(t *testing.T) {
+	type testCase struct {
+		name             string
+		tripState        int32
+		passengerStates  []int32
+		expectedReserved int32
+		expectedOnBoard  int32
+		expectedDropped  bool
+	}
+
+	cases := []testCase{
+		{
+			name:             "RestoreTrips - Reserved party holds reserved seats",
+			tripState:        TripStateReserved,
+			passengerStates:  []int32{PassengerStateReserved, PassengerStateReserved},
+			expectedReserved: 2,
+		},
+		{
+			name:             "RestoreTrips - Scheduled trip holds no seats",
+			tripState:        TripStateScheduled,
+			passengerStates:  []int32{PassengerStateReserved, PassengerStateReserved},
+			expectedReserved: 0,
+		},
+		{
+			name:             "RestoreTrips - Party partly on board holds both",
+			tripState:        TripStateClientOnBoat,
+			passengerStates:  []int32{PassengerStateOnBoard, PassengerStateReserved, PassengerStateOnBoard},
+			expectedReserved: 1,
+			expectedOnBoard:  2,
+		},
+		{
+			name:            "RestoreTrips - Arrived party only holds the seats on board",
+			tripState:       TripStateBoatArrivedAtDest,
+			passengerStates: []int32{PassengerStateOnBoard, PassengerStateReserved},
+			expectedOnBoard: 1,
+		},
+		{
+			name:            "RestoreTrips - Party partly off the boat holds the seats on board",
+			tripState:       TripStateClientOffBoat,
+			passengerStates: []int32{PassengerStateOffBoat, PassengerStateOnBoard},
+			expectedOnBoard: 1,
+		},
+		{
+			name:            "RestoreTrips - Cancelled trip is not restored",
+			tripState:       TripStateCancelled,
+			passengerStates: []int32{PassengerStateReserved},
+			expectedDropped: true,
+		},
+	}
+
+	// Use a separate registry and seat ledger so the trips and the seats of
+	// the scenario are not changed
+	defaultTrips := safeTrips
+	scenarioSeats := safeSeats
+	defer func() {
+		safeTrips = defaultTrips
+		safeSeats = scenarioSeats
+	}()
+
+	for i, tc := range cases {
+		journalPath := filepath.Join(t.TempDir(), "journal.jsonl")
+		journal, _, err := OpenJournal(journalPath)
+		if err != nil {
+			t.Fatalf("Expected journal to open, got: %s in test case: %s", err.Error(), tc.name)
+		}
+		safeTrips = NewTripRegistry()
+		safeTrips.SetJournal(journal)
+
+		// The trip is created, changed and then joined by a trip that is removed
+		reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testJournalClient", simDock1, simDock2, time.Time{}, int32(len(tc.passengerStates)), nil)
+		trip := Trip{
+			Reservation:   reservation,
+			TransactionID: "testJournalTrip" + strconv.Itoa(i),
+			Boat:          simBoat2,
+			TripState:     TripStateReserved,
+			Passengers:    NewPassengers(reservation),
+		}
+		err = safeTrips.Insert(trip)
+		if err != nil {
+			t.Fatalf("Error inserting trip: %s in test case: %s", err.Error(), tc.name)
+		}
+		_, err = safeTrips.Update(trip.TransactionID, func(t *Trip) error {
+			t.TripState = tc.tripState
+			for j, state := range tc.passengerStates {
+				t.Passengers[j].State = state
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Error updating trip: %s in test case: %s", err.Error(), tc.name)
+		}
+		removed := trip
+		removed.TransactionID += "-removed"
+		err = safeTrips.Insert(removed)
+		if err != nil {
+			t.Fatalf("Error inserting trip: %s in test case: %s", err.Error(), tc.name)
+		}
+		safeTrips.Remove(removed.TransactionID)
+		journal.Close()
+
+		// Restart with an empty registry and seat ledger
+		journal, state, err := OpenJournal(journalPath)
+		if err != nil {
+			t.Fatalf("Expected journal to reopen, got: %s in test case: %s", err.Error(), tc.name)
+		}
+		journal.Close()
+		safeTrips = NewTripRegistry()
+		safeSeats = NewSeatLedger()
+		safeSeats.SetCapacity(simBoat2.BoatID, 10)
+		RestoreTrips(state.Trips)
+
+		if tc.expectedDropped {
+			if safeTrips.Len() != 0 {
+				t.Fatalf("Expected no restored trip, got: %d in test case: %s", safeTrips.Len(), tc.name)
+			}
+			continue
+		}
+		if safeTrips.Len() != 1 {
+			t.Fatalf("Expected 1 restored trip, got: %d in test case: %s", safeTrips.Len(), tc.name)
+		}
+		restored, ok := safeTrips.Load(trip.TransactionID)
+		if !ok || restored.TripState != tc.tripState {
+			t.Fatalf("Expected restored trip in TripState: %d, got: %+v in test case: %s",
+				tc.tripState, restored, tc.name)
+		}
+		seats, _ := safeSeats.Load(simBoat2.BoatID)
+		if seats.Reserved != tc.expectedReserved || seats.OnBoard != tc.expectedOnBoard {
+			t.Fatalf("Expected Reserved: %d, OnBoard: %d, got Reserved: %d, OnBoard: %d in test case: %s",
+				tc.expectedReserved, tc.expectedOnBoard, seats.Reserved, seats.OnBoard, tc.name)
+		}
+	}
+}
+
+func TestJournalCompaction(t *testing.T) {
+	defaultClock := SimClock
+	defer func() { SimClock = defaultClock }()
+	start := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	SimClock = clock
+
+	journalPath := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, _, err := OpenJournal(journalPath)
+	if err != nil {
+		t.Fatalf("Expected journal to open, got: %s", err.Error())
+	}
+	defer func() { journal.Close() }()
+	registry := NewTripRegistry()
+	registry.SetJournal(journal)
+
+	newTrip := func(transactionID string) Trip {
+		reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testJournalClient", simDock1, simDock2, time.Time{}, 1, nil)
+		return Trip{
+			Reservation:   reservation,
+			TransactionID: transactionID,
+			Boat:          simBoat1,
+			TripState:     TripStateReserved,
+			Passengers:    NewPassengers(reservation),
+		}
+	}
+	for _, transactionID := range []string{"trip-1", "trip-2"} {
+		err = registry.Insert(newTrip(transactionID))
+		if err != nil {
+			t.Fatalf("Error inserting trip: %s", err.Error())
+		}
+	}
+
+	// The trip is finished when it is cancelled
+	cancelled, err := registry.UpdateTripState("trip-2", TripStateCancelled)
+	if err != nil {
+		t.Fatalf("Error cancelling trip: %s", err.Error())
+	}
+	if !cancelled.FinishedAt.Equal(start) {
+		t.Fatalf("Expected FinishedAt: %s, got: %s", start, cancelled.FinishedAt)
+	}
+
+	// The journal is compacted once enough entries have been appended, and the
+	// finished trip is left out of the compacted journal. Three trip entries have
+	// been appended so far.
+	for step := range JournalCompactionEntries - 3 {
+		if journal.NeedsCompaction() {
+			t.Fatalf("Expected no compaction after %d entries", step+3)
+		}
+		journal.RecordFrame(step)
+	}
+	if !journal.NeedsCompaction() {
+		t.Fatalf("Expected compaction after %d entries", JournalCompactionEntries)
+	}
+	err = registry.CompactJournal(7)
+	if err != nil {
+		t.Fatalf("Error compacting journal: %s", err.Error())
+	}
+	if journal.NeedsCompaction() {
+		t.Fatal("Expected no compaction after the journal was compacted")
+	}
+
+	// The finished trip is kept for the retention period
+	removed := registry.RemoveFinished(clock.Now().Add(-FinishedTripRetention))
+	if len(removed) != 0 || registry.Len() != 2 {
+		t.Fatalf("Expected no removed trips and 2 trips, got: %v and %d trips", removed, registry.Len())
+	}
+	clock.Advance(FinishedTripRetention + time.Second)
+	removed = registry.RemoveFinished(clock.Now().Add(-FinishedTripRetention))
+	if len(removed) != 1 || removed[0].TransactionID != "trip-2" || registry.Len() != 1 {
+		t.Fatalf("Expected trip-2 removed and 1 trip, got: %v and %d trips", removed, registry.Len())
+	}
+
+	// The entries after the compaction are appended to the compacted journal
+	err = registry.Insert(newTrip("trip-3"))
+	if err != nil {
+		t.Fatalf("Error inserting trip: %s", err.Error())
+	}
+	journal.Close()
+
+	contents, err := os.ReadFile(journalPath)
+	if err != nil {
+		t.Fatalf("Error reading journal: %s", err.Error())
+	}
+	if lines := bytes.Count(contents, []byte("\n")); lines != 4 {
+		t.Fatalf("Expected 4 entries in the compacted journal, got: %d", lines)
+	}
+	journal, state, err := OpenJournal(journalPath)
+	if err != nil {
+		t.Fatalf("Expected journal to reopen, got: %s", err.Error())
+	}
+	var transactionIDs []string
+	for _, trip := range state.Trips {
+		transactionIDs = append(transactionIDs, trip.TransactionID)
+	}
+	if !slices.Equal(transactionIDs, []string{"trip-1", "trip-3"}) || state.Step != 7 {
+		t.Fatalf("Expected trips: [trip-1 trip-3] at Step: 7, got: %v at Step: %d",
+			transactionIDs, state.Step)
+	}
+}
+
+func TestFrameScheduleBoatStatuses(t *testing.T) {
+	type testCase struct {
+		name                  string
+		step                  int
+		expectedFrame1        int
+		expectedServiceState1 int32
+		expectedFrame2        int
+		expectedServiceState2 int32
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	// Boat 2 is delayed for two frames from step 2, and boat 1 goes out of
+	// service from step 3 for the rest of the simulation
+	scenario.Disruptions = []ScenarioDisruption{
+		{BoatID: simBoat2.BoatID, ServiceState: a.ServiceStateDelayed, StartFrame: 2, DurationFrames: 2},
+		{BoatID: simBoat1.BoatID, ServiceState: a.ServiceStateUnavailable, StartFrame: 3},
+	}
+	simFrames := scenario.BuildSimulationFrames()
+	schedule := NewFrameSchedule(simFrames, 15*time.Second)
+
+	cases := []testCase{
+		{
+			name:                  "BoatStatuses - Before the disruptions",
+			step:                  1,
+			expectedFrame1:        1,
+			expectedServiceState1: a.ServiceStateOnTime,
+			expectedFrame2:        1,
+			expectedServiceState2: a.ServiceStateOnTime,
+		},
+		{
+			name:                  "BoatStatuses - Boat 2 is delayed",
+			step:                  2,
+			expectedFrame1:        2,
+			expectedServiceState1: a.ServiceStateOnTime,
+			expectedFrame2:        2,
+			expectedServiceState2: a.ServiceStateDelayed,
+		},
+		{
+			name:                  "BoatStatuses - Boat 1 is out of service",
+			step:                  3,
+			expectedFrame1:        2,
+			expectedServiceState1: a.ServiceStateUnavailable,
+			expectedFrame2:        2,
+			expectedServiceState2: a.ServiceStateDelayed,
+		},
+		{
+			name:                  "BoatStatuses - Boat 2 resumes its route after the delay",
+			step:                  4,
+			expectedFrame1:        2,
+			expectedServiceState1: a.ServiceStateUnavailable,
+			expectedFrame2:        2,
+			expectedServiceState2: a.ServiceStateOnTime,
+		},
+		{
+			name:                  "BoatStatuses - Boat 2 remains behind in the next loop of the frames",
+			step:                  21,
+			expectedFrame1:        2,
+			expectedServiceState1: a.ServiceStateUnavailable,
+			expectedFrame2:        3,
+			expectedServiceState2: a.ServiceStateOnTime,
+		},
+	}
+
+	frameStatus := func(frame int, boatID int32) a.BoatStatusAPIMessage {
+		for _, status := range simFrames.BoatLocations[frame] {
+			if status.Boat.BoatID == boatID {
+				return status
+			}
+		}
+		return a.BoatStatusAPIMessage{}
+	}
+
+	for _, testCase := range cases {
+		statuses := schedule.BoatStatuses(testCase.step)
+		if len(statuses) != len(scenario.Boats) {
+			t.Fatalf("Expected %d statuses but received %d in test case: %s",
+				len(scenario.Boats), len(statuses), testCase.name)
+		}
+
+		for _, status := range statuses {
+			expectedStatus := frameStatus(testCase.expectedFrame1, simBoat1.BoatID)
+			expectedStatus.ServiceState = testCase.expectedServiceState1
+			if status.Boat.BoatID == simBoat2.BoatID {
+				expectedStatus = frameStatus(testCase.expectedFrame2, simBoat2.BoatID)
+				expectedStatus.ServiceState = testCase.expectedServiceState2
+			}
+			if status != expectedStatus {
+				t.Fatalf("Expected status %+v but received %+v in test case: %s",
+					expectedStatus, status, testCase.name)
+			}
+		}
+	}
+}
+
+func TestFrameScheduleTripETAs(t *testing.T) {
+	type testCase struct {
+		name                   string
+		recordFrame            bool
+		disruptions            []ScenarioDisruption
+		trip                   Trip
+		expectedSourceETA      time.Duration
+		expectedDestinationETA time.Duration
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	unknownDock := a.NewDock(a.NewAddress(1, "Nowhere St"), a.GangwayLocationFore)
+
+	newTrip := func(sourceDock, destinationDock a.Dock, tripState int32) Trip {
+		return Trip{
+			Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+				"testClient", sourceDock, destinationDock, time.Time{}, 0, nil),
+			Boat:      simBoat1,
+			TripState: tripState,
+		}
+	}
+
+	newScheduledTrip := func(sourceDock, destinationDock a.Dock, departure time.Duration) Trip {
+		trip := newTrip(sourceDock, destinationDock, TripStateScheduled)
+		trip.DepartureTime = sentAt.Add(departure)
+		return trip
+	}
+
+	// In frame 1, boat 1 is at dock 3 for the second frame. It then arrives at
+	// dock 4 in frame 4, dock 1 in frame 8 and dock 2 in frame 12.
+	cases := []testCase{
+		{
+			name:                   "TripETAs - Reserved trip",
+			recordFrame:            true,
+			trip:                   newTrip(simDock4, simDock2, TripStateReserved),
+			expectedSourceETA:      3 * frameDuration,
+			expectedDestinationETA: 11 * frameDuration,
+		},
+		{
+			name:                   "TripETAs - Boat is leaving the source dock",
+			recordFrame:            true,
+			trip:                   newTrip(simDock3, simDock4, TripStateClientAtDock),
+			expectedSourceETA:      15 * frameDuration,
+			expectedDestinationETA: 19 * frameDuration,
+		},
+		{
+			name:                   "TripETAs - Client is on board",
+			recordFrame:            true,
+			trip:                   newTrip(simDock2, simDock1, TripStateClientOnBoat),
+			expectedSourceETA:      0,
+			expectedDestinationETA: 7 * frameDuration,
+		},
+		{
+			name:                   "TripETAs - Client is off the boat",
+			recordFrame:            true,
+			trip:                   newTrip(simDock2, simDock1, TripStateClientOffBoat),
+			expectedSourceETA:      0,
+			expectedDestinationETA: 0,
+		},
+		{
+			name:                   "TripETAs - Destination dock is not served by the boat",
+			recordFrame:            true,
+			trip:                   newTrip(simDock4, unknownDock, TripStateReserved),
+			expectedSourceETA:      3 * frameDuration,
+			expectedDestinationETA: 0,
+		},
+		{
+			name:                   "TripETAs - Scheduled trip",
+			recordFrame:            true,
+			trip:                   newScheduledTrip(simDock1, simDock2, 23*frameDuration),
+			expectedSourceETA:      23 * frameDuration,
+			expectedDestinationETA: 27 * frameDuration,
+		},
+		{
+			name:        "TripETAs - Boat is delayed",
+			recordFrame: true,
+			disruptions: []ScenarioDisruption{
+				{BoatID: simBoat1.BoatID, ServiceState: a.ServiceStateDelayed, StartFrame: 2, DurationFrames: 3},
+			},
+			trip:                   newTrip(simDock4, simDock2, TripStateReserved),
+			expectedSourceETA:      6 * frameDuration,
+			expectedDestinationETA: 14 * frameDuration,
+		},
+		{
+			name:        "TripETAs - Boat goes out of service",
+			recordFrame: true,
+			disruptions: []ScenarioDisruption{
+				{BoatID: simBoat1.BoatID, ServiceState: a.ServiceStateUnavailable, StartFrame: 3},
+			},
+			trip:                   newTrip(simDock4, simDock2, TripStateReserved),
+			expectedSourceETA:      0,
+			expectedDestinationETA: 0,
+		},
+		{
+			name:                   "TripETAs - No frame has been sent",
+			recordFrame:            false,
+			trip:                   newTrip(simDock4, simDock2, TripStateReserved),
+			expectedSourceETA:      0,
+			expectedDestinationETA: 0,
+		},
+	}
+
+	for _, testCase := range cases {
+		simFrames := scenario.BuildSimulationFrames()
+		simFrames.Disruptions = testCase.disruptions
+		schedule := NewFrameSchedule(simFrames, frameDuration)
+		if testCase.recordFrame {
+			schedule.RecordFrame(1, sentAt)
+		}
+
+		sourceETA, destinationETA := schedule.TripETAs(testCase.trip)
+
+		expectedSourceETA := time.Time{}
+		if testCase.expectedSourceETA != 0 {
+			expectedSourceETA = sentAt.Add(testCase.expectedSourceETA)
+		}
+		if !sourceETA.Equal(expectedSourceETA) {
+			t.Fatalf("Expected SourceETA %s but received %s in test case: %s",
+				expectedSourceETA, sourceETA, testCase.name)
+		}
+
+		expectedDestinationETA := time.Time{}
+		if testCase.expectedDestinationETA != 0 {
+			expectedDestinationETA = sentAt.Add(testCase.expectedDestinationETA)
+		}
+		if !destinationETA.Equal(expectedDestinationETA) {
+			t.Fatalf("Expected DestinationETA %s but received %s in test case: %s",
+				expectedDestinationETA, destinationETA, testCase.name)
+		}
+	}
+}
+
+func TestFrameScheduleScheduledDeparture(t *testing.T) {
+	type testCase struct {
+		name              string
+		recordFrame       bool
+		disruptions       []ScenarioDisruption
+		boats             []a.Boat
+		dock              a.Dock
+		after             time.Duration
+		expectedOK        bool
+		expectedBoat      a.Boat
+		expectedDeparture time.Duration
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	unknownDock := a.NewDock(a.NewAddress(1, "Nowhere St"), a.GangwayLocationFore)
+	bothBoats := []a.Boat{simBoat1, simBoat2}
+
+	// In frame 1, boat 1 arrives next at dock 1 in frame 8 and boat 2 arrives
+	// next at dock 1 in frame 0. Each boat is at a dock for two frames.
+	cases := []testCase{
+		{
+			name:              "ScheduledDeparture - First boat to arrive",
+			recordFrame:       true,
+			boats:             bothBoats,
+			dock:              simDock1,
+			after:             frameDuration,
+			expectedOK:        true,
+			expectedBoat:      simBoat1,
+			expectedDeparture: 7 * frameDuration,
+		},
+		{
+			name:              "ScheduledDeparture - Departure between frames",
+			recordFrame:       true,
+			boats:             bothBoats,
+			dock:              simDock1,
+			after:             6*frameDuration + time.Second,
+			expectedOK:        true,
+			expectedBoat:      simBoat1,
+			expectedDeparture: 7 * frameDuration,
+		},
+		{
+			name:              "ScheduledDeparture - First boat has departed",
+			recordFrame:       true,
+			boats:             bothBoats,
+			dock:              simDock1,
+			after:             8 * frameDuration,
+			expectedOK:        true,
+			expectedBoat:      simBoat2,
+			expectedDeparture: 15 * frameDuration,
+		},
+		{
+			name:              "ScheduledDeparture - Boat is not in service",
+			recordFrame:       true,
+			boats:             []a.Boat{simBoat2},
+			dock:              simDock1,
+			after:             frameDuration,
+			expectedOK:        true,
+			expectedBoat:      simBoat2,
+			expectedDeparture: 15 * frameDuration,
+		},
+		{
+			name:              "ScheduledDeparture - Boat remaining at the dock does not depart",
+			recordFrame:       true,
+			boats:             []a.Boat{simBoat1},
+			dock:              simDock4,
+			after:             4 * frameDuration,
+			expectedOK:        true,
+			expectedBoat:      simBoat1,
+			expectedDeparture: 19 * frameDuration,
+		},
+		{
+			name:        "ScheduledDeparture - Delayed boat arrives later",
+			recordFrame: true,
+			disruptions: []ScenarioDisruption{
+				{BoatID: simBoat1.BoatID, ServiceState: a.ServiceStateDelayed, StartFrame: 5, DurationFrames: 2},
+			},
+			boats:             []a.Boat{simBoat1},
+			dock:              simDock1,
+			after:             frameDuration,
+			expectedOK:        true,
+			expectedBoat:      simBoat1,
+			expectedDeparture: 9 * frameDuration,
+		},
+		{
+			name:        "ScheduledDeparture - Boat is out of service when it arrives",
+			recordFrame: true,
+			disruptions: []ScenarioDisruption{
+				{BoatID: simBoat1.BoatID, ServiceState: a.ServiceStateUnavailable, StartFrame: 8, DurationFrames: 4},
+			},
+			boats:             bothBoats,
+			dock:              simDock1,
+			after:             frameDuration,
+			expectedOK:        true,
+			expectedBoat:      simBoat2,
+			expectedDeparture: 15 * frameDuration,
+		},
+		{
+			name:        "ScheduledDeparture - Dock is not served",
+			recordFrame: true,
+			boats:       bothBoats,
+			dock:        unknownDock,
+			after:       frameDuration,
+			expectedOK:  false,
+		},
+		{
+			name:        "ScheduledDeparture - No frame has been sent",
+			recordFrame: false,
+			boats:       bothBoats,
+			dock:        simDock1,
+			after:       frameDuration,
+			expectedOK:  false,
+		},
+	}
+
+	for _, testCase := range cases {
+		simFrames := scenario.BuildSimulationFrames()
+		simFrames.Disruptions = testCase.disruptions
+		schedule := NewFrameSchedule(simFrames, frameDuration)
+		if testCase.recordFrame {
+			schedule.RecordFrame(1, sentAt)
+		}
+
+		boat, departure, ok := schedule.ScheduledDeparture(testCase.boats, testCase.dock,
+			sentAt.Add(testCase.after))
+
+		if ok != testCase.expectedOK {
+			t.Fatalf("Expected ok %v but received %v in test case: %s",
+				testCase.expectedOK, ok, testCase.name)
+		}
+		if !ok {
+			continue
+		}
+
+		if boat != testCase.expectedBoat {
+			t.Fatalf("Expected boat %+v but received %+v in test case: %s",
+				testCase.expectedBoat, boat, testCase.name)
+		}
+
+		expectedDeparture := sentAt.Add(testCase.expectedDeparture)
+		if !departure.Equal(expectedDeparture) {
+			t.Fatalf("Expected departure %s but received %s in test case: %s",
+				expectedDeparture, departure, testCase.name)
+		}
+	}
+}
+
+func TestFrameScheduleIsNextDeparture(t *testing.T) {
+	type testCase struct {
+		name         string
+		recordFrame  bool
+		departure    time.Duration
+		expectedNext bool
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	// In frame 1, boat 1 arrives next at dock 1 in frame 8
+	cases := []testCase{
+		{
+			name:         "IsNextDeparture - Departure is the next arrival",
+			recordFrame:  true,
+			departure:    7 * frameDuration,
+			expectedNext: true,
+		},
+		{
+			name:         "IsNextDeparture - Departure is the next arrival with drift",
+			recordFrame:  true,
+			departure:    7*frameDuration + 2*time.Second,
+			expectedNext: true,
+		},
+		{
+			name:         "IsNextDeparture - Boat arrives before the departure",
+			recordFrame:  true,
+			departure:    23 * frameDuration,
+			expectedNext: false,
+		},
+		{
+			name:         "IsNextDeparture - No frame has been sent",
+			recordFrame:  false,
+			departure:    7 * frameDuration,
+			expectedNext: false,
+		},
+	}
+
+	for _, testCase := range cases {
+		schedule := NewFrameSchedule(scenario.BuildSimulationFrames(), frameDuration)
+		if testCase.recordFrame {
+			schedule.RecordFrame(1, sentAt)
+		}
+		trip := Trip{
+			Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+				"testClient", simDock1, simDock2, time.Time{}, 0, nil),
+			Boat:          simBoat1,
+			TripState:     TripStateScheduled,
+			DepartureTime: sentAt.Add(testCase.departure),
+		}
+
+		isNext := schedule.IsNextDeparture(trip)
+
+		if isNext != testCase.expectedNext {
+			t.Fatalf("Expected IsNextDeparture %v but received %v in test case: %s",
+				testCase.expectedNext, isNext, testCase.name)
+		}
+	}
+}
+
+func TestManualClock(t *testing.T) {
+	type testCase struct {
+		name          string
+		advance       time.Duration
+		expectedTicks []time.Duration
+	}
+
+	start := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	const period = 10 * time.Second
+	clock := NewManualClock(start)
+	ticks := clock.Tick(period)
+
+	// The cases advance the same clock in order
+	cases := []testCase{
+		{
+			name:          "ManualClock - Advance before the period has no tick",
+			advance:       5 * time.Second,
+			expectedTicks: nil,
+		},
+		{
+			name:          "ManualClock - Advance to the period has one tick",
+			advance:       5 * time.Second,
+			expectedTicks: []time.Duration{period},
+		},
+		{
+			name:          "ManualClock - Advance past several periods has a tick for each",
+			advance:       25 * time.Second,
+			expectedTicks: []time.Duration{2 * period, 3 * period},
+		},
+		{
+			name:          "ManualClock - Advance to the next period after a partial one has one tick",
+			advance:       5 * time.Second,
+			expectedTicks: []time.Duration{4 * period},
+		},
+	}
+
+	elapsed := time.Duration(0)
+	for _, tc := range cases {
+		elapsed += tc.advance
+		done := make(chan struct{})
+		go func() {
+			clock.Advance(tc.advance)
+			close(done)
+		}()
+
+		for _, expectedTick := range tc.expectedTicks {
+			select {
+			case tick := <-ticks:
+				if !tick.Equal(start.Add(expectedTick)) {
+					t.Fatalf("Expected tick at: %s, got: %s in test case: %s",
+						start.Add(expectedTick), tick, tc.name)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("Expected tick at: %s, got none in test case: %s",
+					start.Add(expectedTick), tc.name)
+			}
+		}
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected Advance to return in test case: %s", tc.name)
+		}
+		select {
+		case tick := <-ticks:
+			t.Fatalf("Expected no more ticks, got: %s in test case: %s", tick, tc.name)
+		default:
+		}
+		if !clock.Now().Equal(start.Add(elapsed)) {
+			t.Fatalf("Expected Now: %s, got: %s in test case: %s",
+				start.Add(elapsed), clock.Now(), tc.name)
+		}
+	}
+}
+
+// startTestSimulation replaces the globals used by the simulation goroutines with
+// the given scenario and clock, and starts the goroutines. The returned function
+// stops the goroutines and restores the globals.
+func startTestSimulation(scenario Scenario, clock *ManualClock, frameDuration time.Duration) func() {
+	defaultClock := SimClock
+	defaultSchedule := SimSchedule
+	scenarioSeats := safeSeats
+	defaultStop := StopSimFrames
+	defaultSimFrameBoatStatusChannel := SimFrameBoatStatusChannel
+	defaultSimControlChannel := SimControlChannel
+	defaultOutbox := AdapterOutbox
+	previousStatuses := make(map[any]any)
+	safeBoatStatuses.Range(func(boatID, boatStatus any) bool {
+		previousStatuses[boatID] = boatStatus
+		return true
+	})
+
+	SimClock = clock
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), frameDuration)
+	safeSeats = NewSeatLedger()
+	InitializeSeats(scenario)
+	StopSimFrames = make(chan struct{})
+	SimFrameBoatStatusChannel = make(chan a.BoatStatusAPIMessage, len(scenario.Boats))
+	SimControlChannel = make(chan SimControlRequest)
+	AdapterOutbox = a.NewOutbox[any](AdapterOutboxCapacity)
+
+	var wg sync.WaitGroup
+	wg.Go(UpdateBoatStatuses)
+	advance := clock.Tick(frameDuration)
+	wg.Go(func() { AdvanceSimFrames(advance, 0) })
+
+	return func() {
+		// The goroutines are stopped before the globals are restored
+		close(StopSimFrames)
+		wg.Wait()
+
+		SimClock = defaultClock
+		SimSchedule = defaultSchedule
+		safeSeats = scenarioSeats
+		StopSimFrames = defaultStop
+		SimFrameBoatStatusChannel = defaultSimFrameBoatStatusChannel
+		SimControlChannel = defaultSimControlChannel
+		AdapterOutbox = defaultOutbox
+		for boatID, boatStatus := range previousStatuses {
+			safeBoatStatuses.Store(boatID, boatStatus)
+		}
+	}
+}
+
+// nextAdapterMessage waits up to the given timeout for a message of type T to be
+// placed in the AdapterOutbox, and acknowledges the first message of that type as
+// if the Adapter had received it. The second result is false if no message was
+// placed in time.
+func nextAdapterMessage[T any](timeout time.Duration) (T, bool) {
+	deadline := time.After(timeout)
+	for {
+		pushed := AdapterOutbox.Notify()
+		for _, msg := range AdapterOutbox.PendingAfter(0) {
+			if typedMsg, ok := msg.Message.(T); ok {
+				AdapterOutbox.Acknowledge(msg.Epoch, msg.Sequence)
+				return typedMsg, true
+			}
+		}
+		select {
+		case <-pushed:
+		case <-deadline:
+			var msg T
+			return msg, false
+		}
+	}
+}
+
+// pendingAdapterMessages returns the messages of type T in the AdapterOutbox that
+// have not been acknowledged
+func pendingAdapterMessages[T any]() []T {
+	var msgs []T
+	for _, msg := range AdapterOutbox.PendingAfter(0) {
+		if typedMsg, ok := msg.Message.(T); ok {
+			msgs = append(msgs, typedMsg)
+		}
+	}
+	return msgs
+}
+
+func TestAdvanceSimFrames(t *testing.T) {
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	start := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	stopSimulation := startTestSimulation(scenario, clock, frameDuration)
+	defer stopSimulation()
+
+	// Boat 2 is at dock 1 in step 0 and at dock 2 in step 4
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+	reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+		"testAdvanceSimFramesClient", simDock1, simDock2, time.Time{}, 1, nil)
+	trip := Trip{
+		TransactionID: "testAdvanceSimFrames",
+		Reservation:   reservation,
+		Client:        clientData,
+		Boat:          simBoat2,
+		TripState:     TripStateReserved,
+		Passengers:    NewPassengers(reservation),
+	}
+	err = safeTrips.Insert(trip)
+	if err != nil {
+		t.Fatalf("Error inserting trip: %s", err.Error())
+	}
+	defer safeTrips.Remove(trip.TransactionID)
+	err = safeSeats.Reserve(simBoat2.BoatID, 1)
+	if err != nil {
+		t.Fatalf("Error reserving seat: %s", err.Error())
+	}
+
+	expectedArrivals := map[int]a.Dock{
+		0: simDock1,
+		4: simDock2,
+	}
+
+	for step := range len(scenario.Frames) {
+		clock.Advance(frameDuration)
+
+		expectedStatuses := SimSchedule.BoatStatuses(step)
+		for _, expectedStatus := range expectedStatuses {
+			boatStatus, ok := nextAdapterMessage[a.BoatStatusMockLogicMessage](time.Second)
+			if !ok {
+				t.Fatalf("Expected status of BoatID: %d, got none in step: %d",
+					expectedStatus.Boat.BoatID, step)
+			}
+			if boatStatus.APIMessage.Boat != expectedStatus.Boat ||
+				boatStatus.APIMessage.CurrentDock != expectedStatus.CurrentDock {
+				t.Fatalf("Expected BoatID: %d at dock: %v, got BoatID: %d at dock: %v in step: %d",
+					expectedStatus.Boat.BoatID, expectedStatus.CurrentDock,
+					boatStatus.APIMessage.Boat.BoatID, boatStatus.APIMessage.CurrentDock, step)
+			}
+		}
+
+		expectedDock, ok := expectedArrivals[step]
+		if !ok {
+			continue
+		}
+		arrived, ok := nextAdapterMessage[a.ArrivedMockLogicMessage](time.Second)
+		if !ok {
+			t.Fatalf("Expected arrival at dock: %v, got none in step: %d", expectedDock, step)
+		}
+		if arrived.APIMessage.Dock != expectedDock {
+			t.Fatalf("Expected arrival at dock: %v, got: %v in step: %d",
+				expectedDock, arrived.APIMessage.Dock, step)
+		}
+		if expectedDock == simDock1 {
+			err = ProcessOnBoat(a.NewOnBoatAPIMessage(a.APIMessageTypeOnBoat,
+				reservation.ClientID, simBoat2, trip.TransactionID, nil))
+			if err != nil {
+				t.Fatalf("Expected OnBoat to be accepted, got: %s", err.Error())
+			}
+		}
+	}
+
+	if arrivals := pendingAdapterMessages[a.ArrivedMockLogicMessage](); len(arrivals) != 0 {
+		t.Fatalf("Expected no more arrivals, got arrival at dock: %v", arrivals[0].APIMessage.Dock)
+	}
+	// The passenger did not get off, and is taken off the boat once it has left
+	// the destination dock
+	updatedTrip, _ := safeTrips.Load(trip.TransactionID)
+	if updatedTrip.TripState != TripStateClientOffBoat ||
+		updatedTrip.CountPassengers(PassengerStateOffBoat) != 1 {
+		t.Fatalf("Expected TripState: %d with the passenger off the boat, got: %+v",
+			TripStateClientOffBoat, updatedTrip)
+	}
+	expectedNow := start.Add(time.Duration(len(scenario.Frames)) * frameDuration)
+	if !SimClock.Now().Equal(expectedNow) {
+		t.Fatalf("Expected clock at: %s, got: %s", expectedNow, SimClock.Now())
+	}
+}
+
+func TestScaledClock(t *testing.T) {
+	type testCase struct {
+		name  string
+		speed float64
+	}
+
+	const period = 10 * time.Second
+	clock := NewScaledClock(1000)
+	ticks := clock.Tick(period)
+	previousTick := clock.Now()
+
+	// The cases change the speed of the same clock in order, and each tick of
+	// the period takes a few milliseconds of real time
+	cases := []testCase{
+		{
+			name:  "ScaledClock - Ticks at the starting speed",
+			speed: 1000,
+		},
+		{
+			name:  "ScaledClock - Ticks after the speed is raised",
+			speed: 5000,
+		},
+		{
+			name:  "ScaledClock - Ticks after the speed is lowered",
+			speed: 2000,
+		},
+	}
+
+	for _, tc := range cases {
+		clock.SetSpeed(tc.speed)
+		if clock.Speed() != tc.speed {
+			t.Fatalf("Expected Speed: %g, got: %g in test case: %s", tc.speed, clock.Speed(), tc.name)
+		}
+
+		select {
+		case tick := <-ticks:
+			if tick.Before(previousTick) {
+				t.Fatalf("Expected tick after: %s, got: %s in test case: %s", previousTick, tick, tc.name)
+			}
+			previousTick = tick
+		case <-time.After(time.Second):
+			t.Fatalf("Expected tick, got none in test case: %s", tc.name)
+		}
+
+		if clock.Now().Before(previousTick) {
+			t.Fatalf("Expected Now after the tick: %s, got: %s in test case: %s",
+				previousTick, clock.Now(), tc.name)
+		}
+	}
+}
+
+func TestSimControl(t *testing.T) {
+	type testCase struct {
+		name               string
+		advance            bool
+		request            SimControlRequest
+		expectedCode       codes.Code
+		expectedStep       int
+		expectedFrameIndex int
+		expectedPaused     bool
+		expectedFrameSent  bool
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	clock := NewManualClock(time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC))
+	stopSimulation := startTestSimulation(scenario, clock, frameDuration)
+	defer stopSimulation()
+
+	// The cases control the same simulation in order. A case that advances
+	// the clock does so before the request is sent.
+	cases := []testCase{
+		{
+			name:               "SimControl - Status before the first frame",
+			request:            SimControlRequest{Command: SimControlCommandGetStatus},
+			expectedCode:       codes.OK,
+			expectedStep:       -1,
+			expectedFrameIndex: -1,
+		},
+		{
+			name:               "SimControl - Clock sends the first frame",
+			advance:            true,
+			request:            SimControlRequest{Command: SimControlCommandGetStatus},
+			expectedCode:       codes.OK,
+			expectedStep:       0,
+			expectedFrameIndex: 0,
+			expectedFrameSent:  true,
+		},
+		{
+			name:               "SimControl - Pause",
+			request:            SimControlRequest{Command: SimControlCommandPause},
+			expectedCode:       codes.OK,
+			expectedStep:       0,
+			expectedFrameIndex: 0,
+			expectedPaused:     true,
+		},
+		{
+			name:               "SimControl - Clock does not send a frame while paused",
+			advance:            true,
+			request:            SimControlRequest{Command: SimControlCommandGetStatus},
+			expectedCode:       codes.OK,
+			expectedStep:       0,
+			expectedFrameIndex: 0,
+			expectedPaused:     true,
+		},
+		{
+			name:               "SimControl - Step while paused sends the next frame",
+			request:            SimControlRequest{Command: SimControlCommandStep},
+			expectedCode:       codes.OK,
+			expectedStep:       1,
+			expectedFrameIndex: 1,
+			expectedPaused:     true,
+			expectedFrameSent:  true,
+		},
+		{
+			// Boat 2 is at dock 4 in frame 12
+			name:               "SimControl - Jump to a later frame",
+			request:            SimControlRequest{Command: SimControlCommandJumpToFrame, FrameIndex: 12},
+			expectedCode:       codes.OK,
+			expectedStep:       12,
+			expectedFrameIndex: 12,
+			expectedPaused:     true,
+			expectedFrameSent:  true,
+		},
+		{
+			name:               "SimControl - Jump to an earlier frame moves on to the next loop",
+			request:            SimControlRequest{Command: SimControlCommandJumpToFrame, FrameIndex: 4},
+			expectedCode:       codes.OK,
+			expectedStep:       20,
+			expectedFrameIndex: 4,
+			expectedPaused:     true,
+			expectedFrameSent:  true,
+		},
+		{
+			name:               "SimControl - Jump past the last frame is rejected",
+			request:            SimControlRequest{Command: SimControlCommandJumpToFrame, FrameIndex: 16},
+			expectedCode:       codes.InvalidArgument,
+			expectedStep:       20,
+			expectedFrameIndex: 4,
+			expectedPaused:     true,
+		},
+		{
+			name:               "SimControl - Jump to a negative frame is rejected",
+			request:            SimControlRequest{Command: SimControlCommandJumpToFrame, FrameIndex: -1},
+			expectedCode:       codes.InvalidArgument,
+			expectedStep:       20,
+			expectedFrameIndex: 4,
+			expectedPaused:     true,
+		},
+		{
+			name:               "SimControl - SetSpeed of a manual clock is rejected",
+			request:            SimControlRequest{Command: SimControlCommandSetSpeed, Speed: 2},
+			expectedCode:       codes.FailedPrecondition,
+			expectedStep:       20,
+			expectedFrameIndex: 4,
+			expectedPaused:     true,
+		},
+		{
+			name:               "SimControl - Resume",
+			request:            SimControlRequest{Command: SimControlCommandResume},
+			expectedCode:       codes.OK,
+			expectedStep:       20,
+			expectedFrameIndex: 4,
+		},
+		{
+			name:               "SimControl - Step while running is rejected",
+			request:            SimControlRequest{Command: SimControlCommandStep},
+			expectedCode:       codes.FailedPrecondition,
+			expectedStep:       20,
+			expectedFrameIndex: 4,
+		},
+		{
+			name:               "SimControl - Clock sends the frame after the jump when resumed",
+			advance:            true,
+			request:            SimControlRequest{Command: SimControlCommandGetStatus},
+			expectedCode:       codes.OK,
+			expectedStep:       21,
+			expectedFrameIndex: 5,
+			expectedFrameSent:  true,
+		},
+		{
+			name:               "SimControl - Unknown command is rejected",
+			request:            SimControlRequest{Command: SimControlCommandUnknown},
+			expectedCode:       codes.InvalidArgument,
+			expectedStep:       21,
+			expectedFrameIndex: 5,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.advance {
+			clock.Advance(frameDuration)
+		}
+
+		simStatus, err := SendSimControl(tc.request)
+		code := codes.OK
+		if err != nil {
+			var controlErr *SimControlError
+			if !errors.As(err, &controlErr) {
+				t.Fatalf("Expected SimControlError, got: %s in test case: %s", err.Error(), tc.name)
+			}
+			code = controlErr.Code
+		}
+		if code != tc.expectedCode {
+			t.Fatalf("Expected code: %s, got: %s in test case: %s", tc.expectedCode, code, tc.name)
+		}
+		if err == nil && (simStatus.Step != tc.expectedStep ||
+			simStatus.FrameIndex != tc.expectedFrameIndex || simStatus.Paused != tc.expectedPaused ||
+			simStatus.FrameCount != len(scenario.Frames)) {
+			t.Fatalf("Expected Step: %d, FrameIndex: %d, Paused: %t, got: %+v in test case: %s",
+				tc.expectedStep, tc.expectedFrameIndex, tc.expectedPaused, simStatus, tc.name)
+		}
+
+		// The statuses of a frame are sent before the request is applied
+		if !tc.expectedFrameSent {
+			if statuses := pendingAdapterMessages[a.BoatStatusMockLogicMessage](); len(statuses) != 0 {
+				t.Fatalf("Expected no boat statuses, got: %d in test case: %s",
+					len(statuses), tc.name)
+			}
+			continue
+		}
+		if statuses := pendingAdapterMessages[a.BoatStatusMockLogicMessage](); len(statuses) != len(scenario.Boats) {
+			t.Fatalf("Expected %d boat statuses, got: %d in test case: %s",
+				len(scenario.Boats), len(statuses), tc.name)
+		}
+		for _, expectedStatus := range SimSchedule.BoatStatuses(tc.expectedStep) {
+			boatStatus, _ := nextAdapterMessage[a.BoatStatusMockLogicMessage](0)
+			if boatStatus.APIMessage.Boat != expectedStatus.Boat ||
+				boatStatus.APIMessage.CurrentDock != expectedStatus.CurrentDock {
+				t.Fatalf("Expected BoatID: %d at dock: %v, got BoatID: %d at dock: %v in test case: %s",
+					expectedStatus.Boat.BoatID, expectedStatus.CurrentDock,
+					boatStatus.APIMessage.Boat.BoatID, boatStatus.APIMessage.CurrentDock, tc.name)
+			}
+		}
+	}
+}
+
+func TestSimControlJumpToFrameUpdatesTrips(t *testing.T) {
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	clock := NewManualClock(time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC))
+	stopSimulation := startTestSimulation(scenario, clock, frameDuration)
+	defer stopSimulation()
+
+	// Boat 2 is at dock 1 in step 0 and leaves it in step 1, which are both
+	// skipped by the jump
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+	reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+		"testJumpClient", simDock1, simDock2, time.Time{}, 1, nil)
+	trip := Trip{
+		TransactionID: "testJumpToFrame",
+		Reservation:   reservation,
+		Client:        clientData,
+		Boat:          simBoat2,
+		TripState:     TripStateReserved,
+		Passengers:    NewPassengers(reservation),
+	}
+	err = safeTrips.Insert(trip)
+	if err != nil {
+		t.Fatalf("Error inserting trip: %s", err.Error())
+	}
+	defer safeTrips.Remove(trip.TransactionID)
+	err = safeSeats.Reserve(simBoat2.BoatID, 1)
+	if err != nil {
+		t.Fatalf("Error reserving seat: %s", err.Error())
+	}
+
+	const frameIndex = 6
+	simStatus, err := SendSimControl(SimControlRequest{
+		Command:    SimControlCommandJumpToFrame,
+		FrameIndex: frameIndex,
+	})
+	if err != nil || simStatus.Step != frameIndex {
+		t.Fatalf("Expected jump to Step: %d, got: %+v, %v", frameIndex, simStatus, err)
+	}
+
+	// The boat arrived at the source dock and left it without the party in the
+	// skipped steps, so the trip is cancelled
+	arrived, ok := nextAdapterMessage[a.ArrivedMockLogicMessage](time.Second)
+	if !ok || arrived.APIMessage.Dock != simDock1 {
+		t.Fatalf("Expected arrival at dock: %v, got: %+v", simDock1, arrived)
+	}
+	errMsg, ok := nextAdapterMessage[a.ErrorMockLogicMessage](time.Second)
+	if !ok || errMsg.APIMessage.TransactionID != trip.TransactionID {
+		t.Fatalf("Expected Error for TransactionID: %s, got: %+v", trip.TransactionID, errMsg)
+	}
+	updatedTrip, _ := safeTrips.Load(trip.TransactionID)
+	if updatedTrip.TripState != TripStateCancelled {
+		t.Fatalf("Expected TripState: %d, got: %d", TripStateCancelled, updatedTrip.TripState)
+	}
+	seats, _ := safeSeats.Load(simBoat2.BoatID)
+	if seats.Reserved != 0 {
+		t.Fatalf("Expected the seat to be given back, got Reserved: %d", seats.Reserved)
+	}
+	if arrivals := pendingAdapterMessages[a.ArrivedMockLogicMessage](); len(arrivals) != 0 {
+		t.Fatalf("Expected no more arrivals, got arrival at dock: %v", arrivals[0].APIMessage.Dock)
+	}
+
+	// Only the boat statuses of the frame that was jumped to are sent
+	for _, expectedStatus := range SimSchedule.BoatStatuses(frameIndex) {
+		boatStatus, _ := nextAdapterMessage[a.BoatStatusMockLogicMessage](0)
+		if boatStatus.APIMessage.Boat != expectedStatus.Boat ||
+			boatStatus.APIMessage.CurrentDock != expectedStatus.CurrentDock {
+			t.Fatalf("Expected BoatID: %d at dock: %v, got BoatID: %d at dock: %v",
+				expectedStatus.Boat.BoatID, expectedStatus.CurrentDock,
+				boatStatus.APIMessage.Boat.BoatID, boatStatus.APIMessage.CurrentDock)
+		}
+	}
+	if statuses := pendingAdapterMessages[a.BoatStatusMockLogicMessage](); len(statuses) != 0 {
+		t.Fatalf("Expected no more boat statuses, got: %d", len(statuses))
+	}
+}
+
+func TestTripProgressMessages(t *testing.T) {
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	defaultSchedule := SimSchedule
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), 15*time.Second)
+	SimSchedule.RecordFrame(1, sentAt)
+	defer func() { SimSchedule = defaultSchedule }()
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+	trips := []Trip{
+		{
+			Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+				"progressClient1", simDock4, simDock2, time.Time{}, 0, nil),
+			Client:        clientData,
+			TransactionID: "progress-1",
+			Boat:          simBoat1,
+			TripState:     TripStateReserved,
+		},
+		{
+			Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+				"progressClient2", simDock2, simDock1, time.Time{}, 0, nil),
+			Client:        clientData,
+			TransactionID: "progress-2",
+			Boat:          simBoat1,
+			TripState:     TripStateClientOffBoat,
+		},
+	}
+	for _, trip := range trips {
+		err := safeTrips.Insert(trip)
+		if err != nil {
+			t.Fatalf("Error inserting trip: %s", err.Error())
+		}
+		defer safeTrips.Remove(trip.TransactionID)
+	}
+
+	progress := TripProgressMessages(simBoat1.BoatID)
+
+	// Only the trip that has not reached the destination receives progress
+	if len(progress) != 1 {
+		t.Fatalf("Expected 1 TripProgress message but received %d", len(progress))
+	}
+	expectedAPIMsg := a.NewTripProgressAPIMessage(a.APIMessageTypeTripProgress, "progressClient1",
+		simBoat1, "progress-1", sentAt.Add(45*time.Second), sentAt.Add(165*time.Second))
+	if progress[0].APIMessage != expectedAPIMsg {
+		t.Fatalf("Expected TripProgress message %+v but received %+v",
+			expectedAPIMsg, progress[0].APIMessage)
+	}
+	if progress[0].Client != clientData {
+		t.Fatalf("Expected client data %+v but received %+v", clientData, progress[0].Client)
+	}
+}
+
+func TestCheckArrivals(t *testing.T) {
+	type testCase struct {
+		name              string
+		setTripState      int32
+		boatStatus        a.BoatStatusAPIMessage
+		expectedArrivals  int
+		expectedDock      a.Dock
+		expectedTripState int32
+	}
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+	trip := Trip{
+		Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testArrivalsClient", simDock2, simDock4, time.Time{}, 0, nil),
+		Client:        clientData,
+		TransactionID: "testArrivals-1",
+		Boat:          simBoat1,
+		TripState:     TripStateReserved,
+		Passengers:    []Passenger{{}},
+	}
+	err := safeTrips.Insert(trip)
+	if err != nil {
+		t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+	}
+	defer safeTrips.Remove(trip.TransactionID)
+
+	// The test cases are run in order against the same trip
+	cases := []testCase{
+		{
+			name: "CheckArrivals - Boat travelling between docks",
+			boatStatus: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				PreviousDock: simDock1,
+				NextDock:     simDock2,
+			},
+			expectedArrivals:  0,
+			expectedTripState: TripStateReserved,
+		},
+		{
+			name: "CheckArrivals - Boat of another trip at source dock",
+			boatStatus: a.BoatStatusAPIMessage{
+				Boat:         simBoat2,
+				PreviousDock: simDock1,
+				CurrentDock:  simDock2,
+				NextDock:     simDock3,
+			},
+			expectedArrivals:  0,
+			expectedTripState: TripStateReserved,
+		},
+		{
+			name: "CheckArrivals - Boat at source dock",
+			boatStatus: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				PreviousDock: simDock1,
+				CurrentDock:  simDock2,
+				NextDock:     simDock3,
+			},
+			expectedArrivals:  1,
+			expectedDock:      simDock2,
+			expectedTripState: TripStateBoatArrivedAtSource,
+		},
+		{
+			name: "CheckArrivals - Boat remains at source dock for second frame",
+			boatStatus: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				PreviousDock: simDock1,
+				CurrentDock:  simDock2,
+				NextDock:     simDock3,
+			},
+			expectedArrivals:  0,
+			expectedTripState: TripStateBoatArrivedAtSource,
+		},
+		{
+			name:         "CheckArrivals - Boat at destination dock",
+			setTripState: TripStateClientOnBoat,
+			boatStatus: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				PreviousDock: simDock3,
+				CurrentDock:  simDock4,
+				NextDock:     simDock1,
+			},
+			expectedArrivals:  1,
+			expectedDock:      simDock4,
+			expectedTripState: TripStateBoatArrivedAtDest,
+		},
+		{
+			name: "CheckArrivals - Boat remains at destination dock for second frame",
+			boatStatus: a.BoatStatusAPIMessage{
+				Boat:         simBoat1,
+				PreviousDock: simDock3,
+				CurrentDock:  simDock4,
+				NextDock:     simDock1,
+			},
+			expectedArrivals:  0,
+			expectedTripState: TripStateBoatArrivedAtDest,
+		},
+	}
+
+	for _, testCase := range cases {
+		if testCase.setTripState != TripStateUnknown {
+			_, err := safeTrips.UpdateTripState(trip.TransactionID, testCase.setTripState)
+			if err != nil {
+				t.Fatalf("Error setting trip state in test case %s: %s", testCase.name, err.Error())
+			}
+		}
+
+		arrivals := CheckArrivals(testCase.boatStatus)
+
+		if len(arrivals) != testCase.expectedArrivals {
+			t.Fatalf("Expected %d arrivals but received %d in test case: %s",
+				testCase.expectedArrivals, len(arrivals), testCase.name)
+		}
+
+		for _, arrived := range arrivals {
+			if arrived.APIMessage.Dock != testCase.expectedDock {
+				t.Fatalf("Expected dock %+v but received %+v in test case: %s",
+					testCase.expectedDock, arrived.APIMessage.Dock, testCase.name)
+			}
+
+			if arrived.APIMessage.TransactionID != trip.TransactionID {
+				t.Fatalf("Expected TransactionID %s but received %s in test case: %s",
+					trip.TransactionID, arrived.APIMessage.TransactionID, testCase.name)
+			}
+
+			if arrived.Client != clientData {
+				t.Fatalf("Expected client data %+v but received %+v in test case: %s",
+					clientData, arrived.Client, testCase.name)
+			}
+		}
+
+		storedTrip, ok := safeTrips.Load(trip.TransactionID)
+		if !ok {
+			t.Fatalf("Trip was not found in test case: %s", testCase.name)
+		}
+
+		if storedTrip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, storedTrip.TripState, testCase.name)
+		}
+	}
+}
+
+func TestCheckDepartures(t *testing.T) {
+	type testCase struct {
+		name                    string
+		tripState               int32
+		passengerStates         []int32
+		boatStatus              a.BoatStatusAPIMessage
+		expectedErrors          int
+		expectedTripState       int32
+		expectedPassengerStates []int32
+		expectedReserved        int32
+		expectedOnBoard         int32
+	}
+
+	// Use a separate seat ledger for each test case so the seats of the
+	// scenario are not changed
+	scenarioSeats := safeSeats
+	defer func() { safeSeats = scenarioSeats }()
+
+	atSource := a.BoatStatusAPIMessage{
+		Boat:         simBoat1,
+		PreviousDock: simDock1,
+		CurrentDock:  simDock2,
+		NextDock:     simDock3,
+	}
+	leftSource := a.BoatStatusAPIMessage{
+		Boat:         simBoat1,
+		PreviousDock: simDock2,
+		NextDock:     simDock3,
+	}
+	atDestination := a.BoatStatusAPIMessage{
+		Boat:         simBoat1,
+		PreviousDock: simDock3,
+		CurrentDock:  simDock4,
+		NextDock:     simDock1,
+	}
+	leftDestination := a.BoatStatusAPIMessage{
+		Boat:         simBoat1,
+		PreviousDock: simDock4,
+		CurrentDock:  simDock1,
+		NextDock:     simDock2,
+	}
+
+	cases := []testCase{
+		{
+			name:                    "CheckDepartures - Boat remains at source dock",
+			tripState:               TripStateBoatArrivedAtSource,
+			passengerStates:         []int32{PassengerStateReserved, PassengerStateReserved},
+			boatStatus:              atSource,
+			expectedTripState:       TripStateBoatArrivedAtSource,
+			expectedPassengerStates: []int32{PassengerStateReserved, PassengerStateReserved},
+			expectedReserved:        2,
+		},
+		{
+			name:                    "CheckDepartures - Party did not show before boat left source dock",
+			tripState:               TripStateBoatArrivedAtSource,
+			passengerStates:         []int32{PassengerStateReserved, PassengerStateReserved},
+			boatStatus:              leftSource,
+			expectedErrors:          1,
+			expectedTripState:       TripStateCancelled,
+			expectedPassengerStates: []int32{PassengerStateReserved, PassengerStateReserved},
+		},
+		{
+			name:                    "CheckDepartures - Trip on board when boat left source dock",
+			tripState:               TripStateClientOnBoat,
+			passengerStates:         []int32{PassengerStateOnBoard, PassengerStateReserved},
+			boatStatus:              leftSource,
+			expectedTripState:       TripStateClientOnBoat,
+			expectedPassengerStates: []int32{PassengerStateOnBoard, PassengerStateReserved},
+			expectedReserved:        1,
+			expectedOnBoard:         1,
+		},
+		{
+			name:                    "CheckDepartures - Boat remains at destination dock",
+			tripState:               TripStateBoatArrivedAtDest,
+			passengerStates:         []int32{PassengerStateOnBoard},
+			boatStatus:              atDestination,
+			expectedTripState:       TripStateBoatArrivedAtDest,
+			expectedPassengerStates: []int32{PassengerStateOnBoard},
+			expectedOnBoard:         1,
+		},
+		{
+			name:                    "CheckDepartures - Party did not get off before boat left destination dock",
+			tripState:               TripStateBoatArrivedAtDest,
+			passengerStates:         []int32{PassengerStateOnBoard, PassengerStateReserved},
+			boatStatus:              leftDestination,
+			expectedTripState:       TripStateClientOffBoat,
+			expectedPassengerStates: []int32{PassengerStateOffBoat, PassengerStateReserved},
+		},
+		{
+			name:                    "CheckDepartures - Part of party did not get off before boat left destination dock",
+			tripState:               TripStateClientOffBoat,
+			passengerStates:         []int32{PassengerStateOffBoat, PassengerStateOnBoard},
+			boatStatus:              leftDestination,
+			expectedTripState:       TripStateClientOffBoat,
+			expectedPassengerStates: []int32{PassengerStateOffBoat, PassengerStateOffBoat},
+		},
+	}
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+	for i, testCase := range cases {
+		safeSeats = NewSeatLedger()
+		safeSeats.SetCapacity(simBoat1.BoatID, 10)
+		reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testDeparturesClient", simDock2, simDock4, time.Time{},
+			int32(len(testCase.passengerStates)), nil)
+		trip := Trip{
+			Reservation:   reservation,
+			Client:        clientData,
+			TransactionID: "testDepartures-" + strconv.Itoa(i),
+			Boat:          simBoat1,
+			TripState:     testCase.tripState,
+			Passengers:    NewPassengers(reservation),
+		}
+		for j, state := range testCase.passengerStates {
+			trip.Passengers[j].State = state
+		}
+		err := safeTrips.Insert(trip)
+		if err != nil {
+			t.Fatalf("Error inserting trip in test case %s: %s", testCase.name, err.Error())
+		}
+		err = restoreTripSeats(trip)
+		if err != nil {
+			t.Fatalf("Error taking seats in test case %s: %s", testCase.name, err.Error())
+		}
+
+		errMsgs := CheckDepartures(testCase.boatStatus)
+		storedTrip, ok := safeTrips.Remove(trip.TransactionID)
+		if !ok {
+			t.Fatalf("Trip was not found in test case: %s", testCase.name)
+		}
+
+		if len(errMsgs) != testCase.expectedErrors {
+			t.Fatalf("Expected %d errors but received %d in test case: %s",
+				testCase.expectedErrors, len(errMsgs), testCase.name)
+		}
+		for _, errMsg := range errMsgs {
+			if errMsg.APIMessage.TransactionID != trip.TransactionID ||
+				errMsg.APIMessage.ReasonCode != a.ErrorReasonInvalidTripState ||
+				errMsg.Client != clientData {
+				t.Fatalf("Expected Error for TransactionID %s with ReasonCode %d but received %+v in test case: %s",
+					trip.TransactionID, a.ErrorReasonInvalidTripState, errMsg, testCase.name)
+			}
+		}
+
+		if storedTrip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, storedTrip.TripState, testCase.name)
+		}
+		var passengerStates []int32
+		for _, passenger := range storedTrip.Passengers {
+			passengerStates = append(passengerStates, passenger.State)
+		}
+		if !slices.Equal(passengerStates, testCase.expectedPassengerStates) {
+			t.Fatalf("Expected passenger states %v but received %v in test case: %s",
+				testCase.expectedPassengerStates, passengerStates, testCase.name)
+		}
+		seats, _ := safeSeats.Load(simBoat1.BoatID)
+		if seats.Reserved != testCase.expectedReserved || seats.OnBoard != testCase.expectedOnBoard {
+			t.Fatalf("Expected Reserved: %d, OnBoard: %d, got Reserved: %d, OnBoard: %d in test case: %s",
+				testCase.expectedReserved, testCase.expectedOnBoard, seats.Reserved, seats.OnBoard, testCase.name)
+		}
+	}
+}
+
+func TestActivateScheduledTrips(t *testing.T) {
+	type testCase struct {
+		name               string
+		capacity1          int32
+		departure          time.Duration
+		expectedTripState  int32
+		expectedErrors     int
+		expectedReasonCode int32
+		expectedReserved   int32
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	defaultSchedule := SimSchedule
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), frameDuration)
+	SimSchedule.RecordFrame(1, sentAt)
+	defer func() { SimSchedule = defaultSchedule }()
+
+	// Use a separate seat ledger so the seats of the scenario are not changed
+	scenarioSeats := safeSeats
+	safeSeats = NewSeatLedger()
+	defer func() { safeSeats = scenarioSeats }()
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+
+	// In frame 1, boat 1 arrives next at dock 1 in frame 8, and then again in
+	// frame 24
+	cases := []testCase{
+		{
+			name:              "ActivateScheduledTrips - Departure is the next call",
+			capacity1:         40,
+			departure:         7 * frameDuration,
+			expectedTripState: TripStateReserved,
+			expectedErrors:    0,
+			expectedReserved:  1,
+		},
+		{
+			name:              "ActivateScheduledTrips - Boat calls before the departure",
+			capacity1:         40,
+			departure:         23 * frameDuration,
+			expectedTripState: TripStateScheduled,
+			expectedErrors:    0,
+			expectedReserved:  0,
+		},
+		{
+			name:               "ActivateScheduledTrips - Boat is full",
+			capacity1:          0,
+			departure:          7 * frameDuration,
+			expectedTripState:  TripStateCancelled,
+			expectedErrors:     1,
+			expectedReasonCode: a.ErrorReasonBoatsFull,
+			expectedReserved:   0,
+		},
+	}
+
+	for _, testCase := range cases {
+		safeSeats.SetCapacity(simBoat1.BoatID, testCase.capacity1)
+		scheduledTrip := Trip{
+			Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+				"testScheduledClient", simDock1, simDock2, sentAt, 0, nil),
+			Client:        clientData,
+			TransactionID: "testScheduledTrip-1",
+			Boat:          simBoat1,
+			TripState:     TripStateScheduled,
+			DepartureTime: sentAt.Add(testCase.departure),
+		}
+		err := safeTrips.Insert(scheduledTrip)
+		if err != nil {
+			t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+		}
+
+		errMsgs := ActivateScheduledTrips(simBoat1.BoatID)
+		trip, _ := safeTrips.Remove(scheduledTrip.TransactionID)
+		seats, _ := safeSeats.Load(simBoat1.BoatID)
+		if trip.TripState == TripStateReserved {
+			safeSeats.Release(simBoat1.BoatID, 1, false)
+		}
+
+		if trip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, trip.TripState, testCase.name)
+		}
+
+		if seats.Reserved != testCase.expectedReserved {
+			t.Fatalf("Expected %d reserved seats but received %d in test case: %s",
+				testCase.expectedReserved, seats.Reserved, testCase.name)
+		}
+
+		if len(errMsgs) != testCase.expectedErrors {
+			t.Fatalf("Expected %d Error messages but received %d in test case: %s",
+				testCase.expectedErrors, len(errMsgs), testCase.name)
+		}
+
+		for _, errMsg := range errMsgs {
+			if errMsg.APIMessage.ReasonCode != testCase.expectedReasonCode {
+				t.Fatalf("Expected ReasonCode %d but received %d in test case: %s",
+					testCase.expectedReasonCode, errMsg.APIMessage.ReasonCode, testCase.name)
+			}
+			if errMsg.APIMessage.TransactionID != scheduledTrip.TransactionID {
+				t.Fatalf("Expected TransactionID %s but received %s in test case: %s",
+					scheduledTrip.TransactionID, errMsg.APIMessage.TransactionID, testCase.name)
+			}
+			if errMsg.Client != clientData {
+				t.Fatalf("Expected client data %+v but received %+v in test case: %s",
+					clientData, errMsg.Client, testCase.name)
+			}
+		}
+	}
+}
+
+func TestReassignTrips(t *testing.T) {
+	type testCase struct {
+		name                  string
+		trip                  Trip
+		expectedTripState     int32
+		expectedBoat          a.Boat
+		expectedAck           bool
+		expectedReasonCode    int32
+		expectedNeedsFollowUp bool
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	const frameDuration = 15 * time.Second
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	defaultSchedule := SimSchedule
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), frameDuration)
+	SimSchedule.RecordFrame(1, sentAt)
+	defer func() { SimSchedule = defaultSchedule }()
+
+	// Use a separate seat ledger so the seats of the scenario are not changed
+	scenarioSeats := safeSeats
+	safeSeats = NewSeatLedger()
+	defer func() { safeSeats = scenarioSeats }()
+	safeSeats.SetCapacity(simBoat1.BoatID, 10)
+	safeSeats.SetCapacity(simBoat2.BoatID, 4)
+
+	// Boat 1 has gone out of service and boat 2 is the only boat in service
+	for _, boatStatus := range []a.BoatStatusAPIMessage{
+		{Boat: simBoat1, ServiceState: a.ServiceStateUnavailable, PreviousDock: simDock3, NextDock: simDock4},
+		{Boat: simBoat2, ServiceState: a.ServiceStateOnTime, PreviousDock: simDock1, NextDock: simDock2},
+	} {
+		previousStatus, ok := safeBoatStatuses.Load(boatStatus.Boat.BoatID)
+		if ok {
+			defer safeBoatStatuses.Store(boatStatus.Boat.BoatID, previousStatus)
+		}
+		safeBoatStatuses.Store(boatStatus.Boat.BoatID, boatStatus)
+	}
+
+	clientData := a.NewClientData("testConnName", a.ConnectionTypeWebSocket)
+	newTrip := func(transactionID string, partySize int32, tripState int32) Trip {
+		reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testReassignClient", simDock2, simDock4, time.Time{}, partySize, nil)
+		return Trip{
+			Reservation:   reservation,
+			Client:        clientData,
+			TransactionID: transactionID,
+			Boat:          simBoat1,
+			ServiceState:  a.ServiceStateOnTime,
+			TripState:     tripState,
+			Passengers:    NewPassengers(reservation),
+		}
+	}
+	scheduledTrip := newTrip("testReassign-6", 1, TripStateScheduled)
+	scheduledTrip.Reservation.SourceDock = simDock1
+	scheduledTrip.Reservation.DestinationDock = simDock2
+	scheduledTrip.Reservation.DepartureTime = sentAt.Add(20 * frameDuration)
+	scheduledTrip.DepartureTime = sentAt.Add(23 * frameDuration)
+
+	// The trips are reassigned in order of TransactionID, until boat 2 is full
+	cases := []testCase{
+		{
+			name:              "ReassignTrips - Reserved party",
+			trip:              newTrip("testReassign-1", 2, TripStateReserved),
+			expectedTripState: TripStateReserved,
+			expectedBoat:      simBoat2,
+			expectedAck:       true,
+		},
+		{
+			name:              "ReassignTrips - Client waits at the dock for the new boat",
+			trip:              newTrip("testReassign-2", 1, TripStateClientAtDock),
+			expectedTripState: TripStateClientAtDock,
+			expectedBoat:      simBoat2,
+			expectedAck:       true,
+		},
+		{
+			name:              "ReassignTrips - Boat had arrived at the source dock",
+			trip:              newTrip("testReassign-3", 1, TripStateBoatArrivedAtSource),
+			expectedTripState: TripStateReserved,
+			expectedBoat:      simBoat2,
+			expectedAck:       true,
+		},
+		{
+			name:               "ReassignTrips - No boat has room for the trip",
+			trip:               newTrip("testReassign-4", 1, TripStateReserved),
+			expectedTripState:  TripStateCancelled,
+			expectedBoat:       simBoat1,
+			expectedReasonCode: a.ErrorReasonBoatsFull,
+		},
+		{
+			name:                  "ReassignTrips - Client is on board",
+			trip:                  newTrip("testReassign-5", 1, TripStateClientOnBoat),
+			expectedTripState:     TripStateClientOnBoat,
+			expectedBoat:          simBoat1,
+			expectedNeedsFollowUp: true,
+		},
+		{
+			name:              "ReassignTrips - Scheduled trip",
+			trip:              scheduledTrip,
+			expectedTripState: TripStateScheduled,
+			expectedBoat:      simBoat2,
+			expectedAck:       true,
+		},
+		{
+			name:              "ReassignTrips - Trip has ended",
+			trip:              newTrip("testReassign-7", 1, TripStateClientOffBoat),
+			expectedTripState: TripStateClientOffBoat,
+			expectedBoat:      simBoat1,
+		},
+	}
+
+	for _, testCase := range cases {
+		err := safeTrips.Insert(testCase.trip)
+		if err != nil {
+			t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+		}
+		defer safeTrips.Remove(testCase.trip.TransactionID)
+		if testCase.trip.TripState >= TripStateReserved && testCase.trip.TripState <= TripStateBoatArrivedAtSource {
+			err = safeSeats.Reserve(simBoat1.BoatID, PartySize(testCase.trip.Reservation))
+			if err != nil {
+				t.Fatalf("Error reserving seats in test set-up: %s", err.Error())
+			}
+		}
+	}
+
+	acks, errMsgs := ReassignTrips(simBoat1.BoatID)
+
+	for _, testCase := range cases {
+		storedTrip, _ := safeTrips.Load(testCase.trip.TransactionID)
+		if storedTrip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, storedTrip.TripState, testCase.name)
+		}
+
+		if storedTrip.Boat != testCase.expectedBoat {
+			t.Fatalf("Expected boat %+v but received %+v in test case: %s",
+				testCase.expectedBoat, storedTrip.Boat, testCase.name)
+		}
+
+		if storedTrip.NeedsFollowUp != testCase.expectedNeedsFollowUp {
+			t.Fatalf("Expected NeedsFollowUp %v but received %v in test case: %s",
+				testCase.expectedNeedsFollowUp, storedTrip.NeedsFollowUp, testCase.name)
+		}
+
+		ackIndex := slices.IndexFunc(acks, func(ack a.AckMockLogicMessage) bool {
+			return ack.APIMessage.TransactionID == testCase.trip.TransactionID
+		})
+		if testCase.expectedAck != (ackIndex >= 0) {
+			t.Fatalf("Expected an Ack: %v but received acks: %+v in test case: %s",
+				testCase.expectedAck, acks, testCase.name)
+		}
+		if ackIndex >= 0 {
+			ack := acks[ackIndex]
+			if !ack.APIMessage.IsReserved || ack.APIMessage.Boat != testCase.expectedBoat ||
+				ack.Client != clientData {
+				t.Fatalf("Expected a reserved Ack on boat %+v but received %+v in test case: %s",
+					testCase.expectedBoat, ack, testCase.name)
+			}
+		}
+
+		errIndex := slices.IndexFunc(errMsgs, func(errMsg a.ErrorMockLogicMessage) bool {
+			return errMsg.APIMessage.TransactionID == testCase.trip.TransactionID
+		})
+		if (testCase.expectedReasonCode != a.ErrorReasonUnknown) != (errIndex >= 0) {
+			t.Fatalf("Expected an Error with reason code %d but received errors: %+v in test case: %s",
+				testCase.expectedReasonCode, errMsgs, testCase.name)
+		}
+		if errIndex >= 0 && errMsgs[errIndex].APIMessage.ReasonCode != testCase.expectedReasonCode {
+			t.Fatalf("Expected reason code %d but received %d in test case: %s",
+				testCase.expectedReasonCode, errMsgs[errIndex].APIMessage.ReasonCode, testCase.name)
+		}
+	}
+
+	// The seats of the trips that had not boarded are moved off boat 1
+	seats1, _ := safeSeats.Load(simBoat1.BoatID)
+	seats2, _ := safeSeats.Load(simBoat2.BoatID)
+	if seats1.Reserved != 0 || seats2.Reserved != 4 {
+		t.Fatalf("Expected 0 reserved seats on boat 1 and 4 on boat 2 but received %d and %d",
+			seats1.Reserved, seats2.Reserved)
+	}
+}
+
+func TestProcessTripMessages(t *testing.T) {
+	type testCase struct {
+		name               string
+		process            func() error
+		expectedReasonCode int32
+		expectedError      bool
+		expectedTripState  int32
+	}
+
+	trip := Trip{
+		Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testTripMsgClient", simDock2, simDock4, time.Time{}, 0, nil),
+		TransactionID: "testTripMessages-1",
+		Boat:          simBoat1,
+		TripState:     TripStateReserved,
+		Passengers:    []Passenger{{}},
+	}
+	err := safeTrips.Insert(trip)
+	if err != nil {
+		t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+	}
+	defer safeTrips.Remove(trip.TransactionID)
+
+	clientID := trip.Reservation.ClientID
+	transactionID := trip.TransactionID
+
+	// The test cases are run in order against the same trip
+	cases := []testCase{
+		{
+			name: "ProcessTripMessages - OnBoat before boat arrived at source dock",
+			process: func() error {
+				return ProcessOnBoat(a.NewOnBoatAPIMessage(a.APIMessageTypeOnBoat,
+					clientID, simBoat1, transactionID, nil))
+			},
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateReserved,
+		},
+		{
+			name: "ProcessTripMessages - AtDock for unknown trip",
+			process: func() error {
+				return ProcessAtDock(a.NewAtDockAPIMessage(a.APIMessageTypeAtDock,
+					clientID, simBoat1, simDock2, "unknown"))
+			},
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonTripNotFound,
+			expectedTripState:  TripStateReserved,
+		},
+		{
+			name: "ProcessTripMessages - AtDock from another client",
+			process: func() error {
+				return ProcessAtDock(a.NewAtDockAPIMessage(a.APIMessageTypeAtDock,
+					"anotherClient", simBoat1, simDock2, transactionID))
+			},
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonTripMismatch,
+			expectedTripState:  TripStateReserved,
+		},
+		{
+			name: "ProcessTripMessages - AtDock with another boat",
+			process: func() error {
+				return ProcessAtDock(a.NewAtDockAPIMessage(a.APIMessageTypeAtDock,
+					clientID, simBoat2, simDock2, transactionID))
+			},
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonTripMismatch,
+			expectedTripState:  TripStateReserved,
+		},
+		{
+			name: "ProcessTripMessages - AtDock at another dock",
+			process: func() error {
+				return ProcessAtDock(a.NewAtDockAPIMessage(a.APIMessageTypeAtDock,
+					clientID, simBoat1, simDock3, transactionID))
+			},
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonTripMismatch,
+			expectedTripState:  TripStateReserved,
+		},
+		{
+			name: "ProcessTripMessages - AtDock at source dock",
+			process: func() error {
+				return ProcessAtDock(a.NewAtDockAPIMessage(a.APIMessageTypeAtDock,
+					clientID, simBoat1, simDock2, transactionID))
+			},
+			expectedError:     false,
+			expectedTripState: TripStateClientAtDock,
+		},
+		{
+			name: "ProcessTripMessages - Boat arrives at source dock",
+			process: func() error {
+				CheckArrivals(a.BoatStatusAPIMessage{Boat: simBoat1, CurrentDock: simDock2})
+				return nil
+			},
+			expectedError:     false,
+			expectedTripState: TripStateBoatArrivedAtSource,
+		},
+		{
+			name: "ProcessTripMessages - OffBoat before boat arrived at destination dock",
+			process: func() error {
+				return ProcessOffBoat(a.NewOffBoatAPIMessage(a.APIMessageTypeOffBoat,
+					clientID, simBoat1, transactionID, nil))
+			},
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateBoatArrivedAtSource,
+		},
+		{
+			name: "ProcessTripMessages - OnBoat after boat arrived at source dock",
+			process: func() error {
+				return ProcessOnBoat(a.NewOnBoatAPIMessage(a.APIMessageTypeOnBoat,
+					clientID, simBoat1, transactionID, nil))
+			},
+			expectedError:     false,
+			expectedTripState: TripStateClientOnBoat,
+		},
+		{
+			name: "ProcessTripMessages - Boat arrives at destination dock",
+			process: func() error {
+				CheckArrivals(a.BoatStatusAPIMessage{Boat: simBoat1, CurrentDock: simDock4})
+				return nil
+			},
+			expectedError:     false,
+			expectedTripState: TripStateBoatArrivedAtDest,
+		},
+		{
+			name: "ProcessTripMessages - OffBoat after boat arrived at destination dock",
+			process: func() error {
+				return ProcessOffBoat(a.NewOffBoatAPIMessage(a.APIMessageTypeOffBoat,
+					clientID, simBoat1, transactionID, nil))
+			},
+			expectedError:     false,
+			expectedTripState: TripStateClientOffBoat,
+		},
+	}
+
+	for _, testCase := range cases {
+		err := testCase.process()
+
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expectation of error was %v for test %s but received error was: %v",
+				testCase.expectedError, testCase.name, err)
+		}
+
+		if err != nil {
+			errMsg := NewTripMessageErrorMessage(err, a.APIMessageTypeOnBoat, clientID,
+				transactionID, a.ClientData{})
+			if errMsg.APIMessage.ReasonCode != testCase.expectedReasonCode {
+				t.Fatalf("Expected reason code %d but received %d in test case: %s",
+					testCase.expectedReasonCode, errMsg.APIMessage.ReasonCode, testCase.name)
+			}
+		}
+
+		storedTrip, _ := safeTrips.Load(transactionID)
+		if storedTrip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, storedTrip.TripState, testCase.name)
+		}
+	}
+}
+
+func TestProcessPartyTripMessages(t *testing.T) {
+	type testCase struct {
+		name               string
+		process            func() error
+		expectedReasonCode int32
+		expectedError      bool
+		expectedTripState  int32
+		expectedSeats      BoatSeats
+	}
+
+	// Use a separate seat ledger so the seats of the scenario are not changed
+	scenarioSeats := safeSeats
+	safeSeats = NewSeatLedger()
+	defer func() { safeSeats = scenarioSeats }()
+	safeSeats.SetCapacity(simBoat1.BoatID, 4)
+
+	// A party of three with two named passengers
+	reservation := a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+		"testPartyClient", simDock2, simDock4, time.Time{}, 3, []string{"Ana", "Ben"})
+	trip := Trip{
+		Reservation:   reservation,
+		TransactionID: "testPartyTrip-1",
+		Boat:          simBoat1,
+		TripState:     TripStateBoatArrivedAtSource,
+		Passengers:    NewPassengers(reservation),
+	}
+	err := safeTrips.Insert(trip)
+	if err != nil {
+		t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+	}
+	defer safeTrips.Remove(trip.TransactionID)
+	err = safeSeats.Reserve(simBoat1.BoatID, 3)
+	if err != nil {
+		t.Fatalf("Error reserving seats in test set-up: %s", err.Error())
+	}
+
+	clientID := reservation.ClientID
+	transactionID := trip.TransactionID
+	onBoat := func(names ...string) func() error {
+		return func() error {
+			return ProcessOnBoat(a.NewOnBoatAPIMessage(a.APIMessageTypeOnBoat,
+				clientID, simBoat1, transactionID, names))
+		}
+	}
+	offBoat := func(names ...string) func() error {
+		return func() error {
+			return ProcessOffBoat(a.NewOffBoatAPIMessage(a.APIMessageTypeOffBoat,
+				clientID, simBoat1, transactionID, names))
+		}
+	}
+
+	// The test cases are run in order against the same trip
+	cases := []testCase{
+		{
+			name:               "ProcessPartyTripMessages - OnBoat for passenger not in the party",
+			process:            onBoat("Zed"),
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonTripMismatch,
+			expectedTripState:  TripStateBoatArrivedAtSource,
+			expectedSeats:      BoatSeats{Capacity: 4, Reserved: 3},
+		},
+		{
+			name:              "ProcessPartyTripMessages - OnBoat for first passenger",
+			process:           onBoat("Ana"),
+			expectedError:     false,
+			expectedTripState: TripStateClientOnBoat,
+			expectedSeats:     BoatSeats{Capacity: 4, Reserved: 2, OnBoard: 1},
+		},
+		{
+			name:               "ProcessPartyTripMessages - OnBoat for passenger already on board",
+			process:            onBoat("Ana"),
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateClientOnBoat,
+			expectedSeats:      BoatSeats{Capacity: 4, Reserved: 2, OnBoard: 1},
+		},
+		{
+			name:              "ProcessPartyTripMessages - OnBoat for second passenger",
+			process:           onBoat("Ben"),
+			expectedError:     false,
+			expectedTripState: TripStateClientOnBoat,
+			expectedSeats:     BoatSeats{Capacity: 4, Reserved: 1, OnBoard: 2},
+		},
+		{
+			name: "ProcessPartyTripMessages - Boat arrives at destination dock without last passenger",
+			process: func() error {
+				CheckArrivals(a.BoatStatusAPIMessage{Boat: simBoat1, CurrentDock: simDock4})
+				return nil
+			},
+			expectedError:     false,
+			expectedTripState: TripStateBoatArrivedAtDest,
+			expectedSeats:     BoatSeats{Capacity: 4, OnBoard: 2},
+		},
+		{
+			name:               "ProcessPartyTripMessages - OnBoat after boat arrived at destination dock",
+			process:            onBoat(),
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateBoatArrivedAtDest,
+			expectedSeats:      BoatSeats{Capacity: 4, OnBoard: 2},
+		},
+		{
+			name:              "ProcessPartyTripMessages - OffBoat for one passenger",
+			process:           offBoat("Ben"),
+			expectedError:     false,
+			expectedTripState: TripStateClientOffBoat,
+			expectedSeats:     BoatSeats{Capacity: 4, OnBoard: 1},
+		},
+		{
+			name:              "ProcessPartyTripMessages - OffBoat for the rest of the party",
+			process:           offBoat(),
+			expectedError:     false,
+			expectedTripState: TripStateClientOffBoat,
+			expectedSeats:     BoatSeats{Capacity: 4},
+		},
+		{
+			name:               "ProcessPartyTripMessages - OffBoat with nobody on board",
+			process:            offBoat(),
+			expectedError:      true,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateClientOffBoat,
+			expectedSeats:      BoatSeats{Capacity: 4},
+		},
+	}
+
+	for _, testCase := range cases {
+		err := testCase.process()
+
+		if testCase.expectedError == (err == nil) {
+			t.Fatalf("Expectation of error was %v for test %s but received error was: %v",
+				testCase.expectedError, testCase.name, err)
+		}
+
+		if err != nil {
+			errMsg := NewTripMessageErrorMessage(err, a.APIMessageTypeOnBoat, clientID,
+				transactionID, a.ClientData{})
+			if errMsg.APIMessage.ReasonCode != testCase.expectedReasonCode {
+				t.Fatalf("Expected reason code %d but received %d in test case: %s",
+					testCase.expectedReasonCode, errMsg.APIMessage.ReasonCode, testCase.name)
+			}
+		}
+
+		storedTrip, _ := safeTrips.Load(transactionID)
+		if storedTrip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, storedTrip.TripState, testCase.name)
+		}
+
+		seats, _ := safeSeats.Load(simBoat1.BoatID)
+		if seats != testCase.expectedSeats {
+			t.Fatalf("Expected seats %+v but received %+v in test case: %s",
+				testCase.expectedSeats, seats, testCase.name)
+		}
+	}
+}
+
+func TestProcessCancelTrip(t *testing.T) {
+	type testCase struct {
+		name               string
+		message            a.CancelTripAPIMessage
+		expectedCancelled  bool
+		expectedReasonCode int32
+		expectedTripState  int32
+		expectedAvailable  int32
+	}
+
+	// Use a separate seat ledger so the seats of the scenario are not changed
+	scenarioSeats := safeSeats
+	safeSeats = NewSeatLedger()
+	defer func() { safeSeats = scenarioSeats }()
+	safeSeats.SetCapacity(simBoat1.BoatID, 2)
+
+	reservedTrip := Trip{
+		Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"testCancelClient", simDock2, simDock4, time.Time{}, 0, nil),
+		TransactionID: "testCancelTrip-1",
+		Boat:          simBoat1,
+		TripState:     TripStateReserved,
+	}
+	onBoatTrip := reservedTrip
+	onBoatTrip.TransactionID = "testCancelTrip-2"
+	onBoatTrip.TripState = TripStateClientOnBoat
+	for _, trip := range []Trip{reservedTrip, onBoatTrip} {
+		err := safeTrips.Insert(trip)
+		if err != nil {
+			t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+		}
+		defer safeTrips.Remove(trip.TransactionID)
+		err = safeSeats.Reserve(simBoat1.BoatID, 1)
+		if err != nil {
+			t.Fatalf("Error reserving seat in test set-up: %s", err.Error())
+		}
+	}
+	err := safeSeats.Board(simBoat1.BoatID, 1)
+	if err != nil {
+		t.Fatalf("Error boarding seat in test set-up: %s", err.Error())
+	}
+	// A scheduled trip does not hold a seat
+	scheduledTrip := reservedTrip
+	scheduledTrip.TransactionID = "testCancelTrip-3"
+	scheduledTrip.TripState = TripStateScheduled
+	err = safeTrips.Insert(scheduledTrip)
+	if err != nil {
+		t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+	}
+	defer safeTrips.Remove(scheduledTrip.TransactionID)
+
+	clientID := reservedTrip.Reservation.ClientID
+
+	// The test cases are run in order against the same trips
+	cases := []testCase{
+		{
+			name: "ProcessCancelTrip - Unknown trip",
+			message: a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+				clientID, "unknown"),
+			expectedCancelled:  false,
+			expectedReasonCode: a.ErrorReasonTripNotFound,
+			expectedTripState:  TripStateReserved,
+			expectedAvailable:  0,
+		},
+		{
+			name: "ProcessCancelTrip - Trip reserved by another client",
+			message: a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+				"anotherClient", reservedTrip.TransactionID),
+			expectedCancelled:  false,
+			expectedReasonCode: a.ErrorReasonTripMismatch,
+			expectedTripState:  TripStateReserved,
+			expectedAvailable:  0,
+		},
+		{
+			name: "ProcessCancelTrip - Reserved trip",
+			message: a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+				clientID, reservedTrip.TransactionID),
+			expectedCancelled:  true,
+			expectedReasonCode: a.ErrorReasonUnknown,
+			expectedTripState:  TripStateCancelled,
+			expectedAvailable:  1,
+		},
+		{
+			name: "ProcessCancelTrip - Trip already cancelled",
+			message: a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+				clientID, reservedTrip.TransactionID),
+			expectedCancelled:  false,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateCancelled,
+			expectedAvailable:  1,
+		},
+		{
+			name: "ProcessCancelTrip - Client already on board",
+			message: a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+				clientID, onBoatTrip.TransactionID),
+			expectedCancelled:  false,
+			expectedReasonCode: a.ErrorReasonInvalidTripState,
+			expectedTripState:  TripStateClientOnBoat,
+			expectedAvailable:  1,
+		},
+		{
+			name: "ProcessCancelTrip - Scheduled trip",
+			message: a.NewCancelTripAPIMessage(a.APIMessageTypeCancelTrip,
+				clientID, scheduledTrip.TransactionID),
+			expectedCancelled:  true,
+			expectedReasonCode: a.ErrorReasonUnknown,
+			expectedTripState:  TripStateCancelled,
+			expectedAvailable:  1,
+		},
+	}
+
+	for _, testCase := range cases {
+		cancelMsg := a.NewCancelTripMockLogicMessage(testCase.message, a.ClientData{})
+		cancelAck := ProcessCancelTrip(cancelMsg)
+
+		if cancelAck.APIMessage.IsCancelled != testCase.expectedCancelled {
+			t.Fatalf("Expected IsCancelled %v but received %v with reason: %s in test case: %s",
+				testCase.expectedCancelled, cancelAck.APIMessage.IsCancelled,
+				cancelAck.APIMessage.Reason, testCase.name)
+		}
+
+		if cancelAck.APIMessage.ReasonCode != testCase.expectedReasonCode {
+			t.Fatalf("Expected reason code %d but received %d in test case: %s",
+				testCase.expectedReasonCode, cancelAck.APIMessage.ReasonCode, testCase.name)
+		}
+
+		if cancelAck.APIMessage.TransactionID != testCase.message.TransactionID {
+			t.Fatalf("Expected TransactionID %s but received %s in test case: %s",
+				testCase.message.TransactionID, cancelAck.APIMessage.TransactionID, testCase.name)
+		}
+
+		storedTrip, ok := safeTrips.Load(testCase.message.TransactionID)
+		if !ok {
+			storedTrip, _ = safeTrips.Load(reservedTrip.TransactionID)
+		}
+		if storedTrip.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %d but received %d in test case: %s",
+				testCase.expectedTripState, storedTrip.TripState, testCase.name)
+		}
+
+		available := safeSeats.SeatsAvailable(simBoat1.BoatID)
+		if available != testCase.expectedAvailable {
+			t.Fatalf("Expected %d seats available but received %d in test case: %s",
+				testCase.expectedAvailable, available, testCase.name)
+		}
+	}
+}
+
+func TestProcessGetTrip(t *testing.T) {
+	type testCase struct {
+		name               string
+		message            a.GetTripAPIMessage
+		expectedFound      bool
+		expectedReasonCode int32
+		expectedTripState  string
+		expectedBoatStatus a.BoatStatusAPIMessage
+		expectedSourceETA  time.Time
+		expectedDestETA    time.Time
+	}
+
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+	sentAt := time.Date(2025, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	defaultSchedule := SimSchedule
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), 15*time.Second)
+	SimSchedule.RecordFrame(1, sentAt)
+	defer func() { SimSchedule = defaultSchedule }()
+
+	boatStatus := a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus, simBoat1,
+		a.ServiceStateOnTime, simDock1, simDock2, simDock3, 39)
+	safeBoatStatuses.Store(simBoat1.BoatID, boatStatus)
+	defer safeBoatStatuses.Delete(simBoat1.BoatID)
+
+	trip := Trip{
+		Reservation: a.NewReserveTripAPIMessage(a.APIMessageTypeReserveTrip, "testToken",
+			"getTripClient", simDock4, simDock2, time.Time{}, 0, nil),
+		TransactionID: "getTrip-1",
+		Boat:          simBoat1,
+		TripState:     TripStateReserved,
+	}
+	err = safeTrips.Insert(trip)
+	if err != nil {
+		t.Fatalf("Error inserting trip in test set-up: %s", err.Error())
+	}
+	defer safeTrips.Remove(trip.TransactionID)
+
+	cases := []testCase{
+		{
+			name: "ProcessGetTrip - Reserved trip",
+			message: a.NewGetTripAPIMessage(a.APIMessageTypeGetTrip, "getTripClient",
+				trip.TransactionID),
+			expectedFound:      true,
+			expectedReasonCode: a.ErrorReasonUnknown,
+			expectedTripState:  "reserved",
+			expectedBoatStatus: boatStatus,
+			expectedSourceETA:  sentAt.Add(45 * time.Second),
+			expectedDestETA:    sentAt.Add(165 * time.Second),
+		},
+		{
+			name: "ProcessGetTrip - Trip reserved by another client",
+			message: a.NewGetTripAPIMessage(a.APIMessageTypeGetTrip, "anotherClient",
+				trip.TransactionID),
+			expectedFound:      false,
+			expectedReasonCode: a.ErrorReasonTripNotFound,
+		},
+		{
+			name: "ProcessGetTrip - Unknown trip",
+			message: a.NewGetTripAPIMessage(a.APIMessageTypeGetTrip, "getTripClient",
+				"unknown"),
+			expectedFound:      false,
+			expectedReasonCode: a.ErrorReasonTripNotFound,
+		},
+	}
+
+	for _, testCase := range cases {
+		getTripMsg := a.NewGetTripMockLogicMessage(testCase.message, a.ClientData{})
+		tripStatus := ProcessGetTrip(getTripMsg).APIMessage
+
+		if tripStatus.IsFound != testCase.expectedFound {
+			t.Fatalf("Expected IsFound %v but received %v with reason: %s in test case: %s",
+				testCase.expectedFound, tripStatus.IsFound, tripStatus.Reason, testCase.name)
+		}
+
+		if tripStatus.ReasonCode != testCase.expectedReasonCode {
+			t.Fatalf("Expected reason code %d but received %d in test case: %s",
+				testCase.expectedReasonCode, tripStatus.ReasonCode, testCase.name)
+		}
+
+		if tripStatus.TripState != testCase.expectedTripState {
+			t.Fatalf("Expected trip state %q but received %q in test case: %s",
+				testCase.expectedTripState, tripStatus.TripState, testCase.name)
+		}
+
+		if tripStatus.BoatStatus != testCase.expectedBoatStatus {
+			t.Fatalf("Expected boat status %+v but received %+v in test case: %s",
+				testCase.expectedBoatStatus, tripStatus.BoatStatus, testCase.name)
+		}
+
+		if !tripStatus.SourceETA.Equal(testCase.expectedSourceETA) ||
+			!tripStatus.DestinationETA.Equal(testCase.expectedDestETA) {
+			t.Fatalf("Expected ETAs %v and %v but received %v and %v in test case: %s",
+				testCase.expectedSourceETA, testCase.expectedDestETA,
+				tripStatus.SourceETA, tripStatus.DestinationETA, testCase.name)
+		}
+	}
+}
+
+// testHandshakeServer is an Adapter gRPC server that replies to every Hello with
+// the given Welcome, and records the Hello
+type testHandshakeServer struct {
+	pb.UnimplementedAdapterServer
+	welcome *pb.Welcome
+	hello   chan *pb.Hello
+}
+
+func (s *testHandshakeServer) Handshake(ctx context.Context, hello *pb.Hello) (*pb.Welcome, error) {
+	s.hello <- hello
+	return s.welcome, nil
+}
+
+func TestHandshakeWithAdapter(t *testing.T) {
+	type testCase struct {
+		name          string
+		welcome       *pb.Welcome
+		expectedError bool
+	}
+
+	// Create test cases
+	cases := []testCase{
+		{
+			name: "Accepted",
+			welcome: &pb.Welcome{
+				IsAccepted:      true,
+				Component:       "Adapter",
+				ProtocolVersion: a.ProtocolVersion,
+			},
+			expectedError: false,
+		},
+		{
+			name: "Rejected",
+			welcome: &pb.Welcome{
+				IsAccepted:      false,
+				Reason:          "message types: getTrip, are not handled",
+				Component:       "Adapter",
+				ProtocolVersion: a.ProtocolVersion,
+			},
+			expectedError: true,
+		},
+		{
+			name: "Accepted with another protocol version",
+			welcome: &pb.Welcome{
+				IsAccepted:      true,
+				Component:       "Adapter",
+				ProtocolVersion: a.ProtocolVersion + 1,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("Failed to listen: %s in test case: %s", err.Error(), tc.name)
+		}
+		adapter := &testHandshakeServer{
+			welcome: tc.welcome,
+			hello:   make(chan *pb.Hello, 1),
+		}
+		server := grpc.NewServer()
+		pb.RegisterAdapterServer(server, adapter)
+		go server.Serve(listener)
+
+		conn, err := grpc.NewClient(listener.Addr().String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to dial gRPC: %s in test case: %s", err.Error(), tc.name)
+		}
+
+		err = HandshakeWithAdapter(pb.NewAdapterClient(conn))
+		conn.Close()
+		server.Stop()
+		if (err != nil) != tc.expectedError {
+			t.Fatalf("Expected error: %t, got: %v in test case: %s", tc.expectedError, err, tc.name)
+		}
+
+		hello := <-adapter.hello
+		if hello.GetComponent() != MockLogicComponentName ||
+			hello.GetProtocolVersion() != a.ProtocolVersion ||
+			!slices.Equal(hello.GetMessageTypes(), a.ToMockLogicMessageTypes) {
+			t.Fatalf("Expected Hello from %s with protocol version: %d and message types: %v, got: %+v in test case: %s",
+				MockLogicComponentName, a.ProtocolVersion, a.ToMockLogicMessageTypes, hello, tc.name)
+		}
+	}
+}
+
+// testSessionServer is an Adapter gRPC server that passes each Session stream to
+// the test and holds it open until the test is done
+type testSessionServer struct {
+	pb.UnimplementedAdapterServer
+	streams chan pb.Adapter_SessionServer
+	done    chan struct{}
+}
+
+func (s *testSessionServer) Session(stream pb.Adapter_SessionServer) error {
+	s.streams <- stream
+	<-s.done
+	return nil
+}
+
+// recvEnvelopes receives the given number of envelopes on a Session stream and
+// returns the Receipts and the other messages apart
+func recvEnvelopes(t *testing.T, stream pb.Adapter_SessionServer, count int) ([]*pb.Receipt, []*pb.MockLogicEnvelope) {
+	var receipts []*pb.Receipt
+	var envelopes []*pb.MockLogicEnvelope
+	for range count {
+		envelope, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive on the Session stream: %s", err.Error())
+		}
+		if receipt := envelope.GetReceipt(); receipt != nil {
+			receipts = append(receipts, receipt)
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	return receipts, envelopes
+}
+
+func TestRunSession(t *testing.T) {
+	defaultOutbox := AdapterOutbox
+	defaultInbox := AdapterInbox
+	defer func() {
+		AdapterOutbox = defaultOutbox
+		AdapterInbox = defaultInbox
+	}()
+	AdapterOutbox = a.NewOutbox[any](AdapterOutboxCapacity)
+	AdapterInbox = a.NewInbox()
+	Once = new(sync.Once)
+	GRPCStreamWaitChannel = make(chan struct{})
+	CloseWaitChan = func() {
+		close(GRPCStreamWaitChannel)
+	}
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err.Error())
+	}
+	adapter := &testSessionServer{
+		streams: make(chan pb.Adapter_SessionServer, 1),
+		done:    make(chan struct{}),
+	}
+	server := grpc.NewServer()
+	pb.RegisterAdapterServer(server, adapter)
+	go server.Serve(listener)
+	defer server.Stop()
+	defer close(adapter.done)
+
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial gRPC: %s", err.Error())
+	}
+	defer conn.Close()
+
+	// The messages placed in the outbox before the stream opens are sent in order,
+	// whatever their types
+	const sessionClientID = "sessionClientID"
+	client := a.NewClientData(sessionClientID, a.ConnectionTypeWebSocket)
+	arrived := a.NewArrivedMockLogicMessage(a.NewArrivedAPIMessage(a.APIMessageTypeArrived,
+		sessionClientID, simBoat1, simDock1, "arrivedTransactionID"), client)
+	errMsg := NewTripMessageErrorMessage(ErrBoatFull, a.APIMessageTypeAtDock,
+		sessionClientID, "errorTransactionID", client)
+	pushToAdapter(arrived, a.APIMessageTypeArrived)
+	pushToAdapter(errMsg, a.APIMessageTypeError)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Go(func() { runSession(ctx, pb.NewAdapterClient(conn)) })
+	defer wg.Wait()
+	defer cancel()
+
+	stream := <-adapter.streams
+	_, envelopes := recvEnvelopes(t, stream, 2)
+	if envelopes[0].GetSequence() != 1 || envelopes[0].GetArrived() == nil ||
+		envelopes[1].GetSequence() != 2 || envelopes[1].GetError() == nil {
+		t.Fatalf("Expected an Arrived message and then an Error message, got: %v", envelopes)
+	}
+	if envelopes[1].GetError().GetApiMessage().GetTransactionId() != "errorTransactionID" {
+		t.Fatalf("Expected Error message for TransactionID: errorTransactionID, got: %v", envelopes[1])
+	}
+
+	// The Receipts remove the messages from the outbox
+	for _, envelope := range envelopes {
+		err = stream.Send(&pb.AdapterEnvelope{
+			Message: &pb.AdapterEnvelope_Receipt{
+				Receipt: &pb.Receipt{Epoch: envelope.GetEpoch(), Sequence: envelope.GetSequence()},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to send Receipt: %s", err.Error())
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for AdapterOutbox.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected an empty outbox, got: %d messages", AdapterOutbox.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A message from the Adapter is processed and acknowledged, and a duplicate
+	// of it is only acknowledged
+	getTrip := &pb.AdapterEnvelope{
+		Epoch:    1,
+		Sequence: 1,
+		Message: &pb.AdapterEnvelope_GetTrip{GetTrip: &pb.GetTripMessage{
+			ApiMessage: &pb.GetTripAPIMessage{
+				MessageType:   a.APIMessageTypeGetTrip,
+				ClientId:      sessionClientID,
+				TransactionId: "unknownTransactionID",
+			},
+			ClientData: &pb.ClientData{
+				ConnName: sessionClientID,
+				ConnType: a.ConnectionTypeWebSocket,
+			},
+		}},
+	}
+	err = stream.Send(getTrip)
+	if err != nil {
+		t.Fatalf("Failed to send GetTrip: %s", err.Error())
+	}
+	receipts, envelopes := recvEnvelopes(t, stream, 2)
+	if len(receipts) != 1 || receipts[0].GetEpoch() != 1 || receipts[0].GetSequence() != 1 {
+		t.Fatalf("Expected a Receipt for sequence 1, got: %v", receipts)
+	}
+	if len(envelopes) != 1 || envelopes[0].GetSequence() != 3 ||
+		envelopes[0].GetTripStatus().GetApiMessage().GetIsFound() {
+		t.Fatalf("Expected a TripStatus for a trip that was not found with sequence 3, got: %v", envelopes)
+	}
+
+	err = stream.Send(getTrip)
+	if err != nil {
+		t.Fatalf("Failed to send GetTrip: %s", err.Error())
+	}
+	receipts, _ = recvEnvelopes(t, stream, 1)
+	if len(receipts) != 1 || receipts[0].GetSequence() != 1 {
+		t.Fatalf("Expected a Receipt for sequence 1, got: %v", receipts)
+	}
+	if statuses := pendingAdapterMessages[a.TripStatusMockLogicMessage](); len(statuses) != 1 {
+		t.Fatalf("Expected 1 TripStatus in the outbox, got: %d", len(statuses))
+	}
+}
+
+func TestRunSessionAfterFramesWhileDisconnected(t *testing.T) {
+	scenario, err := LoadScenario("")
+	if err != nil {
+		t.Fatalf("Error loading default scenario: %s", err.Error())
+	}
+
+	defaultOutbox := AdapterOutbox
+	defaultInbox := AdapterInbox
+	defaultSchedule := SimSchedule
+	defaultSimFrameBoatStatusChannel := SimFrameBoatStatusChannel
+	defer func() {
+		AdapterOutbox = defaultOutbox
+		AdapterInbox = defaultInbox
+		SimSchedule = defaultSchedule
+		SimFrameBoatStatusChannel = defaultSimFrameBoatStatusChannel
+	}()
+	SimSchedule = NewFrameSchedule(scenario.BuildSimulationFrames(), DefaultSimFrameDuration)
+	AdapterOutbox = a.NewOutbox[any](AdapterOutboxCapacity)
+	AdapterInbox = a.NewInbox()
+
+	// The boat statuses of the frames are not stored by this test
+	SimFrameBoatStatusChannel = make(chan a.BoatStatusAPIMessage)
+	drained := make(chan struct{})
+	go func() {
+		for range SimFrameBoatStatusChannel {
+		}
+		close(drained)
+	}()
+
+	// An Ack is placed in the outbox, and more frames than the outbox holds are
+	// sent before the Adapter connects
+	const sessionClientID = "sessionClientID"
+	client := a.NewClientData(sessionClientID, a.ConnectionTypeWebSocket)
+	ack := a.NewAckMockLogicMessage(a.NewAckAPIMessage(a.APIMessageTypeAck,
+		sessionClientID, true, simBoat1, "ackTransactionID", 0, "",
+		time.Time{}, time.Time{}, time.Time{}), client)
+	pushToAdapter(ack, a.APIMessageTypeAck)
+	for step := range AdapterOutboxCapacity + 1 {
+		sendSimFrame(step%SimSchedule.FrameCount(), true)
+	}
+	close(SimFrameBoatStatusChannel)
+	<-drained
+
+	boatIDs := make(map[int32]bool)
+	for _, boatStatus := range pendingAdapterMessages[a.BoatStatusMockLogicMessage]() {
+		if boatIDs[boatStatus.APIMessage.Boat.BoatID] {
+			t.Fatalf("Expected one pending BoatStatus for boat: %d", boatStatus.APIMessage.Boat.BoatID)
+		}
+		boatIDs[boatStatus.APIMessage.Boat.BoatID] = true
+	}
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err.Error())
+	}
+	adapter := &testSessionServer{
+		streams: make(chan pb.Adapter_SessionServer, 1),
+		done:    make(chan struct{}),
+	}
+	server := grpc.NewServer()
+	pb.RegisterAdapterServer(server, adapter)
+	go server.Serve(listener)
+	defer server.Stop()
+	defer close(adapter.done)
+
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial gRPC: %s", err.Error())
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Go(func() { runSession(ctx, pb.NewAdapterClient(conn)) })
+	defer wg.Wait()
+	defer cancel()
+
+	// The Ack is sent first, followed by the latest BoatStatus of each boat
+	stream := <-adapter.streams
+	_, envelopes := recvEnvelopes(t, stream, 1+len(boatIDs))
+	if envelopes[0].GetSequence() != 1 ||
+		envelopes[0].GetAck().GetApiMessage().GetTransactionId() != "ackTransactionID" {
+		t.Fatalf("Expected the Ack for TransactionID: ackTransactionID with sequence 1, got: %v", envelopes[0])
+	}
+	for _, envelope := range envelopes[1:] {
+		if envelope.GetBoatStatus() == nil {
+			t.Fatalf("Expected a BoatStatus, got: %v", envelope)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FinishedTripRetention is how long a trip stays in the registry after it has
+// finished, so that the client can still get the final state of the trip
+const FinishedTripRetention time.Duration = 10 * time.Minute
+
+// TripRegistry holds the reserved trips indexed by TransactionID, with
+// secondary indexes by the ClientID that made the reservation and by the
+// BoatID of the boat assigned to the trip. The stream handlers and the
+// simulation loop access the registry at the same time, so every method
+// is safe for concurrent use. Trips are copied in and out of the registry,
+// so a Trip returned by a method may be modified without affecting the
+// stored Trip. Every change to a trip is written to the journal of the
+// registry, if it has one.
+type TripRegistry struct {
+	mux             sync.RWMutex
+	trips           map[string]*Trip
+	tripsByClientID map[string]map[string]struct{}
+	tripsByBoatID   map[int32]map[string]struct{}
+	journal         *Journal
+}
+
+func NewTripRegistry() *TripRegistry {
+	return &TripRegistry{
+		trips:           make(map[string]*Trip),
+		tripsByClientID: make(map[string]map[string]struct{}),
+		tripsByBoatID:   make(map[int32]map[string]struct{}),
+	}
+}
+
+// safeTrips holds the reserved trips in the system
+var safeTrips = NewTripRegistry()
+
+// SetJournal sets the journal that the changes to the trips are written to. A
+// nil journal stops the changes from being written.
+func (tr *TripRegistry) SetJournal(journal *Journal) {
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+
+	tr.journal = journal
+}
+
+// Insert adds the given trip to the registry and returns an error if the
+// trip has no TransactionID or a trip with the same TransactionID is
+// already stored
+func (tr *TripRegistry) Insert(trip Trip) error {
+	var err error
+	if trip.TransactionID == "" {
+		err = fmt.Errorf("trip does not have a TransactionID")
+		return err
+	}
+
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+
+	if _, ok := tr.trips[trip.TransactionID]; ok {
+		err = fmt.Errorf("trip with TransactionID: %s already exists", trip.TransactionID)
+		return err
+	}
+
+	tr.trips[trip.TransactionID] = &trip
+	tr.addToIndexes(&trip)
+	tr.journal.RecordTrip(trip)
+
+	return err
+}
+
+// Load returns the trip with the given TransactionID and whether it
+// was found
+func (tr *TripRegistry) Load(transactionID string) (Trip, bool) {
+	tr.mux.RLock()
+	defer tr.mux.RUnlock()
+
+	trip, ok := tr.trips[transactionID]
+	if !ok {
+		return Trip{}, false
+	}
+
+	return *trip, true
+}
+
+// LoadByClientID returns the trips reserved by the given ClientID,
+// ordered by TransactionID
+func (tr *TripRegistry) LoadByClientID(clientID string) []Trip {
+	tr.mux.RLock()
+	defer tr.mux.RUnlock()
+
+	return tr.loadIndexed(tr.tripsByClientID[clientID])
+}
+
+// LoadByBoatID returns the trips assigned to the given BoatID, ordered
+// by TransactionID
+func (tr *TripRegistry) LoadByBoatID(boatID int32) []Trip {
+	tr.mux.RLock()
+	defer tr.mux.RUnlock()
+
+	return tr.loadIndexed(tr.tripsByBoatID[boatID])
+}
+
+// UpdateTripState advances the trip with the given TransactionID to the given
+// state and returns the updated trip. An error is returned if the trip is not
+// found or the state transition is not permitted.
+func (tr *TripRegistry) UpdateTripState(transactionID string, state int32) (Trip, error) {
+	return tr.Update(transactionID, func(trip *Trip) error {
+		return trip.AdvanceToTripState(state)
+	})
+}
+
+// Update calls the given function with the trip that has the given TransactionID
+// while holding the registry lock. The changes made by the function are only stored
+// if it returns nil. The secondary indexes are updated if the ClientID or the boat
+// of the trip changed, and FinishedAt is set when the trip finishes. The stored trip
+// is returned, along with an error if the trip was not found or the function
+// returned an error.
+func (tr *TripRegistry) Update(transactionID string, update func(*Trip) error) (Trip, error) {
+	var err error
+
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+
+	stored, ok := tr.trips[transactionID]
+	if !ok {
+		err = fmt.Errorf("trip with TransactionID: %s was not found", transactionID)
+		return Trip{}, err
+	}
+
+	trip := *stored
+	err = update(&trip)
+	if err != nil {
+		return *stored, err
+	}
+	if trip.TransactionID != transactionID {
+		err = fmt.Errorf("the TransactionID of trip: %s cannot be changed", transactionID)
+		return *stored, err
+	}
+
+	if trip.FinishedAt.IsZero() && trip.IsFinished() {
+		trip.FinishedAt = SimClock.Now()
+	}
+
+	tr.removeFromIndexes(stored)
+	*stored = trip
+	tr.addToIndexes(stored)
+	tr.journal.RecordTrip(trip)
+
+	return trip, err
+}
+
+// Remove deletes the trip with the given TransactionID from the registry and
+// returns the removed trip and whether it was found
+func (tr *TripRegistry) Remove(transactionID string) (Trip, bool) {
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+
+	trip, ok := tr.trips[transactionID]
+	if !ok {
+		return Trip{}, false
+	}
+
+	tr.removeFromIndexes(trip)
+	delete(tr.trips, transactionID)
+	tr.journal.RecordRemoval(transactionID)
+
+	return *trip, true
+}
+
+// RemoveFinished deletes the trips that finished before the given time from the
+// registry and returns the removed trips, ordered by TransactionID
+func (tr *TripRegistry) RemoveFinished(before time.Time) []Trip {
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+
+	var removed []Trip
+	for transactionID, trip := range tr.trips {
+		if trip.FinishedAt.IsZero() || !trip.FinishedAt.Before(before) {
+			continue
+		}
+		tr.removeFromIndexes(trip)
+		delete(tr.trips, transactionID)
+		tr.journal.RecordRemoval(transactionID)
+		removed = append(removed, *trip)
+	}
+	slices.SortFunc(removed, func(t1, t2 Trip) int {
+		return strings.Compare(t1.TransactionID, t2.TransactionID)
+	})
+
+	return removed
+}
+
+// CompactJournal replaces the journal of the registry with an entry for each trip
+// that has not finished and a frame entry for the given step. The registry lock is
+// held while compacting, so that no change to a trip is written to the journal
+// that is being replaced.
+func (tr *TripRegistry) CompactJournal(step int) error {
+	tr.mux.Lock()
+	defer tr.mux.Unlock()
+
+	state := JournalState{Step: step}
+	for _, trip := range tr.trips {
+		state.Trips = append(state.Trips, *trip)
+	}
+	slices.SortFunc(state.Trips, func(t1, t2 Trip) int {
+		return strings.Compare(t1.TransactionID, t2.TransactionID)
+	})
+
+	return tr.journal.Compact(state)
+}
+
+// Len returns the number of trips in the registry
+func (tr *TripRegistry) Len() int {
+	tr.mux.RLock()
+	defer tr.mux.RUnlock()
+
+	return len(tr.trips)
+}
+
+// addToIndexes adds the trip to the secondary indexes. The caller must
+// hold the write lock.
+func (tr *TripRegistry) addToIndexes(trip *Trip) {
+	clientID := trip.Reservation.ClientID
+	if tr.tripsByClientID[clientID] == nil {
+		tr.tripsByClientID[clientID] = make(map[string]struct{})
+	}
+	tr.tripsByClientID[clientID][trip.TransactionID] = struct{}{}
+
+	boatID := trip.Boat.BoatID
+	if tr.tripsByBoatID[boatID] == nil {
+		tr.tripsByBoatID[boatID] = make(map[string]struct{})
+	}
+	tr.tripsByBoatID[boatID][trip.TransactionID] = struct{}{}
+}
+
+// removeFromIndexes removes the trip from the secondary indexes. The caller
+// must hold the write lock.
+func (tr *TripRegistry) removeFromIndexes(trip *Trip) {
+	clientID := trip.Reservation.ClientID
+	delete(tr.tripsByClientID[clientID], trip.TransactionID)
+	if len(tr.tripsByClientID[clientID]) == 0 {
+		delete(tr.tripsByClientID, clientID)
+	}
+
+	boatID := trip.Boat.BoatID
+	delete(tr.tripsByBoatID[boatID], trip.TransactionID)
+	if len(tr.tripsByBoatID[boatID]) == 0 {
+		delete(tr.tripsByBoatID, boatID)
+	}
+}
+
+// loadIndexed returns copies of the trips for the given set of TransactionIDs,
+// ordered by TransactionID. The caller must hold the read lock.
+func (tr *TripRegistry) loadIndexed(transactionIDs map[string]struct{}) []Trip {
+	trips := make([]Trip, 0, len(transactionIDs))
+	for transactionID := range transactionIDs {
+		trips = append(trips, *tr.trips[transactionID])
+	}
+	slices.SortFunc(trips, func(t1, t2 Trip) int {
+		return strings.Compare(t1.TransactionID, t2.TransactionID)
+	})
+
+	return trips
+}
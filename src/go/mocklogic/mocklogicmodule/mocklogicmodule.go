@@ -1,8 +1,6 @@
 package main
 
 import (
-	"container/list"
-	"container/ring"
 	"context"
 	"flag"
 	"fmt"
@@ -16,48 +14,88 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
 )
 
 // Logger Handles all log writing for the MockLogic
 var Logger logger.Logger
 
+// VersionNumber - Build-time variable
+var VersionNumber string
+
+// BuildDate - Build-time variable
+var BuildDate string
+
+// MockLogicComponentName is the name of the MockLogic in the handshake
+const MockLogicComponentName string = "MockLogic"
+
+// HandshakeTimeout is the time that the MockLogic waits for the Welcome from the
+// Adapter
+var HandshakeTimeout time.Duration = 5 * time.Second
+
 var LogDirectory string
 
 // Simulation frame related
-var SimFrameRing *ring.Ring
 var StopSimFrames chan struct{}
 var SimFrameBoatStatusChannel chan a.BoatStatusAPIMessage
-var SimDockAdjacencyList map[string]*list.List
+var SimDockGraph *DockGraph
 
 // gRPC related
 var GRPCDialTimer *time.Timer
 var GRPCStreamWaitChannel chan struct{}
 var Once *sync.Once
 var CloseWaitChan func()
-var AdapterAckChannel chan a.AckMockLogicMessage
-var AdapterBoatStatusChannel chan a.BoatStatusMockLogicMessage
-var AdapterArrivedChannel chan a.ArrivedMockLogicMessage
-
-// InitializeSimFrames builds the sim frame ring and launches the goroutine
-// that advances the frames
-func InitializeSimFrames() {
-	SimFrameRing = BuildSimFrameRing(SimFrames)
 
-	SimFrameBoatStatusChannel = make(chan a.BoatStatusAPIMessage, 2)
-
-	SimDockAdjacencyList = BuildDockAdjacencyList()
-
-	// TODO: Make safeTrips sync.Map [string]Trip to hold reserved trips and
-	// create loop to check SimFrameBoatStatusChannel and determine if Arrived
-	// should be sent every time a new status is updated.
-
-	go AdvanceSimFrames()
+// AdapterOutboxCapacity is the number of messages that are held for the Adapter
+// while it is not connected
+const AdapterOutboxCapacity int = 1024
+
+// AdapterOutbox holds the messages that are sent to the Adapter, in the order that
+// they were placed in it, as AckMockLogicMessage, CancelAckMockLogicMessage,
+// TripStatusMockLogicMessage, BoatStatusMockLogicMessage, ArrivedMockLogicMessage,
+// TripProgressMockLogicMessage and ErrorMockLogicMessage values. A message stays in
+// the outbox until the Adapter sends a Receipt for it, so the outbox is kept
+// across reconnects and the messages that were lost when a stream dropped are
+// sent again on the next stream. The BoatStatus and TripProgress messages are sent
+// every frame, so the outbox holds only the latest of them for each boat and trip,
+// and they never take the place of the messages that the clients wait for.
+var AdapterOutbox = a.NewOutbox[any](AdapterOutboxCapacity)
+
+// AdapterInbox holds the sequences of the messages received from the Adapter, so
+// that the messages that it sends again after a reconnect are only processed once
+var AdapterInbox = a.NewInbox()
+
+// InitializeSimFrames builds the frame schedule, the dock graph and the seat ledger
+// from the given scenario and restores the trips replayed from the journal. The
+// changes to the trips are written to SimJournal from then on. The goroutine that
+// advances the frames is launched from the step after the last one in the
+// journal. The scenario must have been validated.
+func InitializeSimFrames(scenario Scenario, journalState JournalState) {
+	SimFrameDuration, _ = scenario.SimFrameDuration()
+
+	simFrames := scenario.BuildSimulationFrames()
+	SimSchedule = NewFrameSchedule(simFrames, SimFrameDuration)
+
+	SimFrameBoatStatusChannel = make(chan a.BoatStatusAPIMessage, len(scenario.Boats))
+	SimControlChannel = make(chan SimControlRequest)
+
+	SimDockGraph = BuildDockGraph(scenario)
+
+	InitializeSeats(scenario)
+	RestoreTrips(journalState.Trips)
+	// The restored trips are already in the journal
+	safeTrips.SetJournal(SimJournal)
+
+	go UpdateBoatStatuses()
+	go AdvanceSimFrames(SimClock.Tick(SimFrameDuration), journalState.Step+1)
 }
 
 func InitializeAdapterGRPCStreams() {
 	// Create new *sync.Once
 	Once = new(sync.Once)
-	var opts []grpc.DialOption // No options, currently
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	conn, err := grpc.NewClient(a.GRPCServerAddress, opts...)
 	if err != nil {
 		Logger.Error().Msgf("Failed to dial gRPC: %s", err.Error())
@@ -65,16 +103,20 @@ func InitializeAdapterGRPCStreams() {
 		return
 	}
 
+	// The streams are only opened once the Adapter has accepted the handshake
+	client := pb.NewAdapterClient(conn)
+	err = HandshakeWithAdapter(client)
+	if err != nil {
+		Logger.Error().Msgf("Failed to complete the handshake with the Adapter: %s", err.Error())
+		conn.Close()
+		GRPCDialTimer = time.AfterFunc(time.Duration(2*time.Second), InitializeAdapterGRPCStreams)
+		return
+	}
+
 	if GRPCDialTimer != nil {
 		GRPCDialTimer.Stop()
 	}
 
-	// Make channels for outgoing messages
-	AdapterAckChannel = make(chan a.AckMockLogicMessage)
-	AdapterBoatStatusChannel = make(chan a.BoatStatusMockLogicMessage, 2)
-	AdapterArrivedChannel = make(chan a.ArrivedMockLogicMessage)
-
-	client := pb.NewAdapterClient(conn)
 	ctx, cancel := context.WithCancel(context.Background())
 	// Make the wait channel and close function
 	GRPCStreamWaitChannel = make(chan struct{})
@@ -83,13 +125,23 @@ func InitializeAdapterGRPCStreams() {
 	}
 
 	// Launch streams
-	go runReserveTrip(ctx, client)
-	go runAck(ctx, client)
-	go runAtDock(ctx, client)
-	go runOnBoat(ctx, client)
-	go runOffBoat(ctx, client)
-	go runBoatStatus(ctx, client)
-	go runArrived(ctx, client)
+	if *LegacyStreams {
+		go runReserveTrip(ctx, client)
+		go runAck(ctx, client)
+		go runAtDock(ctx, client)
+		go runOnBoat(ctx, client)
+		go runOffBoat(ctx, client)
+		go runCancelTrip(ctx, client)
+		go runCancelAck(ctx, client)
+		go runGetTrip(ctx, client)
+		go runTripStatus(ctx, client)
+		go runBoatStatus(ctx, client)
+		go runArrived(ctx, client)
+		go runTripProgress(ctx, client)
+		go runError(ctx, client)
+	} else {
+		go runSession(ctx, client)
+	}
 
 	// Block until signaled
 	<-GRPCStreamWaitChannel
@@ -101,73 +153,369 @@ func InitializeAdapterGRPCStreams() {
 
 }
 
-// runReserveTrip handles the ReserveTrip bidi stream. The stream is receiving the ReserveTrip
-// messages from the Adapter and is not expected to send any Empty messages to the Adapter,
-// so Send() will not be called.
-func runReserveTrip(ctx context.Context, client pb.AdapterClient) {
-	Logger.Info().Msg("Starting ReserveTrip stream")
+// NewHello returns the Hello that the MockLogic sends to start the handshake
+func NewHello() *pb.Hello {
+	return &pb.Hello{
+		Component:       MockLogicComponentName,
+		VersionNumber:   VersionNumber,
+		BuildDate:       BuildDate,
+		ProtocolVersion: a.ProtocolVersion,
+		MessageTypes:    a.ToMockLogicMessageTypes,
+	}
+}
 
-	stream, err := client.ReserveTrip(ctx)
+// HandshakeWithAdapter sends a Hello to the Adapter and returns an error if the
+// Adapter could not be reached, rejected the MockLogic or speaks another
+// protocol version
+func HandshakeWithAdapter(client pb.AdapterClient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), HandshakeTimeout)
+	defer cancel()
+
+	welcome, err := client.Handshake(ctx, NewHello())
 	if err != nil {
-		Logger.Error().Msgf("client.Reserve failed to create stream: %s", err.Error())
+		return err
+	}
+	if !welcome.GetIsAccepted() {
+		return fmt.Errorf("%s rejected the handshake: %s", welcome.GetComponent(), welcome.GetReason())
+	}
+	if welcome.GetProtocolVersion() != a.ProtocolVersion {
+		return fmt.Errorf("protocol version: %d of %s is not supported, expected: %d",
+			welcome.GetProtocolVersion(), welcome.GetComponent(), a.ProtocolVersion)
+	}
+	Logger.Info().Msgf("Completed the handshake with %s Version: %s Build Date: %s",
+		welcome.GetComponent(), welcome.GetVersionNumber(), welcome.GetBuildDate())
+
+	return nil
+}
+
+// runSession handles the Session bidi stream. The stream is sending the messages in
+// the AdapterOutbox to the Adapter and receives the messages from the Adapter,
+// along with a Receipt for each message in either direction.
+func runSession(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting Session stream")
+
+	stream, err := client.Session(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.Session failed to create stream: %s", err.Error())
 		Once.Do(CloseWaitChan)
 		return
 	}
 
+	// The messages from the outbox and the Receipts are sent from different
+	// goroutines, and a stream must not be sent on concurrently
+	var sendMux sync.Mutex
+	send := func(envelope *pb.MockLogicEnvelope) error {
+		sendMux.Lock()
+		defer sendMux.Unlock()
+		return stream.Send(envelope)
+	}
+
+	// Launch a goroutine to receive the stream of AdapterEnvelope messages
+	go receiveSession(stream.Recv, send)
+
+	// Send the messages that have not been acknowledged, and then each message
+	// placed in the outbox, to the Adapter
+	err = a.SendPending(AdapterOutbox, ctx.Done(), func(msg a.SequencedMessage[any]) error {
+		envelope, err := mockLogicEnvelopeToGRPC(msg)
+		if err != nil {
+			Logger.Error().Msgf("Failed to convert a message in the AdapterOutbox: %s", err.Error())
+			return nil
+		}
+		return send(envelope)
+	})
+	if err != nil {
+		Logger.Error().Msgf("client.Session failed to send msg: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+	Logger.Warn().Msgf("client.Session context canceled with err: %s", ctx.Err().Error())
+}
+
+// receiveSession receives the stream of AdapterEnvelope messages on the Session
+// stream. The Receipts remove the messages that they acknowledge from the
+// AdapterOutbox, and the other messages are processed and acknowledged. The
+// streams are reconnected when the stream fails.
+func receiveSession(recv func() (*pb.AdapterEnvelope, error),
+	send func(*pb.MockLogicEnvelope) error) {
+	sendReceiptEnvelope := func(receipt *pb.Receipt) error {
+		return send(&pb.MockLogicEnvelope{
+			Message: &pb.MockLogicEnvelope_Receipt{Receipt: receipt},
+		})
+	}
+
 	for {
-		in, err := stream.Recv()
+		in, err := recv()
 		if err == io.EOF {
 			// Read done
-			Logger.Warn().Msg("client.ReserveTrip ended with EOF")
+			Logger.Warn().Msg("client.Session ended with EOF")
 			break
 		}
 		if err != nil {
-			Logger.Error().Msgf("client.ReserveTrip failed: %s", err.Error())
+			Logger.Error().Msgf("client.Session failed: %s", err.Error())
+			break
+		}
+
+		if receipt := in.GetReceipt(); receipt != nil {
+			AdapterOutbox.Acknowledge(receipt.GetEpoch(), receipt.GetSequence())
+			continue
+		}
+
+		Logger.Info().Msgf("Received AdapterEnvelope: %q", in)
+		if !AdapterInbox.Accept(a.SessionStreamName, in.GetEpoch(), in.GetSequence()) {
+			Logger.Info().Msgf("Discarding duplicate AdapterEnvelope with sequence: %d", in.GetSequence())
+			if !sendReceipt(sendReceiptEnvelope, in.GetEpoch(), in.GetSequence()) {
+				break
+			}
+			continue
+		}
+
+		switch msg := in.GetMessage().(type) {
+		case *pb.AdapterEnvelope_ReserveTrip:
+			handleReserveTrip(msg.ReserveTrip)
+		case *pb.AdapterEnvelope_AtDock:
+			handleAtDock(msg.AtDock)
+		case *pb.AdapterEnvelope_OnBoat:
+			handleOnBoat(msg.OnBoat)
+		case *pb.AdapterEnvelope_OffBoat:
+			handleOffBoat(msg.OffBoat)
+		case *pb.AdapterEnvelope_CancelTrip:
+			handleCancelTrip(msg.CancelTrip)
+		case *pb.AdapterEnvelope_GetTrip:
+			handleGetTrip(msg.GetTrip)
+		default:
+			Logger.Warn().Msgf("Received an AdapterEnvelope without a message with sequence: %d",
+				in.GetSequence())
+		}
+
+		// The Receipt is sent once the message has been processed, so that the
+		// Adapter sends it again if the MockLogic stops before processing it
+		if !sendReceipt(sendReceiptEnvelope, in.GetEpoch(), in.GetSequence()) {
 			break
 		}
-		Logger.Info().Msgf("Received ReserveTripMessage: %q", in)
-		// TODO: Handle message
 	}
 
 	Once.Do(CloseWaitChan)
-	stream.CloseSend()
 }
 
-// runAtDock handles the AtDock bidi stream. The stream is receiving the AtDock
-// messages from the Adapter and is not expected to send any Empty messages to
-// the Adapter, so Send() will not be called.
-func runAtDock(ctx context.Context, client pb.AdapterClient) {
-	Logger.Info().Msg("Starting AtDock stream")
+// mockLogicEnvelopeToGRPC converts a message in the AdapterOutbox to a
+// pb.MockLogicEnvelope with the epoch and sequence that it is sent with
+func mockLogicEnvelopeToGRPC(msg a.SequencedMessage[any]) (*pb.MockLogicEnvelope, error) {
+	envelope := pb.MockLogicEnvelope{
+		Epoch:    msg.Epoch,
+		Sequence: msg.Sequence,
+	}
+	var err error
+	switch m := msg.Message.(type) {
+	case a.AckMockLogicMessage:
+		ack := &pb.MockLogicEnvelope_Ack{}
+		ack.Ack, err = a.ToGRPC[*pb.AckMessage](m)
+		envelope.Message = ack
+	case a.CancelAckMockLogicMessage:
+		cancelAck := &pb.MockLogicEnvelope_CancelAck{}
+		cancelAck.CancelAck, err = a.ToGRPC[*pb.CancelAckMessage](m)
+		envelope.Message = cancelAck
+	case a.TripStatusMockLogicMessage:
+		tripStatus := &pb.MockLogicEnvelope_TripStatus{}
+		tripStatus.TripStatus, err = a.ToGRPC[*pb.TripStatusMessage](m)
+		envelope.Message = tripStatus
+	case a.BoatStatusMockLogicMessage:
+		boatStatus := &pb.MockLogicEnvelope_BoatStatus{}
+		boatStatus.BoatStatus, err = a.ToGRPC[*pb.BoatStatusMessage](m)
+		envelope.Message = boatStatus
+	case a.ArrivedMockLogicMessage:
+		arrived := &pb.MockLogicEnvelope_Arrived{}
+		arrived.Arrived, err = a.ToGRPC[*pb.ArrivedMessage](m)
+		envelope.Message = arrived
+	case a.TripProgressMockLogicMessage:
+		tripProgress := &pb.MockLogicEnvelope_TripProgress{}
+		tripProgress.TripProgress, err = a.ToGRPC[*pb.TripProgressMessage](m)
+		envelope.Message = tripProgress
+	case a.ErrorMockLogicMessage:
+		errMsg := &pb.MockLogicEnvelope_Error{}
+		errMsg.Error, err = a.ToGRPC[*pb.ErrorMessage](m)
+		envelope.Message = errMsg
+	default:
+		err = fmt.Errorf("unexpected message type: %T", msg.Message)
+	}
 
-	stream, err := client.AtDock(ctx)
+	return &envelope, err
+}
+
+// handleReserveTrip processes a ReserveTripMessage from the Adapter and places the
+// Ack in the AdapterOutbox
+func handleReserveTrip(in *pb.ReserveTripMessage) {
+	reserveTripMsg, err := a.FromGRPC[a.ReserveTripMockLogicMessage](in)
 	if err != nil {
-		Logger.Error().Msgf("client.AtDock failed to create stream: %s", err.Error())
-		Once.Do(CloseWaitChan)
+		logConversionError(a.APIMessageTypeReserveTrip, err)
+		return
+	}
+
+	ack := ProcessReserveTrip(reserveTripMsg)
+	pushToAdapter(ack, a.APIMessageTypeAck)
+}
+
+// handleAtDock processes an AtDockMessage from the Adapter and places an Error in
+// the AdapterOutbox if it is rejected
+func handleAtDock(in *pb.AtDockMessage) {
+	atDockMsg, err := a.FromGRPC[a.AtDockMockLogicMessage](in)
+	if err != nil {
+		logConversionError(a.APIMessageTypeAtDock, err)
+		return
+	}
+
+	err = ProcessAtDock(atDockMsg.APIMessage)
+	if err != nil {
+		sendTripMessageError(err, a.APIMessageTypeAtDock, atDockMsg.APIMessage.ClientID,
+			atDockMsg.APIMessage.TransactionID, atDockMsg.Client)
+	}
+}
+
+// handleOnBoat processes an OnBoatMessage from the Adapter and places an Error in
+// the AdapterOutbox if it is rejected
+func handleOnBoat(in *pb.OnBoatMessage) {
+	onBoatMsg, err := a.FromGRPC[a.OnBoatMockLogicMessage](in)
+	if err != nil {
+		logConversionError(a.APIMessageTypeOnBoat, err)
+		return
+	}
+
+	err = ProcessOnBoat(onBoatMsg.APIMessage)
+	if err != nil {
+		sendTripMessageError(err, a.APIMessageTypeOnBoat, onBoatMsg.APIMessage.ClientID,
+			onBoatMsg.APIMessage.TransactionID, onBoatMsg.Client)
+	}
+}
+
+// handleOffBoat processes an OffBoatMessage from the Adapter and places an Error in
+// the AdapterOutbox if it is rejected
+func handleOffBoat(in *pb.OffBoatMessage) {
+	offBoatMsg, err := a.FromGRPC[a.OffBoatMockLogicMessage](in)
+	if err != nil {
+		logConversionError(a.APIMessageTypeOffBoat, err)
+		return
+	}
+
+	err = ProcessOffBoat(offBoatMsg.APIMessage)
+	if err != nil {
+		sendTripMessageError(err, a.APIMessageTypeOffBoat, offBoatMsg.APIMessage.ClientID,
+			offBoatMsg.APIMessage.TransactionID, offBoatMsg.Client)
+	}
+}
+
+// handleCancelTrip processes a CancelTripMessage from the Adapter and places the
+// CancelAck in the AdapterOutbox
+func handleCancelTrip(in *pb.CancelTripMessage) {
+	cancelTripMsg, err := a.FromGRPC[a.CancelTripMockLogicMessage](in)
+	if err != nil {
+		logConversionError(a.APIMessageTypeCancelTrip, err)
+		return
+	}
+
+	cancelAck := ProcessCancelTrip(cancelTripMsg)
+	pushToAdapter(cancelAck, a.APIMessageTypeCancelAck)
+}
+
+// handleGetTrip processes a GetTripMessage from the Adapter and places the
+// TripStatus in the AdapterOutbox
+func handleGetTrip(in *pb.GetTripMessage) {
+	getTripMsg, err := a.FromGRPC[a.GetTripMockLogicMessage](in)
+	if err != nil {
+		logConversionError(a.APIMessageTypeGetTrip, err)
 		return
 	}
 
+	tripStatus := ProcessGetTrip(getTripMsg)
+	pushToAdapter(tripStatus, a.APIMessageTypeTripStatus)
+}
+
+// logConversionError logs that a message received from the Adapter could not be
+// converted, in which case it is not processed
+func logConversionError(messageType string, err error) {
+	Logger.Error().Msgf("Error converting %s message received from Adapter: %s",
+		messageType, err.Error())
+}
+
+// sequencedGRPCMessage is a gRPC message that is sent with an epoch and sequence
+type sequencedGRPCMessage interface {
+	GetEpoch() uint64
+	GetSequence() uint64
+	String() string
+}
+
+// receiveFromAdapter receives the messages of one type from the Adapter with the
+// given recv function, on the named stream that carries only that type of
+// message, and handles each message that was not received before. The Receipt
+// for each message is sent with the given send function.
+func receiveFromAdapter[M sequencedGRPCMessage](streamName string, recv func() (M, error),
+	send func(*pb.Receipt) error, handle func(M)) {
 	for {
-		in, err := stream.Recv()
+		in, err := recv()
 		if err == io.EOF {
 			// Read done
-			Logger.Warn().Msg("client.AtDock ended with EOF")
-			break
+			Logger.Warn().Msgf("client.%s ended with EOF", streamName)
+			return
 		}
 		if err != nil {
-			Logger.Error().Msgf("client.AtDock failed: %s", err.Error())
-			break
+			Logger.Error().Msgf("client.%s failed: %s", streamName, err.Error())
+			return
+		}
+		Logger.Info().Msgf("Received %sMessage: %q", streamName, in.String())
+		if !AdapterInbox.Accept(streamName, in.GetEpoch(), in.GetSequence()) {
+			Logger.Info().Msgf("Discarding duplicate %sMessage with sequence: %d", streamName, in.GetSequence())
+			if !sendReceipt(send, in.GetEpoch(), in.GetSequence()) {
+				return
+			}
+			continue
+		}
+
+		handle(in)
+
+		// The Receipt is sent once the message has been processed, so that the
+		// Adapter sends it again if the MockLogic stops before processing it
+		if !sendReceipt(send, in.GetEpoch(), in.GetSequence()) {
+			return
 		}
-		Logger.Info().Msgf("Received AtDockMessage: %q", in)
-		// TODO: Handle message
 	}
+}
+
+// runReserveTrip handles the ReserveTrip bidi stream. The stream is receiving the ReserveTrip
+// messages from the Adapter and sends a Receipt to the Adapter for each of them.
+func runReserveTrip(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting ReserveTrip stream")
+
+	stream, err := client.ReserveTrip(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.Reserve failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+
+	receiveFromAdapter("ReserveTrip", stream.Recv, stream.Send, handleReserveTrip)
+
+	Once.Do(CloseWaitChan)
+	stream.CloseSend()
+}
+
+// runAtDock handles the AtDock bidi stream. The stream is receiving the AtDock
+// messages from the Adapter and sends a Receipt to the Adapter for each of them.
+func runAtDock(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting AtDock stream")
+
+	stream, err := client.AtDock(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.AtDock failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+
+	receiveFromAdapter("AtDock", stream.Recv, stream.Send, handleAtDock)
 
 	Once.Do(CloseWaitChan)
 	stream.CloseSend()
 }
 
 // runOnBoat handles the OnBoat bidi stream. The stream is receiving the OnBoat
-// messages from the Adapter and is not expected to send any Empty messages to
-// the Adapter, so Send() will not be called.
+// messages from the Adapter and sends a Receipt to the Adapter for each of them.
 func runOnBoat(ctx context.Context, client pb.AdapterClient) {
 	Logger.Info().Msg("Starting OnBoat stream")
 
@@ -178,28 +526,14 @@ func runOnBoat(ctx context.Context, client pb.AdapterClient) {
 		return
 	}
 
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			// Read done
-			Logger.Warn().Msg("client.OnBoat ended with EOF")
-			break
-		}
-		if err != nil {
-			Logger.Error().Msgf("client.OnBoat failed: %s", err.Error())
-			break
-		}
-		Logger.Info().Msgf("Received OnBoatMessage: %q", in)
-		// TODO: Handle message
-	}
+	receiveFromAdapter("OnBoat", stream.Recv, stream.Send, handleOnBoat)
 
 	Once.Do(CloseWaitChan)
 	stream.CloseSend()
 }
 
 // runOffBoat handles the OffBoat bidi stream. The stream is receiving the OffBoat
-// messages from the Adapter and is not expected to send any Empty messages to
-// the Adapter, so Send() will not be called.
+// messages from the Adapter and sends a Receipt to the Adapter for each of them.
 func runOffBoat(ctx context.Context, client pb.AdapterClient) {
 	Logger.Info().Msg("Starting OffBoat stream")
 
@@ -210,28 +544,82 @@ func runOffBoat(ctx context.Context, client pb.AdapterClient) {
 		return
 	}
 
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			// Read done
-			Logger.Warn().Msg("client.OffBoat ended with EOF")
-			break
-		}
-		if err != nil {
-			Logger.Error().Msgf("client.OffBoat failed: %s", err.Error())
-			break
-		}
-		Logger.Info().Msgf("Received OffBoatMessage: %q", in)
-		// TODO: Handle message
+	receiveFromAdapter("OffBoat", stream.Recv, stream.Send, handleOffBoat)
+
+	Once.Do(CloseWaitChan)
+	stream.CloseSend()
+}
+
+// runCancelTrip handles the CancelTrip bidi stream. The stream is receiving the CancelTrip
+// messages from the Adapter and sends a Receipt to the Adapter for each of them.
+func runCancelTrip(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting CancelTrip stream")
+
+	stream, err := client.CancelTrip(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.CancelTrip failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
 	}
 
+	receiveFromAdapter("CancelTrip", stream.Recv, stream.Send, handleCancelTrip)
+
+	Once.Do(CloseWaitChan)
+	stream.CloseSend()
+}
+
+// runGetTrip handles the GetTrip bidi stream. The stream is receiving the GetTrip
+// messages from the Adapter and sends a Receipt to the Adapter for each of them.
+func runGetTrip(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting GetTrip stream")
+
+	stream, err := client.GetTrip(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.GetTrip failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+
+	receiveFromAdapter("GetTrip", stream.Recv, stream.Send, handleGetTrip)
+
 	Once.Do(CloseWaitChan)
 	stream.CloseSend()
 }
 
-// runAck handles the Ack bidi stream. The stream is sending the Ack messages to the
-// Adapter and is not expected to receive any Empty messages from the Adapter, so
-// Recv() will not be called.
+// sendToAdapter sends the messages of type T in the AdapterOutbox to the Adapter
+// as gRPC messages of type M with the given send function, on the named stream
+// that carries only that type of message, and receives the Receipts for them with
+// the given recv function. The messages of the other types are sent on their own
+// streams.
+func sendToAdapter[T any, M proto.Message](ctx context.Context, streamName string,
+	recv func() (*pb.Receipt, error), send func(M) error) {
+	// Launch a goroutine to receive the stream of Receipt messages
+	go receiveReceipts(streamName, recv, AdapterOutbox)
+
+	// Send the messages that have not been acknowledged, and then each message
+	// placed in the outbox, to the Adapter
+	err := a.SendPending(AdapterOutbox, ctx.Done(), func(msg a.SequencedMessage[any]) error {
+		if _, ok := msg.Message.(T); !ok {
+			return nil
+		}
+		grpcMsg, err := a.SequencedToGRPC[M](msg)
+		if err != nil {
+			Logger.Error().Msgf("Failed to convert a message in the AdapterOutbox: %s", err.Error())
+			return nil
+		}
+		return send(grpcMsg)
+	})
+	if err != nil {
+		Logger.Error().Msgf("client.%s failed to send msg: %s", streamName, err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+	Logger.Warn().Msgf("client.%s context canceled with err: %s", streamName, ctx.Err().Error())
+}
+
+// runAck handles the Ack bidi stream. The stream is sending the Ack
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
 func runAck(ctx context.Context, client pb.AdapterClient) {
 	Logger.Info().Msg("Starting Ack stream")
 
@@ -242,45 +630,44 @@ func runAck(ctx context.Context, client pb.AdapterClient) {
 		return
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			Logger.Warn().Msgf("client.Ack context canceled with err: %s", ctx.Err().Error())
-			return
+	sendToAdapter[a.AckMockLogicMessage](ctx, "Ack", stream.Recv, stream.Send)
+}
 
-		case ack := <-AdapterAckChannel:
-			ackBoatGRPC := pb.Boat{
-				BoatId: ack.APIMessage.Boat.BoatID,
-				Name:   ack.APIMessage.Boat.Name,
-			}
-			ackAPIMessageGRPC := pb.AckAPIMessage{
-				MessageType:   ack.APIMessage.MessageType,
-				ClientId:      ack.APIMessage.ClientID,
-				IsReserved:    ack.APIMessage.IsReserved,
-				Boat:          &ackBoatGRPC,
-				TransactionId: ack.APIMessage.TransactionID,
-			}
-			ackClientDataGRPC := pb.ClientData{
-				ConnName: ack.Client.ConnName,
-				ConnType: ack.Client.ConnType,
-			}
-			ackMessageGRPC := pb.AckMessage{
-				ApiMessage: &ackAPIMessageGRPC,
-				ClientData: &ackClientDataGRPC,
-			}
+// runCancelAck handles the CancelAck bidi stream. The stream is sending the CancelAck
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
+func runCancelAck(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting CancelAck stream")
 
-			if err := stream.Send(&ackMessageGRPC); err != nil {
-				Logger.Error().Msgf("client.Ack failed to send msg: %s", err.Error())
-				Once.Do(CloseWaitChan)
-				return
-			}
-		}
+	stream, err := client.CancelAck(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.CancelAck failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+
+	sendToAdapter[a.CancelAckMockLogicMessage](ctx, "CancelAck", stream.Recv, stream.Send)
+}
+
+// runTripStatus handles the TripStatus bidi stream. The stream is sending the TripStatus
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
+func runTripStatus(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting TripStatus stream")
+
+	stream, err := client.TripStatus(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.TripStatus failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
 	}
+
+	sendToAdapter[a.TripStatusMockLogicMessage](ctx, "TripStatus", stream.Recv, stream.Send)
 }
 
-// runBoatStatus handles the BoatStatus bidi stream. The stream is sending the BoatStatus messages to the
-// Adapter and is not expected to receive any Empty messages from the Adapter, so
-// Recv() will not be called.
+// runBoatStatus handles the BoatStatus bidi stream. The stream is sending the BoatStatus
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
 func runBoatStatus(ctx context.Context, client pb.AdapterClient) {
 	Logger.Info().Msg("Starting BoatStatus stream")
 
@@ -291,70 +678,12 @@ func runBoatStatus(ctx context.Context, client pb.AdapterClient) {
 		return
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			Logger.Warn().Msgf("client.BoatStatus context canceled with err: %s", ctx.Err().Error())
-			return
-
-		case status := <-AdapterBoatStatusChannel:
-			statusBoatGRPC := pb.Boat{
-				BoatId: status.APIMessage.Boat.BoatID,
-				Name:   status.APIMessage.Boat.Name,
-			}
-			statusPrevDockAddress := pb.Address{
-				Number: status.APIMessage.PreviousDock.Address.Number,
-				Street: status.APIMessage.PreviousDock.Address.Street,
-			}
-			statusPrevDock := pb.Dock{
-				Address: &statusPrevDockAddress,
-				Gangway: status.APIMessage.PreviousDock.Gangway,
-			}
-			statusCurrDockAddress := pb.Address{
-				Number: status.APIMessage.CurrentDock.Address.Number,
-				Street: status.APIMessage.CurrentDock.Address.Street,
-			}
-			statusCurrDock := pb.Dock{
-				Address: &statusCurrDockAddress,
-				Gangway: status.APIMessage.CurrentDock.Gangway,
-			}
-			statusNextDockAddress := pb.Address{
-				Number: status.APIMessage.NextDock.Address.Number,
-				Street: status.APIMessage.NextDock.Address.Street,
-			}
-			statusNextDock := pb.Dock{
-				Address: &statusNextDockAddress,
-				Gangway: status.APIMessage.NextDock.Gangway,
-			}
-			statusAPIMessageGRPC := pb.BoatStatusAPIMessage{
-				MessageType:  status.APIMessage.MessageType,
-				Boat:         &statusBoatGRPC,
-				ServiceState: pb.ServiceState(status.APIMessage.ServiceState),
-				PreviousDock: &statusPrevDock,
-				CurrentDock:  &statusCurrDock,
-				NextDock:     &statusNextDock,
-			}
-			statusClientDataGRPC := pb.ClientData{
-				ConnName: status.Client.ConnName,
-				ConnType: status.Client.ConnType,
-			}
-			statusMessageGRPC := pb.BoatStatusMessage{
-				ApiMessage: &statusAPIMessageGRPC,
-				ClientData: &statusClientDataGRPC,
-			}
-
-			if err := stream.Send(&statusMessageGRPC); err != nil {
-				Logger.Error().Msgf("client.BoatStatus failed to send msg: %s", err.Error())
-				Once.Do(CloseWaitChan)
-				return
-			}
-		}
-	}
+	sendToAdapter[a.BoatStatusMockLogicMessage](ctx, "BoatStatus", stream.Recv, stream.Send)
 }
 
-// runArrived handles the Arrived bidi stream. The stream is sending the Arrived messages to the
-// Adapter and is not expected to receive any Empty messages from the Adapter, so
-// Recv() will not be called.
+// runArrived handles the Arrived bidi stream. The stream is sending the Arrived
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
 func runArrived(ctx context.Context, client pb.AdapterClient) {
 	Logger.Info().Msg("Starting Arrived stream")
 
@@ -365,52 +694,124 @@ func runArrived(ctx context.Context, client pb.AdapterClient) {
 		return
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			Logger.Warn().Msgf("client.Arrived context canceled with err: %s", ctx.Err().Error())
-			return
+	sendToAdapter[a.ArrivedMockLogicMessage](ctx, "Arrived", stream.Recv, stream.Send)
+}
 
-		case arr := <-AdapterArrivedChannel:
-			arrBoatGRPC := pb.Boat{
-				BoatId: arr.APIMessage.Boat.BoatID,
-				Name:   arr.APIMessage.Boat.Name,
-			}
-			arrDockAddress := pb.Address{
-				Number: arr.APIMessage.Dock.Address.Number,
-				Street: arr.APIMessage.Dock.Address.Street,
-			}
-			arrDock := pb.Dock{
-				Address: &arrDockAddress,
-				Gangway: arr.APIMessage.Dock.Gangway,
-			}
-			arrAPIMessageGRPC := pb.ArrivedAPIMessage{
-				MessageType:   arr.APIMessage.MessageType,
-				ClientId:      arr.APIMessage.ClientID,
-				Boat:          &arrBoatGRPC,
-				Dock:          &arrDock,
-				TransactionId: arr.APIMessage.TransactionID,
-			}
-			arrClientDataGRPC := pb.ClientData{
-				ConnName: arr.Client.ConnName,
-				ConnType: arr.Client.ConnType,
-			}
-			arrMessageGRPC := pb.ArrivedMessage{
-				ApiMessage: &arrAPIMessageGRPC,
-				ClientData: &arrClientDataGRPC,
-			}
+// runTripProgress handles the TripProgress bidi stream. The stream is sending the TripProgress
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
+func runTripProgress(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting TripProgress stream")
 
-			if err := stream.Send(&arrMessageGRPC); err != nil {
-				Logger.Error().Msgf("client.Arrived failed to send msg: %s", err.Error())
-				Once.Do(CloseWaitChan)
-				return
-			}
+	stream, err := client.TripProgress(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.TripProgress failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+
+	sendToAdapter[a.TripProgressMockLogicMessage](ctx, "TripProgress", stream.Recv, stream.Send)
+}
+
+// runError handles the Error bidi stream. The stream is sending the Error
+// messages in the AdapterOutbox to the Adapter and receives a Receipt from
+// the Adapter for each of them.
+func runError(ctx context.Context, client pb.AdapterClient) {
+	Logger.Info().Msg("Starting Error stream")
+
+	stream, err := client.Error(ctx)
+	if err != nil {
+		Logger.Error().Msgf("client.Error failed to create stream: %s", err.Error())
+		Once.Do(CloseWaitChan)
+		return
+	}
+
+	sendToAdapter[a.ErrorMockLogicMessage](ctx, "Error", stream.Recv, stream.Send)
+}
+
+// sendTripMessageError places an Error message in the AdapterOutbox for the
+// client whose message was rejected with the given error
+func sendTripMessageError(err error, rejectedMsgType, clientID, transactionID string,
+	client a.ClientData) {
+	errMsg := NewTripMessageErrorMessage(err, rejectedMsgType, clientID, transactionID, client)
+	Logger.Warn().Msgf("Rejected %s message for TransactionID: %s from ClientID: %s: %s",
+		rejectedMsgType, transactionID, clientID, errMsg.APIMessage.Reason)
+
+	pushToAdapter(errMsg, a.APIMessageTypeError)
+}
+
+// pushToAdapter places a message for the Adapter in the AdapterOutbox. The message
+// is dropped if the outbox is full, since the Adapter has not acknowledged the
+// messages before it for too long.
+func pushToAdapter(msg any, messageType string) {
+	_, err := AdapterOutbox.Push(msg)
+	if err != nil {
+		Logger.Error().Msgf("Could not place %s message in outbox: %+v: %s",
+			messageType, msg, err.Error())
+	}
+}
+
+// pushLatestToAdapter places a message for the Adapter in the AdapterOutbox in
+// place of the message with the same key that the Adapter has not acknowledged.
+// The message is dropped if the outbox is full.
+func pushLatestToAdapter(key string, msg any, messageType string) {
+	_, err := AdapterOutbox.PushLatest(messageType+"/"+key, msg)
+	if err != nil {
+		Logger.Error().Msgf("Could not place %s message in outbox: %+v: %s",
+			messageType, msg, err.Error())
+	}
+}
+
+// sendReceipt sends a Receipt to the Adapter for the message with the given epoch
+// and sequence, and returns whether it was sent
+func sendReceipt(send func(*pb.Receipt) error, epoch, sequence uint64) bool {
+	err := send(&pb.Receipt{Epoch: epoch, Sequence: sequence})
+	if err != nil {
+		Logger.Error().Msgf("Failed to send Receipt for sequence: %d: %s", sequence, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// receiveReceipts receives the stream of Receipt messages from the Adapter on the
+// named stream and removes the messages that they acknowledge from the outbox.
+// The streams are reconnected when the stream fails.
+func receiveReceipts[T any](streamName string, recv func() (*pb.Receipt, error),
+	outbox *a.Outbox[T]) {
+	for {
+		receipt, err := recv()
+		if err == io.EOF {
+			Logger.Warn().Msgf("client.%s ended with EOF", streamName)
+			break
 		}
+		if err != nil {
+			Logger.Error().Msgf("client.%s failed to receive Receipt: %s", streamName, err.Error())
+			break
+		}
+		outbox.Acknowledge(receipt.GetEpoch(), receipt.GetSequence())
 	}
+
+	Once.Do(CloseWaitChan)
 }
 
+// ScenarioFile is the path of the scenario that is simulated. The default
+// scenario is simulated when it is empty.
+var ScenarioFile = flag.String("scenario", "", "path of a JSON simulation scenario file")
+
+// JournalFile is the path of the trip journal. The journal is kept in the log
+// directory when it is empty.
+var JournalFile = flag.String("journal", "", "path of the trip journal file")
+
+// SimSpeed is the multiple of real time that the simulation runs at
+var SimSpeed = flag.Float64("speed", 1, "multiple of real time that the simulation runs at")
+
+// LegacyStreams selects a stream for each message type, for an Adapter that does
+// not serve the Session stream yet
+var LegacyStreams = flag.Bool("legacy-streams", false, "open a stream for each message type instead of the Session stream")
+
 func Usage() {
-	fmt.Println("Usage:", os.Args[0], "log_dir log_level")
+	fmt.Println("Usage:", os.Args[0], "[-scenario scenario_file] [-journal journal_file] [-speed multiple] [-legacy-streams] log_dir log_level")
 	os.Exit(1) // 1 - Non-zero exit code indicates an error
 }
 
@@ -418,9 +819,10 @@ func main() {
 	// Parse the arguments
 	flag.Parse()
 
-	if len(flag.Args()) != 2 {
+	if len(flag.Args()) != 2 || *SimSpeed <= 0 {
 		Usage()
 	}
+	SimClock = NewScaledClock(*SimSpeed)
 
 	LogDirectory = flag.Arg(0)
 
@@ -435,10 +837,35 @@ func main() {
 		fmt.Println("Error opening log file:", logFile, ":", err.Error())
 		os.Exit(1)
 	}
+	Logger.Info().Msgf("MockLogic Version: %s Build Date: %s", VersionNumber, BuildDate)
+
+	scenario, err := LoadScenario(*ScenarioFile)
+	if err != nil {
+		Logger.Error().Msgf("Error loading scenario: %s", err.Error())
+		fmt.Println("Error loading scenario:", err.Error())
+		os.Exit(1)
+	}
+	Logger.Info().Msgf("Loaded scenario: %q with %d boats, %d docks and %d frames",
+		scenario.Name, len(scenario.Boats), len(scenario.Docks), len(scenario.Frames))
+
+	journalPath := *JournalFile
+	if journalPath == "" {
+		journalPath = path.Join(LogDirectory, "mocklogic_journal.jsonl")
+	}
+	journal, journalState, err := OpenJournal(journalPath)
+	if err != nil {
+		Logger.Error().Msgf("Error opening journal: %s", err.Error())
+		fmt.Println("Error opening journal:", err.Error())
+		os.Exit(1)
+	}
+	Logger.Info().Msgf("Replayed journal: %q with %d trips at step %d",
+		journalPath, len(journalState.Trips), journalState.Step)
 
-	InitializeSimFrames()
+	SimJournal = journal
+	InitializeSimFrames(scenario, journalState)
 
 	go InitializeAdapterGRPCStreams()
+	go InitializeSimControlServer()
 
 	// Block
 	select {}
@@ -0,0 +1,108 @@
+// simctl controls the simulation of a running MockLogic through its SimControl
+// gRPC service
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	a "riden/adapter"
+	pb "riden/proto"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ServerAddress is the address of the SimControl service of the MockLogic
+var ServerAddress = flag.String("addr", a.SimControlServerAddress, "address of the MockLogic SimControl service")
+
+// RequestTimeout is how long the CLI waits for the reply of the MockLogic
+const RequestTimeout time.Duration = 10 * time.Second
+
+func Usage() {
+	fmt.Println("Usage:", os.Args[0], "[-addr address] command")
+	fmt.Println("Commands:")
+	fmt.Println("  status             report the current frame of the simulation")
+	fmt.Println("  pause              stop the frames from advancing")
+	fmt.Println("  resume             advance the frames with the clock again")
+	fmt.Println("  step               send the next frame while paused")
+	fmt.Println("  speed multiple     run the clock at a multiple of real time")
+	fmt.Println("  jump frame_index   send the next step that shows the frame, updating")
+	fmt.Println("                     the trips for the skipped steps")
+	os.Exit(1) // 1 - Non-zero exit code indicates an error
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		Usage()
+	}
+
+	conn, err := grpc.NewClient(*ServerAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Println("Error connecting to:", *ServerAddress, ":", err.Error())
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	client := pb.NewSimControlClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	simStatus, err := runCommand(ctx, client, flag.Arg(0), flag.Args()[1:])
+	if err != nil {
+		fmt.Println("Error:", err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Step: %d, Frame: %d of %d, Paused: %t, Speed: %g\n",
+		simStatus.GetStep(), simStatus.GetFrameIndex(), simStatus.GetFrameCount(),
+		simStatus.GetPaused(), simStatus.GetSpeed())
+}
+
+// runCommand calls the RPC of the given command with its arguments and returns
+// the status of the simulation
+func runCommand(ctx context.Context, client pb.SimControlClient, command string,
+	args []string) (*pb.SimStatus, error) {
+	switch command {
+	case "status":
+		return client.GetStatus(ctx, &pb.GetStatusRequest{})
+
+	case "pause":
+		return client.Pause(ctx, &pb.PauseRequest{})
+
+	case "resume":
+		return client.Resume(ctx, &pb.ResumeRequest{})
+
+	case "step":
+		return client.Step(ctx, &pb.StepRequest{})
+
+	case "speed":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("speed takes one argument, the multiple of real time")
+		}
+		speed, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("speed: %q, is not a number", args[0])
+		}
+		return client.SetSpeed(ctx, &pb.SetSpeedRequest{Speed: speed})
+
+	case "jump":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("jump takes one argument, the frame index")
+		}
+		frameIndex, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("frame index: %q, is not an integer", args[0])
+		}
+		return client.JumpToFrame(ctx, &pb.JumpToFrameRequest{FrameIndex: int32(frameIndex)})
+
+	default:
+		return nil, fmt.Errorf("command: %q, is unknown", command)
+	}
+}
@@ -9,6 +9,7 @@ package adapter
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -75,6 +76,78 @@ func (ServiceState) EnumDescriptor() ([]byte, []int) {
 	return file_proto_adapter_proto_rawDescGZIP(), []int{0}
 }
 
+// ErrorReason represents enum values for the reasons that a message sent
+// by a client was invalid, unknown or rejected
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNKNOWN              ErrorReason = 0
+	ErrorReason_ERROR_REASON_TRIP_NOT_FOUND       ErrorReason = 1
+	ErrorReason_ERROR_REASON_TRIP_MISMATCH        ErrorReason = 2
+	ErrorReason_ERROR_REASON_INVALID_TRIP_STATE   ErrorReason = 3
+	ErrorReason_ERROR_REASON_INVALID_MESSAGE      ErrorReason = 4
+	ErrorReason_ERROR_REASON_UNKNOWN_MESSAGE_TYPE ErrorReason = 5
+	ErrorReason_ERROR_REASON_UNAVAILABLE          ErrorReason = 6
+	ErrorReason_ERROR_REASON_INVALID_TRIP         ErrorReason = 7
+	ErrorReason_ERROR_REASON_NO_BOAT_IN_SERVICE   ErrorReason = 8
+	ErrorReason_ERROR_REASON_BOATS_FULL           ErrorReason = 9
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0: "ERROR_REASON_UNKNOWN",
+		1: "ERROR_REASON_TRIP_NOT_FOUND",
+		2: "ERROR_REASON_TRIP_MISMATCH",
+		3: "ERROR_REASON_INVALID_TRIP_STATE",
+		4: "ERROR_REASON_INVALID_MESSAGE",
+		5: "ERROR_REASON_UNKNOWN_MESSAGE_TYPE",
+		6: "ERROR_REASON_UNAVAILABLE",
+		7: "ERROR_REASON_INVALID_TRIP",
+		8: "ERROR_REASON_NO_BOAT_IN_SERVICE",
+		9: "ERROR_REASON_BOATS_FULL",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNKNOWN":              0,
+		"ERROR_REASON_TRIP_NOT_FOUND":       1,
+		"ERROR_REASON_TRIP_MISMATCH":        2,
+		"ERROR_REASON_INVALID_TRIP_STATE":   3,
+		"ERROR_REASON_INVALID_MESSAGE":      4,
+		"ERROR_REASON_UNKNOWN_MESSAGE_TYPE": 5,
+		"ERROR_REASON_UNAVAILABLE":          6,
+		"ERROR_REASON_INVALID_TRIP":         7,
+		"ERROR_REASON_NO_BOAT_IN_SERVICE":   8,
+		"ERROR_REASON_BOATS_FULL":           9,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_adapter_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_proto_adapter_proto_enumTypes[1]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{1}
+}
+
 // Address represents the number and street name of an address where a dock is
 // located
 type Address struct {
@@ -300,8 +373,14 @@ type ReserveTripAPIMessage struct {
 	ClientId        string `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	SourceDock      *Dock  `protobuf:"bytes,5,opt,name=source_dock,json=sourceDock,proto3" json:"source_dock,omitempty"`
 	DestinationDock *Dock  `protobuf:"bytes,6,opt,name=destination_dock,json=destinationDock,proto3" json:"destination_dock,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// departure_time is not set when the trip is reserved on the next boat
+	DepartureTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=departure_time,json=departureTime,proto3" json:"departure_time,omitempty"`
+	// party_size is zero when a seat is reserved for each of the passenger_names,
+	// or for one passenger if no names are given
+	PartySize      int32    `protobuf:"varint,8,opt,name=party_size,json=partySize,proto3" json:"party_size,omitempty"`
+	PassengerNames []string `protobuf:"bytes,9,rep,name=passenger_names,json=passengerNames,proto3" json:"passenger_names,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ReserveTripAPIMessage) Reset() {
@@ -369,6 +448,27 @@ func (x *ReserveTripAPIMessage) GetDestinationDock() *Dock {
 	return nil
 }
 
+func (x *ReserveTripAPIMessage) GetDepartureTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartureTime
+	}
+	return nil
+}
+
+func (x *ReserveTripAPIMessage) GetPartySize() int32 {
+	if x != nil {
+		return x.PartySize
+	}
+	return 0
+}
+
+func (x *ReserveTripAPIMessage) GetPassengerNames() []string {
+	if x != nil {
+		return x.PassengerNames
+	}
+	return nil
+}
+
 // AckAPIMessage represents the Ack API message that the MockLogic
 // sends to a client indicating that a ReserveTrip message is being processed
 type AckAPIMessage struct {
@@ -379,6 +479,14 @@ type AckAPIMessage struct {
 	IsReserved    bool   `protobuf:"varint,3,opt,name=is_reserved,json=isReserved,proto3" json:"is_reserved,omitempty"`
 	Boat          *Boat  `protobuf:"bytes,4,opt,name=boat,proto3" json:"boat,omitempty"`
 	TransactionId string `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// reason_code and reason are set when is_reserved is false
+	ReasonCode ErrorReason `protobuf:"varint,6,opt,name=reason_code,json=reasonCode,proto3,enum=adapter.ErrorReason" json:"reason_code,omitempty"`
+	Reason     string      `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	// source_eta and destination_eta are not set when they are not known
+	SourceEta      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=source_eta,json=sourceEta,proto3" json:"source_eta,omitempty"`
+	DestinationEta *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=destination_eta,json=destinationEta,proto3" json:"destination_eta,omitempty"`
+	// departure_time is not set when the trip is reserved on the next boat
+	DepartureTime *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=departure_time,json=departureTime,proto3" json:"departure_time,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -448,6 +556,41 @@ func (x *AckAPIMessage) GetTransactionId() string {
 	return ""
 }
 
+func (x *AckAPIMessage) GetReasonCode() ErrorReason {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ErrorReason_ERROR_REASON_UNKNOWN
+}
+
+func (x *AckAPIMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AckAPIMessage) GetSourceEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SourceEta
+	}
+	return nil
+}
+
+func (x *AckAPIMessage) GetDestinationEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DestinationEta
+	}
+	return nil
+}
+
+func (x *AckAPIMessage) GetDepartureTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartureTime
+	}
+	return nil
+}
+
 // AtDockAPIMessage reperesents the AtDock API message that the client
 // sends to the MockLogic
 type AtDockAPIMessage struct {
@@ -536,8 +679,10 @@ type OnBoatAPIMessage struct {
 	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	Boat          *Boat  `protobuf:"bytes,3,opt,name=boat,proto3" json:"boat,omitempty"`
 	TransactionId string `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// passenger_names is empty when the message applies to the whole party
+	PassengerNames []string `protobuf:"bytes,5,rep,name=passenger_names,json=passengerNames,proto3" json:"passenger_names,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *OnBoatAPIMessage) Reset() {
@@ -598,6 +743,13 @@ func (x *OnBoatAPIMessage) GetTransactionId() string {
 	return ""
 }
 
+func (x *OnBoatAPIMessage) GetPassengerNames() []string {
+	if x != nil {
+		return x.PassengerNames
+	}
+	return nil
+}
+
 // OffBoatAPIMessage reperesents the OffBoat API message that the client
 // sends to the MockLogic
 type OffBoatAPIMessage struct {
@@ -607,8 +759,10 @@ type OffBoatAPIMessage struct {
 	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	Boat          *Boat  `protobuf:"bytes,3,opt,name=boat,proto3" json:"boat,omitempty"`
 	TransactionId string `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// passenger_names is empty when the message applies to the whole party
+	PassengerNames []string `protobuf:"bytes,5,rep,name=passenger_names,json=passengerNames,proto3" json:"passenger_names,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *OffBoatAPIMessage) Reset() {
@@ -669,35 +823,39 @@ func (x *OffBoatAPIMessage) GetTransactionId() string {
 	return ""
 }
 
-// BoatStatusAPIMessage represents the BoatStatus API message that the MockLogic
-// sends to all clients
-type BoatStatusAPIMessage struct {
+func (x *OffBoatAPIMessage) GetPassengerNames() []string {
+	if x != nil {
+		return x.PassengerNames
+	}
+	return nil
+}
+
+// CancelTripAPIMessage reperesents the CancelTrip API message that the client
+// sends to the MockLogic
+type CancelTripAPIMessage struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// message_type is a const = "boatStatus"
-	MessageType   string       `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
-	Boat          *Boat        `protobuf:"bytes,2,opt,name=boat,proto3" json:"boat,omitempty"`
-	ServiceState  ServiceState `protobuf:"varint,3,opt,name=service_state,json=serviceState,proto3,enum=adapter.ServiceState" json:"service_state,omitempty"`
-	PreviousDock  *Dock        `protobuf:"bytes,4,opt,name=previous_dock,json=previousDock,proto3" json:"previous_dock,omitempty"`
-	CurrentDock   *Dock        `protobuf:"bytes,5,opt,name=current_dock,json=currentDock,proto3" json:"current_dock,omitempty"`
-	NextDock      *Dock        `protobuf:"bytes,6,opt,name=next_dock,json=nextDock,proto3" json:"next_dock,omitempty"`
+	// message_type is a const = "cancelTrip"
+	MessageType   string `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	TransactionId string `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BoatStatusAPIMessage) Reset() {
-	*x = BoatStatusAPIMessage{}
+func (x *CancelTripAPIMessage) Reset() {
+	*x = CancelTripAPIMessage{}
 	mi := &file_proto_adapter_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BoatStatusAPIMessage) String() string {
+func (x *CancelTripAPIMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BoatStatusAPIMessage) ProtoMessage() {}
+func (*CancelTripAPIMessage) ProtoMessage() {}
 
-func (x *BoatStatusAPIMessage) ProtoReflect() protoreflect.Message {
+func (x *CancelTripAPIMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_adapter_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -709,81 +867,62 @@ func (x *BoatStatusAPIMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BoatStatusAPIMessage.ProtoReflect.Descriptor instead.
-func (*BoatStatusAPIMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelTripAPIMessage.ProtoReflect.Descriptor instead.
+func (*CancelTripAPIMessage) Descriptor() ([]byte, []int) {
 	return file_proto_adapter_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *BoatStatusAPIMessage) GetMessageType() string {
+func (x *CancelTripAPIMessage) GetMessageType() string {
 	if x != nil {
 		return x.MessageType
 	}
 	return ""
 }
 
-func (x *BoatStatusAPIMessage) GetBoat() *Boat {
-	if x != nil {
-		return x.Boat
-	}
-	return nil
-}
-
-func (x *BoatStatusAPIMessage) GetServiceState() ServiceState {
-	if x != nil {
-		return x.ServiceState
-	}
-	return ServiceState_UNKNOWN
-}
-
-func (x *BoatStatusAPIMessage) GetPreviousDock() *Dock {
-	if x != nil {
-		return x.PreviousDock
-	}
-	return nil
-}
-
-func (x *BoatStatusAPIMessage) GetCurrentDock() *Dock {
+func (x *CancelTripAPIMessage) GetClientId() string {
 	if x != nil {
-		return x.CurrentDock
+		return x.ClientId
 	}
-	return nil
+	return ""
 }
 
-func (x *BoatStatusAPIMessage) GetNextDock() *Dock {
+func (x *CancelTripAPIMessage) GetTransactionId() string {
 	if x != nil {
-		return x.NextDock
+		return x.TransactionId
 	}
-	return nil
+	return ""
 }
 
-// ArrivedAPIMessage reperesents the Arrived API message that the
-// MockLogic sends to the client
-type ArrivedAPIMessage struct {
+// CancelAckAPIMessage represents the CancelAck API message that the MockLogic
+// sends to a client as a reply to a CancelTrip message
+type CancelAckAPIMessage struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// message_type is a const = "arrived"
+	// message_type is a const = "cancelAck"
 	MessageType   string `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
 	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	Boat          *Boat  `protobuf:"bytes,3,opt,name=boat,proto3" json:"boat,omitempty"`
-	Dock          *Dock  `protobuf:"bytes,4,opt,name=dock,proto3" json:"dock,omitempty"`
-	TransactionId string `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	TransactionId string `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	IsCancelled   bool   `protobuf:"varint,4,opt,name=is_cancelled,json=isCancelled,proto3" json:"is_cancelled,omitempty"`
+	// reason_code and reason are set when is_cancelled is false
+	ReasonCode    ErrorReason `protobuf:"varint,5,opt,name=reason_code,json=reasonCode,proto3,enum=adapter.ErrorReason" json:"reason_code,omitempty"`
+	Reason        string      `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ArrivedAPIMessage) Reset() {
-	*x = ArrivedAPIMessage{}
+func (x *CancelAckAPIMessage) Reset() {
+	*x = CancelAckAPIMessage{}
 	mi := &file_proto_adapter_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ArrivedAPIMessage) String() string {
+func (x *CancelAckAPIMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ArrivedAPIMessage) ProtoMessage() {}
+func (*CancelAckAPIMessage) ProtoMessage() {}
 
-func (x *ArrivedAPIMessage) ProtoReflect() protoreflect.Message {
+func (x *CancelAckAPIMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_adapter_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -795,70 +934,79 @@ func (x *ArrivedAPIMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ArrivedAPIMessage.ProtoReflect.Descriptor instead.
-func (*ArrivedAPIMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelAckAPIMessage.ProtoReflect.Descriptor instead.
+func (*CancelAckAPIMessage) Descriptor() ([]byte, []int) {
 	return file_proto_adapter_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ArrivedAPIMessage) GetMessageType() string {
+func (x *CancelAckAPIMessage) GetMessageType() string {
 	if x != nil {
 		return x.MessageType
 	}
 	return ""
 }
 
-func (x *ArrivedAPIMessage) GetClientId() string {
+func (x *CancelAckAPIMessage) GetClientId() string {
 	if x != nil {
 		return x.ClientId
 	}
 	return ""
 }
 
-func (x *ArrivedAPIMessage) GetBoat() *Boat {
+func (x *CancelAckAPIMessage) GetTransactionId() string {
 	if x != nil {
-		return x.Boat
+		return x.TransactionId
 	}
-	return nil
+	return ""
 }
 
-func (x *ArrivedAPIMessage) GetDock() *Dock {
+func (x *CancelAckAPIMessage) GetIsCancelled() bool {
 	if x != nil {
-		return x.Dock
+		return x.IsCancelled
 	}
-	return nil
+	return false
 }
 
-func (x *ArrivedAPIMessage) GetTransactionId() string {
+func (x *CancelAckAPIMessage) GetReasonCode() ErrorReason {
 	if x != nil {
-		return x.TransactionId
+		return x.ReasonCode
+	}
+	return ErrorReason_ERROR_REASON_UNKNOWN
+}
+
+func (x *CancelAckAPIMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-// ReserveTripMessage represents the Reserve API message and the client
-// connection data that the Adapter uses
-type ReserveTripMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *ReserveTripAPIMessage `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+// GetTripAPIMessage reperesents the GetTrip API message that the client
+// sends to the MockLogic
+type GetTripAPIMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message_type is a const = "getTrip"
+	MessageType   string `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	TransactionId string `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReserveTripMessage) Reset() {
-	*x = ReserveTripMessage{}
+func (x *GetTripAPIMessage) Reset() {
+	*x = GetTripAPIMessage{}
 	mi := &file_proto_adapter_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReserveTripMessage) String() string {
+func (x *GetTripAPIMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReserveTripMessage) ProtoMessage() {}
+func (*GetTripAPIMessage) ProtoMessage() {}
 
-func (x *ReserveTripMessage) ProtoReflect() protoreflect.Message {
+func (x *GetTripAPIMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_adapter_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -870,49 +1018,62 @@ func (x *ReserveTripMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReserveTripMessage.ProtoReflect.Descriptor instead.
-func (*ReserveTripMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetTripAPIMessage.ProtoReflect.Descriptor instead.
+func (*GetTripAPIMessage) Descriptor() ([]byte, []int) {
 	return file_proto_adapter_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ReserveTripMessage) GetApiMessage() *ReserveTripAPIMessage {
+func (x *GetTripAPIMessage) GetMessageType() string {
 	if x != nil {
-		return x.ApiMessage
+		return x.MessageType
 	}
-	return nil
+	return ""
 }
 
-func (x *ReserveTripMessage) GetClientData() *ClientData {
+func (x *GetTripAPIMessage) GetClientId() string {
 	if x != nil {
-		return x.ClientData
+		return x.ClientId
 	}
-	return nil
+	return ""
 }
 
-// AckMessage represents the Ack API message and the client
-// connection data that the Adapter uses
-type AckMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *AckAPIMessage         `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetTripAPIMessage) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
 }
 
-func (x *AckMessage) Reset() {
-	*x = AckMessage{}
+// BoatStatusAPIMessage represents the BoatStatus API message that the MockLogic
+// sends to all clients
+type BoatStatusAPIMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message_type is a const = "boatStatus"
+	MessageType    string       `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	Boat           *Boat        `protobuf:"bytes,2,opt,name=boat,proto3" json:"boat,omitempty"`
+	ServiceState   ServiceState `protobuf:"varint,3,opt,name=service_state,json=serviceState,proto3,enum=adapter.ServiceState" json:"service_state,omitempty"`
+	PreviousDock   *Dock        `protobuf:"bytes,4,opt,name=previous_dock,json=previousDock,proto3" json:"previous_dock,omitempty"`
+	CurrentDock    *Dock        `protobuf:"bytes,5,opt,name=current_dock,json=currentDock,proto3" json:"current_dock,omitempty"`
+	NextDock       *Dock        `protobuf:"bytes,6,opt,name=next_dock,json=nextDock,proto3" json:"next_dock,omitempty"`
+	SeatsAvailable int32        `protobuf:"varint,7,opt,name=seats_available,json=seatsAvailable,proto3" json:"seats_available,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BoatStatusAPIMessage) Reset() {
+	*x = BoatStatusAPIMessage{}
 	mi := &file_proto_adapter_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AckMessage) String() string {
+func (x *BoatStatusAPIMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AckMessage) ProtoMessage() {}
+func (*BoatStatusAPIMessage) ProtoMessage() {}
 
-func (x *AckMessage) ProtoReflect() protoreflect.Message {
+func (x *BoatStatusAPIMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_adapter_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -924,50 +1085,315 @@ func (x *AckMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AckMessage.ProtoReflect.Descriptor instead.
-func (*AckMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use BoatStatusAPIMessage.ProtoReflect.Descriptor instead.
+func (*BoatStatusAPIMessage) Descriptor() ([]byte, []int) {
 	return file_proto_adapter_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *AckMessage) GetApiMessage() *AckAPIMessage {
+func (x *BoatStatusAPIMessage) GetMessageType() string {
 	if x != nil {
-		return x.ApiMessage
+		return x.MessageType
 	}
-	return nil
+	return ""
 }
 
-func (x *AckMessage) GetClientData() *ClientData {
+func (x *BoatStatusAPIMessage) GetBoat() *Boat {
 	if x != nil {
-		return x.ClientData
+		return x.Boat
 	}
 	return nil
 }
 
-// AtDockMessage represents the AtDock API message and the client
-// connection data that the Adapter uses
-type AtDockMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *AtDockAPIMessage      `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *BoatStatusAPIMessage) GetServiceState() ServiceState {
+	if x != nil {
+		return x.ServiceState
+	}
+	return ServiceState_UNKNOWN
 }
 
-func (x *AtDockMessage) Reset() {
-	*x = AtDockMessage{}
-	mi := &file_proto_adapter_proto_msgTypes[13]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *BoatStatusAPIMessage) GetPreviousDock() *Dock {
+	if x != nil {
+		return x.PreviousDock
+	}
+	return nil
 }
 
-func (x *AtDockMessage) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *BoatStatusAPIMessage) GetCurrentDock() *Dock {
+	if x != nil {
+		return x.CurrentDock
+	}
+	return nil
 }
 
-func (*AtDockMessage) ProtoMessage() {}
-
-func (x *AtDockMessage) ProtoReflect() protoreflect.Message {
+func (x *BoatStatusAPIMessage) GetNextDock() *Dock {
+	if x != nil {
+		return x.NextDock
+	}
+	return nil
+}
+
+func (x *BoatStatusAPIMessage) GetSeatsAvailable() int32 {
+	if x != nil {
+		return x.SeatsAvailable
+	}
+	return 0
+}
+
+// TripStatusAPIMessage represents the TripStatus API message that the MockLogic
+// sends to a client as a reply to a GetTrip message
+type TripStatusAPIMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message_type is a const = "tripStatus"
+	MessageType   string `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	TransactionId string `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	IsFound       bool   `protobuf:"varint,4,opt,name=is_found,json=isFound,proto3" json:"is_found,omitempty"`
+	// reason_code and reason are set when is_found is false
+	ReasonCode      ErrorReason           `protobuf:"varint,5,opt,name=reason_code,json=reasonCode,proto3,enum=adapter.ErrorReason" json:"reason_code,omitempty"`
+	Reason          string                `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	TripState       string                `protobuf:"bytes,7,opt,name=trip_state,json=tripState,proto3" json:"trip_state,omitempty"`
+	SourceDock      *Dock                 `protobuf:"bytes,8,opt,name=source_dock,json=sourceDock,proto3" json:"source_dock,omitempty"`
+	DestinationDock *Dock                 `protobuf:"bytes,9,opt,name=destination_dock,json=destinationDock,proto3" json:"destination_dock,omitempty"`
+	Boat            *Boat                 `protobuf:"bytes,10,opt,name=boat,proto3" json:"boat,omitempty"`
+	BoatStatus      *BoatStatusAPIMessage `protobuf:"bytes,11,opt,name=boat_status,json=boatStatus,proto3" json:"boat_status,omitempty"`
+	// source_eta and destination_eta are not set when they are not known
+	SourceEta      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=source_eta,json=sourceEta,proto3" json:"source_eta,omitempty"`
+	DestinationEta *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=destination_eta,json=destinationEta,proto3" json:"destination_eta,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TripStatusAPIMessage) Reset() {
+	*x = TripStatusAPIMessage{}
 	mi := &file_proto_adapter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripStatusAPIMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripStatusAPIMessage) ProtoMessage() {}
+
+func (x *TripStatusAPIMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripStatusAPIMessage.ProtoReflect.Descriptor instead.
+func (*TripStatusAPIMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TripStatusAPIMessage) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *TripStatusAPIMessage) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *TripStatusAPIMessage) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TripStatusAPIMessage) GetIsFound() bool {
+	if x != nil {
+		return x.IsFound
+	}
+	return false
+}
+
+func (x *TripStatusAPIMessage) GetReasonCode() ErrorReason {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ErrorReason_ERROR_REASON_UNKNOWN
+}
+
+func (x *TripStatusAPIMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *TripStatusAPIMessage) GetTripState() string {
+	if x != nil {
+		return x.TripState
+	}
+	return ""
+}
+
+func (x *TripStatusAPIMessage) GetSourceDock() *Dock {
+	if x != nil {
+		return x.SourceDock
+	}
+	return nil
+}
+
+func (x *TripStatusAPIMessage) GetDestinationDock() *Dock {
+	if x != nil {
+		return x.DestinationDock
+	}
+	return nil
+}
+
+func (x *TripStatusAPIMessage) GetBoat() *Boat {
+	if x != nil {
+		return x.Boat
+	}
+	return nil
+}
+
+func (x *TripStatusAPIMessage) GetBoatStatus() *BoatStatusAPIMessage {
+	if x != nil {
+		return x.BoatStatus
+	}
+	return nil
+}
+
+func (x *TripStatusAPIMessage) GetSourceEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SourceEta
+	}
+	return nil
+}
+
+func (x *TripStatusAPIMessage) GetDestinationEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DestinationEta
+	}
+	return nil
+}
+
+// ArrivedAPIMessage reperesents the Arrived API message that the
+// MockLogic sends to the client
+type ArrivedAPIMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message_type is a const = "arrived"
+	MessageType   string `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Boat          *Boat  `protobuf:"bytes,3,opt,name=boat,proto3" json:"boat,omitempty"`
+	Dock          *Dock  `protobuf:"bytes,4,opt,name=dock,proto3" json:"dock,omitempty"`
+	TransactionId string `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArrivedAPIMessage) Reset() {
+	*x = ArrivedAPIMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArrivedAPIMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArrivedAPIMessage) ProtoMessage() {}
+
+func (x *ArrivedAPIMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArrivedAPIMessage.ProtoReflect.Descriptor instead.
+func (*ArrivedAPIMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ArrivedAPIMessage) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *ArrivedAPIMessage) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ArrivedAPIMessage) GetBoat() *Boat {
+	if x != nil {
+		return x.Boat
+	}
+	return nil
+}
+
+func (x *ArrivedAPIMessage) GetDock() *Dock {
+	if x != nil {
+		return x.Dock
+	}
+	return nil
+}
+
+func (x *ArrivedAPIMessage) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+// TripProgressAPIMessage reperesents the TripProgress API message that the
+// MockLogic sends to the client as the frames advance
+type TripProgressAPIMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message_type is a const = "tripProgress"
+	MessageType   string `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	ClientId      string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Boat          *Boat  `protobuf:"bytes,3,opt,name=boat,proto3" json:"boat,omitempty"`
+	TransactionId string `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// source_eta and destination_eta are not set when they are not known
+	SourceEta      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=source_eta,json=sourceEta,proto3" json:"source_eta,omitempty"`
+	DestinationEta *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=destination_eta,json=destinationEta,proto3" json:"destination_eta,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TripProgressAPIMessage) Reset() {
+	*x = TripProgressAPIMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripProgressAPIMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripProgressAPIMessage) ProtoMessage() {}
+
+func (x *TripProgressAPIMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -978,50 +1404,1339 @@ func (x *AtDockMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AtDockMessage.ProtoReflect.Descriptor instead.
-func (*AtDockMessage) Descriptor() ([]byte, []int) {
-	return file_proto_adapter_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use TripProgressAPIMessage.ProtoReflect.Descriptor instead.
+func (*TripProgressAPIMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TripProgressAPIMessage) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *TripProgressAPIMessage) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *TripProgressAPIMessage) GetBoat() *Boat {
+	if x != nil {
+		return x.Boat
+	}
+	return nil
+}
+
+func (x *TripProgressAPIMessage) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TripProgressAPIMessage) GetSourceEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SourceEta
+	}
+	return nil
+}
+
+func (x *TripProgressAPIMessage) GetDestinationEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DestinationEta
+	}
+	return nil
+}
+
+// ErrorAPIMessage reperesents the Error API message that the
+// Adapter or the MockLogic sends to the client when a message from the client
+// was invalid, unknown or rejected
+type ErrorAPIMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message_type is a const = "error"
+	MessageType         string      `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	ClientId            string      `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ReasonCode          ErrorReason `protobuf:"varint,3,opt,name=reason_code,json=reasonCode,proto3,enum=adapter.ErrorReason" json:"reason_code,omitempty"`
+	Reason              string      `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	RejectedMessageType string      `protobuf:"bytes,5,opt,name=rejected_message_type,json=rejectedMessageType,proto3" json:"rejected_message_type,omitempty"`
+	TransactionId       string      `protobuf:"bytes,6,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ErrorAPIMessage) Reset() {
+	*x = ErrorAPIMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorAPIMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorAPIMessage) ProtoMessage() {}
+
+func (x *ErrorAPIMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorAPIMessage.ProtoReflect.Descriptor instead.
+func (*ErrorAPIMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ErrorAPIMessage) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *ErrorAPIMessage) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ErrorAPIMessage) GetReasonCode() ErrorReason {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ErrorReason_ERROR_REASON_UNKNOWN
+}
+
+func (x *ErrorAPIMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ErrorAPIMessage) GetRejectedMessageType() string {
+	if x != nil {
+		return x.RejectedMessageType
+	}
+	return ""
+}
+
+func (x *ErrorAPIMessage) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+// ReserveTripMessage represents the Reserve API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type ReserveTripMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *ReserveTripAPIMessage `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveTripMessage) Reset() {
+	*x = ReserveTripMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveTripMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveTripMessage) ProtoMessage() {}
+
+func (x *ReserveTripMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveTripMessage.ProtoReflect.Descriptor instead.
+func (*ReserveTripMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReserveTripMessage) GetApiMessage() *ReserveTripAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *ReserveTripMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *ReserveTripMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *ReserveTripMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// AckMessage represents the Ack API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type AckMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *AckAPIMessage         `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckMessage) Reset() {
+	*x = AckMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckMessage) ProtoMessage() {}
+
+func (x *AckMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckMessage.ProtoReflect.Descriptor instead.
+func (*AckMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AckMessage) GetApiMessage() *AckAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *AckMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *AckMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *AckMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// AtDockMessage represents the AtDock API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type AtDockMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *AtDockAPIMessage      `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AtDockMessage) Reset() {
+	*x = AtDockMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AtDockMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AtDockMessage) ProtoMessage() {}
+
+func (x *AtDockMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AtDockMessage.ProtoReflect.Descriptor instead.
+func (*AtDockMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AtDockMessage) GetApiMessage() *AtDockAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *AtDockMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *AtDockMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *AtDockMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// OnBoatMessage represents the OnBoat API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type OnBoatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *OnBoatAPIMessage      `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnBoatMessage) Reset() {
+	*x = OnBoatMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnBoatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnBoatMessage) ProtoMessage() {}
+
+func (x *OnBoatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnBoatMessage.ProtoReflect.Descriptor instead.
+func (*OnBoatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OnBoatMessage) GetApiMessage() *OnBoatAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *OnBoatMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *OnBoatMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *OnBoatMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// OffBoatMessage represents the OffBoat API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type OffBoatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *OffBoatAPIMessage     `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OffBoatMessage) Reset() {
+	*x = OffBoatMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OffBoatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OffBoatMessage) ProtoMessage() {}
+
+func (x *OffBoatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OffBoatMessage.ProtoReflect.Descriptor instead.
+func (*OffBoatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *OffBoatMessage) GetApiMessage() *OffBoatAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *OffBoatMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *OffBoatMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *OffBoatMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// CancelTripMessage represents the CancelTrip API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type CancelTripMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *CancelTripAPIMessage  `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTripMessage) Reset() {
+	*x = CancelTripMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTripMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTripMessage) ProtoMessage() {}
+
+func (x *CancelTripMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTripMessage.ProtoReflect.Descriptor instead.
+func (*CancelTripMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CancelTripMessage) GetApiMessage() *CancelTripAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *CancelTripMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *CancelTripMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *CancelTripMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// CancelAckMessage represents the CancelAck API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type CancelAckMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *CancelAckAPIMessage   `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelAckMessage) Reset() {
+	*x = CancelAckMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelAckMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelAckMessage) ProtoMessage() {}
+
+func (x *CancelAckMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelAckMessage.ProtoReflect.Descriptor instead.
+func (*CancelAckMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CancelAckMessage) GetApiMessage() *CancelAckAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *CancelAckMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *CancelAckMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *CancelAckMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// GetTripMessage represents the GetTrip API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type GetTripMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *GetTripAPIMessage     `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripMessage) Reset() {
+	*x = GetTripMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripMessage) ProtoMessage() {}
+
+func (x *GetTripMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripMessage.ProtoReflect.Descriptor instead.
+func (*GetTripMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetTripMessage) GetApiMessage() *GetTripAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *GetTripMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *GetTripMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *GetTripMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// TripStatusMessage represents the TripStatus API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type TripStatusMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *TripStatusAPIMessage  `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripStatusMessage) Reset() {
+	*x = TripStatusMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripStatusMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripStatusMessage) ProtoMessage() {}
+
+func (x *TripStatusMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripStatusMessage.ProtoReflect.Descriptor instead.
+func (*TripStatusMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TripStatusMessage) GetApiMessage() *TripStatusAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *TripStatusMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *TripStatusMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *TripStatusMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// BoatStatusMessage represents the BoatStatus API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type BoatStatusMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *BoatStatusAPIMessage  `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoatStatusMessage) Reset() {
+	*x = BoatStatusMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoatStatusMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoatStatusMessage) ProtoMessage() {}
+
+func (x *BoatStatusMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoatStatusMessage.ProtoReflect.Descriptor instead.
+func (*BoatStatusMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BoatStatusMessage) GetApiMessage() *BoatStatusAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *BoatStatusMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *BoatStatusMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *BoatStatusMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// ArrivedMessage represents the Arrived API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type ArrivedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *ArrivedAPIMessage     `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArrivedMessage) Reset() {
+	*x = ArrivedMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArrivedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArrivedMessage) ProtoMessage() {}
+
+func (x *ArrivedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArrivedMessage.ProtoReflect.Descriptor instead.
+func (*ArrivedMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ArrivedMessage) GetApiMessage() *ArrivedAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *ArrivedMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *ArrivedMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *ArrivedMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// TripProgressMessage represents the TripProgress API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type TripProgressMessage struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	ApiMessage    *TripProgressAPIMessage `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData             `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                  `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                  `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripProgressMessage) Reset() {
+	*x = TripProgressMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripProgressMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripProgressMessage) ProtoMessage() {}
+
+func (x *TripProgressMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripProgressMessage.ProtoReflect.Descriptor instead.
+func (*TripProgressMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TripProgressMessage) GetApiMessage() *TripProgressAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *TripProgressMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *TripProgressMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *TripProgressMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// ErrorMessage represents the Error API message and the client
+// connection data that the Adapter uses, with the epoch and sequence that it
+// was sent with
+type ErrorMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiMessage    *ErrorAPIMessage       `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
+	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+	Epoch         uint64                 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorMessage) Reset() {
+	*x = ErrorMessage{}
+	mi := &file_proto_adapter_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorMessage) ProtoMessage() {}
+
+func (x *ErrorMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorMessage.ProtoReflect.Descriptor instead.
+func (*ErrorMessage) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ErrorMessage) GetApiMessage() *ErrorAPIMessage {
+	if x != nil {
+		return x.ApiMessage
+	}
+	return nil
+}
+
+func (x *ErrorMessage) GetClientData() *ClientData {
+	if x != nil {
+		return x.ClientData
+	}
+	return nil
+}
+
+func (x *ErrorMessage) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *ErrorMessage) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// Receipt acknowledges that the message with the given epoch and sequence was
+// received and processed, so that the sender no longer needs to keep it. It is
+// sent on the other half of the bi-directional stream that carried the message.
+type Receipt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Epoch         uint64                 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_proto_adapter_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Receipt) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *Receipt) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+// AdapterEnvelope holds a message that the Adapter sends to the MockLogic on the
+// Session stream, with the epoch and sequence that it was sent with. A Receipt is
+// not numbered, and the epoch and sequence of the messages inside the envelope are
+// not set.
+type AdapterEnvelope struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Epoch    uint64                 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*AdapterEnvelope_ReserveTrip
+	//	*AdapterEnvelope_AtDock
+	//	*AdapterEnvelope_OnBoat
+	//	*AdapterEnvelope_OffBoat
+	//	*AdapterEnvelope_CancelTrip
+	//	*AdapterEnvelope_GetTrip
+	//	*AdapterEnvelope_Receipt
+	Message       isAdapterEnvelope_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdapterEnvelope) Reset() {
+	*x = AdapterEnvelope{}
+	mi := &file_proto_adapter_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdapterEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdapterEnvelope) ProtoMessage() {}
+
+func (x *AdapterEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdapterEnvelope.ProtoReflect.Descriptor instead.
+func (*AdapterEnvelope) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AdapterEnvelope) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *AdapterEnvelope) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AdapterEnvelope) GetMessage() isAdapterEnvelope_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetReserveTrip() *ReserveTripMessage {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_ReserveTrip); ok {
+			return x.ReserveTrip
+		}
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetAtDock() *AtDockMessage {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_AtDock); ok {
+			return x.AtDock
+		}
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetOnBoat() *OnBoatMessage {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_OnBoat); ok {
+			return x.OnBoat
+		}
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetOffBoat() *OffBoatMessage {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_OffBoat); ok {
+			return x.OffBoat
+		}
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetCancelTrip() *CancelTripMessage {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_CancelTrip); ok {
+			return x.CancelTrip
+		}
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetGetTrip() *GetTripMessage {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_GetTrip); ok {
+			return x.GetTrip
+		}
+	}
+	return nil
+}
+
+func (x *AdapterEnvelope) GetReceipt() *Receipt {
+	if x != nil {
+		if x, ok := x.Message.(*AdapterEnvelope_Receipt); ok {
+			return x.Receipt
+		}
+	}
+	return nil
+}
+
+type isAdapterEnvelope_Message interface {
+	isAdapterEnvelope_Message()
+}
+
+type AdapterEnvelope_ReserveTrip struct {
+	ReserveTrip *ReserveTripMessage `protobuf:"bytes,3,opt,name=reserve_trip,json=reserveTrip,proto3,oneof"`
+}
+
+type AdapterEnvelope_AtDock struct {
+	AtDock *AtDockMessage `protobuf:"bytes,4,opt,name=at_dock,json=atDock,proto3,oneof"`
+}
+
+type AdapterEnvelope_OnBoat struct {
+	OnBoat *OnBoatMessage `protobuf:"bytes,5,opt,name=on_boat,json=onBoat,proto3,oneof"`
+}
+
+type AdapterEnvelope_OffBoat struct {
+	OffBoat *OffBoatMessage `protobuf:"bytes,6,opt,name=off_boat,json=offBoat,proto3,oneof"`
 }
 
-func (x *AtDockMessage) GetApiMessage() *AtDockAPIMessage {
-	if x != nil {
-		return x.ApiMessage
-	}
-	return nil
+type AdapterEnvelope_CancelTrip struct {
+	CancelTrip *CancelTripMessage `protobuf:"bytes,7,opt,name=cancel_trip,json=cancelTrip,proto3,oneof"`
 }
 
-func (x *AtDockMessage) GetClientData() *ClientData {
-	if x != nil {
-		return x.ClientData
-	}
-	return nil
+type AdapterEnvelope_GetTrip struct {
+	GetTrip *GetTripMessage `protobuf:"bytes,8,opt,name=get_trip,json=getTrip,proto3,oneof"`
 }
 
-// OnBoatMessage represents the OnBoat API message and the client
-// connection data that the Adapter uses
-type OnBoatMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *OnBoatAPIMessage      `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
+type AdapterEnvelope_Receipt struct {
+	Receipt *Receipt `protobuf:"bytes,9,opt,name=receipt,proto3,oneof"`
+}
+
+func (*AdapterEnvelope_ReserveTrip) isAdapterEnvelope_Message() {}
+
+func (*AdapterEnvelope_AtDock) isAdapterEnvelope_Message() {}
+
+func (*AdapterEnvelope_OnBoat) isAdapterEnvelope_Message() {}
+
+func (*AdapterEnvelope_OffBoat) isAdapterEnvelope_Message() {}
+
+func (*AdapterEnvelope_CancelTrip) isAdapterEnvelope_Message() {}
+
+func (*AdapterEnvelope_GetTrip) isAdapterEnvelope_Message() {}
+
+func (*AdapterEnvelope_Receipt) isAdapterEnvelope_Message() {}
+
+// MockLogicEnvelope holds a message that the MockLogic sends to the Adapter on the
+// Session stream, with the epoch and sequence that it was sent with. A Receipt is
+// not numbered, and the epoch and sequence of the messages inside the envelope are
+// not set.
+type MockLogicEnvelope struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Epoch    uint64                 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sequence uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*MockLogicEnvelope_Ack
+	//	*MockLogicEnvelope_CancelAck
+	//	*MockLogicEnvelope_TripStatus
+	//	*MockLogicEnvelope_BoatStatus
+	//	*MockLogicEnvelope_Arrived
+	//	*MockLogicEnvelope_TripProgress
+	//	*MockLogicEnvelope_Error
+	//	*MockLogicEnvelope_Receipt
+	Message       isMockLogicEnvelope_Message `protobuf_oneof:"message"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OnBoatMessage) Reset() {
-	*x = OnBoatMessage{}
-	mi := &file_proto_adapter_proto_msgTypes[14]
+func (x *MockLogicEnvelope) Reset() {
+	*x = MockLogicEnvelope{}
+	mi := &file_proto_adapter_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OnBoatMessage) String() string {
+func (x *MockLogicEnvelope) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OnBoatMessage) ProtoMessage() {}
+func (*MockLogicEnvelope) ProtoMessage() {}
 
-func (x *OnBoatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_adapter_proto_msgTypes[14]
+func (x *MockLogicEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1032,104 +2747,185 @@ func (x *OnBoatMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OnBoatMessage.ProtoReflect.Descriptor instead.
-func (*OnBoatMessage) Descriptor() ([]byte, []int) {
-	return file_proto_adapter_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use MockLogicEnvelope.ProtoReflect.Descriptor instead.
+func (*MockLogicEnvelope) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *OnBoatMessage) GetApiMessage() *OnBoatAPIMessage {
+func (x *MockLogicEnvelope) GetEpoch() uint64 {
 	if x != nil {
-		return x.ApiMessage
+		return x.Epoch
 	}
-	return nil
+	return 0
 }
 
-func (x *OnBoatMessage) GetClientData() *ClientData {
+func (x *MockLogicEnvelope) GetSequence() uint64 {
 	if x != nil {
-		return x.ClientData
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *MockLogicEnvelope) GetMessage() isMockLogicEnvelope_Message {
+	if x != nil {
+		return x.Message
 	}
 	return nil
 }
 
-// OffBoatMessage represents the OffBoat API message and the client
-// connection data that the Adapter uses
-type OffBoatMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *OffBoatAPIMessage     `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *MockLogicEnvelope) GetAck() *AckMessage {
+	if x != nil {
+		if x, ok := x.Message.(*MockLogicEnvelope_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
 }
 
-func (x *OffBoatMessage) Reset() {
-	*x = OffBoatMessage{}
-	mi := &file_proto_adapter_proto_msgTypes[15]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *MockLogicEnvelope) GetCancelAck() *CancelAckMessage {
+	if x != nil {
+		if x, ok := x.Message.(*MockLogicEnvelope_CancelAck); ok {
+			return x.CancelAck
+		}
+	}
+	return nil
 }
 
-func (x *OffBoatMessage) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *MockLogicEnvelope) GetTripStatus() *TripStatusMessage {
+	if x != nil {
+		if x, ok := x.Message.(*MockLogicEnvelope_TripStatus); ok {
+			return x.TripStatus
+		}
+	}
+	return nil
 }
 
-func (*OffBoatMessage) ProtoMessage() {}
+func (x *MockLogicEnvelope) GetBoatStatus() *BoatStatusMessage {
+	if x != nil {
+		if x, ok := x.Message.(*MockLogicEnvelope_BoatStatus); ok {
+			return x.BoatStatus
+		}
+	}
+	return nil
+}
 
-func (x *OffBoatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_adapter_proto_msgTypes[15]
+func (x *MockLogicEnvelope) GetArrived() *ArrivedMessage {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x, ok := x.Message.(*MockLogicEnvelope_Arrived); ok {
+			return x.Arrived
 		}
-		return ms
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use OffBoatMessage.ProtoReflect.Descriptor instead.
-func (*OffBoatMessage) Descriptor() ([]byte, []int) {
-	return file_proto_adapter_proto_rawDescGZIP(), []int{15}
+func (x *MockLogicEnvelope) GetTripProgress() *TripProgressMessage {
+	if x != nil {
+		if x, ok := x.Message.(*MockLogicEnvelope_TripProgress); ok {
+			return x.TripProgress
+		}
+	}
+	return nil
 }
 
-func (x *OffBoatMessage) GetApiMessage() *OffBoatAPIMessage {
+func (x *MockLogicEnvelope) GetError() *ErrorMessage {
 	if x != nil {
-		return x.ApiMessage
+		if x, ok := x.Message.(*MockLogicEnvelope_Error); ok {
+			return x.Error
+		}
 	}
 	return nil
 }
 
-func (x *OffBoatMessage) GetClientData() *ClientData {
+func (x *MockLogicEnvelope) GetReceipt() *Receipt {
 	if x != nil {
-		return x.ClientData
+		if x, ok := x.Message.(*MockLogicEnvelope_Receipt); ok {
+			return x.Receipt
+		}
 	}
 	return nil
 }
 
-// BoatStatusMessage represents the BoatStatus API message and the client
-// connection data that the Adapter uses
-type BoatStatusMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *BoatStatusAPIMessage  `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type isMockLogicEnvelope_Message interface {
+	isMockLogicEnvelope_Message()
 }
 
-func (x *BoatStatusMessage) Reset() {
-	*x = BoatStatusMessage{}
-	mi := &file_proto_adapter_proto_msgTypes[16]
+type MockLogicEnvelope_Ack struct {
+	Ack *AckMessage `protobuf:"bytes,3,opt,name=ack,proto3,oneof"`
+}
+
+type MockLogicEnvelope_CancelAck struct {
+	CancelAck *CancelAckMessage `protobuf:"bytes,4,opt,name=cancel_ack,json=cancelAck,proto3,oneof"`
+}
+
+type MockLogicEnvelope_TripStatus struct {
+	TripStatus *TripStatusMessage `protobuf:"bytes,5,opt,name=trip_status,json=tripStatus,proto3,oneof"`
+}
+
+type MockLogicEnvelope_BoatStatus struct {
+	BoatStatus *BoatStatusMessage `protobuf:"bytes,6,opt,name=boat_status,json=boatStatus,proto3,oneof"`
+}
+
+type MockLogicEnvelope_Arrived struct {
+	Arrived *ArrivedMessage `protobuf:"bytes,7,opt,name=arrived,proto3,oneof"`
+}
+
+type MockLogicEnvelope_TripProgress struct {
+	TripProgress *TripProgressMessage `protobuf:"bytes,8,opt,name=trip_progress,json=tripProgress,proto3,oneof"`
+}
+
+type MockLogicEnvelope_Error struct {
+	Error *ErrorMessage `protobuf:"bytes,9,opt,name=error,proto3,oneof"`
+}
+
+type MockLogicEnvelope_Receipt struct {
+	Receipt *Receipt `protobuf:"bytes,10,opt,name=receipt,proto3,oneof"`
+}
+
+func (*MockLogicEnvelope_Ack) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_CancelAck) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_TripStatus) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_BoatStatus) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_Arrived) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_TripProgress) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_Error) isMockLogicEnvelope_Message() {}
+
+func (*MockLogicEnvelope_Receipt) isMockLogicEnvelope_Message() {}
+
+// Hello is sent by the MockLogic to start the handshake. It holds the version of
+// the component, the version of this protocol and the API message types that the
+// MockLogic handles.
+type Hello struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Component       string                 `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	VersionNumber   string                 `protobuf:"bytes,2,opt,name=version_number,json=versionNumber,proto3" json:"version_number,omitempty"`
+	BuildDate       string                 `protobuf:"bytes,3,opt,name=build_date,json=buildDate,proto3" json:"build_date,omitempty"`
+	ProtocolVersion uint32                 `protobuf:"varint,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	MessageTypes    []string               `protobuf:"bytes,5,rep,name=message_types,json=messageTypes,proto3" json:"message_types,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Hello) Reset() {
+	*x = Hello{}
+	mi := &file_proto_adapter_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BoatStatusMessage) String() string {
+func (x *Hello) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BoatStatusMessage) ProtoMessage() {}
+func (*Hello) ProtoMessage() {}
 
-func (x *BoatStatusMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_adapter_proto_msgTypes[16]
+func (x *Hello) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1140,50 +2936,78 @@ func (x *BoatStatusMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BoatStatusMessage.ProtoReflect.Descriptor instead.
-func (*BoatStatusMessage) Descriptor() ([]byte, []int) {
-	return file_proto_adapter_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use Hello.ProtoReflect.Descriptor instead.
+func (*Hello) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *BoatStatusMessage) GetApiMessage() *BoatStatusAPIMessage {
+func (x *Hello) GetComponent() string {
 	if x != nil {
-		return x.ApiMessage
+		return x.Component
 	}
-	return nil
+	return ""
 }
 
-func (x *BoatStatusMessage) GetClientData() *ClientData {
+func (x *Hello) GetVersionNumber() string {
 	if x != nil {
-		return x.ClientData
+		return x.VersionNumber
+	}
+	return ""
+}
+
+func (x *Hello) GetBuildDate() string {
+	if x != nil {
+		return x.BuildDate
+	}
+	return ""
+}
+
+func (x *Hello) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *Hello) GetMessageTypes() []string {
+	if x != nil {
+		return x.MessageTypes
 	}
 	return nil
 }
 
-// ArrivedMessage represents the Arrived API message and the client
-// connection data that the Adapter uses
-type ArrivedMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiMessage    *ArrivedAPIMessage     `protobuf:"bytes,1,opt,name=api_message,json=apiMessage,proto3" json:"api_message,omitempty"`
-	ClientData    *ClientData            `protobuf:"bytes,2,opt,name=client_data,json=clientData,proto3" json:"client_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Welcome is the reply of the Adapter to a Hello. It holds whether the MockLogic
+// was accepted and, if it was not, the reason, along with the version of the
+// Adapter, the version of this protocol and the API message types that the Adapter
+// delivers to the API clients.
+type Welcome struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IsAccepted      bool                   `protobuf:"varint,1,opt,name=is_accepted,json=isAccepted,proto3" json:"is_accepted,omitempty"`
+	Reason          string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Component       string                 `protobuf:"bytes,3,opt,name=component,proto3" json:"component,omitempty"`
+	VersionNumber   string                 `protobuf:"bytes,4,opt,name=version_number,json=versionNumber,proto3" json:"version_number,omitempty"`
+	BuildDate       string                 `protobuf:"bytes,5,opt,name=build_date,json=buildDate,proto3" json:"build_date,omitempty"`
+	ProtocolVersion uint32                 `protobuf:"varint,6,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	MessageTypes    []string               `protobuf:"bytes,7,rep,name=message_types,json=messageTypes,proto3" json:"message_types,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ArrivedMessage) Reset() {
-	*x = ArrivedMessage{}
-	mi := &file_proto_adapter_proto_msgTypes[17]
+func (x *Welcome) Reset() {
+	*x = Welcome{}
+	mi := &file_proto_adapter_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ArrivedMessage) String() string {
+func (x *Welcome) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ArrivedMessage) ProtoMessage() {}
+func (*Welcome) ProtoMessage() {}
 
-func (x *ArrivedMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_adapter_proto_msgTypes[17]
+func (x *Welcome) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_adapter_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1194,69 +3018,65 @@ func (x *ArrivedMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ArrivedMessage.ProtoReflect.Descriptor instead.
-func (*ArrivedMessage) Descriptor() ([]byte, []int) {
-	return file_proto_adapter_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use Welcome.ProtoReflect.Descriptor instead.
+func (*Welcome) Descriptor() ([]byte, []int) {
+	return file_proto_adapter_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *ArrivedMessage) GetApiMessage() *ArrivedAPIMessage {
+func (x *Welcome) GetIsAccepted() bool {
 	if x != nil {
-		return x.ApiMessage
+		return x.IsAccepted
 	}
-	return nil
+	return false
 }
 
-func (x *ArrivedMessage) GetClientData() *ClientData {
+func (x *Welcome) GetReason() string {
 	if x != nil {
-		return x.ClientData
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-// Empty represents an empty message that is not expected to
-// ever be sent or received and is used for one half of a
-// bi-directional streaming message service
-type Empty struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Welcome) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_proto_adapter_proto_msgTypes[18]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Welcome) GetVersionNumber() string {
+	if x != nil {
+		return x.VersionNumber
+	}
+	return ""
 }
 
-func (x *Empty) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Welcome) GetBuildDate() string {
+	if x != nil {
+		return x.BuildDate
+	}
+	return ""
 }
 
-func (*Empty) ProtoMessage() {}
-
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_adapter_proto_msgTypes[18]
+func (x *Welcome) GetProtocolVersion() uint32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ProtocolVersion
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_proto_adapter_proto_rawDescGZIP(), []int{18}
+func (x *Welcome) GetMessageTypes() []string {
+	if x != nil {
+		return x.MessageTypes
+	}
+	return nil
 }
 
 var File_proto_adapter_proto protoreflect.FileDescriptor
 
 const file_proto_adapter_proto_rawDesc = "" +
 	"\n" +
-	"\x13proto/adapter.proto\x12\aadapter\"9\n" +
+	"\x13proto/adapter.proto\x12\aadapter\x1a\x1fgoogle/protobuf/timestamp.proto\"9\n" +
 	"\aAddress\x12\x16\n" +
 	"\x06number\x18\x01 \x01(\x05R\x06number\x12\x16\n" +
 	"\x06street\x18\x02 \x01(\tR\x06street\"L\n" +
@@ -1269,7 +3089,7 @@ const file_proto_adapter_proto_rawDesc = "" +
 	"\n" +
 	"ClientData\x12\x1b\n" +
 	"\tconn_name\x18\x01 \x01(\tR\bconnName\x12\x1b\n" +
-	"\tconn_type\x18\x02 \x01(\tR\bconnType\"\xe0\x01\n" +
+	"\tconn_type\x18\x02 \x01(\tR\bconnType\"\xeb\x02\n" +
 	"\x15ReserveTripAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1d\n" +
 	"\n" +
@@ -1277,94 +3097,285 @@ const file_proto_adapter_proto_rawDesc = "" +
 	"\tclient_id\x18\x03 \x01(\tR\bclientId\x12.\n" +
 	"\vsource_dock\x18\x05 \x01(\v2\r.adapter.DockR\n" +
 	"sourceDock\x128\n" +
-	"\x10destination_dock\x18\x06 \x01(\v2\r.adapter.DockR\x0fdestinationDock\"\xba\x01\n" +
+	"\x10destination_dock\x18\x06 \x01(\v2\r.adapter.DockR\x0fdestinationDock\x12A\n" +
+	"\x0edeparture_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rdepartureTime\x12\x1d\n" +
+	"\n" +
+	"party_size\x18\b \x01(\x05R\tpartySize\x12'\n" +
+	"\x0fpassenger_names\x18\t \x03(\tR\x0epassengerNames\"\xcc\x03\n" +
 	"\rAckAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
 	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12\x1f\n" +
 	"\vis_reserved\x18\x03 \x01(\bR\n" +
 	"isReserved\x12!\n" +
 	"\x04boat\x18\x04 \x01(\v2\r.adapter.BoatR\x04boat\x12%\n" +
-	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\"\xbf\x01\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\x125\n" +
+	"\vreason_code\x18\x06 \x01(\x0e2\x14.adapter.ErrorReasonR\n" +
+	"reasonCode\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"source_eta\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tsourceEta\x12C\n" +
+	"\x0fdestination_eta\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x0edestinationEta\x12A\n" +
+	"\x0edeparture_time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\rdepartureTime\"\xbf\x01\n" +
 	"\x10AtDockAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
 	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12!\n" +
 	"\x04boat\x18\x03 \x01(\v2\r.adapter.BoatR\x04boat\x12!\n" +
 	"\x04dock\x18\x04 \x01(\v2\r.adapter.DockR\x04dock\x12%\n" +
-	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\"\x9c\x01\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\"\xc5\x01\n" +
 	"\x10OnBoatAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
 	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12!\n" +
 	"\x04boat\x18\x03 \x01(\v2\r.adapter.BoatR\x04boat\x12%\n" +
-	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\"\x9d\x01\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x12'\n" +
+	"\x0fpassenger_names\x18\x05 \x03(\tR\x0epassengerNames\"\xc6\x01\n" +
 	"\x11OffBoatAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
 	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12!\n" +
 	"\x04boat\x18\x03 \x01(\v2\r.adapter.BoatR\x04boat\x12%\n" +
-	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\"\xaa\x02\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x12'\n" +
+	"\x0fpassenger_names\x18\x05 \x03(\tR\x0epassengerNames\"}\n" +
+	"\x14CancelTripAPIMessage\x12!\n" +
+	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\"\xee\x01\n" +
+	"\x13CancelAckAPIMessage\x12!\n" +
+	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\x12!\n" +
+	"\fis_cancelled\x18\x04 \x01(\bR\visCancelled\x125\n" +
+	"\vreason_code\x18\x05 \x01(\x0e2\x14.adapter.ErrorReasonR\n" +
+	"reasonCode\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"z\n" +
+	"\x11GetTripAPIMessage\x12!\n" +
+	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\"\xd3\x02\n" +
 	"\x14BoatStatusAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12!\n" +
 	"\x04boat\x18\x02 \x01(\v2\r.adapter.BoatR\x04boat\x12:\n" +
 	"\rservice_state\x18\x03 \x01(\x0e2\x15.adapter.ServiceStateR\fserviceState\x122\n" +
 	"\rprevious_dock\x18\x04 \x01(\v2\r.adapter.DockR\fpreviousDock\x120\n" +
 	"\fcurrent_dock\x18\x05 \x01(\v2\r.adapter.DockR\vcurrentDock\x12*\n" +
-	"\tnext_dock\x18\x06 \x01(\v2\r.adapter.DockR\bnextDock\"\xc0\x01\n" +
+	"\tnext_dock\x18\x06 \x01(\v2\r.adapter.DockR\bnextDock\x12'\n" +
+	"\x0fseats_available\x18\a \x01(\x05R\x0eseatsAvailable\"\xd3\x04\n" +
+	"\x14TripStatusAPIMessage\x12!\n" +
+	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\x12\x19\n" +
+	"\bis_found\x18\x04 \x01(\bR\aisFound\x125\n" +
+	"\vreason_code\x18\x05 \x01(\x0e2\x14.adapter.ErrorReasonR\n" +
+	"reasonCode\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"trip_state\x18\a \x01(\tR\ttripState\x12.\n" +
+	"\vsource_dock\x18\b \x01(\v2\r.adapter.DockR\n" +
+	"sourceDock\x128\n" +
+	"\x10destination_dock\x18\t \x01(\v2\r.adapter.DockR\x0fdestinationDock\x12!\n" +
+	"\x04boat\x18\n" +
+	" \x01(\v2\r.adapter.BoatR\x04boat\x12>\n" +
+	"\vboat_status\x18\v \x01(\v2\x1d.adapter.BoatStatusAPIMessageR\n" +
+	"boatStatus\x129\n" +
+	"\n" +
+	"source_eta\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tsourceEta\x12C\n" +
+	"\x0fdestination_eta\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x0edestinationEta\"\xc0\x01\n" +
 	"\x11ArrivedAPIMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
 	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12!\n" +
 	"\x04boat\x18\x03 \x01(\v2\r.adapter.BoatR\x04boat\x12!\n" +
 	"\x04dock\x18\x04 \x01(\v2\r.adapter.DockR\x04dock\x12%\n" +
-	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\"\x8b\x01\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\"\xa2\x02\n" +
+	"\x16TripProgressAPIMessage\x12!\n" +
+	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12!\n" +
+	"\x04boat\x18\x03 \x01(\v2\r.adapter.BoatR\x04boat\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x129\n" +
+	"\n" +
+	"source_eta\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tsourceEta\x12C\n" +
+	"\x0fdestination_eta\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0edestinationEta\"\xfb\x01\n" +
+	"\x0fErrorAPIMessage\x12!\n" +
+	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x125\n" +
+	"\vreason_code\x18\x03 \x01(\x0e2\x14.adapter.ErrorReasonR\n" +
+	"reasonCode\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x122\n" +
+	"\x15rejected_message_type\x18\x05 \x01(\tR\x13rejectedMessageType\x12%\n" +
+	"\x0etransaction_id\x18\x06 \x01(\tR\rtransactionId\"\xbd\x01\n" +
 	"\x12ReserveTripMessage\x12?\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x1e.adapter.ReserveTripAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"{\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xad\x01\n" +
 	"\n" +
 	"AckMessage\x127\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x16.adapter.AckAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"\x81\x01\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb3\x01\n" +
 	"\rAtDockMessage\x12:\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x19.adapter.AtDockAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"\x81\x01\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb3\x01\n" +
 	"\rOnBoatMessage\x12:\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x19.adapter.OnBoatAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"\x83\x01\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb5\x01\n" +
 	"\x0eOffBoatMessage\x12;\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x1a.adapter.OffBoatAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"\x89\x01\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xbb\x01\n" +
+	"\x11CancelTripMessage\x12>\n" +
+	"\vapi_message\x18\x01 \x01(\v2\x1d.adapter.CancelTripAPIMessageR\n" +
+	"apiMessage\x124\n" +
+	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb9\x01\n" +
+	"\x10CancelAckMessage\x12=\n" +
+	"\vapi_message\x18\x01 \x01(\v2\x1c.adapter.CancelAckAPIMessageR\n" +
+	"apiMessage\x124\n" +
+	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb5\x01\n" +
+	"\x0eGetTripMessage\x12;\n" +
+	"\vapi_message\x18\x01 \x01(\v2\x1a.adapter.GetTripAPIMessageR\n" +
+	"apiMessage\x124\n" +
+	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xbb\x01\n" +
+	"\x11TripStatusMessage\x12>\n" +
+	"\vapi_message\x18\x01 \x01(\v2\x1d.adapter.TripStatusAPIMessageR\n" +
+	"apiMessage\x124\n" +
+	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xbb\x01\n" +
 	"\x11BoatStatusMessage\x12>\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x1d.adapter.BoatStatusAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"\x83\x01\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb5\x01\n" +
 	"\x0eArrivedMessage\x12;\n" +
 	"\vapi_message\x18\x01 \x01(\v2\x1a.adapter.ArrivedAPIMessageR\n" +
 	"apiMessage\x124\n" +
 	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
-	"clientData\"\a\n" +
-	"\x05Empty*F\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xbf\x01\n" +
+	"\x13TripProgressMessage\x12@\n" +
+	"\vapi_message\x18\x01 \x01(\v2\x1f.adapter.TripProgressAPIMessageR\n" +
+	"apiMessage\x124\n" +
+	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"\xb1\x01\n" +
+	"\fErrorMessage\x129\n" +
+	"\vapi_message\x18\x01 \x01(\v2\x18.adapter.ErrorAPIMessageR\n" +
+	"apiMessage\x124\n" +
+	"\vclient_data\x18\x02 \x01(\v2\x13.adapter.ClientDataR\n" +
+	"clientData\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\";\n" +
+	"\aReceipt\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\"\xcf\x03\n" +
+	"\x0fAdapterEnvelope\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12@\n" +
+	"\freserve_trip\x18\x03 \x01(\v2\x1b.adapter.ReserveTripMessageH\x00R\vreserveTrip\x121\n" +
+	"\aat_dock\x18\x04 \x01(\v2\x16.adapter.AtDockMessageH\x00R\x06atDock\x121\n" +
+	"\aon_boat\x18\x05 \x01(\v2\x16.adapter.OnBoatMessageH\x00R\x06onBoat\x124\n" +
+	"\boff_boat\x18\x06 \x01(\v2\x17.adapter.OffBoatMessageH\x00R\aoffBoat\x12=\n" +
+	"\vcancel_trip\x18\a \x01(\v2\x1a.adapter.CancelTripMessageH\x00R\n" +
+	"cancelTrip\x124\n" +
+	"\bget_trip\x18\b \x01(\v2\x17.adapter.GetTripMessageH\x00R\agetTrip\x12,\n" +
+	"\areceipt\x18\t \x01(\v2\x10.adapter.ReceiptH\x00R\areceiptB\t\n" +
+	"\amessage\"\x8a\x04\n" +
+	"\x11MockLogicEnvelope\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\x04R\x05epoch\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\x12'\n" +
+	"\x03ack\x18\x03 \x01(\v2\x13.adapter.AckMessageH\x00R\x03ack\x12:\n" +
+	"\n" +
+	"cancel_ack\x18\x04 \x01(\v2\x19.adapter.CancelAckMessageH\x00R\tcancelAck\x12=\n" +
+	"\vtrip_status\x18\x05 \x01(\v2\x1a.adapter.TripStatusMessageH\x00R\n" +
+	"tripStatus\x12=\n" +
+	"\vboat_status\x18\x06 \x01(\v2\x1a.adapter.BoatStatusMessageH\x00R\n" +
+	"boatStatus\x123\n" +
+	"\aarrived\x18\a \x01(\v2\x17.adapter.ArrivedMessageH\x00R\aarrived\x12C\n" +
+	"\rtrip_progress\x18\b \x01(\v2\x1c.adapter.TripProgressMessageH\x00R\ftripProgress\x12-\n" +
+	"\x05error\x18\t \x01(\v2\x15.adapter.ErrorMessageH\x00R\x05error\x12,\n" +
+	"\areceipt\x18\n" +
+	" \x01(\v2\x10.adapter.ReceiptH\x00R\areceiptB\t\n" +
+	"\amessage\"\xbb\x01\n" +
+	"\x05Hello\x12\x1c\n" +
+	"\tcomponent\x18\x01 \x01(\tR\tcomponent\x12%\n" +
+	"\x0eversion_number\x18\x02 \x01(\tR\rversionNumber\x12\x1d\n" +
+	"\n" +
+	"build_date\x18\x03 \x01(\tR\tbuildDate\x12)\n" +
+	"\x10protocol_version\x18\x04 \x01(\rR\x0fprotocolVersion\x12#\n" +
+	"\rmessage_types\x18\x05 \x03(\tR\fmessageTypes\"\xf6\x01\n" +
+	"\aWelcome\x12\x1f\n" +
+	"\vis_accepted\x18\x01 \x01(\bR\n" +
+	"isAccepted\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1c\n" +
+	"\tcomponent\x18\x03 \x01(\tR\tcomponent\x12%\n" +
+	"\x0eversion_number\x18\x04 \x01(\tR\rversionNumber\x12\x1d\n" +
+	"\n" +
+	"build_date\x18\x05 \x01(\tR\tbuildDate\x12)\n" +
+	"\x10protocol_version\x18\x06 \x01(\rR\x0fprotocolVersion\x12#\n" +
+	"\rmessage_types\x18\a \x03(\tR\fmessageTypes*F\n" +
 	"\fServiceState\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aON_TIME\x10\x01\x12\v\n" +
 	"\aDELAYED\x10\x02\x12\x0f\n" +
-	"\vUNAVAILABLE\x10\x032\xa1\x03\n" +
-	"\aAdapter\x12@\n" +
-	"\vReserveTrip\x12\x0e.adapter.Empty\x1a\x1b.adapter.ReserveTripMessage\"\x00(\x010\x01\x120\n" +
-	"\x03Ack\x12\x13.adapter.AckMessage\x1a\x0e.adapter.Empty\"\x00(\x010\x01\x126\n" +
-	"\x06AtDock\x12\x0e.adapter.Empty\x1a\x16.adapter.AtDockMessage\"\x00(\x010\x01\x126\n" +
-	"\x06OnBoat\x12\x0e.adapter.Empty\x1a\x16.adapter.OnBoatMessage\"\x00(\x010\x01\x128\n" +
-	"\aOffBoat\x12\x0e.adapter.Empty\x1a\x17.adapter.OffBoatMessage\"\x00(\x010\x01\x12>\n" +
+	"\vUNAVAILABLE\x10\x03*\xd5\x02\n" +
+	"\vErrorReason\x12\x18\n" +
+	"\x14ERROR_REASON_UNKNOWN\x10\x00\x12\x1f\n" +
+	"\x1bERROR_REASON_TRIP_NOT_FOUND\x10\x01\x12\x1e\n" +
+	"\x1aERROR_REASON_TRIP_MISMATCH\x10\x02\x12#\n" +
+	"\x1fERROR_REASON_INVALID_TRIP_STATE\x10\x03\x12 \n" +
+	"\x1cERROR_REASON_INVALID_MESSAGE\x10\x04\x12%\n" +
+	"!ERROR_REASON_UNKNOWN_MESSAGE_TYPE\x10\x05\x12\x1c\n" +
+	"\x18ERROR_REASON_UNAVAILABLE\x10\x06\x12\x1d\n" +
+	"\x19ERROR_REASON_INVALID_TRIP\x10\a\x12#\n" +
+	"\x1fERROR_REASON_NO_BOAT_IN_SERVICE\x10\b\x12\x1b\n" +
+	"\x17ERROR_REASON_BOATS_FULL\x10\t2\xa5\a\n" +
+	"\aAdapter\x12/\n" +
+	"\tHandshake\x12\x0e.adapter.Hello\x1a\x10.adapter.Welcome\"\x00\x12E\n" +
+	"\aSession\x12\x1a.adapter.MockLogicEnvelope\x1a\x18.adapter.AdapterEnvelope\"\x00(\x010\x01\x12B\n" +
+	"\vReserveTrip\x12\x10.adapter.Receipt\x1a\x1b.adapter.ReserveTripMessage\"\x00(\x010\x01\x122\n" +
+	"\x03Ack\x12\x13.adapter.AckMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01\x128\n" +
+	"\x06AtDock\x12\x10.adapter.Receipt\x1a\x16.adapter.AtDockMessage\"\x00(\x010\x01\x128\n" +
+	"\x06OnBoat\x12\x10.adapter.Receipt\x1a\x16.adapter.OnBoatMessage\"\x00(\x010\x01\x12:\n" +
+	"\aOffBoat\x12\x10.adapter.Receipt\x1a\x17.adapter.OffBoatMessage\"\x00(\x010\x01\x12@\n" +
+	"\n" +
+	"CancelTrip\x12\x10.adapter.Receipt\x1a\x1a.adapter.CancelTripMessage\"\x00(\x010\x01\x12>\n" +
+	"\tCancelAck\x12\x19.adapter.CancelAckMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01\x12:\n" +
+	"\aGetTrip\x12\x10.adapter.Receipt\x1a\x17.adapter.GetTripMessage\"\x00(\x010\x01\x12@\n" +
 	"\n" +
-	"BoatStatus\x12\x1a.adapter.BoatStatusMessage\x1a\x0e.adapter.Empty\"\x00(\x010\x01\x128\n" +
-	"\aArrived\x12\x17.adapter.ArrivedMessage\x1a\x0e.adapter.Empty\"\x00(\x010\x01B\x17Z\x15riden/adapter/adapterb\x06proto3"
+	"TripStatus\x12\x1a.adapter.TripStatusMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01\x12@\n" +
+	"\n" +
+	"BoatStatus\x12\x1a.adapter.BoatStatusMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01\x12:\n" +
+	"\aArrived\x12\x17.adapter.ArrivedMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01\x12D\n" +
+	"\fTripProgress\x12\x1c.adapter.TripProgressMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01\x126\n" +
+	"\x05Error\x12\x15.adapter.ErrorMessage\x1a\x10.adapter.Receipt\"\x00(\x010\x01B\x17Z\x15riden/adapter/adapterb\x06proto3"
 
 var (
 	file_proto_adapter_proto_rawDescOnce sync.Once
@@ -1378,79 +3389,157 @@ func file_proto_adapter_proto_rawDescGZIP() []byte {
 	return file_proto_adapter_proto_rawDescData
 }
 
-var file_proto_adapter_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_adapter_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_proto_adapter_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_adapter_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_proto_adapter_proto_goTypes = []any{
-	(ServiceState)(0),             // 0: adapter.ServiceState
-	(*Address)(nil),               // 1: adapter.Address
-	(*Dock)(nil),                  // 2: adapter.Dock
-	(*Boat)(nil),                  // 3: adapter.Boat
-	(*ClientData)(nil),            // 4: adapter.ClientData
-	(*ReserveTripAPIMessage)(nil), // 5: adapter.ReserveTripAPIMessage
-	(*AckAPIMessage)(nil),         // 6: adapter.AckAPIMessage
-	(*AtDockAPIMessage)(nil),      // 7: adapter.AtDockAPIMessage
-	(*OnBoatAPIMessage)(nil),      // 8: adapter.OnBoatAPIMessage
-	(*OffBoatAPIMessage)(nil),     // 9: adapter.OffBoatAPIMessage
-	(*BoatStatusAPIMessage)(nil),  // 10: adapter.BoatStatusAPIMessage
-	(*ArrivedAPIMessage)(nil),     // 11: adapter.ArrivedAPIMessage
-	(*ReserveTripMessage)(nil),    // 12: adapter.ReserveTripMessage
-	(*AckMessage)(nil),            // 13: adapter.AckMessage
-	(*AtDockMessage)(nil),         // 14: adapter.AtDockMessage
-	(*OnBoatMessage)(nil),         // 15: adapter.OnBoatMessage
-	(*OffBoatMessage)(nil),        // 16: adapter.OffBoatMessage
-	(*BoatStatusMessage)(nil),     // 17: adapter.BoatStatusMessage
-	(*ArrivedMessage)(nil),        // 18: adapter.ArrivedMessage
-	(*Empty)(nil),                 // 19: adapter.Empty
+	(ServiceState)(0),              // 0: adapter.ServiceState
+	(ErrorReason)(0),               // 1: adapter.ErrorReason
+	(*Address)(nil),                // 2: adapter.Address
+	(*Dock)(nil),                   // 3: adapter.Dock
+	(*Boat)(nil),                   // 4: adapter.Boat
+	(*ClientData)(nil),             // 5: adapter.ClientData
+	(*ReserveTripAPIMessage)(nil),  // 6: adapter.ReserveTripAPIMessage
+	(*AckAPIMessage)(nil),          // 7: adapter.AckAPIMessage
+	(*AtDockAPIMessage)(nil),       // 8: adapter.AtDockAPIMessage
+	(*OnBoatAPIMessage)(nil),       // 9: adapter.OnBoatAPIMessage
+	(*OffBoatAPIMessage)(nil),      // 10: adapter.OffBoatAPIMessage
+	(*CancelTripAPIMessage)(nil),   // 11: adapter.CancelTripAPIMessage
+	(*CancelAckAPIMessage)(nil),    // 12: adapter.CancelAckAPIMessage
+	(*GetTripAPIMessage)(nil),      // 13: adapter.GetTripAPIMessage
+	(*BoatStatusAPIMessage)(nil),   // 14: adapter.BoatStatusAPIMessage
+	(*TripStatusAPIMessage)(nil),   // 15: adapter.TripStatusAPIMessage
+	(*ArrivedAPIMessage)(nil),      // 16: adapter.ArrivedAPIMessage
+	(*TripProgressAPIMessage)(nil), // 17: adapter.TripProgressAPIMessage
+	(*ErrorAPIMessage)(nil),        // 18: adapter.ErrorAPIMessage
+	(*ReserveTripMessage)(nil),     // 19: adapter.ReserveTripMessage
+	(*AckMessage)(nil),             // 20: adapter.AckMessage
+	(*AtDockMessage)(nil),          // 21: adapter.AtDockMessage
+	(*OnBoatMessage)(nil),          // 22: adapter.OnBoatMessage
+	(*OffBoatMessage)(nil),         // 23: adapter.OffBoatMessage
+	(*CancelTripMessage)(nil),      // 24: adapter.CancelTripMessage
+	(*CancelAckMessage)(nil),       // 25: adapter.CancelAckMessage
+	(*GetTripMessage)(nil),         // 26: adapter.GetTripMessage
+	(*TripStatusMessage)(nil),      // 27: adapter.TripStatusMessage
+	(*BoatStatusMessage)(nil),      // 28: adapter.BoatStatusMessage
+	(*ArrivedMessage)(nil),         // 29: adapter.ArrivedMessage
+	(*TripProgressMessage)(nil),    // 30: adapter.TripProgressMessage
+	(*ErrorMessage)(nil),           // 31: adapter.ErrorMessage
+	(*Receipt)(nil),                // 32: adapter.Receipt
+	(*AdapterEnvelope)(nil),        // 33: adapter.AdapterEnvelope
+	(*MockLogicEnvelope)(nil),      // 34: adapter.MockLogicEnvelope
+	(*Hello)(nil),                  // 35: adapter.Hello
+	(*Welcome)(nil),                // 36: adapter.Welcome
+	(*timestamppb.Timestamp)(nil),  // 37: google.protobuf.Timestamp
 }
 var file_proto_adapter_proto_depIdxs = []int32{
-	1,  // 0: adapter.Dock.address:type_name -> adapter.Address
-	2,  // 1: adapter.ReserveTripAPIMessage.source_dock:type_name -> adapter.Dock
-	2,  // 2: adapter.ReserveTripAPIMessage.destination_dock:type_name -> adapter.Dock
-	3,  // 3: adapter.AckAPIMessage.boat:type_name -> adapter.Boat
-	3,  // 4: adapter.AtDockAPIMessage.boat:type_name -> adapter.Boat
-	2,  // 5: adapter.AtDockAPIMessage.dock:type_name -> adapter.Dock
-	3,  // 6: adapter.OnBoatAPIMessage.boat:type_name -> adapter.Boat
-	3,  // 7: adapter.OffBoatAPIMessage.boat:type_name -> adapter.Boat
-	3,  // 8: adapter.BoatStatusAPIMessage.boat:type_name -> adapter.Boat
-	0,  // 9: adapter.BoatStatusAPIMessage.service_state:type_name -> adapter.ServiceState
-	2,  // 10: adapter.BoatStatusAPIMessage.previous_dock:type_name -> adapter.Dock
-	2,  // 11: adapter.BoatStatusAPIMessage.current_dock:type_name -> adapter.Dock
-	2,  // 12: adapter.BoatStatusAPIMessage.next_dock:type_name -> adapter.Dock
-	3,  // 13: adapter.ArrivedAPIMessage.boat:type_name -> adapter.Boat
-	2,  // 14: adapter.ArrivedAPIMessage.dock:type_name -> adapter.Dock
-	5,  // 15: adapter.ReserveTripMessage.api_message:type_name -> adapter.ReserveTripAPIMessage
-	4,  // 16: adapter.ReserveTripMessage.client_data:type_name -> adapter.ClientData
-	6,  // 17: adapter.AckMessage.api_message:type_name -> adapter.AckAPIMessage
-	4,  // 18: adapter.AckMessage.client_data:type_name -> adapter.ClientData
-	7,  // 19: adapter.AtDockMessage.api_message:type_name -> adapter.AtDockAPIMessage
-	4,  // 20: adapter.AtDockMessage.client_data:type_name -> adapter.ClientData
-	8,  // 21: adapter.OnBoatMessage.api_message:type_name -> adapter.OnBoatAPIMessage
-	4,  // 22: adapter.OnBoatMessage.client_data:type_name -> adapter.ClientData
-	9,  // 23: adapter.OffBoatMessage.api_message:type_name -> adapter.OffBoatAPIMessage
-	4,  // 24: adapter.OffBoatMessage.client_data:type_name -> adapter.ClientData
-	10, // 25: adapter.BoatStatusMessage.api_message:type_name -> adapter.BoatStatusAPIMessage
-	4,  // 26: adapter.BoatStatusMessage.client_data:type_name -> adapter.ClientData
-	11, // 27: adapter.ArrivedMessage.api_message:type_name -> adapter.ArrivedAPIMessage
-	4,  // 28: adapter.ArrivedMessage.client_data:type_name -> adapter.ClientData
-	19, // 29: adapter.Adapter.ReserveTrip:input_type -> adapter.Empty
-	13, // 30: adapter.Adapter.Ack:input_type -> adapter.AckMessage
-	19, // 31: adapter.Adapter.AtDock:input_type -> adapter.Empty
-	19, // 32: adapter.Adapter.OnBoat:input_type -> adapter.Empty
-	19, // 33: adapter.Adapter.OffBoat:input_type -> adapter.Empty
-	17, // 34: adapter.Adapter.BoatStatus:input_type -> adapter.BoatStatusMessage
-	18, // 35: adapter.Adapter.Arrived:input_type -> adapter.ArrivedMessage
-	12, // 36: adapter.Adapter.ReserveTrip:output_type -> adapter.ReserveTripMessage
-	19, // 37: adapter.Adapter.Ack:output_type -> adapter.Empty
-	14, // 38: adapter.Adapter.AtDock:output_type -> adapter.AtDockMessage
-	15, // 39: adapter.Adapter.OnBoat:output_type -> adapter.OnBoatMessage
-	16, // 40: adapter.Adapter.OffBoat:output_type -> adapter.OffBoatMessage
-	19, // 41: adapter.Adapter.BoatStatus:output_type -> adapter.Empty
-	19, // 42: adapter.Adapter.Arrived:output_type -> adapter.Empty
-	36, // [36:43] is the sub-list for method output_type
-	29, // [29:36] is the sub-list for method input_type
-	29, // [29:29] is the sub-list for extension type_name
-	29, // [29:29] is the sub-list for extension extendee
-	0,  // [0:29] is the sub-list for field type_name
+	2,  // 0: adapter.Dock.address:type_name -> adapter.Address
+	3,  // 1: adapter.ReserveTripAPIMessage.source_dock:type_name -> adapter.Dock
+	3,  // 2: adapter.ReserveTripAPIMessage.destination_dock:type_name -> adapter.Dock
+	37, // 3: adapter.ReserveTripAPIMessage.departure_time:type_name -> google.protobuf.Timestamp
+	4,  // 4: adapter.AckAPIMessage.boat:type_name -> adapter.Boat
+	1,  // 5: adapter.AckAPIMessage.reason_code:type_name -> adapter.ErrorReason
+	37, // 6: adapter.AckAPIMessage.source_eta:type_name -> google.protobuf.Timestamp
+	37, // 7: adapter.AckAPIMessage.destination_eta:type_name -> google.protobuf.Timestamp
+	37, // 8: adapter.AckAPIMessage.departure_time:type_name -> google.protobuf.Timestamp
+	4,  // 9: adapter.AtDockAPIMessage.boat:type_name -> adapter.Boat
+	3,  // 10: adapter.AtDockAPIMessage.dock:type_name -> adapter.Dock
+	4,  // 11: adapter.OnBoatAPIMessage.boat:type_name -> adapter.Boat
+	4,  // 12: adapter.OffBoatAPIMessage.boat:type_name -> adapter.Boat
+	1,  // 13: adapter.CancelAckAPIMessage.reason_code:type_name -> adapter.ErrorReason
+	4,  // 14: adapter.BoatStatusAPIMessage.boat:type_name -> adapter.Boat
+	0,  // 15: adapter.BoatStatusAPIMessage.service_state:type_name -> adapter.ServiceState
+	3,  // 16: adapter.BoatStatusAPIMessage.previous_dock:type_name -> adapter.Dock
+	3,  // 17: adapter.BoatStatusAPIMessage.current_dock:type_name -> adapter.Dock
+	3,  // 18: adapter.BoatStatusAPIMessage.next_dock:type_name -> adapter.Dock
+	1,  // 19: adapter.TripStatusAPIMessage.reason_code:type_name -> adapter.ErrorReason
+	3,  // 20: adapter.TripStatusAPIMessage.source_dock:type_name -> adapter.Dock
+	3,  // 21: adapter.TripStatusAPIMessage.destination_dock:type_name -> adapter.Dock
+	4,  // 22: adapter.TripStatusAPIMessage.boat:type_name -> adapter.Boat
+	14, // 23: adapter.TripStatusAPIMessage.boat_status:type_name -> adapter.BoatStatusAPIMessage
+	37, // 24: adapter.TripStatusAPIMessage.source_eta:type_name -> google.protobuf.Timestamp
+	37, // 25: adapter.TripStatusAPIMessage.destination_eta:type_name -> google.protobuf.Timestamp
+	4,  // 26: adapter.ArrivedAPIMessage.boat:type_name -> adapter.Boat
+	3,  // 27: adapter.ArrivedAPIMessage.dock:type_name -> adapter.Dock
+	4,  // 28: adapter.TripProgressAPIMessage.boat:type_name -> adapter.Boat
+	37, // 29: adapter.TripProgressAPIMessage.source_eta:type_name -> google.protobuf.Timestamp
+	37, // 30: adapter.TripProgressAPIMessage.destination_eta:type_name -> google.protobuf.Timestamp
+	1,  // 31: adapter.ErrorAPIMessage.reason_code:type_name -> adapter.ErrorReason
+	6,  // 32: adapter.ReserveTripMessage.api_message:type_name -> adapter.ReserveTripAPIMessage
+	5,  // 33: adapter.ReserveTripMessage.client_data:type_name -> adapter.ClientData
+	7,  // 34: adapter.AckMessage.api_message:type_name -> adapter.AckAPIMessage
+	5,  // 35: adapter.AckMessage.client_data:type_name -> adapter.ClientData
+	8,  // 36: adapter.AtDockMessage.api_message:type_name -> adapter.AtDockAPIMessage
+	5,  // 37: adapter.AtDockMessage.client_data:type_name -> adapter.ClientData
+	9,  // 38: adapter.OnBoatMessage.api_message:type_name -> adapter.OnBoatAPIMessage
+	5,  // 39: adapter.OnBoatMessage.client_data:type_name -> adapter.ClientData
+	10, // 40: adapter.OffBoatMessage.api_message:type_name -> adapter.OffBoatAPIMessage
+	5,  // 41: adapter.OffBoatMessage.client_data:type_name -> adapter.ClientData
+	11, // 42: adapter.CancelTripMessage.api_message:type_name -> adapter.CancelTripAPIMessage
+	5,  // 43: adapter.CancelTripMessage.client_data:type_name -> adapter.ClientData
+	12, // 44: adapter.CancelAckMessage.api_message:type_name -> adapter.CancelAckAPIMessage
+	5,  // 45: adapter.CancelAckMessage.client_data:type_name -> adapter.ClientData
+	13, // 46: adapter.GetTripMessage.api_message:type_name -> adapter.GetTripAPIMessage
+	5,  // 47: adapter.GetTripMessage.client_data:type_name -> adapter.ClientData
+	15, // 48: adapter.TripStatusMessage.api_message:type_name -> adapter.TripStatusAPIMessage
+	5,  // 49: adapter.TripStatusMessage.client_data:type_name -> adapter.ClientData
+	14, // 50: adapter.BoatStatusMessage.api_message:type_name -> adapter.BoatStatusAPIMessage
+	5,  // 51: adapter.BoatStatusMessage.client_data:type_name -> adapter.ClientData
+	16, // 52: adapter.ArrivedMessage.api_message:type_name -> adapter.ArrivedAPIMessage
+	5,  // 53: adapter.ArrivedMessage.client_data:type_name -> adapter.ClientData
+	17, // 54: adapter.TripProgressMessage.api_message:type_name -> adapter.TripProgressAPIMessage
+	5,  // 55: adapter.TripProgressMessage.client_data:type_name -> adapter.ClientData
+	18, // 56: adapter.ErrorMessage.api_message:type_name -> adapter.ErrorAPIMessage
+	5,  // 57: adapter.ErrorMessage.client_data:type_name -> adapter.ClientData
+	19, // 58: adapter.AdapterEnvelope.reserve_trip:type_name -> adapter.ReserveTripMessage
+	21, // 59: adapter.AdapterEnvelope.at_dock:type_name -> adapter.AtDockMessage
+	22, // 60: adapter.AdapterEnvelope.on_boat:type_name -> adapter.OnBoatMessage
+	23, // 61: adapter.AdapterEnvelope.off_boat:type_name -> adapter.OffBoatMessage
+	24, // 62: adapter.AdapterEnvelope.cancel_trip:type_name -> adapter.CancelTripMessage
+	26, // 63: adapter.AdapterEnvelope.get_trip:type_name -> adapter.GetTripMessage
+	32, // 64: adapter.AdapterEnvelope.receipt:type_name -> adapter.Receipt
+	20, // 65: adapter.MockLogicEnvelope.ack:type_name -> adapter.AckMessage
+	25, // 66: adapter.MockLogicEnvelope.cancel_ack:type_name -> adapter.CancelAckMessage
+	27, // 67: adapter.MockLogicEnvelope.trip_status:type_name -> adapter.TripStatusMessage
+	28, // 68: adapter.MockLogicEnvelope.boat_status:type_name -> adapter.BoatStatusMessage
+	29, // 69: adapter.MockLogicEnvelope.arrived:type_name -> adapter.ArrivedMessage
+	30, // 70: adapter.MockLogicEnvelope.trip_progress:type_name -> adapter.TripProgressMessage
+	31, // 71: adapter.MockLogicEnvelope.error:type_name -> adapter.ErrorMessage
+	32, // 72: adapter.MockLogicEnvelope.receipt:type_name -> adapter.Receipt
+	35, // 73: adapter.Adapter.Handshake:input_type -> adapter.Hello
+	34, // 74: adapter.Adapter.Session:input_type -> adapter.MockLogicEnvelope
+	32, // 75: adapter.Adapter.ReserveTrip:input_type -> adapter.Receipt
+	20, // 76: adapter.Adapter.Ack:input_type -> adapter.AckMessage
+	32, // 77: adapter.Adapter.AtDock:input_type -> adapter.Receipt
+	32, // 78: adapter.Adapter.OnBoat:input_type -> adapter.Receipt
+	32, // 79: adapter.Adapter.OffBoat:input_type -> adapter.Receipt
+	32, // 80: adapter.Adapter.CancelTrip:input_type -> adapter.Receipt
+	25, // 81: adapter.Adapter.CancelAck:input_type -> adapter.CancelAckMessage
+	32, // 82: adapter.Adapter.GetTrip:input_type -> adapter.Receipt
+	27, // 83: adapter.Adapter.TripStatus:input_type -> adapter.TripStatusMessage
+	28, // 84: adapter.Adapter.BoatStatus:input_type -> adapter.BoatStatusMessage
+	29, // 85: adapter.Adapter.Arrived:input_type -> adapter.ArrivedMessage
+	30, // 86: adapter.Adapter.TripProgress:input_type -> adapter.TripProgressMessage
+	31, // 87: adapter.Adapter.Error:input_type -> adapter.ErrorMessage
+	36, // 88: adapter.Adapter.Handshake:output_type -> adapter.Welcome
+	33, // 89: adapter.Adapter.Session:output_type -> adapter.AdapterEnvelope
+	19, // 90: adapter.Adapter.ReserveTrip:output_type -> adapter.ReserveTripMessage
+	32, // 91: adapter.Adapter.Ack:output_type -> adapter.Receipt
+	21, // 92: adapter.Adapter.AtDock:output_type -> adapter.AtDockMessage
+	22, // 93: adapter.Adapter.OnBoat:output_type -> adapter.OnBoatMessage
+	23, // 94: adapter.Adapter.OffBoat:output_type -> adapter.OffBoatMessage
+	24, // 95: adapter.Adapter.CancelTrip:output_type -> adapter.CancelTripMessage
+	32, // 96: adapter.Adapter.CancelAck:output_type -> adapter.Receipt
+	26, // 97: adapter.Adapter.GetTrip:output_type -> adapter.GetTripMessage
+	32, // 98: adapter.Adapter.TripStatus:output_type -> adapter.Receipt
+	32, // 99: adapter.Adapter.BoatStatus:output_type -> adapter.Receipt
+	32, // 100: adapter.Adapter.Arrived:output_type -> adapter.Receipt
+	32, // 101: adapter.Adapter.TripProgress:output_type -> adapter.Receipt
+	32, // 102: adapter.Adapter.Error:output_type -> adapter.Receipt
+	88, // [88:103] is the sub-list for method output_type
+	73, // [73:88] is the sub-list for method input_type
+	73, // [73:73] is the sub-list for extension type_name
+	73, // [73:73] is the sub-list for extension extendee
+	0,  // [0:73] is the sub-list for field type_name
 }
 
 func init() { file_proto_adapter_proto_init() }
@@ -1458,13 +3547,32 @@ func file_proto_adapter_proto_init() {
 	if File_proto_adapter_proto != nil {
 		return
 	}
+	file_proto_adapter_proto_msgTypes[31].OneofWrappers = []any{
+		(*AdapterEnvelope_ReserveTrip)(nil),
+		(*AdapterEnvelope_AtDock)(nil),
+		(*AdapterEnvelope_OnBoat)(nil),
+		(*AdapterEnvelope_OffBoat)(nil),
+		(*AdapterEnvelope_CancelTrip)(nil),
+		(*AdapterEnvelope_GetTrip)(nil),
+		(*AdapterEnvelope_Receipt)(nil),
+	}
+	file_proto_adapter_proto_msgTypes[32].OneofWrappers = []any{
+		(*MockLogicEnvelope_Ack)(nil),
+		(*MockLogicEnvelope_CancelAck)(nil),
+		(*MockLogicEnvelope_TripStatus)(nil),
+		(*MockLogicEnvelope_BoatStatus)(nil),
+		(*MockLogicEnvelope_Arrived)(nil),
+		(*MockLogicEnvelope_TripProgress)(nil),
+		(*MockLogicEnvelope_Error)(nil),
+		(*MockLogicEnvelope_Receipt)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_adapter_proto_rawDesc), len(file_proto_adapter_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   19,
+			NumEnums:      2,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,13 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Adapter_ReserveTrip_FullMethodName = "/adapter.Adapter/ReserveTrip"
-	Adapter_Ack_FullMethodName         = "/adapter.Adapter/Ack"
-	Adapter_AtDock_FullMethodName      = "/adapter.Adapter/AtDock"
-	Adapter_OnBoat_FullMethodName      = "/adapter.Adapter/OnBoat"
-	Adapter_OffBoat_FullMethodName     = "/adapter.Adapter/OffBoat"
-	Adapter_BoatStatus_FullMethodName  = "/adapter.Adapter/BoatStatus"
-	Adapter_Arrived_FullMethodName     = "/adapter.Adapter/Arrived"
+	Adapter_Handshake_FullMethodName    = "/adapter.Adapter/Handshake"
+	Adapter_Session_FullMethodName      = "/adapter.Adapter/Session"
+	Adapter_ReserveTrip_FullMethodName  = "/adapter.Adapter/ReserveTrip"
+	Adapter_Ack_FullMethodName          = "/adapter.Adapter/Ack"
+	Adapter_AtDock_FullMethodName       = "/adapter.Adapter/AtDock"
+	Adapter_OnBoat_FullMethodName       = "/adapter.Adapter/OnBoat"
+	Adapter_OffBoat_FullMethodName      = "/adapter.Adapter/OffBoat"
+	Adapter_CancelTrip_FullMethodName   = "/adapter.Adapter/CancelTrip"
+	Adapter_CancelAck_FullMethodName    = "/adapter.Adapter/CancelAck"
+	Adapter_GetTrip_FullMethodName      = "/adapter.Adapter/GetTrip"
+	Adapter_TripStatus_FullMethodName   = "/adapter.Adapter/TripStatus"
+	Adapter_BoatStatus_FullMethodName   = "/adapter.Adapter/BoatStatus"
+	Adapter_Arrived_FullMethodName      = "/adapter.Adapter/Arrived"
+	Adapter_TripProgress_FullMethodName = "/adapter.Adapter/TripProgress"
+	Adapter_Error_FullMethodName        = "/adapter.Adapter/Error"
 )
 
 // AdapterClient is the client API for Adapter service.
@@ -34,30 +42,61 @@ const (
 //
 // The Adapter service definition
 type AdapterClient interface {
+	// A unary RPC.
+	// The MockLogic(gRPC client) introduces itself with a Hello before it opens the
+	// streams, and the Adapter replies with a Welcome that accepts the MockLogic if
+	// the protocol version and message types are compatible
+	Handshake(ctx context.Context, in *Hello, opts ...grpc.CallOption) (*Welcome, error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends the messages for the API clients, and receives
+	// the messages that the API clients have sent, on a single stream
+	Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MockLogicEnvelope, AdapterEnvelope], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives a Reserve request that an API client has sent
-	ReserveTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, ReserveTripMessage], error)
+	ReserveTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, ReserveTripMessage], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) sends an Acknowledgement that a previously sent
 	// ReserveMessage is being handled
-	Ack(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AckMessage, Empty], error)
+	Ack(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AckMessage, Receipt], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives an AtDock message that an API client has sent
-	AtDock(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, AtDockMessage], error)
+	AtDock(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, AtDockMessage], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives an OnBoat message that an API client has sent
-	OnBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, OnBoatMessage], error)
+	OnBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, OnBoatMessage], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives an OffBoat message that an API client has sent
-	OffBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, OffBoatMessage], error)
+	OffBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, OffBoatMessage], error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) receives a CancelTrip message that an API client has sent
+	CancelTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, CancelTripMessage], error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends a CancelAck message that the Adapter will send
+	// to the API client as a reply to a CancelTrip message
+	CancelAck(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CancelAckMessage, Receipt], error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) receives a GetTrip message that an API client has sent
+	GetTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, GetTripMessage], error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends a TripStatus message that the Adapter will send
+	// to the API client as a reply to a GetTrip message
+	TripStatus(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TripStatusMessage, Receipt], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) sends an BoatStatus message that the Adapter will
 	// broadcast
-	BoatStatus(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BoatStatusMessage, Empty], error)
+	BoatStatus(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BoatStatusMessage, Receipt], error)
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) sends an Arrived message that the Adapter will send
 	// to the API client
-	Arrived(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ArrivedMessage, Empty], error)
+	Arrived(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ArrivedMessage, Receipt], error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends a TripProgress message that the Adapter will
+	// send to the API client
+	TripProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TripProgressMessage, Receipt], error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends an Error message that the Adapter will send
+	// to the API client
+	Error(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ErrorMessage, Receipt], error)
 }
 
 type adapterClient struct {
@@ -68,96 +107,197 @@ func NewAdapterClient(cc grpc.ClientConnInterface) AdapterClient {
 	return &adapterClient{cc}
 }
 
-func (c *adapterClient) ReserveTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, ReserveTripMessage], error) {
+func (c *adapterClient) Handshake(ctx context.Context, in *Hello, opts ...grpc.CallOption) (*Welcome, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Welcome)
+	err := c.cc.Invoke(ctx, Adapter_Handshake_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adapterClient) Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MockLogicEnvelope, AdapterEnvelope], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[0], Adapter_Session_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MockLogicEnvelope, AdapterEnvelope]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_SessionClient = grpc.BidiStreamingClient[MockLogicEnvelope, AdapterEnvelope]
+
+func (c *adapterClient) ReserveTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, ReserveTripMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[1], Adapter_ReserveTrip_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Receipt, ReserveTripMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_ReserveTripClient = grpc.BidiStreamingClient[Receipt, ReserveTripMessage]
+
+func (c *adapterClient) Ack(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AckMessage, Receipt], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[2], Adapter_Ack_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AckMessage, Receipt]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_AckClient = grpc.BidiStreamingClient[AckMessage, Receipt]
+
+func (c *adapterClient) AtDock(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, AtDockMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[3], Adapter_AtDock_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Receipt, AtDockMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_AtDockClient = grpc.BidiStreamingClient[Receipt, AtDockMessage]
+
+func (c *adapterClient) OnBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, OnBoatMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[4], Adapter_OnBoat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Receipt, OnBoatMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_OnBoatClient = grpc.BidiStreamingClient[Receipt, OnBoatMessage]
+
+func (c *adapterClient) OffBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, OffBoatMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[5], Adapter_OffBoat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Receipt, OffBoatMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_OffBoatClient = grpc.BidiStreamingClient[Receipt, OffBoatMessage]
+
+func (c *adapterClient) CancelTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, CancelTripMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[6], Adapter_CancelTrip_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Receipt, CancelTripMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_CancelTripClient = grpc.BidiStreamingClient[Receipt, CancelTripMessage]
+
+func (c *adapterClient) CancelAck(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CancelAckMessage, Receipt], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[0], Adapter_ReserveTrip_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[7], Adapter_CancelAck_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, ReserveTripMessage]{ClientStream: stream}
+	x := &grpc.GenericClientStream[CancelAckMessage, Receipt]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_ReserveTripClient = grpc.BidiStreamingClient[Empty, ReserveTripMessage]
+type Adapter_CancelAckClient = grpc.BidiStreamingClient[CancelAckMessage, Receipt]
 
-func (c *adapterClient) Ack(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AckMessage, Empty], error) {
+func (c *adapterClient) GetTrip(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Receipt, GetTripMessage], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[1], Adapter_Ack_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[8], Adapter_GetTrip_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[AckMessage, Empty]{ClientStream: stream}
+	x := &grpc.GenericClientStream[Receipt, GetTripMessage]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_AckClient = grpc.BidiStreamingClient[AckMessage, Empty]
+type Adapter_GetTripClient = grpc.BidiStreamingClient[Receipt, GetTripMessage]
 
-func (c *adapterClient) AtDock(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, AtDockMessage], error) {
+func (c *adapterClient) TripStatus(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TripStatusMessage, Receipt], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[2], Adapter_AtDock_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[9], Adapter_TripStatus_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, AtDockMessage]{ClientStream: stream}
+	x := &grpc.GenericClientStream[TripStatusMessage, Receipt]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_AtDockClient = grpc.BidiStreamingClient[Empty, AtDockMessage]
+type Adapter_TripStatusClient = grpc.BidiStreamingClient[TripStatusMessage, Receipt]
 
-func (c *adapterClient) OnBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, OnBoatMessage], error) {
+func (c *adapterClient) BoatStatus(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BoatStatusMessage, Receipt], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[3], Adapter_OnBoat_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[10], Adapter_BoatStatus_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, OnBoatMessage]{ClientStream: stream}
+	x := &grpc.GenericClientStream[BoatStatusMessage, Receipt]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_OnBoatClient = grpc.BidiStreamingClient[Empty, OnBoatMessage]
+type Adapter_BoatStatusClient = grpc.BidiStreamingClient[BoatStatusMessage, Receipt]
 
-func (c *adapterClient) OffBoat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Empty, OffBoatMessage], error) {
+func (c *adapterClient) Arrived(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ArrivedMessage, Receipt], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[4], Adapter_OffBoat_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[11], Adapter_Arrived_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, OffBoatMessage]{ClientStream: stream}
+	x := &grpc.GenericClientStream[ArrivedMessage, Receipt]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_OffBoatClient = grpc.BidiStreamingClient[Empty, OffBoatMessage]
+type Adapter_ArrivedClient = grpc.BidiStreamingClient[ArrivedMessage, Receipt]
 
-func (c *adapterClient) BoatStatus(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BoatStatusMessage, Empty], error) {
+func (c *adapterClient) TripProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TripProgressMessage, Receipt], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[5], Adapter_BoatStatus_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[12], Adapter_TripProgress_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[BoatStatusMessage, Empty]{ClientStream: stream}
+	x := &grpc.GenericClientStream[TripProgressMessage, Receipt]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_BoatStatusClient = grpc.BidiStreamingClient[BoatStatusMessage, Empty]
+type Adapter_TripProgressClient = grpc.BidiStreamingClient[TripProgressMessage, Receipt]
 
-func (c *adapterClient) Arrived(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ArrivedMessage, Empty], error) {
+func (c *adapterClient) Error(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ErrorMessage, Receipt], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[6], Adapter_Arrived_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Adapter_ServiceDesc.Streams[13], Adapter_Error_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[ArrivedMessage, Empty]{ClientStream: stream}
+	x := &grpc.GenericClientStream[ErrorMessage, Receipt]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_ArrivedClient = grpc.BidiStreamingClient[ArrivedMessage, Empty]
+type Adapter_ErrorClient = grpc.BidiStreamingClient[ErrorMessage, Receipt]
 
 // AdapterServer is the server API for Adapter service.
 // All implementations must embed UnimplementedAdapterServer
@@ -165,30 +305,61 @@ type Adapter_ArrivedClient = grpc.BidiStreamingClient[ArrivedMessage, Empty]
 //
 // The Adapter service definition
 type AdapterServer interface {
+	// A unary RPC.
+	// The MockLogic(gRPC client) introduces itself with a Hello before it opens the
+	// streams, and the Adapter replies with a Welcome that accepts the MockLogic if
+	// the protocol version and message types are compatible
+	Handshake(context.Context, *Hello) (*Welcome, error)
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends the messages for the API clients, and receives
+	// the messages that the API clients have sent, on a single stream
+	Session(grpc.BidiStreamingServer[MockLogicEnvelope, AdapterEnvelope]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives a Reserve request that an API client has sent
-	ReserveTrip(grpc.BidiStreamingServer[Empty, ReserveTripMessage]) error
+	ReserveTrip(grpc.BidiStreamingServer[Receipt, ReserveTripMessage]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) sends an Acknowledgement that a previously sent
 	// ReserveMessage is being handled
-	Ack(grpc.BidiStreamingServer[AckMessage, Empty]) error
+	Ack(grpc.BidiStreamingServer[AckMessage, Receipt]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives an AtDock message that an API client has sent
-	AtDock(grpc.BidiStreamingServer[Empty, AtDockMessage]) error
+	AtDock(grpc.BidiStreamingServer[Receipt, AtDockMessage]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives an OnBoat message that an API client has sent
-	OnBoat(grpc.BidiStreamingServer[Empty, OnBoatMessage]) error
+	OnBoat(grpc.BidiStreamingServer[Receipt, OnBoatMessage]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) receives an OffBoat message that an API client has sent
-	OffBoat(grpc.BidiStreamingServer[Empty, OffBoatMessage]) error
+	OffBoat(grpc.BidiStreamingServer[Receipt, OffBoatMessage]) error
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) receives a CancelTrip message that an API client has sent
+	CancelTrip(grpc.BidiStreamingServer[Receipt, CancelTripMessage]) error
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends a CancelAck message that the Adapter will send
+	// to the API client as a reply to a CancelTrip message
+	CancelAck(grpc.BidiStreamingServer[CancelAckMessage, Receipt]) error
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) receives a GetTrip message that an API client has sent
+	GetTrip(grpc.BidiStreamingServer[Receipt, GetTripMessage]) error
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends a TripStatus message that the Adapter will send
+	// to the API client as a reply to a GetTrip message
+	TripStatus(grpc.BidiStreamingServer[TripStatusMessage, Receipt]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) sends an BoatStatus message that the Adapter will
 	// broadcast
-	BoatStatus(grpc.BidiStreamingServer[BoatStatusMessage, Empty]) error
+	BoatStatus(grpc.BidiStreamingServer[BoatStatusMessage, Receipt]) error
 	// A bi-directional streaming RPC.
 	// The MockLogic(gRPC client) sends an Arrived message that the Adapter will send
 	// to the API client
-	Arrived(grpc.BidiStreamingServer[ArrivedMessage, Empty]) error
+	Arrived(grpc.BidiStreamingServer[ArrivedMessage, Receipt]) error
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends a TripProgress message that the Adapter will
+	// send to the API client
+	TripProgress(grpc.BidiStreamingServer[TripProgressMessage, Receipt]) error
+	// A bi-directional streaming RPC.
+	// The MockLogic(gRPC client) sends an Error message that the Adapter will send
+	// to the API client
+	Error(grpc.BidiStreamingServer[ErrorMessage, Receipt]) error
 	mustEmbedUnimplementedAdapterServer()
 }
 
@@ -199,27 +370,51 @@ type AdapterServer interface {
 // pointer dereference when methods are called.
 type UnimplementedAdapterServer struct{}
 
-func (UnimplementedAdapterServer) ReserveTrip(grpc.BidiStreamingServer[Empty, ReserveTripMessage]) error {
+func (UnimplementedAdapterServer) Handshake(context.Context, *Hello) (*Welcome, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Handshake not implemented")
+}
+func (UnimplementedAdapterServer) Session(grpc.BidiStreamingServer[MockLogicEnvelope, AdapterEnvelope]) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedAdapterServer) ReserveTrip(grpc.BidiStreamingServer[Receipt, ReserveTripMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method ReserveTrip not implemented")
 }
-func (UnimplementedAdapterServer) Ack(grpc.BidiStreamingServer[AckMessage, Empty]) error {
+func (UnimplementedAdapterServer) Ack(grpc.BidiStreamingServer[AckMessage, Receipt]) error {
 	return status.Errorf(codes.Unimplemented, "method Ack not implemented")
 }
-func (UnimplementedAdapterServer) AtDock(grpc.BidiStreamingServer[Empty, AtDockMessage]) error {
+func (UnimplementedAdapterServer) AtDock(grpc.BidiStreamingServer[Receipt, AtDockMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method AtDock not implemented")
 }
-func (UnimplementedAdapterServer) OnBoat(grpc.BidiStreamingServer[Empty, OnBoatMessage]) error {
+func (UnimplementedAdapterServer) OnBoat(grpc.BidiStreamingServer[Receipt, OnBoatMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method OnBoat not implemented")
 }
-func (UnimplementedAdapterServer) OffBoat(grpc.BidiStreamingServer[Empty, OffBoatMessage]) error {
+func (UnimplementedAdapterServer) OffBoat(grpc.BidiStreamingServer[Receipt, OffBoatMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method OffBoat not implemented")
 }
-func (UnimplementedAdapterServer) BoatStatus(grpc.BidiStreamingServer[BoatStatusMessage, Empty]) error {
+func (UnimplementedAdapterServer) CancelTrip(grpc.BidiStreamingServer[Receipt, CancelTripMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method CancelTrip not implemented")
+}
+func (UnimplementedAdapterServer) CancelAck(grpc.BidiStreamingServer[CancelAckMessage, Receipt]) error {
+	return status.Errorf(codes.Unimplemented, "method CancelAck not implemented")
+}
+func (UnimplementedAdapterServer) GetTrip(grpc.BidiStreamingServer[Receipt, GetTripMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method GetTrip not implemented")
+}
+func (UnimplementedAdapterServer) TripStatus(grpc.BidiStreamingServer[TripStatusMessage, Receipt]) error {
+	return status.Errorf(codes.Unimplemented, "method TripStatus not implemented")
+}
+func (UnimplementedAdapterServer) BoatStatus(grpc.BidiStreamingServer[BoatStatusMessage, Receipt]) error {
 	return status.Errorf(codes.Unimplemented, "method BoatStatus not implemented")
 }
-func (UnimplementedAdapterServer) Arrived(grpc.BidiStreamingServer[ArrivedMessage, Empty]) error {
+func (UnimplementedAdapterServer) Arrived(grpc.BidiStreamingServer[ArrivedMessage, Receipt]) error {
 	return status.Errorf(codes.Unimplemented, "method Arrived not implemented")
 }
+func (UnimplementedAdapterServer) TripProgress(grpc.BidiStreamingServer[TripProgressMessage, Receipt]) error {
+	return status.Errorf(codes.Unimplemented, "method TripProgress not implemented")
+}
+func (UnimplementedAdapterServer) Error(grpc.BidiStreamingServer[ErrorMessage, Receipt]) error {
+	return status.Errorf(codes.Unimplemented, "method Error not implemented")
+}
 func (UnimplementedAdapterServer) mustEmbedUnimplementedAdapterServer() {}
 func (UnimplementedAdapterServer) testEmbeddedByValue()                 {}
 
@@ -241,54 +436,121 @@ func RegisterAdapterServer(s grpc.ServiceRegistrar, srv AdapterServer) {
 	s.RegisterService(&Adapter_ServiceDesc, srv)
 }
 
+func _Adapter_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hello)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdapterServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Adapter_Handshake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdapterServer).Handshake(ctx, req.(*Hello))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Adapter_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).Session(&grpc.GenericServerStream[MockLogicEnvelope, AdapterEnvelope]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_SessionServer = grpc.BidiStreamingServer[MockLogicEnvelope, AdapterEnvelope]
+
 func _Adapter_ReserveTrip_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).ReserveTrip(&grpc.GenericServerStream[Empty, ReserveTripMessage]{ServerStream: stream})
+	return srv.(AdapterServer).ReserveTrip(&grpc.GenericServerStream[Receipt, ReserveTripMessage]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_ReserveTripServer = grpc.BidiStreamingServer[Empty, ReserveTripMessage]
+type Adapter_ReserveTripServer = grpc.BidiStreamingServer[Receipt, ReserveTripMessage]
 
 func _Adapter_Ack_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).Ack(&grpc.GenericServerStream[AckMessage, Empty]{ServerStream: stream})
+	return srv.(AdapterServer).Ack(&grpc.GenericServerStream[AckMessage, Receipt]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_AckServer = grpc.BidiStreamingServer[AckMessage, Empty]
+type Adapter_AckServer = grpc.BidiStreamingServer[AckMessage, Receipt]
 
 func _Adapter_AtDock_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).AtDock(&grpc.GenericServerStream[Empty, AtDockMessage]{ServerStream: stream})
+	return srv.(AdapterServer).AtDock(&grpc.GenericServerStream[Receipt, AtDockMessage]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_AtDockServer = grpc.BidiStreamingServer[Empty, AtDockMessage]
+type Adapter_AtDockServer = grpc.BidiStreamingServer[Receipt, AtDockMessage]
 
 func _Adapter_OnBoat_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).OnBoat(&grpc.GenericServerStream[Empty, OnBoatMessage]{ServerStream: stream})
+	return srv.(AdapterServer).OnBoat(&grpc.GenericServerStream[Receipt, OnBoatMessage]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_OnBoatServer = grpc.BidiStreamingServer[Empty, OnBoatMessage]
+type Adapter_OnBoatServer = grpc.BidiStreamingServer[Receipt, OnBoatMessage]
 
 func _Adapter_OffBoat_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).OffBoat(&grpc.GenericServerStream[Empty, OffBoatMessage]{ServerStream: stream})
+	return srv.(AdapterServer).OffBoat(&grpc.GenericServerStream[Receipt, OffBoatMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_OffBoatServer = grpc.BidiStreamingServer[Receipt, OffBoatMessage]
+
+func _Adapter_CancelTrip_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).CancelTrip(&grpc.GenericServerStream[Receipt, CancelTripMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_CancelTripServer = grpc.BidiStreamingServer[Receipt, CancelTripMessage]
+
+func _Adapter_CancelAck_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).CancelAck(&grpc.GenericServerStream[CancelAckMessage, Receipt]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_CancelAckServer = grpc.BidiStreamingServer[CancelAckMessage, Receipt]
+
+func _Adapter_GetTrip_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).GetTrip(&grpc.GenericServerStream[Receipt, GetTripMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_GetTripServer = grpc.BidiStreamingServer[Receipt, GetTripMessage]
+
+func _Adapter_TripStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).TripStatus(&grpc.GenericServerStream[TripStatusMessage, Receipt]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_OffBoatServer = grpc.BidiStreamingServer[Empty, OffBoatMessage]
+type Adapter_TripStatusServer = grpc.BidiStreamingServer[TripStatusMessage, Receipt]
 
 func _Adapter_BoatStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).BoatStatus(&grpc.GenericServerStream[BoatStatusMessage, Empty]{ServerStream: stream})
+	return srv.(AdapterServer).BoatStatus(&grpc.GenericServerStream[BoatStatusMessage, Receipt]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_BoatStatusServer = grpc.BidiStreamingServer[BoatStatusMessage, Empty]
+type Adapter_BoatStatusServer = grpc.BidiStreamingServer[BoatStatusMessage, Receipt]
 
 func _Adapter_Arrived_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AdapterServer).Arrived(&grpc.GenericServerStream[ArrivedMessage, Empty]{ServerStream: stream})
+	return srv.(AdapterServer).Arrived(&grpc.GenericServerStream[ArrivedMessage, Receipt]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Adapter_ArrivedServer = grpc.BidiStreamingServer[ArrivedMessage, Empty]
+type Adapter_ArrivedServer = grpc.BidiStreamingServer[ArrivedMessage, Receipt]
+
+func _Adapter_TripProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).TripProgress(&grpc.GenericServerStream[TripProgressMessage, Receipt]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_TripProgressServer = grpc.BidiStreamingServer[TripProgressMessage, Receipt]
+
+func _Adapter_Error_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdapterServer).Error(&grpc.GenericServerStream[ErrorMessage, Receipt]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Adapter_ErrorServer = grpc.BidiStreamingServer[ErrorMessage, Receipt]
 
 // Adapter_ServiceDesc is the grpc.ServiceDesc for Adapter service.
 // It's only intended for direct use with grpc.RegisterService,
@@ -296,8 +558,19 @@ type Adapter_ArrivedServer = grpc.BidiStreamingServer[ArrivedMessage, Empty]
 var Adapter_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "adapter.Adapter",
 	HandlerType: (*AdapterServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    _Adapter_Handshake_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Session",
+			Handler:       _Adapter_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "ReserveTrip",
 			Handler:       _Adapter_ReserveTrip_Handler,
@@ -328,6 +601,30 @@ var Adapter_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "CancelTrip",
+			Handler:       _Adapter_CancelTrip_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "CancelAck",
+			Handler:       _Adapter_CancelAck_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetTrip",
+			Handler:       _Adapter_GetTrip_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TripStatus",
+			Handler:       _Adapter_TripStatus_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "BoatStatus",
 			Handler:       _Adapter_BoatStatus_Handler,
@@ -340,6 +637,18 @@ var Adapter_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "TripProgress",
+			Handler:       _Adapter_TripProgress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Error",
+			Handler:       _Adapter_Error_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/adapter.proto",
 }
@@ -0,0 +1,431 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: proto/simcontrol.proto
+
+package adapter
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PauseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseRequest) Reset() {
+	*x = PauseRequest{}
+	mi := &file_proto_simcontrol_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseRequest) ProtoMessage() {}
+
+func (x *PauseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseRequest.ProtoReflect.Descriptor instead.
+func (*PauseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{0}
+}
+
+type ResumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeRequest) Reset() {
+	*x = ResumeRequest{}
+	mi := &file_proto_simcontrol_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeRequest) ProtoMessage() {}
+
+func (x *ResumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeRequest.ProtoReflect.Descriptor instead.
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{1}
+}
+
+type StepRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepRequest) Reset() {
+	*x = StepRequest{}
+	mi := &file_proto_simcontrol_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepRequest) ProtoMessage() {}
+
+func (x *StepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepRequest.ProtoReflect.Descriptor instead.
+func (*StepRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{2}
+}
+
+type SetSpeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Speed         float64                `protobuf:"fixed64,1,opt,name=speed,proto3" json:"speed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSpeedRequest) Reset() {
+	*x = SetSpeedRequest{}
+	mi := &file_proto_simcontrol_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSpeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSpeedRequest) ProtoMessage() {}
+
+func (x *SetSpeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSpeedRequest.ProtoReflect.Descriptor instead.
+func (*SetSpeedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetSpeedRequest) GetSpeed() float64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+type JumpToFrameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FrameIndex    int32                  `protobuf:"varint,1,opt,name=frame_index,json=frameIndex,proto3" json:"frame_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JumpToFrameRequest) Reset() {
+	*x = JumpToFrameRequest{}
+	mi := &file_proto_simcontrol_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JumpToFrameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JumpToFrameRequest) ProtoMessage() {}
+
+func (x *JumpToFrameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JumpToFrameRequest.ProtoReflect.Descriptor instead.
+func (*JumpToFrameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JumpToFrameRequest) GetFrameIndex() int32 {
+	if x != nil {
+		return x.FrameIndex
+	}
+	return 0
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_proto_simcontrol_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{5}
+}
+
+type SimStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The step of the simulation that was sent last, or -1 before the first frame
+	Step int64 `protobuf:"varint,1,opt,name=step,proto3" json:"step,omitempty"`
+	// The index of the frame of the last step, or -1 before the first frame
+	FrameIndex int32 `protobuf:"varint,2,opt,name=frame_index,json=frameIndex,proto3" json:"frame_index,omitempty"`
+	FrameCount int32 `protobuf:"varint,3,opt,name=frame_count,json=frameCount,proto3" json:"frame_count,omitempty"`
+	Paused     bool  `protobuf:"varint,4,opt,name=paused,proto3" json:"paused,omitempty"`
+	// The multiple of real time, or 0 if the clock cannot change speed
+	Speed         float64 `protobuf:"fixed64,5,opt,name=speed,proto3" json:"speed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimStatus) Reset() {
+	*x = SimStatus{}
+	mi := &file_proto_simcontrol_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimStatus) ProtoMessage() {}
+
+func (x *SimStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_simcontrol_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimStatus.ProtoReflect.Descriptor instead.
+func (*SimStatus) Descriptor() ([]byte, []int) {
+	return file_proto_simcontrol_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SimStatus) GetStep() int64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *SimStatus) GetFrameIndex() int32 {
+	if x != nil {
+		return x.FrameIndex
+	}
+	return 0
+}
+
+func (x *SimStatus) GetFrameCount() int32 {
+	if x != nil {
+		return x.FrameCount
+	}
+	return 0
+}
+
+func (x *SimStatus) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *SimStatus) GetSpeed() float64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+var File_proto_simcontrol_proto protoreflect.FileDescriptor
+
+const file_proto_simcontrol_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/simcontrol.proto\x12\n" +
+	"simcontrol\"\x0e\n" +
+	"\fPauseRequest\"\x0f\n" +
+	"\rResumeRequest\"\r\n" +
+	"\vStepRequest\"'\n" +
+	"\x0fSetSpeedRequest\x12\x14\n" +
+	"\x05speed\x18\x01 \x01(\x01R\x05speed\"5\n" +
+	"\x12JumpToFrameRequest\x12\x1f\n" +
+	"\vframe_index\x18\x01 \x01(\x05R\n" +
+	"frameIndex\"\x12\n" +
+	"\x10GetStatusRequest\"\x8f\x01\n" +
+	"\tSimStatus\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\x03R\x04step\x12\x1f\n" +
+	"\vframe_index\x18\x02 \x01(\x05R\n" +
+	"frameIndex\x12\x1f\n" +
+	"\vframe_count\x18\x03 \x01(\x05R\n" +
+	"frameCount\x12\x16\n" +
+	"\x06paused\x18\x04 \x01(\bR\x06paused\x12\x14\n" +
+	"\x05speed\x18\x05 \x01(\x01R\x05speed2\x8e\x03\n" +
+	"\n" +
+	"SimControl\x12:\n" +
+	"\x05Pause\x12\x18.simcontrol.PauseRequest\x1a\x15.simcontrol.SimStatus\"\x00\x12<\n" +
+	"\x06Resume\x12\x19.simcontrol.ResumeRequest\x1a\x15.simcontrol.SimStatus\"\x00\x128\n" +
+	"\x04Step\x12\x17.simcontrol.StepRequest\x1a\x15.simcontrol.SimStatus\"\x00\x12@\n" +
+	"\bSetSpeed\x12\x1b.simcontrol.SetSpeedRequest\x1a\x15.simcontrol.SimStatus\"\x00\x12F\n" +
+	"\vJumpToFrame\x12\x1e.simcontrol.JumpToFrameRequest\x1a\x15.simcontrol.SimStatus\"\x00\x12B\n" +
+	"\tGetStatus\x12\x1c.simcontrol.GetStatusRequest\x1a\x15.simcontrol.SimStatus\"\x00B\x17Z\x15riden/adapter/adapterb\x06proto3"
+
+var (
+	file_proto_simcontrol_proto_rawDescOnce sync.Once
+	file_proto_simcontrol_proto_rawDescData []byte
+)
+
+func file_proto_simcontrol_proto_rawDescGZIP() []byte {
+	file_proto_simcontrol_proto_rawDescOnce.Do(func() {
+		file_proto_simcontrol_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_simcontrol_proto_rawDesc), len(file_proto_simcontrol_proto_rawDesc)))
+	})
+	return file_proto_simcontrol_proto_rawDescData
+}
+
+var file_proto_simcontrol_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_simcontrol_proto_goTypes = []any{
+	(*PauseRequest)(nil),       // 0: simcontrol.PauseRequest
+	(*ResumeRequest)(nil),      // 1: simcontrol.ResumeRequest
+	(*StepRequest)(nil),        // 2: simcontrol.StepRequest
+	(*SetSpeedRequest)(nil),    // 3: simcontrol.SetSpeedRequest
+	(*JumpToFrameRequest)(nil), // 4: simcontrol.JumpToFrameRequest
+	(*GetStatusRequest)(nil),   // 5: simcontrol.GetStatusRequest
+	(*SimStatus)(nil),          // 6: simcontrol.SimStatus
+}
+var file_proto_simcontrol_proto_depIdxs = []int32{
+	0, // 0: simcontrol.SimControl.Pause:input_type -> simcontrol.PauseRequest
+	1, // 1: simcontrol.SimControl.Resume:input_type -> simcontrol.ResumeRequest
+	2, // 2: simcontrol.SimControl.Step:input_type -> simcontrol.StepRequest
+	3, // 3: simcontrol.SimControl.SetSpeed:input_type -> simcontrol.SetSpeedRequest
+	4, // 4: simcontrol.SimControl.JumpToFrame:input_type -> simcontrol.JumpToFrameRequest
+	5, // 5: simcontrol.SimControl.GetStatus:input_type -> simcontrol.GetStatusRequest
+	6, // 6: simcontrol.SimControl.Pause:output_type -> simcontrol.SimStatus
+	6, // 7: simcontrol.SimControl.Resume:output_type -> simcontrol.SimStatus
+	6, // 8: simcontrol.SimControl.Step:output_type -> simcontrol.SimStatus
+	6, // 9: simcontrol.SimControl.SetSpeed:output_type -> simcontrol.SimStatus
+	6, // 10: simcontrol.SimControl.JumpToFrame:output_type -> simcontrol.SimStatus
+	6, // 11: simcontrol.SimControl.GetStatus:output_type -> simcontrol.SimStatus
+	6, // [6:12] is the sub-list for method output_type
+	0, // [0:6] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_simcontrol_proto_init() }
+func file_proto_simcontrol_proto_init() {
+	if File_proto_simcontrol_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_simcontrol_proto_rawDesc), len(file_proto_simcontrol_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_simcontrol_proto_goTypes,
+		DependencyIndexes: file_proto_simcontrol_proto_depIdxs,
+		MessageInfos:      file_proto_simcontrol_proto_msgTypes,
+	}.Build()
+	File_proto_simcontrol_proto = out.File
+	file_proto_simcontrol_proto_goTypes = nil
+	file_proto_simcontrol_proto_depIdxs = nil
+}
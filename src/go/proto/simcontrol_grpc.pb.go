@@ -0,0 +1,333 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.1
+// source: proto/simcontrol.proto
+
+package adapter
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SimControl_Pause_FullMethodName       = "/simcontrol.SimControl/Pause"
+	SimControl_Resume_FullMethodName      = "/simcontrol.SimControl/Resume"
+	SimControl_Step_FullMethodName        = "/simcontrol.SimControl/Step"
+	SimControl_SetSpeed_FullMethodName    = "/simcontrol.SimControl/SetSpeed"
+	SimControl_JumpToFrame_FullMethodName = "/simcontrol.SimControl/JumpToFrame"
+	SimControl_GetStatus_FullMethodName   = "/simcontrol.SimControl/GetStatus"
+)
+
+// SimControlClient is the client API for SimControl service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The SimControl service is served by the MockLogic so that operators and QA can
+// control the simulation while it runs. Each RPC replies with the status of the
+// simulation after the request was applied.
+type SimControlClient interface {
+	// Stops the frames from advancing with the clock
+	Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*SimStatus, error)
+	// Advances the frames with the clock again
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*SimStatus, error)
+	// Sends the next frame while the simulation is paused
+	Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*SimStatus, error)
+	// Changes the multiple of real time that the clock runs at
+	SetSpeed(ctx context.Context, in *SetSpeedRequest, opts ...grpc.CallOption) (*SimStatus, error)
+	// Sends the next step of the simulation that shows the given frame. The trips
+	// are updated for the skipped steps, but their boat statuses are not sent.
+	JumpToFrame(ctx context.Context, in *JumpToFrameRequest, opts ...grpc.CallOption) (*SimStatus, error)
+	// Reports the current frame of the simulation
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*SimStatus, error)
+}
+
+type simControlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSimControlClient(cc grpc.ClientConnInterface) SimControlClient {
+	return &simControlClient{cc}
+}
+
+func (c *simControlClient) Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*SimStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimStatus)
+	err := c.cc.Invoke(ctx, SimControl_Pause_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simControlClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*SimStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimStatus)
+	err := c.cc.Invoke(ctx, SimControl_Resume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simControlClient) Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*SimStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimStatus)
+	err := c.cc.Invoke(ctx, SimControl_Step_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simControlClient) SetSpeed(ctx context.Context, in *SetSpeedRequest, opts ...grpc.CallOption) (*SimStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimStatus)
+	err := c.cc.Invoke(ctx, SimControl_SetSpeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simControlClient) JumpToFrame(ctx context.Context, in *JumpToFrameRequest, opts ...grpc.CallOption) (*SimStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimStatus)
+	err := c.cc.Invoke(ctx, SimControl_JumpToFrame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simControlClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*SimStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimStatus)
+	err := c.cc.Invoke(ctx, SimControl_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SimControlServer is the server API for SimControl service.
+// All implementations must embed UnimplementedSimControlServer
+// for forward compatibility.
+//
+// The SimControl service is served by the MockLogic so that operators and QA can
+// control the simulation while it runs. Each RPC replies with the status of the
+// simulation after the request was applied.
+type SimControlServer interface {
+	// Stops the frames from advancing with the clock
+	Pause(context.Context, *PauseRequest) (*SimStatus, error)
+	// Advances the frames with the clock again
+	Resume(context.Context, *ResumeRequest) (*SimStatus, error)
+	// Sends the next frame while the simulation is paused
+	Step(context.Context, *StepRequest) (*SimStatus, error)
+	// Changes the multiple of real time that the clock runs at
+	SetSpeed(context.Context, *SetSpeedRequest) (*SimStatus, error)
+	// Sends the next step of the simulation that shows the given frame. The trips
+	// are updated for the skipped steps, but their boat statuses are not sent.
+	JumpToFrame(context.Context, *JumpToFrameRequest) (*SimStatus, error)
+	// Reports the current frame of the simulation
+	GetStatus(context.Context, *GetStatusRequest) (*SimStatus, error)
+	mustEmbedUnimplementedSimControlServer()
+}
+
+// UnimplementedSimControlServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSimControlServer struct{}
+
+func (UnimplementedSimControlServer) Pause(context.Context, *PauseRequest) (*SimStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedSimControlServer) Resume(context.Context, *ResumeRequest) (*SimStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedSimControlServer) Step(context.Context, *StepRequest) (*SimStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Step not implemented")
+}
+func (UnimplementedSimControlServer) SetSpeed(context.Context, *SetSpeedRequest) (*SimStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSpeed not implemented")
+}
+func (UnimplementedSimControlServer) JumpToFrame(context.Context, *JumpToFrameRequest) (*SimStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JumpToFrame not implemented")
+}
+func (UnimplementedSimControlServer) GetStatus(context.Context, *GetStatusRequest) (*SimStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedSimControlServer) mustEmbedUnimplementedSimControlServer() {}
+func (UnimplementedSimControlServer) testEmbeddedByValue()                    {}
+
+// UnsafeSimControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SimControlServer will
+// result in compilation errors.
+type UnsafeSimControlServer interface {
+	mustEmbedUnimplementedSimControlServer()
+}
+
+func RegisterSimControlServer(s grpc.ServiceRegistrar, srv SimControlServer) {
+	// If the following call pancis, it indicates UnimplementedSimControlServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SimControl_ServiceDesc, srv)
+}
+
+func _SimControl_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimControlServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimControl_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimControlServer).Pause(ctx, req.(*PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimControl_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimControlServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimControl_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimControlServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimControl_Step_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimControlServer).Step(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimControl_Step_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimControlServer).Step(ctx, req.(*StepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimControl_SetSpeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSpeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimControlServer).SetSpeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimControl_SetSpeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimControlServer).SetSpeed(ctx, req.(*SetSpeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimControl_JumpToFrame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JumpToFrameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimControlServer).JumpToFrame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimControl_JumpToFrame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimControlServer).JumpToFrame(ctx, req.(*JumpToFrameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimControl_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimControlServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimControl_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimControlServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SimControl_ServiceDesc is the grpc.ServiceDesc for SimControl service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SimControl_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simcontrol.SimControl",
+	HandlerType: (*SimControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Pause",
+			Handler:    _SimControl_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _SimControl_Resume_Handler,
+		},
+		{
+			MethodName: "Step",
+			Handler:    _SimControl_Step_Handler,
+		},
+		{
+			MethodName: "SetSpeed",
+			Handler:    _SimControl_SetSpeed_Handler,
+		},
+		{
+			MethodName: "JumpToFrame",
+			Handler:    _SimControl_JumpToFrame_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _SimControl_GetStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/simcontrol.proto",
+}
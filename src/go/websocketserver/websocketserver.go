@@ -13,6 +13,11 @@ var WSServerClientPath string = "/api/v1/riden"
 
 var WSSServerAllClientsConnName string = "allClients"
 
+// WSSClientConnectedMessageType is the MessageType of the message that the
+// WebSocketServer sends to the adapter when a client connects, so that the
+// current state of the system can be sent to the client
+var WSSClientConnectedMessageType string = "clientConnected"
+
 // AdapterMessage holds a riden API message in the form of
 // a []byte and the name of the client connection that sent or will
 // receive the message. The name string is the unique detail that
@@ -30,3 +35,15 @@ func NewAdapterMessage(name string, msg []byte) AdapterMessage {
 		MessageBytes:   msg,
 	}
 }
+
+// ClientConnectedMessage is sent by the WebSocketServer to the adapter, in the
+// MessageBytes of an AdapterMessage, when a client connects
+type ClientConnectedMessage struct {
+	MessageType string `json:"messageType"`
+}
+
+func NewClientConnectedMessage() ClientConnectedMessage {
+	return ClientConnectedMessage{
+		MessageType: WSSClientConnectedMessageType,
+	}
+}
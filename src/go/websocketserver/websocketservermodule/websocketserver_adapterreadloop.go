@@ -46,8 +46,14 @@ func AdapterReadLoop(a *Client) error {
 		}
 
 		if adapterMsg.ClientConnName == wss.WSSServerAllClientsConnName {
+			// BoatStatus messages are only delivered to the clients that subscribed
+			// to the boat
+			isDelivered := boatStatusFilter(adapterMsg.MessageBytes)
 			safeClients.Range(func(key, clientVal interface{}) bool {
 				client := clientVal.(*Client)
+				if !isDelivered(client) {
+					return true
+				}
 
 				select {
 				case client.Write <- adapterMsg:
@@ -67,6 +73,7 @@ func AdapterReadLoop(a *Client) error {
 				continue
 			}
 			client = clientVal.(*Client)
+			TrackTripForClient(client, adapterMsg.MessageBytes)
 
 			select {
 			case client.Write <- adapterMsg:
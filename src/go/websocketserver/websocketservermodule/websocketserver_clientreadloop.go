@@ -1,6 +1,7 @@
 package main
 
 import (
+	a "riden/adapter"
 	wss "riden/websocketserver"
 	"time"
 
@@ -38,6 +39,15 @@ func ClientReadLoop(c *Client) error {
 
 		// %q used to escape untrusted user input
 		Logger.Info().Msgf("Received message from client connection %s: %q", c.RemoteConnString(), string(message))
+		if a.HasLegacyKeys(message) {
+			Logger.Warn().Msgf("Received message from client connection %s with the deprecated PascalCase keys",
+				c.RemoteConnString())
+		}
+
+		// Subscription messages are handled here and are not sent to the adapter
+		if HandleSubscriptionMessage(c, message) {
+			continue
+		}
 
 		adapterMsg := wss.NewAdapterMessage(c.RemoteConnString(), message)
 
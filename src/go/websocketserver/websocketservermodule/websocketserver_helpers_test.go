@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 	wss "riden/websocketserver"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -77,3 +78,22 @@ ForLoop:
 	// Clean up
 	safeClients.Delete(clientConn.RemoteConnString())
 }
+
+// waitForAdapterConnection waits for the adapter connection to be set, since the
+// adapter handlers set the connection after the upgrade has completed
+func waitForAdapterConnection() {
+	for i := 0; i < 50 && !AdapterConn.IsConnectionSet(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitForClient waits for the client with the given connection name to be stored,
+// since the client handlers store the client after the upgrade has completed
+func waitForClient(connName string) {
+	for i := 0; i < 50; i++ {
+		if _, ok := safeClients.Load(connName); ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	a "riden/adapter"
+	wss "riden/websocketserver"
+	"slices"
+	"sync"
+)
+
+// Subscriptions holds the BoatStatus subscriptions of a client connection and the
+// boats of the trips reserved on the connection. The ClientReadLoop updates the
+// subscriptions and the AdapterReadLoop reads them, so every method is safe for
+// concurrent use. A client that has never subscribed receives every BoatStatus
+// message, so that clients that do not use subscriptions are not affected.
+type Subscriptions struct {
+	mux        sync.RWMutex
+	isFiltered bool
+	boatIDs    map[int32]struct{}
+	docks      map[a.Dock]struct{}
+	myTrips    bool
+	trips      map[string]trackedTrip
+}
+
+// trackedTrip holds the boat of a trip reserved on the client connection and the
+// number of Arrived messages that were sent for the trip. The first arrival is at
+// the SourceDock and the second is at the DestinationDock.
+type trackedTrip struct {
+	boatID   int32
+	arrivals int
+}
+
+// tripLegs is the number of arrivals of a trip, at the SourceDock and then at
+// the DestinationDock
+const tripLegs int = 2
+
+func NewSubscriptions() *Subscriptions {
+	return &Subscriptions{
+		boatIDs: make(map[int32]struct{}),
+		docks:   make(map[a.Dock]struct{}),
+		trips:   make(map[string]trackedTrip),
+	}
+}
+
+// Subscribe adds the boats, docks and trips in the Subscribe message to the
+// subscriptions
+func (s *Subscriptions) Subscribe(msg a.SubscribeAPIMessage) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.isFiltered = true
+	for _, boatID := range msg.BoatIDs {
+		s.boatIDs[boatID] = struct{}{}
+	}
+	for _, dock := range msg.Docks {
+		s.docks[dock] = struct{}{}
+	}
+	if msg.MyTrips {
+		s.myTrips = true
+	}
+}
+
+// Unsubscribe removes the boats, docks and trips in the Unsubscribe message from
+// the subscriptions. The client does not return to receiving every BoatStatus
+// message when the last subscription is removed. An Unsubscribe message from a
+// client that has never subscribed is ignored, so that the client keeps
+// receiving every BoatStatus message.
+func (s *Subscriptions) Unsubscribe(msg a.UnsubscribeAPIMessage) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !s.isFiltered {
+		return
+	}
+	for _, boatID := range msg.BoatIDs {
+		delete(s.boatIDs, boatID)
+	}
+	for _, dock := range msg.Docks {
+		delete(s.docks, dock)
+	}
+	if msg.MyTrips {
+		s.myTrips = false
+	}
+}
+
+// Matches returns whether the BoatStatus message should be delivered to the
+// client. The status matches a dock subscription when the boat is docked at or
+// arriving next at the dock.
+func (s *Subscriptions) Matches(status a.BoatStatusAPIMessage) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	if !s.isFiltered {
+		return true
+	}
+	if _, ok := s.boatIDs[status.Boat.BoatID]; ok {
+		return true
+	}
+	if _, ok := s.docks[status.CurrentDock]; ok {
+		return true
+	}
+	if _, ok := s.docks[status.NextDock]; ok {
+		return true
+	}
+	if s.myTrips {
+		for _, trip := range s.trips {
+			if trip.boatID == status.Boat.BoatID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TrackTrip records the boat of a trip reserved on the client connection and the
+// number of arrivals of the trip so far, so that a "my trips" subscription
+// matches the BoatStatus messages for the boat
+func (s *Subscriptions) TrackTrip(transactionID string, boatID int32, arrivals int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.trips[transactionID] = trackedTrip{boatID: boatID, arrivals: arrivals}
+}
+
+// TripArrived counts an arrival of a trip reserved on the client connection, and
+// removes the trip once its boat has arrived at the DestinationDock
+func (s *Subscriptions) TripArrived(transactionID string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	trip, ok := s.trips[transactionID]
+	if !ok {
+		return
+	}
+	trip.arrivals++
+	if trip.arrivals >= tripLegs {
+		delete(s.trips, transactionID)
+		return
+	}
+	s.trips[transactionID] = trip
+}
+
+// ForgetTrip removes a trip that was cancelled or has finished
+func (s *Subscriptions) ForgetTrip(transactionID string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delete(s.trips, transactionID)
+}
+
+// SubscriptionsMessage returns the Subscriptions message that describes the
+// current subscriptions. The boats and docks are sorted so that the message is
+// stable.
+func (s *Subscriptions) SubscriptionsMessage() a.SubscriptionsAPIMessage {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	boatIDs := make([]int32, 0, len(s.boatIDs))
+	for boatID := range s.boatIDs {
+		boatIDs = append(boatIDs, boatID)
+	}
+	slices.Sort(boatIDs)
+
+	docks := make([]a.Dock, 0, len(s.docks))
+	for dock := range s.docks {
+		docks = append(docks, dock)
+	}
+	slices.SortFunc(docks, compareDocks)
+
+	return a.NewSubscriptionsAPIMessage(a.APIMessageTypeSubscriptions, boatIDs, docks, s.myTrips)
+}
+
+// compareDocks orders docks by street, number and gangway
+func compareDocks(d1, d2 a.Dock) int {
+	return cmp.Or(
+		cmp.Compare(d1.Address.Street, d2.Address.Street),
+		cmp.Compare(d1.Address.Number, d2.Address.Number),
+		cmp.Compare(d1.Gangway, d2.Gangway),
+	)
+}
+
+// messageType returns the messageType of the API message, or an empty string if
+// the message could not be decoded
+func messageType(message []byte) string {
+	// Ignore the error since messages that are not valid JSON are passed on
+	// without being inspected
+	msgType, _ := a.DecodeMessageType(message)
+
+	return msgType
+}
+
+// HandleSubscriptionMessage applies a Subscribe or Unsubscribe message from the
+// client and replies with the Subscriptions message, or with an Error message if
+// the message could not be decoded. It returns false if the message is not a
+// subscription message and should be sent to the adapter.
+func HandleSubscriptionMessage(c *Client, message []byte) bool {
+	msgType := messageType(message)
+	var err error
+	switch msgType {
+	case a.APIMessageTypeSubscribe:
+		var subscribeMsg a.SubscribeAPIMessage
+		err = json.Unmarshal(message, &subscribeMsg)
+		if err == nil {
+			c.Subscriptions.Subscribe(subscribeMsg)
+		}
+
+	case a.APIMessageTypeUnsubscribe:
+		var unsubscribeMsg a.UnsubscribeAPIMessage
+		err = json.Unmarshal(message, &unsubscribeMsg)
+		if err == nil {
+			c.Subscriptions.Unsubscribe(unsubscribeMsg)
+		}
+
+	default:
+		return false
+	}
+
+	var reply any = c.Subscriptions.SubscriptionsMessage()
+	if err != nil {
+		Logger.Warn().Msgf("Error unmarshaling %s message from client %s: %s",
+			msgType, c.RemoteConnString(), err.Error())
+		reply = a.NewErrorAPIMessage(a.APIMessageTypeError, "", a.ErrorReasonInvalidMessage,
+			fmt.Sprintf("message could not be decoded: %s", err.Error()), msgType, "")
+	}
+	replyBytes, err := json.Marshal(reply)
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling reply to %s message: %s", msgType, err.Error())
+		return true
+	}
+
+	select {
+	case c.Write <- wss.NewAdapterMessage(c.RemoteConnString(), replyBytes):
+	default:
+		Logger.Error().Msgf("could not place reply to %s message on client.Write", msgType)
+	}
+
+	return true
+}
+
+// tripFields is used with json.Unmarshal() to decode the fields of the messages
+// that the adapter sends about a trip reserved on a client connection
+type tripFields struct {
+	TransactionID       string `json:"transactionID"`
+	Boat                a.Boat `json:"boat"`
+	IsReserved          bool   `json:"isReserved"`
+	IsCancelled         bool   `json:"isCancelled"`
+	IsFound             bool   `json:"isFound"`
+	TripState           string `json:"tripState"`
+	RejectedMessageType string `json:"rejectedMessageType"`
+}
+
+// tripStateArrivals holds the number of arrivals of a trip in each of the
+// tripState values of the TripStatus message that are reported while the trip
+// is under way. The trips in the other states have finished or have not
+// started.
+var tripStateArrivals = map[string]int{
+	"boatArrivedAtSource": 1,
+	"clientOnBoat":        1,
+	"boatArrivedAtDest":   tripLegs,
+	"clientOffBoat":       tripLegs,
+	"cancelled":           tripLegs,
+}
+
+// TrackTripForClient records or forgets the trip in a message that the adapter
+// sends to the client, so that the "my trips" subscription follows the trips
+// reserved on the connection. Reserved trips are recorded from the Ack and from
+// the TripStatus that a reconnected client requests. They are forgotten when the
+// CancelAck confirms the cancellation, when an Error for the ReserveTrip reports
+// that the trip was cancelled, and when the boat arrives at the DestinationDock.
+func TrackTripForClient(c *Client, message []byte) {
+	msgType := messageType(message)
+	if msgType != a.APIMessageTypeAck && msgType != a.APIMessageTypeTripStatus &&
+		msgType != a.APIMessageTypeCancelAck && msgType != a.APIMessageTypeError &&
+		msgType != a.APIMessageTypeArrived {
+		return
+	}
+
+	var fields tripFields
+	err := json.Unmarshal(message, &fields)
+	if err != nil {
+		Logger.Warn().Msgf("Error unmarshaling %s message for client %s: %s",
+			msgType, c.RemoteConnString(), err.Error())
+		return
+	}
+
+	switch {
+	case msgType == a.APIMessageTypeAck && fields.IsReserved:
+		c.Subscriptions.TrackTrip(fields.TransactionID, fields.Boat.BoatID, 0)
+
+	case msgType == a.APIMessageTypeTripStatus && fields.IsFound:
+		arrivals := tripStateArrivals[fields.TripState]
+		if arrivals >= tripLegs {
+			c.Subscriptions.ForgetTrip(fields.TransactionID)
+			return
+		}
+		c.Subscriptions.TrackTrip(fields.TransactionID, fields.Boat.BoatID, arrivals)
+
+	case msgType == a.APIMessageTypeArrived:
+		c.Subscriptions.TripArrived(fields.TransactionID)
+
+	case msgType == a.APIMessageTypeCancelAck && fields.IsCancelled,
+		msgType == a.APIMessageTypeError && fields.TransactionID != "" &&
+			fields.RejectedMessageType == a.APIMessageTypeReserveTrip:
+		c.Subscriptions.ForgetTrip(fields.TransactionID)
+	}
+}
+
+// boatStatusFilter returns the function that reports whether a message broadcast
+// to all clients should be delivered to a client. Only BoatStatus messages are
+// filtered by the client subscriptions.
+func boatStatusFilter(message []byte) func(c *Client) bool {
+	if messageType(message) != a.APIMessageTypeBoatStatus {
+		return func(c *Client) bool { return true }
+	}
+
+	var status a.BoatStatusAPIMessage
+	err := json.Unmarshal(message, &status)
+	if err != nil {
+		Logger.Warn().Msgf("Error unmarshaling %s message: %s",
+			a.APIMessageTypeBoatStatus, err.Error())
+		return func(c *Client) bool { return true }
+	}
+
+	return func(c *Client) bool {
+		return c.Subscriptions.Matches(status)
+	}
+}
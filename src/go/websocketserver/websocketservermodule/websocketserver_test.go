@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	a "riden/adapter"
 	"riden/logger"
 	wss "riden/websocketserver"
 	"slices"
@@ -113,6 +114,7 @@ func TestClientWebSocketHandlerHandshakeUpgrade(t *testing.T) {
 	if setupErr != nil {
 		t.Fatalf("Error dialing adapter URL in test set-up: %s", setupErr.Error())
 	}
+	waitForAdapterConnection()
 
 	var clients []*websocket.Conn
 
@@ -312,6 +314,7 @@ func TestMultipleAdapterConnections(t *testing.T) {
 		}
 
 		adapters = append(adapters, ws)
+		waitForAdapterConnection()
 
 		// Check the status code is what we expect.
 		if response.StatusCode != testCase.expectedStatusCode {
@@ -384,6 +387,8 @@ func TestSendingMessageFromClientToAdapter(t *testing.T) {
 		t.Fatalf("Error dialing adapter URL in test set-up: %s", setupErr.Error())
 	}
 
+	waitForAdapterConnection()
+
 	clientWebSocketHandler := clientWebSocketHandler{
 		upgrader: websocket.Upgrader{},
 	}
@@ -404,6 +409,24 @@ func TestSendingMessageFromClientToAdapter(t *testing.T) {
 		t.Fatalf("Error dialing client URL in test set-up: %s", setupErr.Error())
 	}
 
+	// The adapter is notified when the client connects
+	var connectedMessage wss.AdapterMessage
+	select {
+	case connectedMessage = <-mockAdapterReceiveHandler.Message:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected a %s message but none was received", wss.WSSClientConnectedMessageType)
+	}
+	if connectedMessage.ClientConnName != ws.LocalAddr().String() {
+		t.Fatalf("Expected client conn name %s in the %s message but received %s",
+			ws.LocalAddr().String(), wss.WSSClientConnectedMessageType, connectedMessage.ClientConnName)
+	}
+	var connected wss.ClientConnectedMessage
+	setupErr = json.Unmarshal(connectedMessage.MessageBytes, &connected)
+	if setupErr != nil || connected.MessageType != wss.WSSClientConnectedMessageType {
+		t.Fatalf("Expected a %s message but received %s with error: %v",
+			wss.WSSClientConnectedMessageType, string(connectedMessage.MessageBytes), setupErr)
+	}
+
 	// Create test cases
 	cases := []testCase{
 		{
@@ -497,6 +520,8 @@ func TestSendingMessageFromAdapterToClient(t *testing.T) {
 		t.Fatalf("Error dialing client URL in test set-up: %s", setupErr.Error())
 	}
 
+	waitForClient(ws.LocalAddr().String())
+
 	// Create test cases
 	cases := []testCase{
 		{
@@ -552,3 +577,214 @@ func TestSendingMessageFromAdapterToClient(t *testing.T) {
 
 	time.Sleep(500 * time.Millisecond)
 }
+
+func TestSubscriptionsBoatStatusFilter(t *testing.T) {
+	type testCase struct {
+		name              string
+		apply             func(c *Client)
+		boatStatus        a.BoatStatusAPIMessage
+		expectedDelivered bool
+	}
+
+	testDock := a.NewDock(a.NewAddress(901, "Carson St"), a.GangwayLocationFore)
+	otherDock := a.NewDock(a.NewAddress(964, "Stanwix St"), a.GangwayLocationFore)
+	newBoatStatus := func(boatID int32, currentDock, nextDock a.Dock) a.BoatStatusAPIMessage {
+		return a.NewBoatStatusAPIMessage(a.APIMessageTypeBoatStatus, a.NewBoat(boatID, "testBoat"),
+			a.ServiceStateOnTime, otherDock, currentDock, nextDock, 10)
+	}
+	marshal := func(msg any) []byte {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("Error marshaling message in test set-up: %s", err.Error())
+		}
+		return b
+	}
+
+	client := Client{
+		Subscriptions: NewSubscriptions(),
+	}
+
+	// The test cases are run in order against the same client
+	cases := []testCase{
+		{
+			name:              "Subscriptions BoatStatus Filter - Client never subscribed",
+			apply:             func(c *Client) {},
+			boatStatus:        newBoatStatus(1, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Client unsubscribed without subscribing",
+			apply: func(c *Client) {
+				c.Subscriptions.Unsubscribe(a.NewUnsubscribeAPIMessage(a.APIMessageTypeUnsubscribe,
+					[]int32{1}, nil, false))
+			},
+			boatStatus:        newBoatStatus(2, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Subscribed to another boat",
+			apply: func(c *Client) {
+				c.Subscriptions.Subscribe(a.NewSubscribeAPIMessage(a.APIMessageTypeSubscribe,
+					[]int32{2}, nil, false))
+			},
+			boatStatus:        newBoatStatus(1, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name:              "Subscriptions BoatStatus Filter - Subscribed boat",
+			apply:             func(c *Client) {},
+			boatStatus:        newBoatStatus(2, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat arriving next at subscribed dock",
+			apply: func(c *Client) {
+				c.Subscriptions.Subscribe(a.NewSubscribeAPIMessage(a.APIMessageTypeSubscribe,
+					nil, []a.Dock{testDock}, false))
+			},
+			boatStatus:        newBoatStatus(1, otherDock, testDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of reserved trip without my trips",
+			apply: func(c *Client) {
+				ack := a.NewAckAPIMessage(a.APIMessageTypeAck, "testClient", true,
+					a.NewBoat(3, "testBoat"), "testTransaction", a.ErrorReasonUnknown, "",
+					time.Time{}, time.Time{}, time.Time{})
+				TrackTripForClient(c, marshal(ack))
+			},
+			boatStatus:        newBoatStatus(3, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of reserved trip with my trips",
+			apply: func(c *Client) {
+				c.Subscriptions.Subscribe(a.NewSubscribeAPIMessage(a.APIMessageTypeSubscribe,
+					nil, nil, true))
+			},
+			boatStatus:        newBoatStatus(3, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of cancelled trip",
+			apply: func(c *Client) {
+				cancelAck := a.NewCancelAckAPIMessage(a.APIMessageTypeCancelAck, "testClient",
+					"testTransaction", true, a.ErrorReasonUnknown, "")
+				TrackTripForClient(c, marshal(cancelAck))
+			},
+			boatStatus:        newBoatStatus(3, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of reserved trip with the deprecated keys",
+			apply: func(c *Client) {
+				TrackTripForClient(c, []byte(`{"MessageType":"ack","IsReserved":true,`+
+					`"Boat":{"BoatID":3,"Name":"testBoat"},"TransactionID":"legacyTransaction"}`))
+			},
+			boatStatus:        newBoatStatus(3, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of trip arrived at the source dock",
+			apply: func(c *Client) {
+				arrived := a.NewArrivedAPIMessage(a.APIMessageTypeArrived, "testClient",
+					a.NewBoat(3, "testBoat"), otherDock, "legacyTransaction")
+				TrackTripForClient(c, marshal(arrived))
+			},
+			boatStatus:        newBoatStatus(3, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of trip arrived at the destination dock",
+			apply: func(c *Client) {
+				arrived := a.NewArrivedAPIMessage(a.APIMessageTypeArrived, "testClient",
+					a.NewBoat(3, "testBoat"), testDock, "legacyTransaction")
+				TrackTripForClient(c, marshal(arrived))
+			},
+			boatStatus:        newBoatStatus(3, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of trip cancelled with an error",
+			apply: func(c *Client) {
+				ack := a.NewAckAPIMessage(a.APIMessageTypeAck, "testClient", true,
+					a.NewBoat(4, "testBoat"), "noShowTransaction", a.ErrorReasonUnknown, "",
+					time.Time{}, time.Time{}, time.Time{})
+				TrackTripForClient(c, marshal(ack))
+				errMsg := a.NewErrorAPIMessage(a.APIMessageTypeError, "testClient",
+					a.ErrorReasonInvalidTripState, "the boat left the source dock before the party boarded",
+					a.APIMessageTypeReserveTrip, "noShowTransaction")
+				TrackTripForClient(c, marshal(errMsg))
+			},
+			boatStatus:        newBoatStatus(4, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of finished trip in a TripStatus",
+			apply: func(c *Client) {
+				tripStatus := a.NewTripStatusAPIMessage(a.APIMessageTypeTripStatus, "testClient",
+					"finishedTransaction", true, a.ErrorReasonUnknown, "", "clientOffBoat",
+					otherDock, testDock, a.NewBoat(5, "testBoat"), a.BoatStatusAPIMessage{},
+					time.Time{}, time.Time{})
+				TrackTripForClient(c, marshal(tripStatus))
+			},
+			boatStatus:        newBoatStatus(5, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of trip on board in a TripStatus",
+			apply: func(c *Client) {
+				tripStatus := a.NewTripStatusAPIMessage(a.APIMessageTypeTripStatus, "testClient",
+					"onBoardTransaction", true, a.ErrorReasonUnknown, "", "clientOnBoat",
+					otherDock, testDock, a.NewBoat(5, "testBoat"), a.BoatStatusAPIMessage{},
+					time.Time{}, time.Time{})
+				TrackTripForClient(c, marshal(tripStatus))
+			},
+			boatStatus:        newBoatStatus(5, otherDock, otherDock),
+			expectedDelivered: true,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Boat of trip on board arrived at the destination dock",
+			apply: func(c *Client) {
+				arrived := a.NewArrivedAPIMessage(a.APIMessageTypeArrived, "testClient",
+					a.NewBoat(5, "testBoat"), testDock, "onBoardTransaction")
+				TrackTripForClient(c, marshal(arrived))
+			},
+			boatStatus:        newBoatStatus(5, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+		{
+			name: "Subscriptions BoatStatus Filter - Unsubscribed boat",
+			apply: func(c *Client) {
+				c.Subscriptions.Unsubscribe(a.NewUnsubscribeAPIMessage(a.APIMessageTypeUnsubscribe,
+					[]int32{2}, nil, false))
+			},
+			boatStatus:        newBoatStatus(2, otherDock, otherDock),
+			expectedDelivered: false,
+		},
+	}
+
+	for _, testCase := range cases {
+		testCase.apply(&client)
+
+		isDelivered := boatStatusFilter(marshal(testCase.boatStatus))
+		if isDelivered(&client) != testCase.expectedDelivered {
+			t.Fatalf("Expected delivery of BoatStatus to be %v in test case: %s",
+				testCase.expectedDelivered, testCase.name)
+		}
+	}
+
+	// Messages that are not BoatStatus messages are delivered to every client
+	isDelivered := boatStatusFilter(testMessageBytes)
+	if !isDelivered(&client) {
+		t.Fatal("Expected a message that is not a BoatStatus message to be delivered")
+	}
+
+	expectedSubscriptions := a.NewSubscriptionsAPIMessage(a.APIMessageTypeSubscriptions,
+		[]int32{}, []a.Dock{testDock}, true)
+	subscriptions := client.Subscriptions.SubscriptionsMessage()
+	if string(marshal(subscriptions)) != string(marshal(expectedSubscriptions)) {
+		t.Fatalf("Expected subscriptions %+v but received %+v",
+			expectedSubscriptions, subscriptions)
+	}
+}
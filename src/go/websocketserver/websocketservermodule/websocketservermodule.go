@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
@@ -21,11 +22,13 @@ var LogDirectory string
 
 // Client holds the details of a client's WebSocket connection. Close
 // is used to signal that the connection is closing soon and no new message
-// operations should occur on the connection.
+// operations should occur on the connection. Subscriptions holds the
+// BoatStatus subscriptions of the client.
 type Client struct {
-	WSConn *websocket.Conn
-	Close  chan struct{}
-	Write  chan wss.AdapterMessage
+	WSConn        *websocket.Conn
+	Close         chan struct{}
+	Write         chan wss.AdapterMessage
+	Subscriptions *Subscriptions
 }
 
 func (c *Client) RemoteConnString() string {
@@ -36,6 +39,7 @@ func (c *Client) RemoteConnString() string {
 func (c *Client) Initialize() {
 	c.Close = make(chan struct{})
 	c.Write = make(chan wss.AdapterMessage, ChannelBufferSize)
+	c.Subscriptions = NewSubscriptions()
 }
 
 func (c *Client) CleanUpAfterReadLoop() {
@@ -153,6 +157,9 @@ func (wsh clientWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 	// Launch the message writer loop that will close when it receives a close signal
 	go ClientWriteLoop(&clientConn)
 
+	// Notify the adapter so that the current state of the system is sent to the client
+	NotifyAdapterOfClient(&clientConn)
+
 	// Call the message reader loop that returns here if the loop is broken
 	err = ClientReadLoop(&clientConn)
 	Logger.Info().Msgf("ClientReadLoop returned for remote address: %s, with err: %v",
@@ -216,6 +223,24 @@ func (wsh adapterWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	AdapterConn.ClearConnection()
 }
 
+// NotifyAdapterOfClient sends a ClientConnected message to the adapter for the
+// given client
+func NotifyAdapterOfClient(c *Client) {
+	msgBytes, err := json.Marshal(wss.NewClientConnectedMessage())
+	if err != nil {
+		Logger.Error().Msgf("Error marshaling %s message: %s",
+			wss.WSSClientConnectedMessageType, err.Error())
+		return
+	}
+	adapterMsg := wss.NewAdapterMessage(c.RemoteConnString(), msgBytes)
+
+	select {
+	case AdapterConn.Write <- adapterMsg:
+	default:
+		Logger.Error().Msgf("could not place messaage on AdapterConn.Write: %+v", adapterMsg)
+	}
+}
+
 // ReturnError returns the given status code and reason on the given ResponseWriter
 func ReturnError(w http.ResponseWriter, status int) {
 	w.Header().Set("Sec-Websocket-Version", "13")